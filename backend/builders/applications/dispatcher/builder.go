@@ -0,0 +1,74 @@
+package dispatcher
+
+import (
+	"time"
+
+	"github.com/muji40k/ozontestcomms/builders/errors"
+	"github.com/muji40k/ozontestcomms/internal/application/dispatcher"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/outbox"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+)
+
+type registration struct {
+	kind    string
+	handler dispatcher.Handler
+}
+
+type DispatcherBuilder struct {
+	outbox   outbox.Repository
+	interval *nullable.Nullable[time.Duration]
+	batch    *nullable.Nullable[int32]
+	handlers []registration
+}
+
+func NewDispatcherBuilder() *DispatcherBuilder {
+	return &DispatcherBuilder{
+		outbox:   nil,
+		interval: nullable.None[time.Duration](),
+		batch:    nullable.None[int32](),
+		handlers: nil,
+	}
+}
+
+func (self *DispatcherBuilder) WithOutbox(value outbox.Repository) *DispatcherBuilder {
+	self.outbox = value
+	return self
+}
+
+// Optional, dispatcher.DEFAULT_INTERVAL is used otherwise
+func (self *DispatcherBuilder) WithInterval(value time.Duration) *DispatcherBuilder {
+	self.interval = nullable.Some(value)
+	return self
+}
+
+// Optional, dispatcher.DEFAULT_BATCH_SIZE is used otherwise
+func (self *DispatcherBuilder) WithBatchSize(value int32) *DispatcherBuilder {
+	self.batch = nullable.Some(value)
+	return self
+}
+
+// Optional, may be repeated, events without handlers are acknowledged
+// right away
+func (self *DispatcherBuilder) WithHandler(kind string, handler dispatcher.Handler) *DispatcherBuilder {
+	self.handlers = append(self.handlers, registration{kind, handler})
+	return self
+}
+
+func (self *DispatcherBuilder) Build() (*dispatcher.Dispatcher, error) {
+	if nil == self.outbox {
+		return nil, errors.NotReady("dispatcher.Dispatcher")
+	}
+
+	out := dispatcher.New(
+		self.outbox,
+		nullable.GetOr(self.interval, dispatcher.DEFAULT_INTERVAL),
+		nullable.GetOr(self.batch, dispatcher.DEFAULT_BATCH_SIZE),
+	)
+
+	for _, v := range self.handlers {
+		out.Register(v.kind, v.handler)
+	}
+
+	return out, nil
+}
+
@@ -5,9 +5,16 @@ import (
 
 	"github.com/muji40k/ozontestcomms/builders/errors"
 	"github.com/muji40k/ozontestcomms/graphql"
+	"github.com/muji40k/ozontestcomms/graphql/persisted"
+	"github.com/muji40k/ozontestcomms/internal/application/httpserver"
 	"github.com/muji40k/ozontestcomms/internal/service/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/follow"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/health"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/moderation"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/notification"
 	"github.com/muji40k/ozontestcomms/internal/service/interface/post"
 	"github.com/muji40k/ozontestcomms/internal/service/interface/user"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/webhook"
 	"github.com/muji40k/ozontestcomms/misc/nullable"
 )
 
@@ -15,9 +22,18 @@ type ServerBuilder struct {
 	host           *nullable.Nullable[string]
 	port           *nullable.Nullable[string]
 	loaderDuration *nullable.Nullable[time.Duration]
+	shutdown       *nullable.Nullable[time.Duration]
+	watch          *nullable.Nullable[time.Duration]
+	queries        *nullable.Nullable[string]
+	maxAge         *nullable.Nullable[time.Duration]
 	user           user.Service
 	comment        comment.Service
 	post           post.Service
+	health         health.Service
+	webhook        webhook.Service
+	notification   notification.Service
+	follow         follow.Service
+	moderation     moderation.Service
 }
 
 func NewServerBuilder() *ServerBuilder {
@@ -25,9 +41,18 @@ func NewServerBuilder() *ServerBuilder {
 		host:           nullable.None[string](),
 		port:           nullable.None[string](),
 		loaderDuration: nullable.None[time.Duration](),
+		shutdown:       nullable.None[time.Duration](),
+		watch:          nullable.None[time.Duration](),
+		queries:        nullable.None[string](),
+		maxAge:         nullable.None[time.Duration](),
 		user:           nil,
 		comment:        nil,
 		post:           nil,
+		health:         nil,
+		webhook:        nil,
+		notification:   nil,
+		follow:         nil,
+		moderation:     nil,
 	}
 }
 
@@ -46,6 +71,31 @@ func (self *ServerBuilder) WithLoaderDuration(value time.Duration) *ServerBuilde
 	return self
 }
 
+// Optional, httpserver.DEFAULT_SHUTDOWN_TIMEOUT is used otherwise
+func (self *ServerBuilder) WithShutdownTimeout(value time.Duration) *ServerBuilder {
+	self.shutdown = nullable.Some(value)
+	return self
+}
+
+// Optional, graphql.DEFAULT_WATCH_INTERVAL is used otherwise
+func (self *ServerBuilder) WithWatchInterval(value time.Duration) *ServerBuilder {
+	self.watch = nullable.Some(value)
+	return self
+}
+
+// Optional, directory of persisted queries, the only operations accepted.
+// Any operation is accepted otherwise
+func (self *ServerBuilder) WithPersistedQueries(dir string) *ServerBuilder {
+	self.queries = nullable.Some(dir)
+	return self
+}
+
+// Optional, graphql.DEFAULT_CACHE_MAX_AGE is used otherwise
+func (self *ServerBuilder) WithCacheMaxAge(value time.Duration) *ServerBuilder {
+	self.maxAge = nullable.Some(value)
+	return self
+}
+
 func (self *ServerBuilder) WithUserService(value user.Service) *ServerBuilder {
 	self.user = value
 	return self
@@ -61,6 +111,37 @@ func (self *ServerBuilder) WithPostService(value post.Service) *ServerBuilder {
 	return self
 }
 
+// Optional, without it readiness only reflects the server state
+func (self *ServerBuilder) WithHealthService(value health.Service) *ServerBuilder {
+	self.health = value
+	return self
+}
+
+// Optional, webhook queries and mutations fail without it
+func (self *ServerBuilder) WithWebhookService(value webhook.Service) *ServerBuilder {
+	self.webhook = value
+	return self
+}
+
+// Optional, notification queries, mutations and subscriptions fail
+// without it
+func (self *ServerBuilder) WithNotificationService(value notification.Service) *ServerBuilder {
+	self.notification = value
+	return self
+}
+
+// Optional, follow mutations, feed and watched threads fail without it
+func (self *ServerBuilder) WithFollowService(value follow.Service) *ServerBuilder {
+	self.follow = value
+	return self
+}
+
+// Optional, review queue and its resolution fail without it
+func (self *ServerBuilder) WithModerationService(value moderation.Service) *ServerBuilder {
+	self.moderation = value
+	return self
+}
+
 func (self *ServerBuilder) Build() (*graphql.Server, error) {
 	if nullable.IsNone(self.host) || nullable.IsNone(self.port) ||
 		nullable.IsNone(self.loaderDuration) || nil == self.user ||
@@ -68,14 +149,36 @@ func (self *ServerBuilder) Build() (*graphql.Server, error) {
 		return nil, errors.NotReady("graphql.Server")
 	}
 
+	queries := graphql.Persisted{
+		MaxAge: nullable.GetOr(self.maxAge, graphql.DEFAULT_CACHE_MAX_AGE),
+	}
+
+	if nullable.IsSome(self.queries) {
+		registry, err := persisted.Load(nullable.Unwrap(self.queries))
+
+		if nil != err {
+			return nil, err
+		}
+
+		queries.Registry = registry
+	}
+
 	return graphql.New(
 		nullable.Unwrap(self.host),
 		nullable.Unwrap(self.port),
 		nullable.Unwrap(self.loaderDuration),
+		nullable.GetOr(self.shutdown, httpserver.DEFAULT_SHUTDOWN_TIMEOUT),
+		nullable.GetOr(self.watch, graphql.DEFAULT_WATCH_INTERVAL),
+		queries,
 		graphql.Context{
-			User:    self.user,
-			Comment: self.comment,
-			Post:    self.post,
+			User:         self.user,
+			Comment:      self.comment,
+			Post:         self.post,
+			Health:       self.health,
+			Webhook:      self.webhook,
+			Notification: self.notification,
+			Follow:       self.follow,
+			Moderation:   self.moderation,
 		},
 	), nil
 }
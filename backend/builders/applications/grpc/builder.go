@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"time"
+
+	"github.com/muji40k/ozontestcomms/builders/errors"
+	"github.com/muji40k/ozontestcomms/grpc"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/health"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/post"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/user"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+)
+
+type ServerBuilder struct {
+	host     *nullable.Nullable[string]
+	port     *nullable.Nullable[string]
+	shutdown *nullable.Nullable[time.Duration]
+	watch    *nullable.Nullable[time.Duration]
+	user     user.Service
+	comment  comment.Service
+	post     post.Service
+	health   health.Service
+}
+
+func NewServerBuilder() *ServerBuilder {
+	return &ServerBuilder{
+		host:     nullable.None[string](),
+		port:     nullable.None[string](),
+		shutdown: nullable.None[time.Duration](),
+		watch:    nullable.None[time.Duration](),
+		user:     nil,
+		comment:  nil,
+		post:     nil,
+		health:   nil,
+	}
+}
+
+func (self *ServerBuilder) WithHost(value string) *ServerBuilder {
+	self.host = nullable.Some(value)
+	return self
+}
+
+func (self *ServerBuilder) WithPort(value string) *ServerBuilder {
+	self.port = nullable.Some(value)
+	return self
+}
+
+func (self *ServerBuilder) WithShutdownTimeout(value time.Duration) *ServerBuilder {
+	self.shutdown = nullable.Some(value)
+	return self
+}
+
+// Optional, grpc.DEFAULT_WATCH_INTERVAL is used otherwise
+func (self *ServerBuilder) WithWatchInterval(value time.Duration) *ServerBuilder {
+	self.watch = nullable.Some(value)
+	return self
+}
+
+func (self *ServerBuilder) WithUserService(value user.Service) *ServerBuilder {
+	self.user = value
+	return self
+}
+
+func (self *ServerBuilder) WithCommentService(value comment.Service) *ServerBuilder {
+	self.comment = value
+	return self
+}
+
+func (self *ServerBuilder) WithPostService(value post.Service) *ServerBuilder {
+	self.post = value
+	return self
+}
+
+// Optional, without it readiness only reflects the server state
+func (self *ServerBuilder) WithHealthService(value health.Service) *ServerBuilder {
+	self.health = value
+	return self
+}
+
+func (self *ServerBuilder) Build() (*grpc.Server, error) {
+	if nullable.IsNone(self.host) || nullable.IsNone(self.port) ||
+		nil == self.user || nil == self.comment || nil == self.post {
+		return nil, errors.NotReady("grpc.Server")
+	}
+
+	return grpc.New(
+		nullable.Unwrap(self.host),
+		nullable.Unwrap(self.port),
+		nullable.GetOr(self.shutdown, grpc.DEFAULT_SHUTDOWN_TIMEOUT),
+		nullable.GetOr(self.watch, grpc.DEFAULT_WATCH_INTERVAL),
+		grpc.Context{
+			User:    self.user,
+			Comment: self.comment,
+			Post:    self.post,
+			Health:  self.health,
+		},
+	), nil
+}
+
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/muji40k/ozontestcomms/builders/errors"
+	"github.com/muji40k/ozontestcomms/internal/application/httpserver"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/health"
+	"github.com/muji40k/ozontestcomms/metrics"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+)
+
+type ServerBuilder struct {
+	host     *nullable.Nullable[string]
+	port     *nullable.Nullable[string]
+	shutdown *nullable.Nullable[time.Duration]
+	health   health.Service
+}
+
+func NewServerBuilder() *ServerBuilder {
+	return &ServerBuilder{
+		host:     nullable.None[string](),
+		port:     nullable.None[string](),
+		shutdown: nullable.None[time.Duration](),
+		health:   nil,
+	}
+}
+
+func (self *ServerBuilder) WithHost(value string) *ServerBuilder {
+	self.host = nullable.Some(value)
+	return self
+}
+
+func (self *ServerBuilder) WithPort(value string) *ServerBuilder {
+	self.port = nullable.Some(value)
+	return self
+}
+
+// Optional, httpserver.DEFAULT_SHUTDOWN_TIMEOUT is used otherwise
+func (self *ServerBuilder) WithShutdownTimeout(value time.Duration) *ServerBuilder {
+	self.shutdown = nullable.Some(value)
+	return self
+}
+
+// Optional, without it readiness only reflects the server state
+func (self *ServerBuilder) WithHealthService(value health.Service) *ServerBuilder {
+	self.health = value
+	return self
+}
+
+func (self *ServerBuilder) Build() (*metrics.Server, error) {
+	if nullable.IsNone(self.host) || nullable.IsNone(self.port) {
+		return nil, errors.NotReady("metrics.Server")
+	}
+
+	return metrics.New(
+		nullable.Unwrap(self.host),
+		nullable.Unwrap(self.port),
+		nullable.GetOr(self.shutdown, httpserver.DEFAULT_SHUTDOWN_TIMEOUT),
+		self.health,
+	), nil
+}
+
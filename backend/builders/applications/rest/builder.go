@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/muji40k/ozontestcomms/builders/errors"
+	"github.com/muji40k/ozontestcomms/internal/application/httpserver"
+	"github.com/muji40k/ozontestcomms/internal/seed"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/health"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/post"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/user"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/rest"
+)
+
+type ServerBuilder struct {
+	host     *nullable.Nullable[string]
+	port     *nullable.Nullable[string]
+	shutdown *nullable.Nullable[time.Duration]
+	user     user.Service
+	comment  comment.Service
+	post     post.Service
+	health   health.Service
+	token    string
+	export   seed.Repositories
+}
+
+func NewServerBuilder() *ServerBuilder {
+	return &ServerBuilder{
+		host:     nullable.None[string](),
+		port:     nullable.None[string](),
+		shutdown: nullable.None[time.Duration](),
+		user:     nil,
+		comment:  nil,
+		post:     nil,
+		health:   nil,
+		token:    "",
+		export:   seed.Repositories{},
+	}
+}
+
+func (self *ServerBuilder) WithHost(value string) *ServerBuilder {
+	self.host = nullable.Some(value)
+	return self
+}
+
+func (self *ServerBuilder) WithPort(value string) *ServerBuilder {
+	self.port = nullable.Some(value)
+	return self
+}
+
+// Optional, httpserver.DEFAULT_SHUTDOWN_TIMEOUT is used otherwise
+func (self *ServerBuilder) WithShutdownTimeout(value time.Duration) *ServerBuilder {
+	self.shutdown = nullable.Some(value)
+	return self
+}
+
+func (self *ServerBuilder) WithUserService(value user.Service) *ServerBuilder {
+	self.user = value
+	return self
+}
+
+func (self *ServerBuilder) WithCommentService(value comment.Service) *ServerBuilder {
+	self.comment = value
+	return self
+}
+
+func (self *ServerBuilder) WithPostService(value post.Service) *ServerBuilder {
+	self.post = value
+	return self
+}
+
+// Optional, the export endpoint is served only with a token
+func (self *ServerBuilder) WithExportToken(value string) *ServerBuilder {
+	self.token = value
+	return self
+}
+
+// Required along with the export token
+func (self *ServerBuilder) WithExportRepositories(value seed.Repositories) *ServerBuilder {
+	self.export = value
+	return self
+}
+
+// Optional, without it readiness only reflects the server state
+func (self *ServerBuilder) WithHealthService(value health.Service) *ServerBuilder {
+	self.health = value
+	return self
+}
+
+func (self *ServerBuilder) Build() (*rest.Server, error) {
+	if nullable.IsNone(self.host) || nullable.IsNone(self.port) ||
+		nil == self.user || nil == self.comment || nil == self.post ||
+		("" != self.token && (nil == self.export.User ||
+			nil == self.export.Post || nil == self.export.Comment)) {
+		return nil, errors.NotReady("rest.Server")
+	}
+
+	return rest.New(
+		nullable.Unwrap(self.host),
+		nullable.Unwrap(self.port),
+		nullable.GetOr(self.shutdown, httpserver.DEFAULT_SHUTDOWN_TIMEOUT),
+		rest.Context{
+			User:        self.user,
+			Comment:     self.comment,
+			Post:        self.post,
+			Health:      self.health,
+			ExportToken: self.token,
+			Export:      self.export,
+		},
+	), nil
+}
+
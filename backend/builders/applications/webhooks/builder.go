@@ -0,0 +1,96 @@
+package webhooks
+
+import (
+	"time"
+
+	"github.com/muji40k/ozontestcomms/builders/errors"
+	"github.com/muji40k/ozontestcomms/internal/application/webhooks"
+	webhookrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/webhook"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+)
+
+type SenderBuilder struct {
+	repo           webhookrepo.Repository
+	interval       *nullable.Nullable[time.Duration]
+	timeout        *nullable.Nullable[time.Duration]
+	maxAttempts    *nullable.Nullable[int]
+	initialBackoff *nullable.Nullable[time.Duration]
+	maxBackoff     *nullable.Nullable[time.Duration]
+	batch          *nullable.Nullable[int32]
+	allowInternal  bool
+}
+
+func NewSenderBuilder() *SenderBuilder {
+	return &SenderBuilder{
+		repo:           nil,
+		interval:       nullable.None[time.Duration](),
+		timeout:        nullable.None[time.Duration](),
+		maxAttempts:    nullable.None[int](),
+		initialBackoff: nullable.None[time.Duration](),
+		maxBackoff:     nullable.None[time.Duration](),
+		batch:          nullable.None[int32](),
+		allowInternal:  false,
+	}
+}
+
+func (self *SenderBuilder) WithWebhookRepository(value webhookrepo.Repository) *SenderBuilder {
+	self.repo = value
+	return self
+}
+
+// Optional, webhooks.DEFAULT_OPTIONS are used otherwise for this and the
+// following values
+func (self *SenderBuilder) WithInterval(value time.Duration) *SenderBuilder {
+	self.interval = nullable.Some(value)
+	return self
+}
+
+func (self *SenderBuilder) WithTimeout(value time.Duration) *SenderBuilder {
+	self.timeout = nullable.Some(value)
+	return self
+}
+
+func (self *SenderBuilder) WithMaxAttempts(value int) *SenderBuilder {
+	self.maxAttempts = nullable.Some(value)
+	return self
+}
+
+func (self *SenderBuilder) WithInitialBackoff(value time.Duration) *SenderBuilder {
+	self.initialBackoff = nullable.Some(value)
+	return self
+}
+
+func (self *SenderBuilder) WithMaxBackoff(value time.Duration) *SenderBuilder {
+	self.maxBackoff = nullable.Some(value)
+	return self
+}
+
+func (self *SenderBuilder) WithBatchSize(value int32) *SenderBuilder {
+	self.batch = nullable.Some(value)
+	return self
+}
+
+// Optional, receivers on internal addresses are refused otherwise
+func (self *SenderBuilder) WithAllowInternal(value bool) *SenderBuilder {
+	self.allowInternal = value
+	return self
+}
+
+func (self *SenderBuilder) Build() (*webhooks.Sender, error) {
+	if nil == self.repo {
+		return nil, errors.NotReady("webhooks.Sender")
+	}
+
+	defaults := webhooks.DEFAULT_OPTIONS
+
+	return webhooks.New(self.repo, webhooks.Options{
+		Interval:       nullable.GetOr(self.interval, defaults.Interval),
+		Timeout:        nullable.GetOr(self.timeout, defaults.Timeout),
+		MaxAttempts:    nullable.GetOr(self.maxAttempts, defaults.MaxAttempts),
+		InitialBackoff: nullable.GetOr(self.initialBackoff, defaults.InitialBackoff),
+		MaxBackoff:     nullable.GetOr(self.maxBackoff, defaults.MaxBackoff),
+		BatchSize:      nullable.GetOr(self.batch, defaults.BatchSize),
+		AllowInternal:  self.allowInternal,
+	}), nil
+}
+
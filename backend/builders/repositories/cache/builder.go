@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/muji40k/ozontestcomms/builders/errors"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/cache"
+	commrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	usrrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/user"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+)
+
+const DEFAULT_SIZE int = 10000
+const DEFAULT_TTL time.Duration = time.Minute
+
+type RepositoryBuilder struct {
+	post    *nullable.Nullable[postrepo.Repository]
+	user    *nullable.Nullable[usrrepo.Repository]
+	comment *nullable.Nullable[commrepo.Repository]
+	size    *nullable.Nullable[int]
+	ttl     *nullable.Nullable[time.Duration]
+}
+
+func NewRepositoryBuilder() *RepositoryBuilder {
+	return &RepositoryBuilder{
+		post:    nullable.None[postrepo.Repository](),
+		user:    nullable.None[usrrepo.Repository](),
+		comment: nullable.None[commrepo.Repository](),
+		size:    nullable.None[int](),
+		ttl:     nullable.None[time.Duration](),
+	}
+}
+
+func (self *RepositoryBuilder) WithPostRepository(repo postrepo.Repository) *RepositoryBuilder {
+	self.post = nullable.Some(repo)
+	return self
+}
+
+func (self *RepositoryBuilder) WithUserRepository(repo usrrepo.Repository) *RepositoryBuilder {
+	self.user = nullable.Some(repo)
+	return self
+}
+
+func (self *RepositoryBuilder) WithCommentRepository(repo commrepo.Repository) *RepositoryBuilder {
+	self.comment = nullable.Some(repo)
+	return self
+}
+
+// Optional, entries kept per entity, DEFAULT_SIZE is used otherwise
+func (self *RepositoryBuilder) WithSize(value int) *RepositoryBuilder {
+	self.size = nullable.Some(value)
+	return self
+}
+
+// Optional, DEFAULT_TTL is used otherwise
+func (self *RepositoryBuilder) WithTTL(value time.Duration) *RepositoryBuilder {
+	self.ttl = nullable.Some(value)
+	return self
+}
+
+func (self *RepositoryBuilder) Build() (*cache.Repository, error) {
+	size := nullable.GetOr(self.size, DEFAULT_SIZE)
+	ttl := nullable.GetOr(self.ttl, DEFAULT_TTL)
+
+	if nullable.IsNone(self.post) || nullable.IsNone(self.user) ||
+		nullable.IsNone(self.comment) || 0 >= size || 0 >= ttl {
+		return nil, errors.NotReady("cache.Repository")
+	}
+
+	return cache.New(
+		nullable.Unwrap(self.post),
+		nullable.Unwrap(self.user),
+		nullable.Unwrap(self.comment),
+		size,
+		ttl,
+	), nil
+}
+
@@ -0,0 +1,95 @@
+package inmemory
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/muji40k/ozontestcomms/builders/errors"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+)
+
+type RepositoryBuilder struct {
+	init     func(func(models.User), func(inmemory.Comment), func(models.Post))
+	snapshot *nullable.Nullable[string]
+	format   *nullable.Nullable[inmemory.Format]
+	log      *nullable.Nullable[string]
+	interval *nullable.Nullable[time.Duration]
+}
+
+func NewRepositoryBuilder() *RepositoryBuilder {
+	return &RepositoryBuilder{
+		init:     nil,
+		snapshot: nullable.None[string](),
+		format:   nullable.None[inmemory.Format](),
+		log:      nullable.None[string](),
+		interval: nullable.None[time.Duration](),
+	}
+}
+
+// Optional, initial content, ignored when restored from a snapshot
+func (self *RepositoryBuilder) WithInit(
+	value func(func(models.User), func(inmemory.Comment), func(models.Post)),
+) *RepositoryBuilder {
+	self.init = value
+	return self
+}
+
+// Optional, enables persistence to the file
+func (self *RepositoryBuilder) WithSnapshot(path string) *RepositoryBuilder {
+	self.snapshot = nullable.Some(path)
+	return self
+}
+
+// Optional, inmemory.FORMAT_JSON is used otherwise
+func (self *RepositoryBuilder) WithFormat(value inmemory.Format) *RepositoryBuilder {
+	self.format = nullable.Some(value)
+	return self
+}
+
+// Optional, requires snapshot
+func (self *RepositoryBuilder) WithLog(path string) *RepositoryBuilder {
+	self.log = nullable.Some(path)
+	return self
+}
+
+// Optional, requires snapshot, snapshot is only taken on clear otherwise
+func (self *RepositoryBuilder) WithSnapshotInterval(value time.Duration) *RepositoryBuilder {
+	self.interval = nullable.Some(value)
+	return self
+}
+
+func (self *RepositoryBuilder) Build() (*inmemory.Repository, func(), error) {
+	if nullable.IsNone(self.snapshot) {
+		if nullable.IsSome(self.log) || nullable.IsSome(self.interval) {
+			return nil, nil, errors.NotReady("inmemory.Repository")
+		}
+
+		return inmemory.New(self.init), func() {}, nil
+	}
+
+	format := nullable.GetOr(self.format, inmemory.FORMAT_JSON)
+
+	if !slices.Contains(inmemory.FORMATS, format) {
+		return nil, nil, fmt.Errorf("Unknown snapshot format %q", format)
+	}
+
+	repo, persister, err := inmemory.Open(
+		inmemory.PersistenceOptions{
+			Snapshot: nullable.Unwrap(self.snapshot),
+			Format:   format,
+			Log:      nullable.GetOr(self.log, ""),
+			Interval: nullable.GetOr(self.interval, 0),
+		},
+		self.init,
+	)
+
+	if nil != err {
+		return nil, nil, err
+	}
+
+	return repo, persister.Clear, nil
+}
+
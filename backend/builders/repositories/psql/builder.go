@@ -2,6 +2,11 @@ package psql
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/muji40k/ozontestcomms/builders/errors"
@@ -11,21 +16,50 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+var SSL_MODES = []string{
+	"disable", "allow", "prefer", "require", "verify-ca", "verify-full",
+}
+
 type RepositoryBuilder struct {
 	host     *nullable.Nullable[string]
 	port     *nullable.Nullable[string]
 	dbname   *nullable.Nullable[string]
 	user     *nullable.Nullable[string]
 	password *nullable.Nullable[string]
+	dsn      *nullable.Nullable[string]
+
+	sslmode          *nullable.Nullable[string]
+	statementTimeout *nullable.Nullable[time.Duration]
+	applicationName  *nullable.Nullable[string]
+
+	maxOpenConns    *nullable.Nullable[int]
+	maxIdleConns    *nullable.Nullable[int]
+	connMaxLifetime *nullable.Nullable[time.Duration]
+	connMaxIdleTime *nullable.Nullable[time.Duration]
+
+	replicaHost *nullable.Nullable[string]
+	replicaPort *nullable.Nullable[string]
+	replicaDSN  *nullable.Nullable[string]
 }
 
 func NewRepositoryBuilder() *RepositoryBuilder {
 	return &RepositoryBuilder{
-		host:     nullable.None[string](),
-		port:     nullable.None[string](),
-		dbname:   nullable.None[string](),
-		user:     nullable.None[string](),
-		password: nullable.None[string](),
+		host:             nullable.None[string](),
+		port:             nullable.None[string](),
+		dbname:           nullable.None[string](),
+		user:             nullable.None[string](),
+		password:         nullable.None[string](),
+		dsn:              nullable.None[string](),
+		sslmode:          nullable.None[string](),
+		statementTimeout: nullable.None[time.Duration](),
+		applicationName:  nullable.None[string](),
+		maxOpenConns:     nullable.None[int](),
+		maxIdleConns:     nullable.None[int](),
+		connMaxLifetime:  nullable.None[time.Duration](),
+		connMaxIdleTime:  nullable.None[time.Duration](),
+		replicaHost:      nullable.None[string](),
+		replicaPort:      nullable.None[string](),
+		replicaDSN:       nullable.None[string](),
 	}
 }
 
@@ -54,36 +88,182 @@ func (self *RepositoryBuilder) WithPassword(value string) *RepositoryBuilder {
 	return self
 }
 
-func (self *RepositoryBuilder) getConnString() (string, error) {
-	if nullable.IsNone(self.host) || nullable.IsNone(self.port) ||
-		nullable.IsNone(self.dbname) || nullable.IsNone(self.user) ||
+// Connection string used as is instead of the one built from host, port,
+// dbname, user, password and connection options. Pool options still apply
+func (self *RepositoryBuilder) WithDSN(value string) *RepositoryBuilder {
+	self.dsn = nullable.Some(value)
+	return self
+}
+
+// Optional, one of SSL_MODES, driver default (prefer) is used otherwise
+func (self *RepositoryBuilder) WithSSLMode(value string) *RepositoryBuilder {
+	self.sslmode = nullable.Some(value)
+	return self
+}
+
+// Optional, server side limit for every statement, no limit otherwise
+func (self *RepositoryBuilder) WithStatementTimeout(value time.Duration) *RepositoryBuilder {
+	self.statementTimeout = nullable.Some(value)
+	return self
+}
+
+// Optional, reported to the server (e.g. in pg_stat_activity)
+func (self *RepositoryBuilder) WithApplicationName(value string) *RepositoryBuilder {
+	self.applicationName = nullable.Some(value)
+	return self
+}
+
+// Optional, unlimited otherwise
+func (self *RepositoryBuilder) WithMaxOpenConns(value int) *RepositoryBuilder {
+	self.maxOpenConns = nullable.Some(value)
+	return self
+}
+
+// Optional, database/sql default is used otherwise
+func (self *RepositoryBuilder) WithMaxIdleConns(value int) *RepositoryBuilder {
+	self.maxIdleConns = nullable.Some(value)
+	return self
+}
+
+// Optional, connections are reused forever otherwise
+func (self *RepositoryBuilder) WithConnMaxLifetime(value time.Duration) *RepositoryBuilder {
+	self.connMaxLifetime = nullable.Some(value)
+	return self
+}
+
+// Optional, idle connections are kept forever otherwise
+func (self *RepositoryBuilder) WithConnMaxIdleTime(value time.Duration) *RepositoryBuilder {
+	self.connMaxIdleTime = nullable.Some(value)
+	return self
+}
+
+// Optional, read replica sharing credentials and options of the primary.
+// Listings are served from a separate pool connected to it
+func (self *RepositoryBuilder) WithReplicaHost(value string) *RepositoryBuilder {
+	self.replicaHost = nullable.Some(value)
+	return self
+}
+
+// Optional, port of the primary is used otherwise
+func (self *RepositoryBuilder) WithReplicaPort(value string) *RepositoryBuilder {
+	self.replicaPort = nullable.Some(value)
+	return self
+}
+
+// Optional, full connection string of the read replica
+func (self *RepositoryBuilder) WithReplicaDSN(value string) *RepositoryBuilder {
+	self.replicaDSN = nullable.Some(value)
+	return self
+}
+
+func (self *RepositoryBuilder) buildConnString(host string, port string) (string, error) {
+	if nullable.IsNone(self.dbname) || nullable.IsNone(self.user) ||
 		nullable.IsNone(self.password) {
 		return "", errors.NotReady("psql.Repository")
+	}
+
+	query := url.Values{}
+
+	nullable.IfSome(self.sslmode, func(v *string) {
+		query.Set("sslmode", *v)
+	})
+	nullable.IfSome(self.applicationName, func(v *string) {
+		query.Set("application_name", *v)
+	})
+	nullable.IfSome(self.statementTimeout, func(v *time.Duration) {
+		query.Set("statement_timeout", strconv.FormatInt(v.Milliseconds(), 10))
+	})
+
+	return (&url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(nullable.Unwrap(self.user), nullable.Unwrap(self.password)),
+		Host:     net.JoinHostPort(host, port),
+		Path:     "/" + nullable.Unwrap(self.dbname),
+		RawQuery: query.Encode(),
+	}).String(), nil
+}
+
+func (self *RepositoryBuilder) getConnString() (string, error) {
+	if nullable.IsSome(self.dsn) {
+		return nullable.Unwrap(self.dsn), nil
+	} else if nullable.IsNone(self.host) || nullable.IsNone(self.port) {
+		return "", errors.NotReady("psql.Repository")
 	} else {
-		return fmt.Sprintf(
-			"postgres://%v:%v@%v:%v/%v",
-			nullable.Unwrap(self.user),
-			nullable.Unwrap(self.password),
-			nullable.Unwrap(self.host),
-			nullable.Unwrap(self.port),
-			nullable.Unwrap(self.dbname),
-		), nil
+		return self.buildConnString(nullable.Unwrap(self.host), nullable.Unwrap(self.port))
 	}
+}
 
+// Empty string if no replica is configured
+func (self *RepositoryBuilder) getReplicaConnString() (string, error) {
+	if nullable.IsSome(self.replicaDSN) {
+		return nullable.Unwrap(self.replicaDSN), nil
+	} else if nullable.IsNone(self.replicaHost) {
+		return "", nil
+	} else if port := nullable.GetOr(self.replicaPort, nullable.GetOr(self.port, "")); "" == port {
+		return "", errors.NotReady("psql.Repository")
+	} else {
+		return self.buildConnString(nullable.Unwrap(self.replicaHost), port)
+	}
+}
+
+func (self *RepositoryBuilder) validate() error {
+	var err error
+
+	nullable.IfSome(self.sslmode, func(v *string) {
+		if !slices.Contains(SSL_MODES, *v) {
+			err = fmt.Errorf("Unknown ssl mode %q", *v)
+		}
+	})
+
+	return err
+}
+
+func (self *RepositoryBuilder) connect(cstr string) (*sqlx.DB, error) {
+	db, err := sqlx.Connect("pgx", cstr)
+
+	if nil == err {
+		nullable.IfSome(self.maxOpenConns, func(v *int) { db.SetMaxOpenConns(*v) })
+		nullable.IfSome(self.maxIdleConns, func(v *int) { db.SetMaxIdleConns(*v) })
+		nullable.IfSome(self.connMaxLifetime, func(v *time.Duration) { db.SetConnMaxLifetime(*v) })
+		nullable.IfSome(self.connMaxIdleTime, func(v *time.Duration) { db.SetConnMaxIdleTime(*v) })
+	}
+
+	return db, err
 }
 
 func (self *RepositoryBuilder) Build() (*psql.Repository, func(), error) {
-	var db *sqlx.DB
-	cstr, err := self.getConnString()
+	var db, replica *sqlx.DB
+	var cstr, rstr string
+	err := self.validate()
 
 	if nil == err {
-		db, err = sqlx.Connect("pgx", cstr)
+		cstr, err = self.getConnString()
 	}
 
 	if nil == err {
+		rstr, err = self.getReplicaConnString()
+	}
+
+	if nil == err {
+		db, err = self.connect(cstr)
+	}
+
+	if nil == err && "" != rstr {
+		if replica, err = self.connect(rstr); nil != err {
+			db.Close()
+			err = fmt.Errorf("replica: %w", err)
+		}
+	}
+
+	if nil != err {
+		return nil, nil, err
+	} else if nil == replica {
 		return psql.NewRepository(db), func() { db.Close() }, nil
 	} else {
-		return nil, nil, err
+		return psql.NewRepositoryWithReplica(db, replica), func() {
+			replica.Close()
+			db.Close()
+		}, nil
 	}
 }
 
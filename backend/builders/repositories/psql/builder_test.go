@@ -0,0 +1,117 @@
+package psql
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func base() *RepositoryBuilder {
+	return NewRepositoryBuilder().
+		WithHost("db.local").
+		WithPort("5432").
+		WithDbname("poster").
+		WithUser("poster").
+		WithPassword("p@ss:w/rd?#")
+}
+
+func TestConnStringEscapesPassword(t *testing.T) {
+	// Arrange
+	builder := base()
+
+	// Act
+	cstr, err := builder.getConnString()
+
+	// Assert
+	require.NoError(t, err)
+	parsed, err := url.Parse(cstr)
+	require.NoError(t, err)
+	password, _ := parsed.User.Password()
+	assert.Equal(t, "p@ss:w/rd?#", password)
+	assert.Equal(t, "db.local:5432", parsed.Host)
+	assert.Equal(t, "/poster", parsed.Path)
+	assert.Empty(t, parsed.RawQuery)
+}
+
+func TestConnStringOptions(t *testing.T) {
+	// Arrange
+	builder := base().
+		WithSSLMode("verify-full").
+		WithApplicationName("poster backend").
+		WithStatementTimeout(1500 * time.Millisecond)
+
+	// Act
+	cstr, err := builder.getConnString()
+
+	// Assert
+	require.NoError(t, err)
+	parsed, err := url.Parse(cstr)
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"sslmode":           {"verify-full"},
+		"application_name":  {"poster backend"},
+		"statement_timeout": {"1500"},
+	}, parsed.Query())
+	cfg, err := pgx.ParseConfig(cstr)
+	require.NoError(t, err)
+	assert.Equal(t, "1500", cfg.RuntimeParams["statement_timeout"])
+	assert.Equal(t, "poster backend", cfg.RuntimeParams["application_name"])
+}
+
+func TestConnStringOverride(t *testing.T) {
+	// Arrange
+	dsn := "postgres://other@elsewhere/db?sslmode=disable"
+	builder := NewRepositoryBuilder().WithDSN(dsn).WithSSLMode("require")
+
+	// Act
+	cstr, err := builder.getConnString()
+	rstr, rerr := builder.getReplicaConnString()
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, rerr)
+	assert.Equal(t, dsn, cstr)
+	assert.Empty(t, rstr, "No replica configured")
+}
+
+func TestReplicaConnString(t *testing.T) {
+	// Arrange
+	builder := base().WithReplicaHost("replica.local").WithSSLMode("require")
+
+	// Act
+	rstr, err := builder.getReplicaConnString()
+
+	// Assert
+	require.NoError(t, err)
+	parsed, err := url.Parse(rstr)
+	require.NoError(t, err)
+	assert.Equal(t, "replica.local:5432", parsed.Host)
+	assert.Equal(t, "require", parsed.Query().Get("sslmode"))
+}
+
+func TestUnknownSSLMode(t *testing.T) {
+	// Arrange
+	builder := base().WithSSLMode("always")
+
+	// Act
+	_, _, err := builder.Build()
+
+	// Assert
+	assert.ErrorContains(t, err, "always")
+}
+
+func TestNotReady(t *testing.T) {
+	// Arrange
+	builder := NewRepositoryBuilder().WithHost("db.local").WithPort("5432")
+
+	// Act
+	_, _, err := builder.Build()
+
+	// Assert
+	assert.Error(t, err)
+}
+
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/muji40k/ozontestcomms/builders/errors"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/sqlite"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+
+	_ "modernc.org/sqlite"
+)
+
+const DEFAULT_BUSY_TIMEOUT = 5 * time.Second
+
+type RepositoryBuilder struct {
+	path        *nullable.Nullable[string]
+	busyTimeout *nullable.Nullable[time.Duration]
+	seed        bool
+}
+
+func NewRepositoryBuilder() *RepositoryBuilder {
+	return &RepositoryBuilder{
+		path:        nullable.None[string](),
+		busyTimeout: nullable.None[time.Duration](),
+		seed:        false,
+	}
+}
+
+// Database file, created if missing
+func (self *RepositoryBuilder) WithPath(value string) *RepositoryBuilder {
+	self.path = nullable.Some(value)
+	return self
+}
+
+// Optional, DEFAULT_BUSY_TIMEOUT is used otherwise
+func (self *RepositoryBuilder) WithBusyTimeout(value time.Duration) *RepositoryBuilder {
+	self.busyTimeout = nullable.Some(value)
+	return self
+}
+
+// Optional, fills the initial content if it's missing
+func (self *RepositoryBuilder) WithSeed() *RepositoryBuilder {
+	self.seed = true
+	return self
+}
+
+func (self *RepositoryBuilder) getConnString() string {
+	path := nullable.Unwrap(self.path)
+	timeout := nullable.GetOr(self.busyTimeout, DEFAULT_BUSY_TIMEOUT)
+	query := url.Values{}
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "busy_timeout("+strconv.FormatInt(timeout.Milliseconds(), 10)+")")
+	// Writers take the lock at the start of transaction, so that it isn't
+	// upgraded midway, which fails without waiting
+	query.Set("_txlock", "immediate")
+	query.Add("_pragma", "journal_mode(WAL)")
+
+	return "file:" + path + "?" + query.Encode()
+}
+
+func (self *RepositoryBuilder) Build() (*sqlite.Repository, func(), error) {
+	if nullable.IsNone(self.path) || "" == nullable.Unwrap(self.path) {
+		return nil, nil, errors.NotReady("sqlite.Repository")
+	}
+
+	db, err := sqlx.Connect("sqlite", self.getConnString())
+
+	if nil == err {
+		err = sqlite.Migrate(context.Background(), db)
+	}
+
+	if nil == err && self.seed {
+		err = sqlite.Seed(context.Background(), db)
+	}
+
+	if nil != err {
+		if nil != db {
+			db.Close()
+		}
+
+		return nil, nil, err
+	}
+
+	return sqlite.NewRepository(db), func() { db.Close() }, nil
+}
+
@@ -3,19 +3,26 @@ package domain
 import (
 	"github.com/muji40k/ozontestcomms/builders/errors"
 	"github.com/muji40k/ozontestcomms/internal/domain/logic"
+	"github.com/muji40k/ozontestcomms/internal/domain/moderation"
 	commrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	moderationrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/moderation"
 	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
 	usrrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/user"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
 )
 
 type LogicBuilder struct {
 	comment commrepo.Repository
 	post    postrepo.Repository
 	user    usrrepo.Repository
+	limits  *nullable.Nullable[logic.Limits]
+	// Optional, both or none
+	pipeline *moderation.Pipeline
+	reviews  moderationrepo.Repository
 }
 
 func NewLogicBuilder() *LogicBuilder {
-	return &LogicBuilder{nil, nil, nil}
+	return &LogicBuilder{nil, nil, nil, nullable.None[logic.Limits](), nil, nil}
 }
 
 func (self *LogicBuilder) WithCommentRepository(repo commrepo.Repository) *LogicBuilder {
@@ -33,15 +40,44 @@ func (self *LogicBuilder) WithUserRepository(repo usrrepo.Repository) *LogicBuil
 	return self
 }
 
+// Optional, logic.DEFAULT_LIMITS are used otherwise
+func (self *LogicBuilder) WithLimits(value logic.Limits) *LogicBuilder {
+	self.limits = nullable.Some(value)
+	return self
+}
+
+// Optional, content isn't moderated otherwise
+func (self *LogicBuilder) WithModeration(
+	pipeline *moderation.Pipeline,
+	reviews moderationrepo.Repository,
+) *LogicBuilder {
+	self.pipeline = pipeline
+	self.reviews = reviews
+	return self
+}
+
 func (self *LogicBuilder) Build() (*logic.Logic, error) {
-	if nil == self.comment || nil == self.post || nil == self.user {
+	if nil == self.comment || nil == self.post || nil == self.user ||
+		(nil == self.pipeline) != (nil == self.reviews) {
 		return nil, errors.NotReady("logic.Logic")
 	}
 
-	return logic.New(logic.Context{
-		Comment: self.comment,
-		Post:    self.post,
-		User:    self.user,
-	}), nil
+	out := logic.NewWithLimits(
+		logic.Context{
+			Comment: self.comment,
+			Post:    self.post,
+			User:    self.user,
+		},
+		nullable.GetOr(self.limits, logic.DEFAULT_LIMITS),
+	)
+
+	if nil != self.pipeline {
+		out.WithModeration(logic.Moderation{
+			Pipeline: self.pipeline,
+			Reviews:  self.reviews,
+		})
+	}
+
+	return out, nil
 }
 
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"github.com/muji40k/ozontestcomms/builders/errors"
+	"github.com/muji40k/ozontestcomms/internal/domain/follows"
+	followrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/follow"
+)
+
+type FollowLogicBuilder struct {
+	follow followrepo.Repository
+}
+
+func NewFollowLogicBuilder() *FollowLogicBuilder {
+	return &FollowLogicBuilder{nil}
+}
+
+func (self *FollowLogicBuilder) WithFollowRepository(repo followrepo.Repository) *FollowLogicBuilder {
+	self.follow = repo
+	return self
+}
+
+func (self *FollowLogicBuilder) Build() (*follows.Logic, error) {
+	if nil == self.follow {
+		return nil, errors.NotReady("follows.Logic")
+	}
+
+	return follows.New(follows.Context{Follows: self.follow}), nil
+}
+
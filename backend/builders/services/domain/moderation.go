@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/builders/errors"
+	"github.com/muji40k/ozontestcomms/internal/domain/moderation"
+	moderationrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/moderation"
+)
+
+type ModerationLogicBuilder struct {
+	review     moderationrepo.Repository
+	publisher  moderation.Publisher
+	moderators []uuid.UUID
+}
+
+func NewModerationLogicBuilder() *ModerationLogicBuilder {
+	return &ModerationLogicBuilder{nil, nil, nil}
+}
+
+func (self *ModerationLogicBuilder) WithReviewRepository(repo moderationrepo.Repository) *ModerationLogicBuilder {
+	self.review = repo
+	return self
+}
+
+// Approved content is published through it, usually logic.Logic
+func (self *ModerationLogicBuilder) WithPublisher(publisher moderation.Publisher) *ModerationLogicBuilder {
+	self.publisher = publisher
+	return self
+}
+
+// Optional, nobody can resolve reviews otherwise
+func (self *ModerationLogicBuilder) WithModerators(ids ...uuid.UUID) *ModerationLogicBuilder {
+	self.moderators = ids
+	return self
+}
+
+func (self *ModerationLogicBuilder) Build() (*moderation.Logic, error) {
+	if nil == self.review || nil == self.publisher {
+		return nil, errors.NotReady("moderation.Logic")
+	}
+
+	return moderation.New(
+		moderation.Context{
+			Reviews:   self.review,
+			Publisher: self.publisher,
+		},
+		self.moderators...,
+	), nil
+}
+
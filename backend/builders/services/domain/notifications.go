@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"github.com/muji40k/ozontestcomms/builders/errors"
+	"github.com/muji40k/ozontestcomms/internal/domain/notifications"
+	commrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	notifrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/notification"
+	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	usrrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/user"
+)
+
+type NotificationLogicBuilder struct {
+	notification notifrepo.Repository
+	comment      commrepo.Repository
+	post         postrepo.Repository
+	user         usrrepo.Repository
+}
+
+func NewNotificationLogicBuilder() *NotificationLogicBuilder {
+	return &NotificationLogicBuilder{nil, nil, nil, nil}
+}
+
+func (self *NotificationLogicBuilder) WithNotificationRepository(repo notifrepo.Repository) *NotificationLogicBuilder {
+	self.notification = repo
+	return self
+}
+
+func (self *NotificationLogicBuilder) WithCommentRepository(repo commrepo.Repository) *NotificationLogicBuilder {
+	self.comment = repo
+	return self
+}
+
+func (self *NotificationLogicBuilder) WithPostRepository(repo postrepo.Repository) *NotificationLogicBuilder {
+	self.post = repo
+	return self
+}
+
+func (self *NotificationLogicBuilder) WithUserRepository(repo usrrepo.Repository) *NotificationLogicBuilder {
+	self.user = repo
+	return self
+}
+
+func (self *NotificationLogicBuilder) Build() (*notifications.Logic, error) {
+	if nil == self.notification || nil == self.comment || nil == self.post || nil == self.user {
+		return nil, errors.NotReady("notifications.Logic")
+	}
+
+	return notifications.New(notifications.Context{
+		Notification: self.notification,
+		Comment:      self.comment,
+		Post:         self.post,
+		User:         self.user,
+	}), nil
+}
+
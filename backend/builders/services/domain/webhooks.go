@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"github.com/muji40k/ozontestcomms/builders/errors"
+	"github.com/muji40k/ozontestcomms/internal/domain/webhooks"
+	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	usrrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/user"
+	webhookrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/webhook"
+)
+
+type WebhookLogicBuilder struct {
+	webhook       webhookrepo.Repository
+	post          postrepo.Repository
+	user          usrrepo.Repository
+	allowInternal bool
+}
+
+func NewWebhookLogicBuilder() *WebhookLogicBuilder {
+	return &WebhookLogicBuilder{nil, nil, nil, false}
+}
+
+func (self *WebhookLogicBuilder) WithWebhookRepository(repo webhookrepo.Repository) *WebhookLogicBuilder {
+	self.webhook = repo
+	return self
+}
+
+func (self *WebhookLogicBuilder) WithPostRepository(repo postrepo.Repository) *WebhookLogicBuilder {
+	self.post = repo
+	return self
+}
+
+func (self *WebhookLogicBuilder) WithUserRepository(repo usrrepo.Repository) *WebhookLogicBuilder {
+	self.user = repo
+	return self
+}
+
+// Optional, urls of internal addresses are refused otherwise
+func (self *WebhookLogicBuilder) WithAllowInternal(value bool) *WebhookLogicBuilder {
+	self.allowInternal = value
+	return self
+}
+
+func (self *WebhookLogicBuilder) Build() (*webhooks.Logic, error) {
+	if nil == self.webhook || nil == self.post || nil == self.user {
+		return nil, errors.NotReady("webhooks.Logic")
+	}
+
+	return webhooks.New(webhooks.Context{
+		Webhook: self.webhook,
+		Post:    self.post,
+		User:    self.user,
+	}).WithInternalAddresses(self.allowInternal), nil
+}
+
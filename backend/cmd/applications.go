@@ -0,0 +1,146 @@
+package main
+
+import (
+	dispatcherbuilder "github.com/muji40k/ozontestcomms/builders/applications/dispatcher"
+	"github.com/muji40k/ozontestcomms/builders/applications/graphql"
+	grpcbuilder "github.com/muji40k/ozontestcomms/builders/applications/grpc"
+	metricsbuilder "github.com/muji40k/ozontestcomms/builders/applications/metrics"
+	"github.com/muji40k/ozontestcomms/builders/applications/rest"
+	webhooksbuilder "github.com/muji40k/ozontestcomms/builders/applications/webhooks"
+	"github.com/muji40k/ozontestcomms/config"
+	"github.com/muji40k/ozontestcomms/internal/application"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/webhooks"
+)
+
+func GraphqlAppConstructor(
+	cfg *config.Config,
+	scontext *ServiceContext,
+) (application.Application, error) {
+	appcfg := cfg.Application.GraphQL
+	builder := graphql.NewServerBuilder()
+
+	if "" != appcfg.PersistedQueries {
+		builder.WithPersistedQueries(appcfg.PersistedQueries)
+	}
+
+	return builder.
+		WithHost(appcfg.Host).
+		WithPort(appcfg.Port).
+		WithLoaderDuration(appcfg.LoaderDuration).
+		WithShutdownTimeout(appcfg.ShutdownTimeout).
+		WithWatchInterval(appcfg.WatchInterval).
+		WithCacheMaxAge(appcfg.CacheMaxAge).
+		WithCommentService(scontext.Comment).
+		WithPostService(scontext.Post).
+		WithUserService(scontext.User).
+		WithHealthService(scontext.Health).
+		WithWebhookService(scontext.Webhook).
+		WithNotificationService(scontext.Notification).
+		WithFollowService(scontext.Follow).
+		WithModerationService(scontext.Moderation).
+		Build()
+}
+
+func RestAppConstructor(
+	cfg *config.Config,
+	scontext *ServiceContext,
+) (application.Application, error) {
+	appcfg := cfg.Application.Rest
+
+	return rest.NewServerBuilder().
+		WithHost(appcfg.Host).
+		WithPort(appcfg.Port).
+		WithShutdownTimeout(appcfg.ShutdownTimeout).
+		WithExportToken(appcfg.ExportToken).
+		WithExportRepositories(scontext.Export).
+		WithCommentService(scontext.Comment).
+		WithPostService(scontext.Post).
+		WithUserService(scontext.User).
+		WithHealthService(scontext.Health).
+		Build()
+}
+
+func GrpcAppConstructor(
+	cfg *config.Config,
+	scontext *ServiceContext,
+) (application.Application, error) {
+	appcfg := cfg.Application.Grpc
+
+	return grpcbuilder.NewServerBuilder().
+		WithHost(appcfg.Host).
+		WithPort(appcfg.Port).
+		WithShutdownTimeout(appcfg.ShutdownTimeout).
+		WithWatchInterval(appcfg.WatchInterval).
+		WithCommentService(scontext.Comment).
+		WithPostService(scontext.Post).
+		WithUserService(scontext.User).
+		WithHealthService(scontext.Health).
+		Build()
+}
+
+func MetricsAppConstructor(
+	cfg *config.Config,
+	scontext *ServiceContext,
+) (application.Application, error) {
+	appcfg := cfg.Application.Metrics
+
+	return metricsbuilder.NewServerBuilder().
+		WithHost(appcfg.Host).
+		WithPort(appcfg.Port).
+		WithShutdownTimeout(appcfg.ShutdownTimeout).
+		WithHealthService(scontext.Health).
+		Build()
+}
+
+func EventsAppConstructor(
+	cfg *config.Config,
+	scontext *ServiceContext,
+) (application.Application, error) {
+	appcfg := cfg.Application.Events
+
+	builder := dispatcherbuilder.NewDispatcherBuilder().
+		WithOutbox(scontext.Outbox).
+		WithInterval(appcfg.Interval).
+		WithBatchSize(int32(appcfg.BatchSize))
+
+	if nil != scontext.WebhookHandler {
+		for _, kind := range webhooks.EVENTS {
+			builder.WithHandler(kind, scontext.WebhookHandler)
+		}
+	}
+
+	if nil != scontext.NotificationHandler {
+		builder.WithHandler(events.COMMENT_CREATED, scontext.NotificationHandler)
+	}
+
+	return builder.Build()
+}
+
+func WebhooksAppConstructor(
+	cfg *config.Config,
+	scontext *ServiceContext,
+) (application.Application, error) {
+	appcfg := cfg.Application.Webhooks
+
+	return webhooksbuilder.NewSenderBuilder().
+		WithWebhookRepository(scontext.WebhookRepository).
+		WithInterval(appcfg.Interval).
+		WithTimeout(appcfg.Timeout).
+		WithMaxAttempts(appcfg.MaxAttempts).
+		WithInitialBackoff(appcfg.InitialBackoff).
+		WithMaxBackoff(appcfg.MaxBackoff).
+		WithBatchSize(int32(appcfg.BatchSize)).
+		WithAllowInternal(appcfg.AllowInternal).
+		Build()
+}
+
+var appConstructors = map[string]func(*config.Config, *ServiceContext) (application.Application, error){
+	"graphql":  GraphqlAppConstructor,
+	"rest":     RestAppConstructor,
+	"grpc":     GrpcAppConstructor,
+	"metrics":  MetricsAppConstructor,
+	"events":   EventsAppConstructor,
+	"webhooks": WebhooksAppConstructor,
+}
+
@@ -0,0 +1,28 @@
+package main
+
+type Clearable interface {
+	Clear()
+}
+
+type FCleaner func()
+
+func (self FCleaner) Clear() {
+	self()
+}
+
+type Cleaner []Clearable
+
+func NewCleaner() Cleaner {
+	return Cleaner(make([]Clearable, 0))
+}
+
+func (self *Cleaner) Push(v Clearable) {
+	*self = append(*self, v)
+}
+
+func (self *Cleaner) Clear() {
+	for i := len(*self) - 1; 0 <= i; i-- {
+		(*self)[i].Clear()
+	}
+}
+
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/muji40k/ozontestcomms/config"
+)
+
+// Utility run instead of the service when its name is the first argument,
+// e.g. `main seed -posts 100`. Configuration is loaded as for the service
+type command struct {
+	description string
+	run         func(args []string) error
+}
+
+var commands = map[string]command{
+	"export":          {"stream posts with their comment trees as ndjson or csv", exportCommand},
+	"extract-queries": {"collect GraphQL operations of client code as persisted queries", extractCommand},
+	"import":          {"restore output of export into the repository", importCommand},
+	"load":            {"run load test against the GraphQL API and report latencies", loadCommand},
+	"seed":            {"fill the repository with generated content or fixture", seedCommand},
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage of %v %v:\n", os.Args[0], name)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// Registers configuration flags after the command ones and parses all
+func loadConfig(fs *flag.FlagSet, args []string) (config.Config, error) {
+	path := fs.String(
+		"config",
+		os.Getenv(config.ENV_CONFIG_FILE),
+		"yaml or toml configuration file, $"+config.ENV_CONFIG_FILE+" by default",
+	)
+	overrides := config.RegisterFlags(fs)
+	fs.Parse(args)
+
+	cfg, err := config.Load(*path, os.Getenv, overrides)
+
+	if nil == err {
+		if err = cfg.Validate(choices()); nil != err {
+			err = fmt.Errorf("invalid configuration:\n%w", err)
+		}
+	}
+
+	return cfg, err
+}
+
+// Runs the command if it's requested, reports whether it was
+func runCommand(args []string) bool {
+	if 0 == len(args) {
+		return false
+	}
+
+	cmd, found := commands[args[0]]
+
+	if !found {
+		return false
+	}
+
+	if err := cmd.run(args[1:]); nil != err {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	return true
+}
+
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/export"
+	"github.com/muji40k/ozontestcomms/internal/seed"
+)
+
+func exportCommand(args []string) error {
+	fs := newFlagSet("export")
+	var filter export.Filter
+	format := fs.String("format", string(export.FORMAT_NDJSON), fmt.Sprintf("output format, one of: %v", export.FORMATS))
+	output := fs.String("output", "", "file to write, standard output by default")
+	from := fs.String("from", "", "export posts created at or after, RFC 3339 or date")
+	to := fs.String("to", "", "export posts created before, RFC 3339 or date")
+	fs.Func("author", "export posts of the author only, repeat for several", func(v string) error {
+		id, err := uuid.Parse(v)
+
+		if nil == err {
+			filter.Authors = append(filter.Authors, id)
+		}
+
+		return err
+	})
+
+	cleaner := NewCleaner()
+	defer cleaner.Clear()
+
+	var rcontext RepositoryContext
+	var stats seed.Stats
+	var parsed export.Format
+	var w io.Writer = os.Stdout
+	cfg, err := loadConfig(fs, args)
+
+	if nil == err {
+		parsed, err = export.ParseFormat(*format)
+	}
+
+	if nil == err {
+		filter.From, err = export.ParseTime(*from)
+	}
+
+	if nil == err {
+		filter.To, err = export.ParseTime(*to)
+	}
+
+	if nil == err {
+		var clr Clearable
+		rcontext, clr, err = repositoryConstructors[cfg.Repository.Type](&cfg)
+
+		if nil == err {
+			cleaner.Push(clr)
+		}
+	}
+
+	if nil == err && "" != *output {
+		var file *os.File
+
+		if file, err = os.Create(*output); nil == err {
+			defer file.Close()
+			w = file
+		}
+	}
+
+	if nil == err {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		buffered := bufio.NewWriter(w)
+		stats, err = export.Export(ctx, seed.Repositories{
+			User:    rcontext.User,
+			Post:    rcontext.Post,
+			Comment: rcontext.Comment,
+		}, buffered, parsed, filter)
+
+		if flushErr := buffered.Flush(); nil == err {
+			err = flushErr
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %v\n", stats)
+
+	return err
+}
+
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/muji40k/ozontestcomms/graphql/persisted"
+)
+
+func extractCommand(args []string) error {
+	fs := newFlagSet("extract-queries")
+	output := fs.String("output", "", "directory to write operations to, one "+persisted.EXTENSION+" file each")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage of %v extract-queries [flags] <source>...:\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Operations are reformatted and interpolations are stripped, so clients")
+		fmt.Fprintln(fs.Output(), "must send the printed hashes in the persistedQuery extension rather")
+		fmt.Fprintln(fs.Output(), "than hashes of their own query text")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if "" == *output || 0 == fs.NArg() {
+		fs.Usage()
+		return errors.New("output and at least one source must be set")
+	}
+
+	extractor := persisted.NewExtractor()
+	var operations []persisted.Operation
+	var err error
+
+	for i := 0; nil == err && fs.NArg() > i; i++ {
+		err = filepath.WalkDir(fs.Arg(i), func(path string, d os.DirEntry, err error) error {
+			if nil != err {
+				return err
+			}
+
+			if d.IsDir() {
+				if "node_modules" == d.Name() {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if !persisted.Supported(path) {
+				return nil
+			}
+
+			content, err := os.ReadFile(path)
+
+			if nil == err {
+				err = extractor.Add(path, string(content))
+			}
+
+			return err
+		})
+	}
+
+	if nil == err {
+		operations, err = extractor.Operations()
+	}
+
+	if nil == err {
+		err = os.MkdirAll(*output, 0o755)
+	}
+
+	for i := 0; nil == err && len(operations) > i; i++ {
+		op := operations[i]
+		path := filepath.Join(*output, op.Name+persisted.EXTENSION)
+
+		if err = os.WriteFile(path, []byte(op.Query), 0o644); nil == err {
+			// Manifest for clients sending hashes only
+			fmt.Printf("%v  %v\n", op.Hash, op.Name)
+		}
+	}
+
+	return err
+}
+
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/muji40k/ozontestcomms/internal/export"
+	"github.com/muji40k/ozontestcomms/internal/seed"
+)
+
+func importCommand(args []string) error {
+	fs := newFlagSet("import")
+	format := fs.String("format", string(export.FORMAT_NDJSON), fmt.Sprintf("input format, one of: %v", export.FORMATS))
+	input := fs.String("input", "", "file to read, standard input by default")
+
+	cleaner := NewCleaner()
+	defer cleaner.Clear()
+
+	var rcontext RepositoryContext
+	var stats seed.Stats
+	var parsed export.Format
+	var r io.Reader = os.Stdin
+	cfg, err := loadConfig(fs, args)
+
+	if nil == err {
+		parsed, err = export.ParseFormat(*format)
+	}
+
+	if nil == err {
+		var clr Clearable
+		rcontext, clr, err = repositoryConstructors[cfg.Repository.Type](&cfg)
+
+		if nil == err {
+			cleaner.Push(clr)
+		}
+	}
+
+	if nil == err && "" != *input {
+		var file *os.File
+
+		if file, err = os.Open(*input); nil == err {
+			defer file.Close()
+			r = file
+		}
+	}
+
+	if nil == err {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		stats, err = export.Import(ctx, seed.Repositories{
+			User:    rcontext.User,
+			Post:    rcontext.Post,
+			Comment: rcontext.Comment,
+		}, bufio.NewReader(r), parsed)
+	}
+
+	fmt.Fprintf(os.Stderr, "Imported %v\n", stats)
+
+	return err
+}
+
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/builders/applications/graphql"
+	"github.com/muji40k/ozontestcomms/config"
+	"github.com/muji40k/ozontestcomms/internal/loadtest"
+)
+
+// GraphQL server over a fresh in-memory repository with default limits,
+// runs are reproducible and don't depend on the environment
+func inMemoryServer() (*httptest.Server, error) {
+	var scontext ServiceContext
+	var handler http.Handler
+	cfg := config.Default()
+	cfg.Repository.Type = "in-memory"
+	rcontext, _, err := InMemoryRepositoryConstructor(&cfg)
+
+	if nil == err {
+		scontext, _, err = DomainServiceConstructor(&cfg, &rcontext)
+	}
+
+	if nil == err {
+		appcfg := cfg.Application.GraphQL
+		app, berr := graphql.NewServerBuilder().
+			WithHost("127.0.0.1").
+			WithPort("0").
+			WithLoaderDuration(appcfg.LoaderDuration).
+			WithShutdownTimeout(appcfg.ShutdownTimeout).
+			WithCommentService(scontext.Comment).
+			WithPostService(scontext.Post).
+			WithUserService(scontext.User).
+			WithHealthService(scontext.Health).
+			Build()
+
+		if err = berr; nil == err {
+			handler = app.Handler()
+		}
+	}
+
+	if nil != err {
+		return nil, err
+	}
+
+	return httptest.NewServer(handler), nil
+}
+
+func loadCommand(args []string) error {
+	fs := newFlagSet("load")
+	options := loadtest.DEFAULT_OPTIONS
+	options.User = uuid.MustParse("9c3d7dba-d1b2-42de-b708-158e32f11623")
+	url := fs.String("url", "http://127.0.0.1:80/query", "GraphQL endpoint under load")
+	inmemory := fs.Bool("inmemory", false, "run against in-process server over in-memory repository instead of -url")
+	fs.IntVar(&options.Concurrency, "concurrency", options.Concurrency, "number of parallel clients")
+	fs.DurationVar(&options.Duration, "duration", options.Duration, "run duration, 0 to limit by -requests only")
+	fs.IntVar(&options.Requests, "requests", options.Requests, "total number of requests, 0 to limit by -duration only")
+	fs.IntVar(&options.PageSize, "page", options.PageSize, "page size of queries")
+	fs.IntVar(&options.Prepare, "prepare", options.Prepare, "comments added to the thread before run")
+	fs.Uint64Var(&options.Seed, "seed", options.Seed, "random seed of operation choice")
+	fs.Func("mix", fmt.Sprintf(
+		"weights of operations, e.g. %v (default)", loadtest.DEFAULT_MIX,
+	), func(value string) error {
+		mix, err := loadtest.ParseMix(value)
+		options.Mix = mix
+
+		return err
+	})
+	fs.Func("user", "author of created content (default "+options.User.String()+")",
+		func(value string) error {
+			id, err := uuid.Parse(value)
+			options.User = id
+
+			return err
+		},
+	)
+	fs.Func("post", "thread under load, a new one is created by default",
+		func(value string) error {
+			id, err := uuid.Parse(value)
+			options.Post = id
+
+			return err
+		},
+	)
+	fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *inmemory {
+		server, err := inMemoryServer()
+
+		if nil != err {
+			return err
+		}
+
+		defer server.Close()
+		*url = server.URL + "/query"
+	}
+
+	report, err := loadtest.Run(ctx, http.DefaultClient, *url, options)
+
+	if nil == err {
+		err = report.Write(os.Stdout)
+	}
+
+	return err
+}
+
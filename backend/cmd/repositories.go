@@ -0,0 +1,193 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+	cachebuilder "github.com/muji40k/ozontestcomms/builders/repositories/cache"
+	inmemorybuilder "github.com/muji40k/ozontestcomms/builders/repositories/inmemory"
+	psqlbuilder "github.com/muji40k/ozontestcomms/builders/repositories/psql"
+	sqlitebuilder "github.com/muji40k/ozontestcomms/builders/repositories/sqlite"
+	"github.com/muji40k/ozontestcomms/config"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	commrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	followrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/follow"
+	moderationrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/moderation"
+	notifrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/notification"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/outbox"
+	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	usrrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/user"
+	webhookrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/webhook"
+)
+
+type RepositoryContext struct {
+	Comment commrepo.Repository
+	Post    postrepo.Repository
+	User    usrrepo.Repository
+	Outbox  outbox.Repository
+	Webhook webhookrepo.Repository
+
+	Notification notifrepo.Repository
+	Follow       followrepo.Repository
+	Moderation   moderationrepo.Repository
+}
+
+// Events are recorded only if there's someone to dispatch them, the outbox
+// grows unbounded otherwise
+func recordsEvents(cfg *config.Config) bool {
+	return slices.Contains(cfg.Application.Types, "events")
+}
+
+func InMemoryRepositoryConstructor(cfg *config.Config) (RepositoryContext, Clearable, error) {
+	memcfg := cfg.Repository.InMemory
+	builder := inmemorybuilder.NewRepositoryBuilder().WithInit(
+		func(adduser func(models.User), _ func(inmemory.Comment), _ func(models.Post)) {
+			adduser(models.User{
+				Id:       uuid.MustParse("9c3d7dba-d1b2-42de-b708-158e32f11623"),
+				Email:    "aboba@mail.com",
+				Password: "asdf",
+			})
+		},
+	)
+
+	if "" != memcfg.Snapshot {
+		builder.WithSnapshot(memcfg.Snapshot).
+			WithFormat(inmemory.Format(memcfg.Format))
+	}
+
+	if "" != memcfg.Log {
+		builder.WithLog(memcfg.Log)
+	}
+
+	if 0 != memcfg.SnapshotInterval {
+		builder.WithSnapshotInterval(memcfg.SnapshotInterval)
+	}
+
+	repo, clr, err := builder.Build()
+
+	if nil == err {
+		if recordsEvents(cfg) {
+			repo.WithOutbox()
+		}
+
+		return RepositoryContext{repo, repo, repo, repo, repo, repo, repo, repo}, FCleaner(clr), nil
+	} else {
+		return RepositoryContext{}, nil, err
+	}
+}
+
+func PSQLRepositoryConstructor(cfg *config.Config) (RepositoryContext, Clearable, error) {
+	psqlcfg := cfg.Repository.PSQL
+	builder := psqlbuilder.NewRepositoryBuilder().
+		WithHost(psqlcfg.Host).
+		WithPort(psqlcfg.Port).
+		WithDbname(psqlcfg.DBName).
+		WithUser(psqlcfg.User).
+		WithPassword(psqlcfg.Password)
+
+	if "" != psqlcfg.DSN {
+		builder.WithDSN(psqlcfg.DSN)
+	}
+
+	if "" != psqlcfg.SSLMode {
+		builder.WithSSLMode(psqlcfg.SSLMode)
+	}
+
+	if 0 != psqlcfg.StatementTimeout {
+		builder.WithStatementTimeout(psqlcfg.StatementTimeout)
+	}
+
+	if "" != psqlcfg.ApplicationName {
+		builder.WithApplicationName(psqlcfg.ApplicationName)
+	}
+
+	if 0 != psqlcfg.MaxOpenConns {
+		builder.WithMaxOpenConns(psqlcfg.MaxOpenConns)
+	}
+
+	if 0 != psqlcfg.MaxIdleConns {
+		builder.WithMaxIdleConns(psqlcfg.MaxIdleConns)
+	}
+
+	if 0 != psqlcfg.ConnMaxLifetime {
+		builder.WithConnMaxLifetime(psqlcfg.ConnMaxLifetime)
+	}
+
+	if 0 != psqlcfg.ConnMaxIdleTime {
+		builder.WithConnMaxIdleTime(psqlcfg.ConnMaxIdleTime)
+	}
+
+	if "" != psqlcfg.Replica.DSN {
+		builder.WithReplicaDSN(psqlcfg.Replica.DSN)
+	} else if "" != psqlcfg.Replica.Host {
+		builder.WithReplicaHost(psqlcfg.Replica.Host)
+
+		if "" != psqlcfg.Replica.Port {
+			builder.WithReplicaPort(psqlcfg.Replica.Port)
+		}
+	}
+
+	repo, clr, err := builder.Build()
+
+	if nil == err {
+		if recordsEvents(cfg) {
+			repo.WithOutbox()
+		}
+
+		return RepositoryContext{repo, repo, repo, repo, repo, repo, repo, repo}, FCleaner(clr), nil
+	} else {
+		return RepositoryContext{}, nil, err
+	}
+}
+
+func SQLiteRepositoryConstructor(cfg *config.Config) (RepositoryContext, Clearable, error) {
+	repo, clr, err := sqlitebuilder.NewRepositoryBuilder().
+		WithPath(cfg.Repository.SQLite.Path).
+		WithBusyTimeout(cfg.Repository.SQLite.BusyTimeout).
+		WithSeed().
+		Build()
+
+	if nil == err {
+		if recordsEvents(cfg) {
+			repo.WithOutbox()
+		}
+
+		return RepositoryContext{repo, repo, repo, repo, repo, repo, repo, repo}, FCleaner(clr), nil
+	} else {
+		return RepositoryContext{}, nil, err
+	}
+}
+
+// Only the serving applications read through the cache, so it's put in
+// front of the repositories separately
+func CachedRepositoryContext(cfg *config.Config, rcontext RepositoryContext) (RepositoryContext, error) {
+	cachecfg := cfg.Repository.Cache
+
+	if 0 == cachecfg.Size {
+		return rcontext, nil
+	}
+
+	repo, err := cachebuilder.NewRepositoryBuilder().
+		WithPostRepository(rcontext.Post).
+		WithUserRepository(rcontext.User).
+		WithCommentRepository(rcontext.Comment).
+		WithSize(cachecfg.Size).
+		WithTTL(cachecfg.TTL).
+		Build()
+
+	if nil == err {
+		rcontext.Comment = repo
+		rcontext.Post = repo
+		rcontext.User = repo
+	}
+
+	return rcontext, err
+}
+
+var repositoryConstructors = map[string]func(*config.Config) (RepositoryContext, Clearable, error){
+	"in-memory": InMemoryRepositoryConstructor,
+	"psql":      PSQLRepositoryConstructor,
+	"sqlite":    SQLiteRepositoryConstructor,
+}
+
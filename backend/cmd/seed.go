@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/muji40k/ozontestcomms/internal/seed"
+)
+
+func seedCommand(args []string) error {
+	fs := newFlagSet("seed")
+	options := seed.DEFAULT_OPTIONS
+	fs.IntVar(&options.Users, "users", options.Users, "number of users to create")
+	fs.IntVar(&options.Posts, "posts", options.Posts, "number of posts to create")
+	fs.IntVar(&options.Comments, "comments", options.Comments,
+		"maximum number of replies to a post, halved on every level")
+	fs.IntVar(&options.Depth, "depth", options.Depth, "maximum depth of comment trees")
+	fs.DurationVar(&options.Period, "period", options.Period, "posts are dated within the period before now")
+	fs.Uint64Var(&options.Seed, "seed", options.Seed, "random seed, same seed generates same posts and comments")
+	fixture := fs.String("fixture", "", "load fixture file instead of generating content")
+
+	cleaner := NewCleaner()
+	defer cleaner.Clear()
+
+	var rcontext RepositoryContext
+	var stats seed.Stats
+	cfg, err := loadConfig(fs, args)
+
+	if nil == err {
+		var clr Clearable
+		rcontext, clr, err = repositoryConstructors[cfg.Repository.Type](&cfg)
+
+		if nil == err {
+			cleaner.Push(clr)
+		}
+	}
+
+	repos := seed.Repositories{
+		User:    rcontext.User,
+		Post:    rcontext.Post,
+		Comment: rcontext.Comment,
+	}
+
+	if nil == err && "" != *fixture {
+		var content seed.Fixture
+
+		if content, err = seed.ReadFixtureFile(*fixture); nil == err {
+			_, stats, err = seed.Load(context.Background(), repos, &content)
+		}
+	} else if nil == err {
+		stats, err = seed.Generate(context.Background(), repos, options)
+	}
+
+	if nil == err {
+		fmt.Printf("Created %v\n", stats)
+	} else if (seed.Stats{}) != stats {
+		fmt.Fprintf(os.Stderr, "Created %v before failing\n", stats)
+	}
+
+	return err
+}
+
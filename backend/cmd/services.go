@@ -0,0 +1,125 @@
+package main
+
+import (
+	"github.com/muji40k/ozontestcomms/builders/services/domain"
+	"github.com/muji40k/ozontestcomms/config"
+	"github.com/muji40k/ozontestcomms/internal/application/dispatcher"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/outbox"
+	webhookrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/webhook"
+	"github.com/muji40k/ozontestcomms/internal/seed"
+	commsrv "github.com/muji40k/ozontestcomms/internal/service/interface/comment"
+	followsrv "github.com/muji40k/ozontestcomms/internal/service/interface/follow"
+	healthsrv "github.com/muji40k/ozontestcomms/internal/service/interface/health"
+	moderationsrv "github.com/muji40k/ozontestcomms/internal/service/interface/moderation"
+	notifsrv "github.com/muji40k/ozontestcomms/internal/service/interface/notification"
+	postsrv "github.com/muji40k/ozontestcomms/internal/service/interface/post"
+	usrsrv "github.com/muji40k/ozontestcomms/internal/service/interface/user"
+	webhooksrv "github.com/muji40k/ozontestcomms/internal/service/interface/webhook"
+)
+
+type ServiceContext struct {
+	Comment commsrv.Service
+	Post    postsrv.Service
+	User    usrsrv.Service
+	Health  healthsrv.Service
+	// Events written along with the changes made through the services
+	Outbox outbox.Repository
+	// Schedules webhook deliveries of the events
+	WebhookHandler dispatcher.Handler
+	Webhook        webhooksrv.Service
+	// Deliveries are sent straight from the repository
+	WebhookRepository webhookrepo.Repository
+	// Makes notifications of the comment events
+	NotificationHandler dispatcher.Handler
+	Notification        notifsrv.Service
+	Follow              followsrv.Service
+	Moderation          moderationsrv.Service
+	// Export bypasses the services to dump everything stored
+	Export seed.Repositories
+}
+
+func DomainServiceConstructor(
+	cfg *config.Config,
+	rcontext *RepositoryContext,
+) (ServiceContext, Clearable, error) {
+	builder := domain.NewLogicBuilder().
+		WithCommentRepository(rcontext.Comment).
+		WithPostRepository(rcontext.Post).
+		WithUserRepository(rcontext.User).
+		WithLimits(cfg.Limits.Logic())
+
+	if pipeline := cfg.Moderation.Pipeline(); nil != pipeline {
+		builder.WithModeration(pipeline, rcontext.Moderation)
+	}
+
+	svc, err := builder.Build()
+
+	if nil != err {
+		return ServiceContext{}, nil, err
+	}
+
+	webhooks, err := domain.NewWebhookLogicBuilder().
+		WithWebhookRepository(rcontext.Webhook).
+		WithPostRepository(rcontext.Post).
+		WithUserRepository(rcontext.User).
+		WithAllowInternal(cfg.Application.Webhooks.AllowInternal).
+		Build()
+
+	if nil != err {
+		return ServiceContext{}, nil, err
+	}
+
+	notifications, err := domain.NewNotificationLogicBuilder().
+		WithNotificationRepository(rcontext.Notification).
+		WithCommentRepository(rcontext.Comment).
+		WithPostRepository(rcontext.Post).
+		WithUserRepository(rcontext.User).
+		Build()
+
+	if nil != err {
+		return ServiceContext{}, nil, err
+	}
+
+	follows, err := domain.NewFollowLogicBuilder().
+		WithFollowRepository(rcontext.Follow).
+		Build()
+
+	if nil != err {
+		return ServiceContext{}, nil, err
+	}
+
+	moderation, err := domain.NewModerationLogicBuilder().
+		WithReviewRepository(rcontext.Moderation).
+		WithPublisher(svc).
+		WithModerators(cfg.Moderation.ModeratorIds()...).
+		Build()
+
+	if nil != err {
+		return ServiceContext{}, nil, err
+	}
+
+	return ServiceContext{
+		Comment:             svc,
+		Post:                svc,
+		User:                svc,
+		Health:              svc,
+		Outbox:              rcontext.Outbox,
+		WebhookHandler:      webhooks.Handle,
+		Webhook:             webhooks,
+		WebhookRepository:   rcontext.Webhook,
+		NotificationHandler: notifications.Handle,
+		Notification:        notifications,
+		Follow:              follows,
+		Moderation:          moderation,
+		Export: seed.Repositories{
+			User:    rcontext.User,
+			Post:    rcontext.Post,
+			Comment: rcontext.Comment,
+		},
+	}, nil, nil
+}
+
+var serviceConstructors = map[string]func(*config.Config, *RepositoryContext) (ServiceContext, Clearable, error){
+	"domain": DomainServiceConstructor,
+}
+
@@ -0,0 +1,339 @@
+package config
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/logic"
+	"github.com/muji40k/ozontestcomms/internal/domain/moderation"
+)
+
+// Every option is addressed by the dotted path of its key tags (e.g.
+// repository.psql.host), which is used in configuration files, as
+// command-line flag name and in error messages. Options with env tag can
+// also be set through environment.
+
+type PSQL struct {
+	Host     string `key:"host" env:"POSTER_PSQL_HOST"`
+	Port     string `key:"port" env:"POSTER_PSQL_PORT"`
+	DBName   string `key:"dbname" env:"POSTER_PSQL_DBNAME"`
+	User     string `key:"user" env:"POSTER_PSQL_USER"`
+	Password string `key:"password" env:"POSTER_PSQL_PASSWORD" secret:"true"`
+	// Replaces all the connection options above when set
+	DSN string `key:"dsn" env:"POSTER_PSQL_DSN" secret:"true"`
+
+	SSLMode          string        `key:"sslmode" env:"POSTER_PSQL_SSLMODE"`
+	StatementTimeout time.Duration `key:"statement_timeout" env:"POSTER_PSQL_STATEMENT_TIMEOUT"`
+	ApplicationName  string        `key:"application_name" env:"POSTER_PSQL_APPLICATION_NAME"`
+
+	// Zero values keep database/sql defaults
+	MaxOpenConns    int           `key:"max_open_conns" env:"POSTER_PSQL_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `key:"max_idle_conns" env:"POSTER_PSQL_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `key:"conn_max_lifetime" env:"POSTER_PSQL_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `key:"conn_max_idle_time" env:"POSTER_PSQL_CONN_MAX_IDLE_TIME"`
+
+	Replica PSQLReplica `key:"replica"`
+}
+
+// Read replica, listings are served from it when host or dsn is set
+type PSQLReplica struct {
+	Host string `key:"host" env:"POSTER_PSQL_REPLICA_HOST"`
+	// Port of the primary is used when empty
+	Port string `key:"port" env:"POSTER_PSQL_REPLICA_PORT"`
+	DSN  string `key:"dsn" env:"POSTER_PSQL_REPLICA_DSN" secret:"true"`
+}
+
+// Content is kept only in memory unless snapshot is set
+type InMemory struct {
+	Snapshot string `key:"snapshot" env:"POSTER_INMEMORY_SNAPSHOT"`
+	Format   string `key:"format" env:"POSTER_INMEMORY_SNAPSHOT_FORMAT"`
+	// Write log, changes since the last snapshot survive a crash
+	Log string `key:"log" env:"POSTER_INMEMORY_LOG"`
+	// Zero takes snapshot only on shutdown
+	SnapshotInterval time.Duration `key:"snapshot_interval" env:"POSTER_INMEMORY_SNAPSHOT_INTERVAL"`
+}
+
+type SQLite struct {
+	Path        string        `key:"path" env:"POSTER_SQLITE_PATH"`
+	BusyTimeout time.Duration `key:"busy_timeout" env:"POSTER_SQLITE_BUSY_TIMEOUT"`
+}
+
+// In-process cache of posts, users and comments by id, disabled unless size
+// is set. Writes of other instances are seen once the entries expire
+type Cache struct {
+	Size int           `key:"size" env:"POSTER_CACHE_SIZE"`
+	TTL  time.Duration `key:"ttl" env:"POSTER_CACHE_TTL"`
+}
+
+type Repository struct {
+	Type     string   `key:"type" env:"POSTER_REPOSITORY_TYPE"`
+	PSQL     PSQL     `key:"psql"`
+	InMemory InMemory `key:"inmemory"`
+	SQLite   SQLite   `key:"sqlite"`
+	Cache    Cache    `key:"cache"`
+}
+
+type Service struct {
+	Type string `key:"type" env:"POSTER_SERVICE_TYPE"`
+}
+
+type GraphQL struct {
+	Host            string        `key:"host" env:"POSTER_GRAPHQL_HOST"`
+	Port            string        `key:"port" env:"POSTER_GRAPHQL_PORT"`
+	LoaderDuration  time.Duration `key:"loader_duration" env:"POSTER_GRAPHQL_LOADER"`
+	ShutdownTimeout time.Duration `key:"shutdown_timeout" env:"POSTER_GRAPHQL_SHUTDOWN_TIMEOUT"`
+	// Subscriptions poll for changes this often
+	WatchInterval time.Duration `key:"watch_interval" env:"POSTER_GRAPHQL_WATCH_INTERVAL"`
+	// Directory of .graphql files, the only operations accepted when set
+	PersistedQueries string `key:"persisted_queries" env:"POSTER_GRAPHQL_PERSISTED_QUERIES"`
+	// Max age of GET responses to persisted read-only queries
+	CacheMaxAge time.Duration `key:"cache_max_age" env:"POSTER_GRAPHQL_CACHE_MAX_AGE"`
+}
+
+type Rest struct {
+	Host            string        `key:"host" env:"POSTER_REST_HOST"`
+	Port            string        `key:"port" env:"POSTER_REST_PORT"`
+	ShutdownTimeout time.Duration `key:"shutdown_timeout" env:"POSTER_REST_SHUTDOWN_TIMEOUT"`
+	// Bearer token of GET /export, the endpoint is disabled when empty
+	ExportToken string `key:"export_token" env:"POSTER_REST_EXPORT_TOKEN" secret:"true"`
+}
+
+type Grpc struct {
+	Host            string        `key:"host" env:"POSTER_GRPC_HOST"`
+	Port            string        `key:"port" env:"POSTER_GRPC_PORT"`
+	ShutdownTimeout time.Duration `key:"shutdown_timeout" env:"POSTER_GRPC_SHUTDOWN_TIMEOUT"`
+	WatchInterval   time.Duration `key:"watch_interval" env:"POSTER_GRPC_WATCH_INTERVAL"`
+}
+
+type Metrics struct {
+	Host            string        `key:"host" env:"POSTER_METRICS_HOST"`
+	Port            string        `key:"port" env:"POSTER_METRICS_PORT"`
+	ShutdownTimeout time.Duration `key:"shutdown_timeout" env:"POSTER_METRICS_SHUTDOWN_TIMEOUT"`
+}
+
+// Dispatcher of domain events, repositories record them only while it's
+// among the applications
+type Events struct {
+	Interval  time.Duration `key:"interval" env:"POSTER_EVENTS_INTERVAL"`
+	BatchSize int           `key:"batch_size" env:"POSTER_EVENTS_BATCH_SIZE"`
+}
+
+// Sender of webhook deliveries, which are scheduled by the events
+// application
+type Webhooks struct {
+	Interval       time.Duration `key:"interval" env:"POSTER_WEBHOOKS_INTERVAL"`
+	Timeout        time.Duration `key:"timeout" env:"POSTER_WEBHOOKS_TIMEOUT"`
+	MaxAttempts    int           `key:"max_attempts" env:"POSTER_WEBHOOKS_MAX_ATTEMPTS"`
+	InitialBackoff time.Duration `key:"initial_backoff" env:"POSTER_WEBHOOKS_INITIAL_BACKOFF"`
+	MaxBackoff     time.Duration `key:"max_backoff" env:"POSTER_WEBHOOKS_MAX_BACKOFF"`
+	BatchSize      int           `key:"batch_size" env:"POSTER_WEBHOOKS_BATCH_SIZE"`
+	// Receivers on loopback, private and link-local addresses are refused
+	// unless set, meant for local development
+	AllowInternal bool `key:"allow_internal" env:"POSTER_WEBHOOKS_ALLOW_INTERNAL"`
+}
+
+type Application struct {
+	Types    []string `key:"types" env:"POSTER_APPLICATION_TYPE"`
+	GraphQL  GraphQL  `key:"graphql"`
+	Rest     Rest     `key:"rest"`
+	Grpc     Grpc     `key:"grpc"`
+	Metrics  Metrics  `key:"metrics"`
+	Events   Events   `key:"events"`
+	Webhooks Webhooks `key:"webhooks"`
+}
+
+// Maximum content lengths in bytes
+type Limits struct {
+	CommentContent int `key:"comment_content" env:"POSTER_LIMIT_COMMENT_CONTENT"`
+	PostTitle      int `key:"post_title" env:"POSTER_LIMIT_POST_TITLE"`
+	PostContent    int `key:"post_content" env:"POSTER_LIMIT_POST_CONTENT"`
+}
+
+func (self Limits) Logic() logic.Limits {
+	return logic.Limits{
+		CommentContent: self.CommentContent,
+		PostTitle:      self.PostTitle,
+		PostContent:    self.PostContent,
+	}
+}
+
+type BannedWords struct {
+	Words  []string `key:"words" env:"POSTER_MODERATION_BANNED_WORDS"`
+	Action string   `key:"action" env:"POSTER_MODERATION_BANNED_WORDS_ACTION"`
+}
+
+type Links struct {
+	Max    int    `key:"max" env:"POSTER_MODERATION_LINKS_MAX"`
+	Action string `key:"action" env:"POSTER_MODERATION_LINKS_ACTION"`
+}
+
+type Repeats struct {
+	Max    int           `key:"max" env:"POSTER_MODERATION_REPEATS_MAX"`
+	Window time.Duration `key:"window" env:"POSTER_MODERATION_REPEATS_WINDOW"`
+	Action string        `key:"action" env:"POSTER_MODERATION_REPEATS_ACTION"`
+}
+
+type CommentRate struct {
+	PerMinute int    `key:"per_minute" env:"POSTER_MODERATION_COMMENTS_PER_MINUTE"`
+	Action    string `key:"action" env:"POSTER_MODERATION_COMMENTS_ACTION"`
+}
+
+// Checkers of the created content, each is enabled by a non-empty word list
+// or a positive limit
+type Moderation struct {
+	Moderators  []string    `key:"moderators" env:"POSTER_MODERATORS"`
+	BannedWords BannedWords `key:"banned_words"`
+	Links       Links       `key:"links"`
+	Repeats     Repeats     `key:"repeats"`
+	Comments    CommentRate `key:"comments"`
+}
+
+// Ids must be validated first
+func (self Moderation) ModeratorIds() []uuid.UUID {
+	out := make([]uuid.UUID, len(self.Moderators))
+
+	for i, id := range self.Moderators {
+		out[i] = uuid.MustParse(id)
+	}
+
+	return out
+}
+
+// Nil if no checker is enabled, actions must be validated first
+func (self Moderation) Pipeline() *moderation.Pipeline {
+	checkers := make([]moderation.Checker, 0)
+
+	if 0 != len(self.BannedWords.Words) {
+		checkers = append(checkers, moderation.NewBannedWords(
+			moderation.ACTIONS[self.BannedWords.Action],
+			self.BannedWords.Words...,
+		))
+	}
+
+	if 0 != self.Links.Max {
+		checkers = append(checkers, moderation.NewLinkLimit(
+			moderation.ACTIONS[self.Links.Action],
+			self.Links.Max,
+		))
+	}
+
+	if 0 != self.Repeats.Max {
+		checkers = append(checkers, moderation.NewRepeatedContent(
+			moderation.ACTIONS[self.Repeats.Action],
+			self.Repeats.Max,
+			self.Repeats.Window,
+		))
+	}
+
+	if 0 != self.Comments.PerMinute {
+		checkers = append(checkers, moderation.NewCommentRate(
+			moderation.ACTIONS[self.Comments.Action],
+			self.Comments.PerMinute,
+			time.Minute,
+		))
+	}
+
+	if 0 == len(checkers) {
+		return nil
+	}
+
+	return moderation.NewPipeline(checkers...)
+}
+
+type Config struct {
+	Repository  Repository  `key:"repository"`
+	Service     Service     `key:"service"`
+	Application Application `key:"application"`
+	Limits      Limits      `key:"limits"`
+	Moderation  Moderation  `key:"moderation"`
+}
+
+// Credentials have no defaults and must be provided explicitly
+func Default() Config {
+	return Config{
+		Repository: Repository{
+			Type: "psql",
+			PSQL: PSQL{
+				Host:            "127.0.0.1",
+				Port:            "5432",
+				DBName:          "poster",
+				ApplicationName: "poster",
+			},
+			InMemory: InMemory{
+				Format: "json",
+			},
+			SQLite: SQLite{
+				Path:        "poster.db",
+				BusyTimeout: 5 * time.Second,
+			},
+			Cache: Cache{
+				TTL: time.Minute,
+			},
+		},
+		Service: Service{
+			Type: "domain",
+		},
+		Application: Application{
+			Types: []string{"graphql"},
+			GraphQL: GraphQL{
+				Host:            "0.0.0.0",
+				Port:            "80",
+				LoaderDuration:  time.Millisecond,
+				ShutdownTimeout: 10 * time.Second,
+				WatchInterval:   time.Second,
+				CacheMaxAge:     time.Minute,
+			},
+			Rest: Rest{
+				Host:            "0.0.0.0",
+				Port:            "8080",
+				ShutdownTimeout: 10 * time.Second,
+			},
+			Grpc: Grpc{
+				Host:            "0.0.0.0",
+				Port:            "9090",
+				ShutdownTimeout: 10 * time.Second,
+				WatchInterval:   time.Second,
+			},
+			Metrics: Metrics{
+				Host:            "0.0.0.0",
+				Port:            "9100",
+				ShutdownTimeout: 10 * time.Second,
+			},
+			Events: Events{
+				Interval:  time.Second,
+				BatchSize: 100,
+			},
+			Webhooks: Webhooks{
+				Interval:       time.Second,
+				Timeout:        10 * time.Second,
+				MaxAttempts:    8,
+				InitialBackoff: 10 * time.Second,
+				MaxBackoff:     time.Hour,
+				BatchSize:      100,
+			},
+		},
+		Limits: Limits{
+			CommentContent: logic.DEFAULT_LIMITS.CommentContent,
+			PostTitle:      logic.DEFAULT_LIMITS.PostTitle,
+			PostContent:    logic.DEFAULT_LIMITS.PostContent,
+		},
+		Moderation: Moderation{
+			Moderators: []string{},
+			BannedWords: BannedWords{
+				Words:  []string{},
+				Action: "reject",
+			},
+			Links: Links{
+				Action: "hide",
+			},
+			Repeats: Repeats{
+				Window: time.Hour,
+				Action: "flag",
+			},
+			Comments: CommentRate{
+				Action: "reject",
+			},
+		},
+	}
+}
+
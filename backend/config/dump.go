@@ -0,0 +1,50 @@
+package config
+
+import (
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const REDACTED string = "<redacted>"
+
+// Writes effective configuration as yaml, which can be loaded back, with
+// secrets replaced
+func (self *Config) Dump(w io.Writer) error {
+	tree := make(map[string]any)
+
+	for _, o := range self.options() {
+		value := o.value.Interface()
+
+		if o.secret && "" != o.String() {
+			value = REDACTED
+		} else if durationType == o.value.Type() {
+			value = o.String()
+		}
+
+		node := tree
+		path := strings.Split(o.key, ".")
+
+		for _, k := range path[:len(path)-1] {
+			if _, found := node[k]; !found {
+				node[k] = make(map[string]any)
+			}
+
+			node = node[k].(map[string]any)
+		}
+
+		node[path[len(path)-1]] = value
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	err := encoder.Encode(tree)
+
+	if nil == err {
+		err = encoder.Close()
+	}
+
+	return err
+}
+
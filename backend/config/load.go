@@ -0,0 +1,256 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const ENV_CONFIG_FILE string = "POSTER_CONFIG"
+
+type option struct {
+	key    string
+	env    string
+	secret bool
+	value  reflect.Value
+}
+
+func options(prefix string, v reflect.Value) []option {
+	out := make([]option, 0)
+	t := v.Type()
+
+	for i := 0; t.NumField() > i; i++ {
+		f := t.Field(i)
+		key := f.Tag.Get("key")
+
+		if "" != prefix {
+			key = prefix + "." + key
+		}
+
+		if reflect.Struct == f.Type.Kind() {
+			out = append(out, options(key, v.Field(i))...)
+		} else {
+			out = append(out, option{
+				key:    key,
+				env:    f.Tag.Get("env"),
+				secret: "true" == f.Tag.Get("secret"),
+				value:  v.Field(i),
+			})
+		}
+	}
+
+	return out
+}
+
+func (self *Config) options() []option {
+	return options("", reflect.ValueOf(self).Elem())
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Comma separated list, blank items are skipped
+func splitList(value string) []string {
+	out := make([]string, 0)
+
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); "" != v {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+func (self *option) set(raw string) error {
+	var err error
+
+	switch {
+	case durationType == self.value.Type():
+		var v time.Duration
+
+		if v, err = time.ParseDuration(raw); nil == err {
+			self.value.SetInt(int64(v))
+		}
+	case reflect.String == self.value.Kind():
+		self.value.SetString(raw)
+	case reflect.Int == self.value.Kind():
+		var v int
+
+		if v, err = strconv.Atoi(raw); nil == err {
+			self.value.SetInt(int64(v))
+		}
+	case reflect.Bool == self.value.Kind():
+		var v bool
+
+		if v, err = strconv.ParseBool(raw); nil == err {
+			self.value.SetBool(v)
+		}
+	case reflect.Slice == self.value.Kind():
+		self.value.Set(reflect.ValueOf(splitList(raw)))
+	default:
+		panic(fmt.Sprintf("Unsupported option type: %v", self.value.Type()))
+	}
+
+	if nil != err {
+		err = fmt.Errorf("%v: malformed value %q", self.key, raw)
+	}
+
+	return err
+}
+
+func (self *option) String() string {
+	switch v := self.value.Interface().(type) {
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Nested tables are turned into dotted keys, lists into comma separated
+// values
+func flatten(prefix string, tree map[string]any, out map[string]string) {
+	for k, v := range tree {
+		if "" != prefix {
+			k = prefix + "." + k
+		}
+
+		switch v := v.(type) {
+		case map[string]any:
+			flatten(k, v, out)
+		case []any:
+			items := make([]string, len(v))
+
+			for i := range v {
+				items[i] = fmt.Sprint(v[i])
+			}
+
+			out[k] = strings.Join(items, ",")
+		default:
+			out[k] = fmt.Sprint(v)
+		}
+	}
+}
+
+func readFile(path string) (map[string]string, error) {
+	tree := make(map[string]any)
+	content, err := os.ReadFile(path)
+
+	if nil == err {
+		switch ext := strings.ToLower(filepath.Ext(path)); ext {
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(content, &tree)
+		case ".toml":
+			err = toml.Unmarshal(content, &tree)
+		default:
+			err = fmt.Errorf("Unsupported config format %q, expected yaml or toml", ext)
+		}
+	}
+
+	if nil != err {
+		return nil, err
+	}
+
+	out := make(map[string]string)
+	flatten("", tree, out)
+
+	return out, nil
+}
+
+// Values set through command-line flags, keyed by option
+type Overrides map[string]string
+
+// Registers flag for every option, named after its key
+func RegisterFlags(fs *flag.FlagSet) Overrides {
+	out := make(Overrides)
+	cfg := Default()
+
+	for _, o := range cfg.options() {
+		usage := fmt.Sprintf("overrides %v", o.key)
+
+		if "" != o.env {
+			usage = fmt.Sprintf("overrides %v and $%v", o.key, o.env)
+		}
+
+		fs.Func(o.key, usage, func(v string) error {
+			out[o.key] = v
+			return nil
+		})
+	}
+
+	return out
+}
+
+// Builds configuration from defaults, the file (if path is not empty),
+// environment and overrides, each next one taking precedence
+func Load(path string, getenv func(string) string, overrides Overrides) (Config, error) {
+	var file map[string]string
+	var err error
+	cfg := Default()
+	opts := cfg.options()
+
+	if "" != path {
+		if file, err = readFile(path); nil != err {
+			err = fmt.Errorf("config file %v: %w", path, err)
+		}
+	}
+
+	if nil == err {
+		errs := make([]error, 0)
+		known := make(map[string]struct{}, len(opts))
+
+		for i := range opts {
+			o := &opts[i]
+			known[o.key] = struct{}{}
+
+			if v, found := file[o.key]; found {
+				errs = append(errs, wrap("config file", o.set(v)))
+			}
+
+			if v := getenv(o.env); "" != o.env && "" != v {
+				errs = append(errs, wrap("$"+o.env, o.set(v)))
+			}
+
+			if v, found := overrides[o.key]; found {
+				errs = append(errs, wrap("flag", o.set(v)))
+			}
+		}
+
+		unknown := make([]string, 0)
+
+		for k := range file {
+			if _, found := known[k]; !found {
+				unknown = append(unknown, k)
+			}
+		}
+
+		sort.Strings(unknown)
+
+		for _, k := range unknown {
+			errs = append(errs, fmt.Errorf("config file: unknown option %v", k))
+		}
+
+		err = errors.Join(errs...)
+	}
+
+	return cfg, err
+}
+
+func wrap(source string, err error) error {
+	if nil != err {
+		err = fmt.Errorf("%v: %w", source, err)
+	}
+
+	return err
+}
+
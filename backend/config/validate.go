@@ -0,0 +1,305 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	psqlbuilder "github.com/muji40k/ozontestcomms/builders/repositories/psql"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/domain/moderation"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+)
+
+// Names of implementations available for each layer
+type Choices struct {
+	Repositories []string
+	Services     []string
+	Applications []string
+}
+
+type validator struct {
+	errs []error
+}
+
+func (self *validator) fail(key string, format string, args ...any) {
+	self.errs = append(self.errs, fmt.Errorf("%v: %v", key, fmt.Sprintf(format, args...)))
+}
+
+func (self *validator) oneOf(key string, value string, choices []string) {
+	if "" == value {
+		self.fail(key, "must be set")
+	} else if !slices.Contains(choices, value) {
+		self.fail(key, "unknown value %q, expected one of: %v",
+			value, strings.Join(choices, ", "))
+	}
+}
+
+func (self *validator) required(key string, value string) {
+	if "" == value {
+		self.fail(key, "must be set")
+	}
+}
+
+func (self *validator) port(key string, value string) {
+	if v, err := strconv.Atoi(value); nil != err || 0 > v || 65535 < v {
+		self.fail(key, "expected port number, got %q", value)
+	}
+}
+
+func (self *validator) host(key string, value string) {
+	if "" == value {
+		self.fail(key, "must be set")
+	} else if strings.ContainsAny(value, " /:") && nil == net.ParseIP(value) {
+		self.fail(key, "malformed host %q", value)
+	}
+}
+
+func (self *validator) positive(key string, value time.Duration) {
+	if 0 >= value {
+		self.fail(key, "must be positive, got %v", value)
+	}
+}
+
+func (self *validator) nonNegative(key string, value time.Duration) {
+	if 0 > value {
+		self.fail(key, "must not be negative, got %v", value)
+	}
+}
+
+func (self *validator) nonNegativeInt(key string, value int) {
+	if 0 > value {
+		self.fail(key, "must not be negative, got %v", value)
+	}
+}
+
+func (self *validator) positiveInt(key string, value int) {
+	if 0 >= value {
+		self.fail(key, "must be positive, got %v", value)
+	}
+}
+
+func (self *validator) atMostInt(key string, value int, max int) {
+	if max < value {
+		self.fail(key, "must not exceed %v, got %v", max, value)
+	}
+}
+
+func (self *validator) listen(key string, host string, port string) {
+	self.host(key+".host", host)
+	self.port(key+".port", port)
+}
+
+func (self *Repository) validate(v *validator, choices []string) {
+	v.oneOf("repository.type", self.Type, choices)
+
+	switch self.Type {
+	case "psql":
+		self.PSQL.validate(v)
+	case "in-memory":
+		self.InMemory.validate(v)
+	case "sqlite":
+		self.SQLite.validate(v)
+	}
+
+	self.Cache.validate(v)
+}
+
+func (self *Cache) validate(v *validator) {
+	v.nonNegativeInt("repository.cache.size", self.Size)
+
+	if 0 != self.Size {
+		v.positive("repository.cache.ttl", self.TTL)
+	}
+}
+
+func (self *SQLite) validate(v *validator) {
+	v.required("repository.sqlite.path", self.Path)
+	v.nonNegative("repository.sqlite.busy_timeout", self.BusyTimeout)
+}
+
+func (self *InMemory) validate(v *validator) {
+	formats := make([]string, len(inmemory.FORMATS))
+
+	for i, f := range inmemory.FORMATS {
+		formats[i] = string(f)
+	}
+
+	v.oneOf("repository.inmemory.format", self.Format, formats)
+	v.nonNegative("repository.inmemory.snapshot_interval", self.SnapshotInterval)
+
+	if "" == self.Snapshot {
+		if "" != self.Log {
+			v.fail("repository.inmemory.log", "set without repository.inmemory.snapshot")
+		}
+
+		if 0 != self.SnapshotInterval {
+			v.fail("repository.inmemory.snapshot_interval",
+				"set without repository.inmemory.snapshot")
+		}
+	} else if self.Snapshot == self.Log {
+		v.fail("repository.inmemory.log", "must differ from repository.inmemory.snapshot")
+	}
+}
+
+func (self *PSQL) validate(v *validator) {
+	if "" == self.DSN {
+		v.required("repository.psql.host", self.Host)
+		v.port("repository.psql.port", self.Port)
+		v.required("repository.psql.dbname", self.DBName)
+		v.required("repository.psql.user", self.User)
+		v.required("repository.psql.password", self.Password)
+	}
+
+	if "" != self.SSLMode {
+		v.oneOf("repository.psql.sslmode", self.SSLMode, psqlbuilder.SSL_MODES)
+	}
+
+	v.nonNegative("repository.psql.statement_timeout", self.StatementTimeout)
+	v.nonNegativeInt("repository.psql.max_open_conns", self.MaxOpenConns)
+	v.nonNegativeInt("repository.psql.max_idle_conns", self.MaxIdleConns)
+	v.nonNegative("repository.psql.conn_max_lifetime", self.ConnMaxLifetime)
+	v.nonNegative("repository.psql.conn_max_idle_time", self.ConnMaxIdleTime)
+
+	if "" == self.Replica.DSN && "" != self.Replica.Host {
+		if "" != self.Replica.Port {
+			v.port("repository.psql.replica.port", self.Replica.Port)
+		}
+
+		if "" != self.DSN {
+			v.fail("repository.psql.replica.host",
+				"can't be combined with repository.psql.dsn, use repository.psql.replica.dsn")
+		}
+	} else if "" == self.Replica.Host && "" != self.Replica.Port {
+		v.fail("repository.psql.replica.port", "set without repository.psql.replica.host")
+	}
+}
+
+func (self *Service) validate(v *validator, choices []string) {
+	v.oneOf("service.type", self.Type, choices)
+}
+
+func (self *Application) validate(v *validator, choices []string) {
+	addresses := make(map[string]string)
+	listen := func(name string, host string, port string) {
+		key := "application." + name
+		v.listen(key, host, port)
+
+		// Port 0 is picked by the system and never collides
+		if "0" == port {
+			return
+		}
+
+		address := net.JoinHostPort(host, port)
+
+		if other, found := addresses[port]; found {
+			v.fail(key+".port", "%v is already used by application.%v", address, other)
+		} else {
+			addresses[port] = name
+		}
+	}
+
+	if 0 == len(self.Types) {
+		v.fail("application.types", "at least one application must be set")
+	}
+
+	for i, t := range self.Types {
+		v.oneOf("application.types", t, choices)
+
+		if slices.Contains(self.Types[:i], t) {
+			v.fail("application.types", "%q listed twice", t)
+			continue
+		}
+
+		switch t {
+		case "graphql":
+			listen(t, self.GraphQL.Host, self.GraphQL.Port)
+			v.positive("application.graphql.loader_duration", self.GraphQL.LoaderDuration)
+			v.positive("application.graphql.shutdown_timeout", self.GraphQL.ShutdownTimeout)
+			v.positive("application.graphql.watch_interval", self.GraphQL.WatchInterval)
+			v.nonNegative("application.graphql.cache_max_age", self.GraphQL.CacheMaxAge)
+		case "rest":
+			listen(t, self.Rest.Host, self.Rest.Port)
+			v.positive("application.rest.shutdown_timeout", self.Rest.ShutdownTimeout)
+		case "grpc":
+			listen(t, self.Grpc.Host, self.Grpc.Port)
+			v.positive("application.grpc.shutdown_timeout", self.Grpc.ShutdownTimeout)
+			v.positive("application.grpc.watch_interval", self.Grpc.WatchInterval)
+		case "metrics":
+			listen(t, self.Metrics.Host, self.Metrics.Port)
+			v.positive("application.metrics.shutdown_timeout", self.Metrics.ShutdownTimeout)
+		case "events":
+			v.positive("application.events.interval", self.Events.Interval)
+			v.positiveInt("application.events.batch_size", self.Events.BatchSize)
+		case "webhooks":
+			v.positive("application.webhooks.interval", self.Webhooks.Interval)
+			v.positive("application.webhooks.timeout", self.Webhooks.Timeout)
+			v.positiveInt("application.webhooks.max_attempts", self.Webhooks.MaxAttempts)
+			v.positive("application.webhooks.initial_backoff", self.Webhooks.InitialBackoff)
+			v.positive("application.webhooks.max_backoff", self.Webhooks.MaxBackoff)
+			v.positiveInt("application.webhooks.batch_size", self.Webhooks.BatchSize)
+
+			if self.Webhooks.MaxBackoff < self.Webhooks.InitialBackoff {
+				v.fail("application.webhooks.max_backoff",
+					"must not be less than application.webhooks.initial_backoff")
+			}
+		}
+	}
+}
+
+// Storage schemas don't hold longer content
+func (self *Limits) validate(v *validator) {
+	v.positiveInt("limits.comment_content", self.CommentContent)
+	v.atMostInt("limits.comment_content", self.CommentContent, models.COMMENT_CONTENT_LENGTH_LIMIT)
+	v.positiveInt("limits.post_title", self.PostTitle)
+	v.atMostInt("limits.post_title", self.PostTitle, models.POST_TITLE_LENGTH_LIMIT)
+	v.positiveInt("limits.post_content", self.PostContent)
+	v.atMostInt("limits.post_content", self.PostContent, models.POST_CONTENT_LENGTH_LIMIT)
+}
+
+func (self *Moderation) validate(v *validator) {
+	actions := make([]string, 0, len(moderation.ACTIONS))
+
+	for name := range moderation.ACTIONS {
+		actions = append(actions, name)
+	}
+
+	slices.Sort(actions)
+
+	for _, id := range self.Moderators {
+		if _, err := uuid.Parse(id); nil != err {
+			v.fail("moderation.moderators", "malformed id %q", id)
+		}
+	}
+
+	v.oneOf("moderation.banned_words.action", self.BannedWords.Action, actions)
+	v.nonNegativeInt("moderation.links.max", self.Links.Max)
+	v.oneOf("moderation.links.action", self.Links.Action, actions)
+	v.nonNegativeInt("moderation.repeats.max", self.Repeats.Max)
+	v.oneOf("moderation.repeats.action", self.Repeats.Action, actions)
+	v.nonNegativeInt("moderation.comments.per_minute", self.Comments.PerMinute)
+	v.oneOf("moderation.comments.action", self.Comments.Action, actions)
+
+	if 0 != self.Repeats.Max {
+		v.positive("moderation.repeats.window", self.Repeats.Window)
+	}
+}
+
+// Reports every problem found at once, each prefixed with option key
+func (self *Config) Validate(choices Choices) error {
+	v := validator{make([]error, 0)}
+
+	self.Repository.validate(&v, choices.Repositories)
+	self.Service.validate(&v, choices.Services)
+	self.Application.validate(&v, choices.Applications)
+	self.Limits.validate(&v)
+	self.Moderation.validate(&v)
+
+	return errors.Join(v.errs...)
+}
+
@@ -0,0 +1,643 @@
+package graphql_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/test/e2e"
+	"github.com/muji40k/ozontestcomms/test/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const CREATE_POST = `
+    mutation ($user: UUID!, $title: String!, $allow: Boolean) {
+        createPost(user_id: $user, input: {
+            title: $title, content: "content", allow_comments: $allow
+        }) {
+            id title content comments_allowed author { id email }
+        }
+    }
+`
+
+const COMMENT_POST = `
+    mutation ($user: UUID!, $post: UUID!, $content: String!) {
+        commentPost(user_id: $user, post_id: $post, input: {content: $content}) {
+            id content author { id }
+        }
+    }
+`
+
+const COMMENT_COMMENT = `
+    mutation ($user: UUID!, $comment: UUID!, $content: String!) {
+        commentComment(user_id: $user, comment_id: $comment, input: {content: $content}) {
+            id content
+        }
+    }
+`
+
+func createPost(api *e2e.GraphQL, user int, title string, allow bool) string {
+	return api.Must(CREATE_POST, map[string]any{
+		"user":  api.Users[user].Id,
+		"title": title,
+		"allow": allow,
+	}).String("createPost.id")
+}
+
+func commentPost(api *e2e.GraphQL, user int, post string, content string) string {
+	return api.Must(COMMENT_POST, map[string]any{
+		"user":    api.Users[user].Id,
+		"post":    post,
+		"content": content,
+	}).String("commentPost.id")
+}
+
+func commentComment(api *e2e.GraphQL, user int, comment string, content string) string {
+	return api.Must(COMMENT_COMMENT, map[string]any{
+		"user":    api.Users[user].Id,
+		"comment": comment,
+		"content": content,
+	}).String("commentComment.id")
+}
+
+func TestE2ECreatePost(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 1)
+
+	// Act
+	resp := api.Do(CREATE_POST, map[string]any{
+		"user":  api.Users[0].Id,
+		"title": "title",
+	})
+
+	// Assert
+	require.Empty(t, resp.Errors)
+	assert.Equal(t, "title", resp.String("createPost.title"))
+	assert.Equal(t, true, resp.Get("createPost.comments_allowed"))
+	assert.Equal(t, api.Users[0].Email, resp.String("createPost.author.email"))
+	id := resp.String("createPost.id")
+	found := api.Must(`query ($id: UUID!) { post(id: $id) { id title } }`, map[string]any{"id": id})
+	assert.Equal(t, id, found.String("post.id"))
+}
+
+func TestE2ENestedComments(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 2)
+	post := createPost(api, 0, "title", true)
+	root := commentPost(api, 1, post, "root")
+	reply := commentComment(api, 0, root, "reply")
+	nested := commentComment(api, 1, reply, "nested")
+
+	// Act
+	resp := api.Must(`
+        query ($id: UUID!) {
+            post(id: $id) {
+                comments(limit: 10) {
+                    data {
+                        id content author { id }
+                        comments(limit: 10) {
+                            data {
+                                id content author { id }
+                                comments(limit: 10) { data { id } }
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    `, map[string]any{"id": post})
+
+	// Assert
+	assert.Equal(t, root, resp.String("post.comments.data.0.id"))
+	assert.Equal(t, api.Users[1].Id.String(), resp.String("post.comments.data.0.author.id"))
+	assert.Equal(t, reply, resp.String("post.comments.data.0.comments.data.0.id"))
+	assert.Equal(t, api.Users[0].Id.String(),
+		resp.String("post.comments.data.0.comments.data.0.author.id"))
+	assert.Equal(t, nested,
+		resp.String("post.comments.data.0.comments.data.0.comments.data.0.id"))
+	comment := api.Must(`query ($id: UUID!) { comment(id: $id) { content } }`,
+		map[string]any{"id": reply})
+	assert.Equal(t, "reply", comment.String("comment.content"))
+}
+
+func TestE2EAuthorsAreBatched(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 3)
+
+	for i := range api.Users {
+		createPost(api, i, "title", true)
+	}
+
+	before := api.UserBatches()
+
+	// Act
+	resp := api.Do(`{ posts(limit: 10, order: DATE_ASC) { data { author { id } } } }`, nil)
+
+	// Assert
+	require.Empty(t, resp.Errors)
+	assert.Equal(t, before+1, api.UserBatches(), "Every author is resolved by a single batch")
+	authors := resp.Pluck("posts.data", "author")
+
+	for i, author := range authors {
+		assert.Equal(t, api.Users[i].Id.String(), author.(map[string]any)["id"])
+	}
+}
+
+func TestE2EPostsPagination(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 1)
+	ids := make([]any, 5)
+
+	for i := range ids {
+		ids[i] = createPost(api, 0, "title", true)
+	}
+
+	query := `
+        query ($after: UUID) {
+            posts(after: $after, limit: 2, order: DATE_ASC) { data { id } end_id }
+        }
+    `
+	pages := make([][]any, 0)
+	var after any
+
+	// Act
+	for {
+		resp := api.Must(query, map[string]any{"after": after})
+		page := resp.Pluck("posts.data", "id")
+
+		if 0 == len(page) {
+			assert.Nil(t, resp.Get("posts.end_id"))
+			break
+		}
+
+		pages = append(pages, page)
+		after = resp.String("posts.end_id")
+		assert.Equal(t, page[len(page)-1], after)
+	}
+
+	// Assert
+	assert.Equal(t, [][]any{ids[0:2], ids[2:4], ids[4:5]}, pages)
+	desc := api.Must(`{ posts(limit: 1) { data { id } } }`, nil)
+	assert.Equal(t, ids[4], desc.String("posts.data.0.id"), "Newest first by default")
+}
+
+func TestE2ECommentsPagination(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 1)
+	post := createPost(api, 0, "title", true)
+	ids := make([]any, 3)
+
+	for i := range ids {
+		ids[i] = commentPost(api, 0, post, "comment")
+	}
+
+	// Act
+	resp := api.Must(`
+        query ($id: UUID!, $after: UUID) {
+            post(id: $id) {
+                comments(after: $after, limit: 5, order: DATE_DESC) { data { id } end_id }
+            }
+        }
+    `, map[string]any{"id": post, "after": ids[2]})
+
+	// Assert
+	assert.Equal(t, []any{ids[1], ids[0]}, resp.Pluck("post.comments.data", "id"))
+	assert.Equal(t, ids[0], resp.String("post.comments.end_id"))
+}
+
+func TestE2ECommentsDisabled(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 2)
+	post := createPost(api, 0, "title", false)
+
+	// Act
+	denied := api.Do(COMMENT_POST, map[string]any{
+		"user":    api.Users[1].Id,
+		"post":    post,
+		"content": "comment",
+	})
+	foreign := api.Do(`
+        mutation ($user: UUID!, $post: UUID!) {
+            modifyPost(user_id: $user, post_id: $post, input: {allow_comments: true}) { id }
+        }
+    `, map[string]any{"user": api.Users[1].Id, "post": post})
+	enabled := api.Must(`
+        mutation ($user: UUID!, $post: UUID!) {
+            modifyPost(user_id: $user, post_id: $post, input: {allow_comments: true}) {
+                comments_allowed
+            }
+        }
+    `, map[string]any{"user": api.Users[0].Id, "post": post})
+
+	// Assert
+	require.Len(t, denied.Errors, 1)
+	assert.Contains(t, denied.Errors[0].Message, "Action violates rules")
+	assert.Equal(t, []any{"commentPost"}, denied.Errors[0].Path)
+	assert.JSONEq(t, "null", string(denied.Data))
+	require.Len(t, foreign.Errors, 1, "Only author can modify the post")
+	assert.Contains(t, foreign.Errors[0].Message, "Authorization error")
+	assert.Equal(t, true, enabled.Get("modifyPost.comments_allowed"))
+	commentPost(api, 1, post, "comment")
+}
+
+func TestE2EErrors(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 1)
+	post := createPost(api, 0, "title", true)
+
+	// Act
+	missing := api.Do(`query ($id: UUID!) { post(id: $id) { id } }`,
+		map[string]any{"id": uuid.New()})
+	negative := api.Do(`{ posts(limit: -1) { data { id } } }`, nil)
+	cursor := api.Do(`query ($after: UUID) { posts(after: $after, limit: 1) { data { id } } }`,
+		map[string]any{"after": uuid.New()})
+	invalid := api.Do(`{ post(id: "not uuid") { id } }`, nil)
+	unknown := api.Do(`{ posts(limit: 1) { data { unknown } } }`, nil)
+	empty := api.Do(COMMENT_POST, map[string]any{
+		"user":    api.Users[0].Id,
+		"post":    post,
+		"content": "",
+	})
+
+	// Assert
+	for name, resp := range map[string]*e2e.Response{
+		"missing":  missing,
+		"negative": negative,
+		"cursor":   cursor,
+		"invalid":  invalid,
+		"unknown":  unknown,
+		"empty":    empty,
+	} {
+		assert.Len(t, resp.Errors, 1, name)
+	}
+
+	assert.Contains(t, missing.Errors[0].Message, "Unable to find")
+	assert.Equal(t, []any{"post"}, missing.Errors[0].Path)
+	assert.Equal(t, []any{"posts"}, negative.Errors[0].Path)
+	assert.Equal(t, http.StatusOK, missing.Status)
+	assert.Equal(t, http.StatusUnprocessableEntity, unknown.Status)
+	assert.Nil(t, unknown.Errors[0].Path, "Validation fails before execution")
+	assert.Equal(t, []any{"post", "id"}, invalid.Errors[0].Path, "Argument is parsed during execution")
+	assert.Contains(t, unknown.Errors[0].Message, "Cannot query field")
+	assert.Contains(t, empty.Errors[0].Message, "can't be empty")
+}
+
+func TestE2EFixtureThread(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 0)
+	loaded := api.Load(fixtures.THREAD)
+
+	// Act
+	resp := api.Must(`
+        query ($id: UUID!) {
+            post(id: $id) {
+                author { email }
+                comments(limit: 10, order: DATE_ASC) {
+                    data { content comments(limit: 10) { data { content author { email } } } }
+                }
+            }
+        }
+    `, map[string]any{"id": loaded.Posts["welcome"].Id})
+
+	// Assert
+	assert.Equal(t, "alice@poster.test", resp.String("post.author.email"))
+	assert.Equal(t, []any{"Hello!", "Are there any rules?"}, resp.Pluck("post.comments.data", "content"))
+	assert.Equal(t, "alice@poster.test",
+		resp.String("post.comments.data.0.comments.data.0.author.email"))
+}
+
+const CREATE_WEBHOOK = `
+    mutation ($user: UUID!, $url: String!, $post: UUID) {
+        createWebhook(user_id: $user, input: {
+            url: $url, events: [COMMENT_CREATED, POST_CREATED], post_id: $post
+        }) {
+            id url events post_id secret
+        }
+    }
+`
+
+const WEBHOOK_DELIVERIES = `
+    query ($user: UUID!, $status: DeliveryStatus) {
+        webhooks(user_id: $user, limit: 10) {
+            data {
+                id secret
+                deliveries(limit: 10, status: $status) {
+                    data { id event status attempts status_code error payload }
+                }
+            }
+        }
+    }
+`
+
+func TestE2EWebhooks(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 2)
+	post := createPost(api, 0, "title", true)
+
+	// Act
+	created := api.Must(CREATE_WEBHOOK, map[string]any{
+		"user": api.Users[0].Id,
+		"url":  "https://example.com/hook",
+		"post": post,
+	})
+	invalid := api.Do(CREATE_WEBHOOK, map[string]any{
+		"user": api.Users[0].Id,
+		"url":  "example.com",
+	})
+	listed := api.Must(`query ($user: UUID!) { webhooks(user_id: $user, limit: 10) { data { id secret } } }`,
+		map[string]any{"user": api.Users[0].Id})
+	other := api.Must(`query ($user: UUID!) { webhooks(user_id: $user, limit: 10) { data { id } } }`,
+		map[string]any{"user": api.Users[1].Id})
+	id := created.String("createWebhook.id")
+	forbidden := api.Do(`mutation ($user: UUID!, $id: UUID!) { deleteWebhook(user_id: $user, webhook_id: $id) }`,
+		map[string]any{"user": api.Users[1].Id, "id": id})
+	deleted := api.Must(`mutation ($user: UUID!, $id: UUID!) { deleteWebhook(user_id: $user, webhook_id: $id) }`,
+		map[string]any{"user": api.Users[0].Id, "id": id})
+
+	// Assert
+	assert.Equal(t, []any{"COMMENT_CREATED", "POST_CREATED"}, created.Get("createWebhook.events"))
+	assert.Equal(t, post, created.String("createWebhook.post_id"))
+	assert.Len(t, created.String("createWebhook.secret"), 64)
+	assert.Len(t, invalid.Errors, 1)
+	assert.Equal(t, []any{id}, listed.Pluck("webhooks.data", "id"))
+	assert.Equal(t, []any{nil}, listed.Pluck("webhooks.data", "secret"), "Secret is shown once")
+	assert.Zero(t, other.Len("webhooks.data"))
+	assert.Len(t, forbidden.Errors, 1)
+	assert.Equal(t, true, deleted.Get("deleteWebhook"))
+}
+
+func TestE2EWebhookDeadLetters(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 1)
+	id := uuid.MustParse(api.Must(CREATE_WEBHOOK, map[string]any{
+		"user": api.Users[0].Id,
+		"url":  "https://example.com/hook",
+	}).String("createWebhook.id"))
+	now := time.Now()
+	require.NoError(t, api.Webhooks.CreateDeliveries(context.Background(),
+		models.WebhookDelivery{
+			WebhookId:    id,
+			EventId:      uuid.New(),
+			EventType:    events.POST_CREATED,
+			Payload:      []byte(`{"type":"post.created"}`),
+			Status:       models.DELIVERY_STATUS_DEAD,
+			Attempts:     8,
+			StatusCode:   500,
+			Error:        "500 Internal Server Error",
+			LastAttempt:  now,
+			NextAttempt:  now,
+			CreationDate: now,
+		},
+		models.WebhookDelivery{
+			WebhookId:    id,
+			EventId:      uuid.New(),
+			EventType:    events.COMMENT_CREATED,
+			Payload:      []byte(`{"type":"comment.created"}`),
+			Status:       models.DELIVERY_STATUS_DELIVERED,
+			Attempts:     1,
+			StatusCode:   200,
+			LastAttempt:  now,
+			NextAttempt:  now,
+			CreationDate: now.Add(time.Second),
+		},
+	))
+
+	// Act
+	all := api.Must(WEBHOOK_DELIVERIES, map[string]any{"user": api.Users[0].Id})
+	dead := api.Must(WEBHOOK_DELIVERIES, map[string]any{"user": api.Users[0].Id, "status": "DEAD"})
+	deadId := dead.String("webhooks.data.0.deliveries.data.0.id")
+	retried := api.Must(`mutation ($user: UUID!, $id: UUID!) { retryDelivery(user_id: $user, delivery_id: $id) { status attempts } }`,
+		map[string]any{"user": api.Users[0].Id, "id": deadId})
+	again := api.Do(`mutation ($user: UUID!, $id: UUID!) { retryDelivery(user_id: $user, delivery_id: $id) { status } }`,
+		map[string]any{"user": api.Users[0].Id, "id": deadId})
+
+	// Assert
+	assert.Equal(t, []any{"COMMENT_CREATED", "POST_CREATED"},
+		all.Pluck("webhooks.data.0.deliveries.data", "event"), "Newest first")
+	require.Equal(t, 1, dead.Len("webhooks.data.0.deliveries.data"))
+	assert.Equal(t, "500 Internal Server Error", dead.String("webhooks.data.0.deliveries.data.0.error"))
+	assert.Equal(t, float64(500), dead.Get("webhooks.data.0.deliveries.data.0.status_code"))
+	assert.Equal(t, `{"type":"post.created"}`, dead.String("webhooks.data.0.deliveries.data.0.payload"))
+	assert.Equal(t, "PENDING", retried.String("retryDelivery.status"))
+	assert.Equal(t, float64(0), retried.Get("retryDelivery.attempts"))
+	assert.Len(t, again.Errors, 1, "Only dead deliveries are retried")
+}
+
+const NOTIFICATIONS = `
+    query ($user: UUID!, $after: UUID, $unread: Boolean) {
+        notifications(user_id: $user, after: $after, limit: 2, unread_only: $unread) {
+            data { id kind read post_id comment_id actor { id } }
+            end_id
+        }
+        unreadNotifications(user_id: $user)
+    }
+`
+
+func TestE2ENotifications(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	api := e2e.NewGraphQL(t, 3)
+	post := createPost(api, 0, "title", true)
+	reply := commentPost(api, 1, post, "reply")
+	nested := commentComment(api, 2, reply, "@"+api.Users[0].Email+" look")
+	_, err := api.Events.Dispatch(ctx)
+	require.NoError(t, err)
+
+	// Act
+	first := api.Must(NOTIFICATIONS, map[string]any{"user": api.Users[0].Id})
+	second := api.Must(NOTIFICATIONS, map[string]any{
+		"user":  api.Users[0].Id,
+		"after": first.String("notifications.end_id"),
+	})
+	replied := api.Must(NOTIFICATIONS, map[string]any{"user": api.Users[1].Id})
+	marked := api.Must(`mutation ($user: UUID!, $ids: [UUID!]) { markRead(user_id: $user, ids: $ids) }`,
+		map[string]any{"user": api.Users[0].Id, "ids": []any{first.String("notifications.data.0.id")}})
+	unread := api.Must(NOTIFICATIONS, map[string]any{"user": api.Users[0].Id, "unread": true})
+
+	// Assert
+	assert.Equal(t, []any{"MENTION", "POST_REPLY"}, first.Pluck("notifications.data", "kind"), "Newest first")
+	assert.Equal(t, []any{nested, reply}, first.Pluck("notifications.data", "comment_id"))
+	assert.Equal(t, api.Users[2].Id.String(), first.String("notifications.data.0.actor.id"))
+	assert.Equal(t, post, first.String("notifications.data.1.post_id"))
+	assert.Equal(t, float64(2), first.Get("unreadNotifications"))
+	assert.Zero(t, second.Len("notifications.data"))
+	assert.Equal(t, []any{"COMMENT_REPLY"}, replied.Pluck("notifications.data", "kind"))
+	assert.Equal(t, float64(1), marked.Get("markRead"))
+	assert.Equal(t, []any{reply}, unread.Pluck("notifications.data", "comment_id"))
+	assert.Equal(t, float64(1), unread.Get("unreadNotifications"))
+}
+
+func TestE2ENotificationAdded(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	api := e2e.NewGraphQL(t, 2)
+	post := createPost(api, 0, "title", true)
+	commentPost(api, 1, post, "before")
+	_, err := api.Events.Dispatch(ctx)
+	require.NoError(t, err)
+	subscription := api.Subscribe(
+		`subscription ($user: UUID!) { notificationAdded(user_id: $user) { kind comment_id actor { id } } }`,
+		map[string]any{"user": api.Users[0].Id},
+	)
+	var received struct {
+		NotificationAdded struct {
+			Kind      string `json:"kind"`
+			CommentID string `json:"comment_id"`
+			Actor     struct {
+				ID string `json:"id"`
+			} `json:"actor"`
+		} `json:"notificationAdded"`
+	}
+	done := make(chan error, 1)
+
+	// Act
+	go func() { done <- subscription.Next(&received) }()
+	// The subscription starts asynchronously, so replies keep coming until
+	// one of them is seen
+	replies := make([]any, 0)
+
+	for waiting := true; waiting; {
+		replies = append(replies, commentPost(api, 1, post, "after"))
+		_, err = api.Events.Dispatch(ctx)
+		require.NoError(t, err)
+
+		select {
+		case err = <-done:
+			waiting = false
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "POST_REPLY", received.NotificationAdded.Kind)
+	assert.Contains(t, replies, received.NotificationAdded.CommentID, "Only new notifications are sent")
+	assert.Equal(t, api.Users[1].Id.String(), received.NotificationAdded.Actor.ID)
+}
+
+const FOLLOW = `
+    mutation ($user: UUID!, $target: FollowTarget!, $id: UUID!) {
+        follow(user_id: $user, target: $target, id: $id)
+    }
+`
+
+const FOLLOWED = `
+    query ($user: UUID!, $after: UUID) {
+        feed(user_id: $user, after: $after, limit: 2) { data { id } end_id }
+        watchedThreads(user_id: $user, limit: 10) { data { id } }
+    }
+`
+
+func TestE2EFollows(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 3)
+	posts := []string{
+		createPost(api, 1, "first", true),
+		createPost(api, 2, "second", true),
+		createPost(api, 1, "third", true),
+		createPost(api, 0, "own", true),
+	}
+	follow := func(target string, id any) *e2e.Response {
+		return api.Do(FOLLOW, map[string]any{"user": api.Users[0].Id, "target": target, "id": id})
+	}
+
+	// Act
+	followed := follow("AUTHOR", api.Users[1].Id)
+	again := follow("AUTHOR", api.Users[1].Id)
+	own := follow("AUTHOR", api.Users[0].Id)
+	watched := follow("POST", posts[1])
+	first := api.Must(FOLLOWED, map[string]any{"user": api.Users[0].Id})
+	second := api.Must(FOLLOWED, map[string]any{
+		"user":  api.Users[0].Id,
+		"after": first.String("feed.end_id"),
+	})
+	unfollowed := api.Must(`mutation ($user: UUID!, $id: UUID!) { unfollow(user_id: $user, target: POST, id: $id) }`,
+		map[string]any{"user": api.Users[0].Id, "id": posts[1]})
+	after := api.Must(FOLLOWED, map[string]any{"user": api.Users[0].Id})
+
+	// Assert
+	assert.Equal(t, true, followed.Get("follow"))
+	assert.Equal(t, false, again.Get("follow"), "Followed already")
+	assert.Len(t, own.Errors, 1, "Users can't follow themselves")
+	assert.Equal(t, true, watched.Get("follow"))
+	assert.Equal(t, []any{posts[2], posts[0]}, first.Pluck("feed.data", "id"), "Newest first")
+	assert.Zero(t, second.Len("feed.data"))
+	assert.Equal(t, []any{posts[1]}, first.Pluck("watchedThreads.data", "id"))
+	assert.Equal(t, true, unfollowed.Get("unfollow"))
+	assert.Zero(t, after.Len("watchedThreads.data"))
+	assert.Equal(t, 2, after.Len("feed.data"))
+}
+
+const REVIEW_QUEUE = `
+    query ($moderator: UUID!) {
+        reviewQueue(moderator_id: $moderator, limit: 10) {
+            data { id kind action content_id target_id content reasons author { id } }
+        }
+    }
+`
+
+const RESOLVE_REVIEW = `
+    mutation ($moderator: UUID!, $id: UUID!, $approve: Boolean!) {
+        resolveReview(moderator_id: $moderator, id: $id, approve: $approve)
+    }
+`
+
+func TestE2EModeration(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 2)
+	post := createPost(api, 1, "thread", true)
+	moderator := map[string]any{"moderator": api.Users[0].Id}
+	comment := func(content string) *e2e.Response {
+		return api.Do(COMMENT_POST, map[string]any{
+			"user":    api.Users[1].Id,
+			"post":    post,
+			"content": content,
+		})
+	}
+	resolve := func(id string, approve bool) *e2e.Response {
+		return api.Do(RESOLVE_REVIEW, map[string]any{
+			"moderator": api.Users[0].Id,
+			"id":        id,
+			"approve":   approve,
+		})
+	}
+
+	// Act
+	rejected := comment("an obvious scam")
+	hidden := comment("buy spam here")
+	dismissed := comment("more spam")
+	flagged := comment("a dubious claim")
+	queue := api.Must(REVIEW_QUEUE, moderator)
+	denied := api.Do(REVIEW_QUEUE, map[string]any{"moderator": api.Users[1].Id})
+	approval := resolve(queue.String("reviewQueue.data.0.id"), true)
+	dismissal := resolve(queue.String("reviewQueue.data.1.id"), false)
+	again := resolve(queue.String("reviewQueue.data.1.id"), true)
+	left := api.Must(REVIEW_QUEUE, moderator)
+	published := api.Must(`query ($id: UUID!) { post(id: $id) { comments(limit: 10, order: DATE_ASC) { data { content } } } }`,
+		map[string]any{"id": post})
+
+	// Assert
+	assert.Len(t, rejected.Errors, 1)
+	assert.Contains(t, rejected.Errors[0].Message, "banned words: scam")
+	assert.Len(t, hidden.Errors, 1, "Held for review")
+	assert.Len(t, dismissed.Errors, 1, "Held for review")
+	require.Empty(t, flagged.Errors)
+	assert.Equal(t, []any{"HIDE", "HIDE", "FLAG"}, queue.Pluck("reviewQueue.data", "action"), "Oldest first")
+	assert.Equal(t, []any{nil, nil, flagged.String("commentPost.id")}, queue.Pluck("reviewQueue.data", "content_id"))
+	assert.Equal(t, post, queue.String("reviewQueue.data.0.target_id"))
+	assert.Equal(t, "POST_COMMENT", queue.String("reviewQueue.data.0.kind"))
+	assert.Equal(t, api.Users[1].Id.String(), queue.String("reviewQueue.data.0.author.id"))
+	assert.Len(t, denied.Errors, 1, "Only moderators see the queue")
+	assert.Empty(t, approval.Errors)
+	assert.Empty(t, dismissal.Errors)
+	assert.Len(t, again.Errors, 1, "Resolved already")
+	assert.Equal(t, []any{"FLAG"}, left.Pluck("reviewQueue.data", "action"))
+	assert.Equal(t, []any{"buy spam here", "a dubious claim"}, published.Pluck("post.comments.data", "content"),
+		"Approved comment keeps its date")
+}
+
@@ -27,11 +27,11 @@ func New(user usrsrv.Service) func(
 		}
 
 		if nil == err {
+			defer iter.Close()
 			out = make([]*model.User, len(ids))
 			errs = make([]error, len(ids))
-			i := 0
 
-			for res := range iterator.Values(iter) {
+			for i, res := range iterator.Enumerate(iter) {
 				if v, err := res.Unwrap(); nil == err {
 					out[i] = mappers.MapUser(&v)
 					errs[i] = nil
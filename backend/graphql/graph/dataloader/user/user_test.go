@@ -0,0 +1,47 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/graphql/graph/dataloader/user"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	mock_user "github.com/muji40k/ozontestcomms/internal/service/mock/user"
+	"github.com/muji40k/ozontestcomms/misc/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLoaderKeepsOrderOfIds(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	service := mock_user.NewMockService(ctrl)
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	failed := errors.New("not found")
+	service.EXPECT().
+		GetUsersById(gomock.Any(), ids[0], ids[1], ids[2]).
+		Return(collection.Slice([]result.Result[models.User]{
+			result.Ok(models.User{Id: ids[0], Email: "first@poster.test"}),
+			result.Err[models.User](failed),
+			result.Ok(models.User{Id: ids[2], Email: "third@poster.test"}),
+		}), nil).
+		Times(1)
+
+	// Act
+	users, errs := user.New(service)(context.Background(), ids)
+
+	// Assert
+	require.Len(t, users, 3)
+	require.Len(t, errs, 3)
+	assert.Equal(t, ids[0], users[0].ID)
+	assert.NoError(t, errs[0])
+	assert.Nil(t, users[1])
+	assert.ErrorIs(t, errs[1], failed)
+	assert.Equal(t, ids[2], users[2].ID, "Every user has its own index")
+	assert.NoError(t, errs[2])
+}
+
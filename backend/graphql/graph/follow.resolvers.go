@@ -0,0 +1,126 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.74
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/graphql/graph/mappers"
+	"github.com/muji40k/ozontestcomms/graphql/graph/model"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// Follow is the resolver for the follow field.
+func (r *mutationResolver) Follow(
+	ctx context.Context,
+	userID uuid.UUID,
+	target model.FollowTarget,
+	id uuid.UUID,
+) (bool, error) {
+	if nil == r.services.follow {
+		return false, errNoFollows
+	}
+
+	return r.services.follow.Follow(ctx, userID, mappers.UnmapFollowTarget(target), id)
+}
+
+// Unfollow is the resolver for the unfollow field.
+func (r *mutationResolver) Unfollow(
+	ctx context.Context,
+	userID uuid.UUID,
+	target model.FollowTarget,
+	id uuid.UUID,
+) (bool, error) {
+	if nil == r.services.follow {
+		return false, errNoFollows
+	}
+
+	return r.services.follow.Unfollow(ctx, userID, mappers.UnmapFollowTarget(target), id)
+}
+
+// Feed is the resolver for the feed field.
+func (r *queryResolver) Feed(
+	ctx context.Context,
+	userID uuid.UUID,
+	after *uuid.UUID,
+	limit int32,
+) (*model.PostCursor, error) {
+	if nil == r.services.follow {
+		return nil, errNoFollows
+	}
+
+	var out *model.PostCursor
+	col, err := r.services.follow.GetFeed(ctx, userID)
+
+	if nil == err {
+		err = pagination.Apply(col, after, limit)
+	}
+
+	if nil == err {
+		out = new(model.PostCursor)
+		out.Data, err = pagination.Collect(collection.Map(col,
+			func(v *result.Result[models.Post]) result.Result[*model.Post] {
+				return result.Map(v, mappers.MapPost)
+			},
+		))
+	}
+
+	if nil == err {
+		if l := len(out.Data); 0 != l {
+			out.EndID = &out.Data[l-1].ID
+		}
+	}
+
+	if nil != err {
+		out = nil
+	}
+
+	return out, err
+}
+
+// WatchedThreads is the resolver for the watchedThreads field.
+func (r *queryResolver) WatchedThreads(
+	ctx context.Context,
+	userID uuid.UUID,
+	after *uuid.UUID,
+	limit int32,
+) (*model.PostCursor, error) {
+	if nil == r.services.follow {
+		return nil, errNoFollows
+	}
+
+	var out *model.PostCursor
+	col, err := r.services.follow.GetWatchedPosts(ctx, userID)
+
+	if nil == err {
+		err = pagination.Apply(col, after, limit)
+	}
+
+	if nil == err {
+		out = new(model.PostCursor)
+		out.Data, err = pagination.Collect(collection.Map(col,
+			func(v *result.Result[models.Post]) result.Result[*model.Post] {
+				return result.Map(v, mappers.MapPost)
+			},
+		))
+	}
+
+	if nil == err {
+		if l := len(out.Data); 0 != l {
+			out.EndID = &out.Data[l-1].ID
+		}
+	}
+
+	if nil != err {
+		out = nil
+	}
+
+	return out, err
+}
+
@@ -0,0 +1,18 @@
+package mappers
+
+import (
+	"github.com/muji40k/ozontestcomms/graphql/graph/model"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/follow"
+)
+
+func UnmapFollowTarget(target model.FollowTarget) follow.FollowKind {
+	switch target {
+	case model.FollowTargetAuthor:
+		return follow.FOLLOW_KIND_AUTHOR
+	case model.FollowTargetPost:
+		return follow.FOLLOW_KIND_POST
+	default:
+		panic("Unknown follow target")
+	}
+}
+
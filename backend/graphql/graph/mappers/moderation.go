@@ -0,0 +1,56 @@
+package mappers
+
+import (
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/graphql/graph/model"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+)
+
+func MapModerationAction(action models.ModerationAction) model.ModerationAction {
+	switch action {
+	case models.MODERATION_ACTION_FLAG:
+		return model.ModerationActionFlag
+	case models.MODERATION_ACTION_HIDE:
+		return model.ModerationActionHide
+	default:
+		panic("Unknown moderation action")
+	}
+}
+
+func MapReviewKind(kind models.ReviewKind) model.ReviewKind {
+	switch kind {
+	case models.REVIEW_KIND_POST:
+		return model.ReviewKindPost
+	case models.REVIEW_KIND_POST_COMMENT:
+		return model.ReviewKindPostComment
+	case models.REVIEW_KIND_COMMENT_COMMENT:
+		return model.ReviewKindCommentComment
+	default:
+		panic("Unknown review kind")
+	}
+}
+
+func mapOptionalId(id uuid.UUID) *uuid.UUID {
+	if (uuid.UUID{}) == id {
+		return nil
+	}
+
+	return &id
+}
+
+func MapReview(value *models.Review) *model.Review {
+	return &model.Review{
+		ID:              value.Id,
+		Kind:            MapReviewKind(value.Kind),
+		Action:          MapModerationAction(value.Action),
+		ContentID:       mapOptionalId(value.ContentId),
+		AuthorId:        value.AuthorId,
+		TargetID:        mapOptionalId(value.TargetId),
+		Title:           value.Title,
+		Content:         value.Content,
+		CommentsAllowed: value.CommentsAllowed,
+		Reasons:         value.Reasons,
+		CreatedAt:       value.CreationDate,
+	}
+}
+
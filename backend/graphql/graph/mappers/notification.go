@@ -0,0 +1,32 @@
+package mappers
+
+import (
+	"github.com/muji40k/ozontestcomms/graphql/graph/model"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+)
+
+func MapNotificationKind(kind models.NotificationKind) model.NotificationKind {
+	switch kind {
+	case models.NOTIFICATION_KIND_POST_REPLY:
+		return model.NotificationKindPostReply
+	case models.NOTIFICATION_KIND_COMMENT_REPLY:
+		return model.NotificationKindCommentReply
+	case models.NOTIFICATION_KIND_MENTION:
+		return model.NotificationKindMention
+	default:
+		panic("Unknown notification kind")
+	}
+}
+
+func MapNotification(value *models.Notification) *model.Notification {
+	return &model.Notification{
+		ID:        value.Id,
+		Kind:      MapNotificationKind(value.Kind),
+		ActorId:   value.ActorId,
+		PostID:    value.PostId,
+		CommentID: value.CommentId,
+		Read:      value.Read,
+		CreatedAt: value.CreationDate,
+	}
+}
+
@@ -0,0 +1,132 @@
+package mappers
+
+import (
+	"github.com/muji40k/ozontestcomms/graphql/graph/model"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/webhook"
+)
+
+var webhookEvents = map[model.WebhookEvent]string{
+	model.WebhookEventPostCreated:    events.POST_CREATED,
+	model.WebhookEventPostUpdated:    events.POST_UPDATED,
+	model.WebhookEventCommentCreated: events.COMMENT_CREATED,
+}
+
+func MapWebhookEvent(kind string) model.WebhookEvent {
+	for k, v := range webhookEvents {
+		if kind == v {
+			return k
+		}
+	}
+
+	panic("Unknown webhook event")
+}
+
+func UnmapWebhookEvent(kind model.WebhookEvent) string {
+	if v, found := webhookEvents[kind]; found {
+		return v
+	}
+
+	panic("Unknown webhook event")
+}
+
+func MapDeliveryStatus(status models.DeliveryStatus) model.DeliveryStatus {
+	switch status {
+	case models.DELIVERY_STATUS_PENDING:
+		return model.DeliveryStatusPending
+	case models.DELIVERY_STATUS_DELIVERED:
+		return model.DeliveryStatusDelivered
+	case models.DELIVERY_STATUS_DEAD:
+		return model.DeliveryStatusDead
+	default:
+		panic("Unknown delivery status")
+	}
+}
+
+// No filter if status isn't set
+func UnmapDeliveryStatus(status *model.DeliveryStatus) []models.DeliveryStatus {
+	if nil == status {
+		return nil
+	}
+
+	switch *status {
+	case model.DeliveryStatusPending:
+		return []models.DeliveryStatus{models.DELIVERY_STATUS_PENDING}
+	case model.DeliveryStatusDelivered:
+		return []models.DeliveryStatus{models.DELIVERY_STATUS_DELIVERED}
+	case model.DeliveryStatusDead:
+		return []models.DeliveryStatus{models.DELIVERY_STATUS_DEAD}
+	default:
+		return nil
+	}
+}
+
+func UnmapCreateWebhookInput(input *model.CreateWebhookInput) webhook.WebhookCreationForm {
+	kinds := make([]string, len(input.Events))
+
+	for i, v := range input.Events {
+		kinds[i] = UnmapWebhookEvent(v)
+	}
+
+	return webhook.WebhookCreationForm{
+		Url:    input.URL,
+		Events: kinds,
+		PostId: input.PostID,
+	}
+}
+
+// Secret is left out, it's shown only once on creation
+func MapWebhook(value *models.Webhook) *model.Webhook {
+	kinds := make([]model.WebhookEvent, len(value.Events))
+
+	for i, v := range value.Events {
+		kinds[i] = MapWebhookEvent(v)
+	}
+
+	out := &model.Webhook{
+		ID:        value.Id,
+		OwnerId:   value.OwnerId,
+		URL:       value.Url,
+		Events:    kinds,
+		CreatedAt: value.CreationDate,
+	}
+
+	if value.PostId.Valid {
+		out.PostID = &value.PostId.UUID
+	}
+
+	return out
+}
+
+func MapDelivery(value *models.WebhookDelivery) *model.Delivery {
+	out := &model.Delivery{
+		ID:        value.Id,
+		EventID:   value.EventId,
+		Event:     MapWebhookEvent(value.EventType),
+		Status:    MapDeliveryStatus(value.Status),
+		Attempts:  int32(value.Attempts),
+		CreatedAt: value.CreationDate,
+		Payload:   string(value.Payload),
+	}
+
+	if 0 != value.Attempts {
+		out.LastAttemptAt = &value.LastAttempt
+
+		if 0 != value.StatusCode {
+			code := int32(value.StatusCode)
+			out.StatusCode = &code
+		}
+
+		if "" != value.Error {
+			out.Error = &value.Error
+		}
+	}
+
+	if models.DELIVERY_STATUS_PENDING == value.Status {
+		out.NextAttemptAt = &value.NextAttempt
+	}
+
+	return out
+}
+
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -34,9 +35,45 @@ type CreatePostInput struct {
 	AllowComments *bool  `json:"allow_comments,omitempty"`
 }
 
+type CreateWebhookInput struct {
+	URL    string         `json:"url"`
+	Events []WebhookEvent `json:"events"`
+	PostID *uuid.UUID     `json:"post_id,omitempty"`
+}
+
+type Delivery struct {
+	ID            uuid.UUID      `json:"id"`
+	EventID       uuid.UUID      `json:"event_id"`
+	Event         WebhookEvent   `json:"event"`
+	Status        DeliveryStatus `json:"status"`
+	Attempts      int32          `json:"attempts"`
+	StatusCode    *int32         `json:"status_code,omitempty"`
+	Error         *string        `json:"error,omitempty"`
+	LastAttemptAt *time.Time     `json:"last_attempt_at,omitempty"`
+	NextAttemptAt *time.Time     `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Payload       string         `json:"payload"`
+}
+
+type DeliveryCursor struct {
+	Data  []*Delivery `json:"data"`
+	EndID *uuid.UUID  `json:"end_id,omitempty"`
+}
+
+func (DeliveryCursor) IsCursor()                 {}
+func (this DeliveryCursor) GetEndID() *uuid.UUID { return this.EndID }
+
 type Mutation struct {
 }
 
+type NotificationCursor struct {
+	Data  []*Notification `json:"data"`
+	EndID *uuid.UUID      `json:"end_id,omitempty"`
+}
+
+func (NotificationCursor) IsCursor()                 {}
+func (this NotificationCursor) GetEndID() *uuid.UUID { return this.EndID }
+
 type PostCursor struct {
 	Data  []*Post    `json:"data"`
 	EndID *uuid.UUID `json:"end_id,omitempty"`
@@ -52,11 +89,30 @@ type PostModificationInput struct {
 type Query struct {
 }
 
+type ReviewCursor struct {
+	Data  []*Review  `json:"data"`
+	EndID *uuid.UUID `json:"end_id,omitempty"`
+}
+
+func (ReviewCursor) IsCursor()                 {}
+func (this ReviewCursor) GetEndID() *uuid.UUID { return this.EndID }
+
+type Subscription struct {
+}
+
 type User struct {
 	ID    uuid.UUID `json:"id"`
 	Email string    `json:"email"`
 }
 
+type WebhookCursor struct {
+	Data  []*Webhook `json:"data"`
+	EndID *uuid.UUID `json:"end_id,omitempty"`
+}
+
+func (WebhookCursor) IsCursor()                 {}
+func (this WebhookCursor) GetEndID() *uuid.UUID { return this.EndID }
+
 type CommentOrder string
 
 const (
@@ -112,6 +168,230 @@ func (e CommentOrder) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "PENDING"
+	DeliveryStatusDelivered DeliveryStatus = "DELIVERED"
+	DeliveryStatusDead      DeliveryStatus = "DEAD"
+)
+
+var AllDeliveryStatus = []DeliveryStatus{
+	DeliveryStatusPending,
+	DeliveryStatusDelivered,
+	DeliveryStatusDead,
+}
+
+func (e DeliveryStatus) IsValid() bool {
+	switch e {
+	case DeliveryStatusPending, DeliveryStatusDelivered, DeliveryStatusDead:
+		return true
+	}
+	return false
+}
+
+func (e DeliveryStatus) String() string {
+	return string(e)
+}
+
+func (e *DeliveryStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeliveryStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeliveryStatus", str)
+	}
+	return nil
+}
+
+func (e DeliveryStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DeliveryStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DeliveryStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type FollowTarget string
+
+const (
+	FollowTargetAuthor FollowTarget = "AUTHOR"
+	FollowTargetPost   FollowTarget = "POST"
+)
+
+var AllFollowTarget = []FollowTarget{
+	FollowTargetAuthor,
+	FollowTargetPost,
+}
+
+func (e FollowTarget) IsValid() bool {
+	switch e {
+	case FollowTargetAuthor, FollowTargetPost:
+		return true
+	}
+	return false
+}
+
+func (e FollowTarget) String() string {
+	return string(e)
+}
+
+func (e *FollowTarget) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FollowTarget(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FollowTarget", str)
+	}
+	return nil
+}
+
+func (e FollowTarget) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *FollowTarget) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e FollowTarget) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ModerationAction string
+
+const (
+	ModerationActionFlag ModerationAction = "FLAG"
+	ModerationActionHide ModerationAction = "HIDE"
+)
+
+var AllModerationAction = []ModerationAction{
+	ModerationActionFlag,
+	ModerationActionHide,
+}
+
+func (e ModerationAction) IsValid() bool {
+	switch e {
+	case ModerationActionFlag, ModerationActionHide:
+		return true
+	}
+	return false
+}
+
+func (e ModerationAction) String() string {
+	return string(e)
+}
+
+func (e *ModerationAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ModerationAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ModerationAction", str)
+	}
+	return nil
+}
+
+func (e ModerationAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ModerationAction) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ModerationAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type NotificationKind string
+
+const (
+	NotificationKindPostReply    NotificationKind = "POST_REPLY"
+	NotificationKindCommentReply NotificationKind = "COMMENT_REPLY"
+	NotificationKindMention      NotificationKind = "MENTION"
+)
+
+var AllNotificationKind = []NotificationKind{
+	NotificationKindPostReply,
+	NotificationKindCommentReply,
+	NotificationKindMention,
+}
+
+func (e NotificationKind) IsValid() bool {
+	switch e {
+	case NotificationKindPostReply, NotificationKindCommentReply, NotificationKindMention:
+		return true
+	}
+	return false
+}
+
+func (e NotificationKind) String() string {
+	return string(e)
+}
+
+func (e *NotificationKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NotificationKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NotificationKind", str)
+	}
+	return nil
+}
+
+func (e NotificationKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *NotificationKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e NotificationKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type PostOrder string
 
 const (
@@ -166,3 +446,117 @@ func (e PostOrder) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+type ReviewKind string
+
+const (
+	ReviewKindPost           ReviewKind = "POST"
+	ReviewKindPostComment    ReviewKind = "POST_COMMENT"
+	ReviewKindCommentComment ReviewKind = "COMMENT_COMMENT"
+)
+
+var AllReviewKind = []ReviewKind{
+	ReviewKindPost,
+	ReviewKindPostComment,
+	ReviewKindCommentComment,
+}
+
+func (e ReviewKind) IsValid() bool {
+	switch e {
+	case ReviewKindPost, ReviewKindPostComment, ReviewKindCommentComment:
+		return true
+	}
+	return false
+}
+
+func (e ReviewKind) String() string {
+	return string(e)
+}
+
+func (e *ReviewKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReviewKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReviewKind", str)
+	}
+	return nil
+}
+
+func (e ReviewKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReviewKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReviewKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WebhookEvent string
+
+const (
+	WebhookEventPostCreated    WebhookEvent = "POST_CREATED"
+	WebhookEventPostUpdated    WebhookEvent = "POST_UPDATED"
+	WebhookEventCommentCreated WebhookEvent = "COMMENT_CREATED"
+)
+
+var AllWebhookEvent = []WebhookEvent{
+	WebhookEventPostCreated,
+	WebhookEventPostUpdated,
+	WebhookEventCommentCreated,
+}
+
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookEventPostCreated, WebhookEventPostUpdated, WebhookEventCommentCreated:
+		return true
+	}
+	return false
+}
+
+func (e WebhookEvent) String() string {
+	return string(e)
+}
+
+func (e *WebhookEvent) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookEvent(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookEvent", str)
+	}
+	return nil
+}
+
+func (e WebhookEvent) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookEvent) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookEvent) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Notification struct {
+	ID        uuid.UUID        `json:"id"`
+	Kind      NotificationKind `json:"kind"`
+	ActorId   uuid.UUID        `json:"-"`
+	Actor     *User            `json:"actor"`
+	PostID    uuid.UUID        `json:"post_id"`
+	CommentID uuid.UUID        `json:"comment_id"`
+	Read      bool             `json:"read"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Review struct {
+	ID              uuid.UUID        `json:"id"`
+	Kind            ReviewKind       `json:"kind"`
+	Action          ModerationAction `json:"action"`
+	ContentID       *uuid.UUID       `json:"content_id,omitempty"`
+	AuthorId        uuid.UUID        `json:"-"`
+	Author          *User            `json:"author"`
+	TargetID        *uuid.UUID       `json:"target_id,omitempty"`
+	Title           string           `json:"title"`
+	Content         string           `json:"content"`
+	CommentsAllowed bool             `json:"comments_allowed"`
+	Reasons         []string         `json:"reasons"`
+	CreatedAt       time.Time        `json:"created_at"`
+}
+
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Webhook struct {
+	ID         uuid.UUID       `json:"id"`
+	OwnerId    uuid.UUID       `json:"-"`
+	URL        string          `json:"url"`
+	Events     []WebhookEvent  `json:"events"`
+	PostID     *uuid.UUID      `json:"post_id,omitempty"`
+	Secret     *string         `json:"secret,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Deliveries *DeliveryCursor `json:"deliveries"`
+}
+
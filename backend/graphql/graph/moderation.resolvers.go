@@ -0,0 +1,102 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.74
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/graphql/graph/dataloader"
+	"github.com/muji40k/ozontestcomms/graphql/graph/mappers"
+	"github.com/muji40k/ozontestcomms/graphql/graph/model"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/singlewrap"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// ResolveReview is the resolver for the resolveReview field.
+func (r *mutationResolver) ResolveReview(
+	ctx context.Context,
+	moderatorID uuid.UUID,
+	id uuid.UUID,
+	approve bool,
+) (bool, error) {
+	if nil == r.services.moderation {
+		return false, errNoModeration
+	}
+
+	err := r.services.moderation.ResolveReview(ctx, moderatorID, id, approve)
+
+	return nil == err, err
+}
+
+// ReviewQueue is the resolver for the reviewQueue field.
+func (r *queryResolver) ReviewQueue(
+	ctx context.Context,
+	moderatorID uuid.UUID,
+	after *uuid.UUID,
+	limit int32,
+) (*model.ReviewCursor, error) {
+	if nil == r.services.moderation {
+		return nil, errNoModeration
+	}
+
+	var out *model.ReviewCursor
+	col, err := r.services.moderation.GetReviews(ctx, moderatorID)
+
+	if nil == err {
+		err = pagination.Apply(col, after, limit)
+	}
+
+	if nil == err {
+		out = new(model.ReviewCursor)
+		out.Data, err = pagination.Collect(collection.Map(col,
+			func(v *result.Result[models.Review]) result.Result[*model.Review] {
+				return result.Map(v, mappers.MapReview)
+			},
+		))
+	}
+
+	if nil == err {
+		if l := len(out.Data); 0 != l {
+			out.EndID = &out.Data[l-1].ID
+		}
+	}
+
+	if nil != err {
+		out = nil
+	}
+
+	return out, err
+}
+
+// Author is the resolver for the author field.
+func (r *reviewResolver) Author(
+	ctx context.Context,
+	obj *model.Review,
+) (*model.User, error) {
+	if loader, found := dataloader.For(ctx); found {
+		return loader.User.Load(ctx, obj.AuthorId)
+	} else {
+		res, err := singlewrap.Unwrap(
+			r.services.user.GetUsersById(ctx, obj.AuthorId),
+		)
+
+		if nil != err {
+			return nil, err
+		} else {
+			r := result.Map(&res, mappers.MapUser)
+			return r.Unwrap()
+		}
+	}
+}
+
+// Review returns ReviewResolver implementation.
+func (r *Resolver) Review() ReviewResolver { return &reviewResolver{r} }
+
+type reviewResolver struct{ *Resolver }
+
@@ -0,0 +1,184 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.74
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/graphql/graph/dataloader"
+	"github.com/muji40k/ozontestcomms/graphql/graph/mappers"
+	"github.com/muji40k/ozontestcomms/graphql/graph/model"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/singlewrap"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/notification"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// MarkRead is the resolver for the markRead field.
+func (r *mutationResolver) MarkRead(
+	ctx context.Context,
+	userID uuid.UUID,
+	ids []uuid.UUID,
+) (int32, error) {
+	if nil == r.services.notification {
+		return 0, errNoNotifications
+	}
+
+	marked, err := r.services.notification.MarkRead(ctx, userID, ids...)
+
+	return int32(marked), err
+}
+
+// Actor is the resolver for the actor field.
+func (r *notificationResolver) Actor(
+	ctx context.Context,
+	obj *model.Notification,
+) (*model.User, error) {
+	if loader, found := dataloader.For(ctx); found {
+		return loader.User.Load(ctx, obj.ActorId)
+	} else {
+		res, err := singlewrap.Unwrap(
+			r.services.user.GetUsersById(ctx, obj.ActorId),
+		)
+
+		if nil != err {
+			return nil, err
+		} else {
+			r := result.Map(&res, mappers.MapUser)
+			return r.Unwrap()
+		}
+	}
+}
+
+// Notifications is the resolver for the notifications field.
+func (r *queryResolver) Notifications(
+	ctx context.Context,
+	userID uuid.UUID,
+	after *uuid.UUID,
+	limit int32,
+	unreadOnly *bool,
+) (*model.NotificationCursor, error) {
+	if nil == r.services.notification {
+		return nil, errNoNotifications
+	}
+
+	var out *model.NotificationCursor
+	col, err := r.services.notification.GetNotifications(
+		ctx,
+		userID,
+		nil != unreadOnly && *unreadOnly,
+		notification.NOTIFICATION_ORDER_DATE_DESC,
+	)
+
+	if nil == err {
+		err = pagination.Apply(col, after, limit)
+	}
+
+	if nil == err {
+		out = new(model.NotificationCursor)
+		out.Data, err = pagination.Collect(collection.Map(col,
+			func(v *result.Result[models.Notification]) result.Result[*model.Notification] {
+				return result.Map(v, mappers.MapNotification)
+			},
+		))
+	}
+
+	if nil == err {
+		if l := len(out.Data); 0 != l {
+			out.EndID = &out.Data[l-1].ID
+		}
+	}
+
+	if nil != err {
+		out = nil
+	}
+
+	return out, err
+}
+
+// UnreadNotifications is the resolver for the unreadNotifications field.
+func (r *queryResolver) UnreadNotifications(
+	ctx context.Context,
+	userID uuid.UUID,
+) (int32, error) {
+	if nil == r.services.notification {
+		return 0, errNoNotifications
+	}
+
+	unread, err := r.services.notification.CountUnread(ctx, userID)
+
+	return int32(unread), err
+}
+
+// NotificationAdded is the resolver for the notificationAdded field.
+func (r *subscriptionResolver) NotificationAdded(
+	ctx context.Context,
+	userID uuid.UUID,
+) (<-chan *model.Notification, error) {
+	if nil == r.services.notification {
+		return nil, errNoNotifications
+	}
+
+	var latest []models.Notification
+	var cursor *uuid.UUID
+	col, err := r.services.notification.GetNotifications(
+		ctx,
+		userID,
+		false,
+		notification.NOTIFICATION_ORDER_DATE_DESC,
+	)
+
+	if nil == err {
+		col.Limit(1)
+		latest, err = pagination.Collect(col)
+	}
+
+	if nil != err {
+		return nil, err
+	} else if 0 != len(latest) {
+		cursor = &latest[0].Id
+	}
+
+	return watch(
+		ctx,
+		r.watch,
+		cursor,
+		func(ctx context.Context, after *uuid.UUID) ([]models.Notification, error) {
+			col, err := r.services.notification.GetNotifications(
+				ctx,
+				userID,
+				false,
+				notification.NOTIFICATION_ORDER_DATE_ASC,
+			)
+
+			if nil == err {
+				err = pagination.Apply(col, after, WATCH_BATCH_SIZE)
+			}
+
+			if nil == err {
+				return pagination.Collect(col)
+			} else {
+				return nil, err
+			}
+		},
+		func(v *models.Notification) uuid.UUID {
+			return v.Id
+		},
+		mappers.MapNotification,
+	), nil
+}
+
+// Notification returns NotificationResolver implementation.
+func (r *Resolver) Notification() NotificationResolver { return &notificationResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+type notificationResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+
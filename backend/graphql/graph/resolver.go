@@ -7,26 +7,55 @@ package graph
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 import (
+	"errors"
+	"time"
+
 	commsrv "github.com/muji40k/ozontestcomms/internal/service/interface/comment"
+	followsrv "github.com/muji40k/ozontestcomms/internal/service/interface/follow"
+	moderationsrv "github.com/muji40k/ozontestcomms/internal/service/interface/moderation"
+	notifsrv "github.com/muji40k/ozontestcomms/internal/service/interface/notification"
 	postsrv "github.com/muji40k/ozontestcomms/internal/service/interface/post"
 	usrsrv "github.com/muji40k/ozontestcomms/internal/service/interface/user"
+	webhooksrv "github.com/muji40k/ozontestcomms/internal/service/interface/webhook"
 )
 
 type services struct {
 	user    usrsrv.Service
 	comment commsrv.Service
 	post    postsrv.Service
+	// Optional, webhook fields fail without it
+	webhook webhooksrv.Service
+	// Optional, notification fields fail without it
+	notification notifsrv.Service
+	// Optional, follow fields fail without it
+	follow followsrv.Service
+	// Optional, review queue fields fail without it
+	moderation moderationsrv.Service
 }
 
+var (
+	errNoWebhooks      = errors.New("Webhooks are not available")
+	errNoNotifications = errors.New("Notifications are not available")
+	errNoFollows       = errors.New("Follows are not available")
+	errNoModeration    = errors.New("Moderation is not available")
+)
+
 type Resolver struct {
 	services services
+	// Subscriptions poll the services this often
+	watch time.Duration
 }
 
 func NewResolver(
 	user usrsrv.Service,
 	comment commsrv.Service,
 	post postsrv.Service,
+	webhook webhooksrv.Service,
+	notification notifsrv.Service,
+	follow followsrv.Service,
+	moderation moderationsrv.Service,
+	watch time.Duration,
 ) Resolver {
-	return Resolver{services{user, comment, post}}
+	return Resolver{services{user, comment, post, webhook, notification, follow, moderation}, watch}
 }
 
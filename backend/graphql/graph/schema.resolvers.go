@@ -11,9 +11,9 @@ import (
 	"github.com/muji40k/ozontestcomms/graphql/graph/dataloader"
 	"github.com/muji40k/ozontestcomms/graphql/graph/mappers"
 	"github.com/muji40k/ozontestcomms/graphql/graph/model"
-	"github.com/muji40k/ozontestcomms/graphql/graph/pagination"
 	"github.com/muji40k/ozontestcomms/internal/domain/models"
 	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
 	"github.com/muji40k/ozontestcomms/internal/service/helpers/singlewrap"
 	"github.com/muji40k/ozontestcomms/misc/result"
 )
@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Most items sent by a subscription between two polls
+const WATCH_BATCH_SIZE int32 = 100
+
+// Items after the cursor in creation order, at most WATCH_BATCH_SIZE
+type watchNext[T any] func(ctx context.Context, after *uuid.UUID) ([]T, error)
+
+// Polls for items after the cursor every interval and sends them until the
+// subscription ends, the channel is closed then. Polling stops on the first
+// error, since the cursor can't be trusted anymore
+func watch[T any, M any](
+	ctx context.Context,
+	interval time.Duration,
+	cursor *uuid.UUID,
+	next watchNext[T],
+	id func(*T) uuid.UUID,
+	mapper func(*T) M,
+) <-chan M {
+	out := make(chan M)
+
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var err error
+
+		for nil == err {
+			var items []T
+			items, err = next(ctx, cursor)
+
+			for i := 0; nil == err && len(items) > i; i++ {
+				select {
+				case out <- mapper(&items[i]):
+					cursor = new(uuid.UUID)
+					*cursor = id(&items[i])
+				case <-ctx.Done():
+					err = ctx.Err()
+				}
+			}
+
+			// Full batch means there might be more pending right away
+			if nil == err && WATCH_BATCH_SIZE > int32(len(items)) {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					err = ctx.Err()
+				}
+			}
+		}
+
+		if nil == ctx.Err() {
+			log.Printf("subscription stopped: %v", err)
+		}
+	}()
+
+	return out
+}
+
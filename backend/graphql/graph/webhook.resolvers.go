@@ -0,0 +1,168 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.74
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/graphql/graph/mappers"
+	"github.com/muji40k/ozontestcomms/graphql/graph/model"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// CreateWebhook is the resolver for the createWebhook field.
+func (r *mutationResolver) CreateWebhook(
+	ctx context.Context,
+	userID uuid.UUID,
+	input model.CreateWebhookInput,
+) (*model.Webhook, error) {
+	if nil == r.services.webhook {
+		return nil, errNoWebhooks
+	}
+
+	webhook, err := r.services.webhook.CreateWebhook(
+		ctx,
+		userID,
+		mappers.UnmapCreateWebhookInput(&input),
+	)
+
+	if nil == err {
+		out := mappers.MapWebhook(&webhook)
+		out.Secret = &webhook.Secret
+		return out, nil
+	} else {
+		return nil, err
+	}
+}
+
+// DeleteWebhook is the resolver for the deleteWebhook field.
+func (r *mutationResolver) DeleteWebhook(
+	ctx context.Context,
+	userID uuid.UUID,
+	webhookID uuid.UUID,
+) (bool, error) {
+	if nil == r.services.webhook {
+		return false, errNoWebhooks
+	}
+
+	err := r.services.webhook.DeleteWebhook(ctx, userID, webhookID)
+
+	return nil == err, err
+}
+
+// RetryDelivery is the resolver for the retryDelivery field.
+func (r *mutationResolver) RetryDelivery(
+	ctx context.Context,
+	userID uuid.UUID,
+	deliveryID uuid.UUID,
+) (*model.Delivery, error) {
+	if nil == r.services.webhook {
+		return nil, errNoWebhooks
+	}
+
+	delivery, err := r.services.webhook.RetryDelivery(ctx, userID, deliveryID)
+
+	if nil == err {
+		return mappers.MapDelivery(&delivery), nil
+	} else {
+		return nil, err
+	}
+}
+
+// Webhooks is the resolver for the webhooks field.
+func (r *queryResolver) Webhooks(
+	ctx context.Context,
+	userID uuid.UUID,
+	after *uuid.UUID,
+	limit int32,
+) (*model.WebhookCursor, error) {
+	if nil == r.services.webhook {
+		return nil, errNoWebhooks
+	}
+
+	var out *model.WebhookCursor
+	col, err := r.services.webhook.GetWebhooks(ctx, userID)
+
+	if nil == err {
+		err = pagination.Apply(col, after, limit)
+	}
+
+	if nil == err {
+		out = new(model.WebhookCursor)
+		out.Data, err = pagination.Collect(collection.Map(col,
+			func(v *result.Result[models.Webhook]) result.Result[*model.Webhook] {
+				return result.Map(v, mappers.MapWebhook)
+			},
+		))
+	}
+
+	if nil == err {
+		if l := len(out.Data); 0 != l {
+			out.EndID = &out.Data[l-1].ID
+		}
+	}
+
+	if nil != err {
+		out = nil
+	}
+
+	return out, err
+}
+
+// Deliveries is the resolver for the deliveries field.
+func (r *webhookResolver) Deliveries(
+	ctx context.Context,
+	obj *model.Webhook,
+	after *uuid.UUID,
+	limit int32,
+	status *model.DeliveryStatus,
+) (*model.DeliveryCursor, error) {
+	if nil == r.services.webhook {
+		return nil, errNoWebhooks
+	}
+
+	var out *model.DeliveryCursor
+	col, err := r.services.webhook.GetDeliveries(
+		ctx,
+		obj.OwnerId,
+		obj.ID,
+		mappers.UnmapDeliveryStatus(status)...,
+	)
+
+	if nil == err {
+		err = pagination.Apply(col, after, limit)
+	}
+
+	if nil == err {
+		out = new(model.DeliveryCursor)
+		out.Data, err = pagination.Collect(collection.Map(col,
+			func(v *result.Result[models.WebhookDelivery]) result.Result[*model.Delivery] {
+				return result.Map(v, mappers.MapDelivery)
+			},
+		))
+	}
+
+	if nil == err {
+		if l := len(out.Data); 0 != l {
+			out.EndID = &out.Data[l-1].ID
+		}
+	}
+
+	if nil != err {
+		out = nil
+	}
+
+	return out, err
+}
+
+// Webhook returns WebhookResolver implementation.
+func (r *Resolver) Webhook() WebhookResolver { return &webhookResolver{r} }
+
+type webhookResolver struct{ *Resolver }
+
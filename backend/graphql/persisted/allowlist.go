@@ -0,0 +1,50 @@
+package persisted
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const ERROR_CODE_NOT_REGISTERED = "PERSISTED_QUERY_NOT_REGISTERED"
+
+// Rejects operations missing in the registry. Must be used after
+// extension.AutomaticPersistedQuery, which resolves hashes into queries
+type Allowlist struct {
+	Registry *Registry
+}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.HandlerExtension
+} = Allowlist{}
+
+func (self Allowlist) ExtensionName() string {
+	return "PersistedQueryAllowlist"
+}
+
+func (self Allowlist) Validate(schema graphql.ExecutableSchema) error {
+	if nil == self.Registry {
+		return errors.New("Allowlist.Registry can not be nil")
+	}
+
+	return nil
+}
+
+func (self Allowlist) MutateOperationParameters(
+	ctx context.Context,
+	params *graphql.RawParams,
+) *gqlerror.Error {
+	if _, found := self.Registry.Operation(Hash(params.Query)); found {
+		return nil
+	}
+
+	err := gqlerror.Errorf("operation is not registered")
+	errcode.Set(err, ERROR_CODE_NOT_REGISTERED)
+
+	return err
+}
+
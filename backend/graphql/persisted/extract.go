@@ -0,0 +1,156 @@
+package persisted
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Documents are the files themselves
+var DOCUMENT_EXTENSIONS = []string{".graphql", ".gql"}
+
+// Documents are the gql`...` and graphql`...` tagged templates
+var SOURCE_EXTENSIONS = []string{".js", ".jsx", ".mjs", ".ts", ".tsx", ".vue", ".svelte"}
+
+var (
+	templatePattern      = regexp.MustCompile("\\b(?:gql|graphql)\\s*(?:\\(\\s*)?`([^`]*)`")
+	interpolationPattern = regexp.MustCompile(`\$\{[^}]*\}`)
+)
+
+// Collects operations of client code. Fragments are shared between files,
+// as clients usually interpolate them into templates, operations get the
+// ones they use
+type Extractor struct {
+	operations []*ast.OperationDefinition
+	fragments  map[string]*ast.FragmentDefinition
+}
+
+func NewExtractor() *Extractor {
+	return &Extractor{
+		operations: make([]*ast.OperationDefinition, 0),
+		fragments:  make(map[string]*ast.FragmentDefinition),
+	}
+}
+
+func Supported(path string) bool {
+	ext := filepath.Ext(path)
+	return slices.Contains(DOCUMENT_EXTENSIONS, ext) ||
+		slices.Contains(SOURCE_EXTENSIONS, ext)
+}
+
+func (self *Extractor) Add(path string, content string) error {
+	documents := []string{content}
+
+	if !slices.Contains(DOCUMENT_EXTENSIONS, filepath.Ext(path)) {
+		documents = documents[:0]
+
+		for _, match := range templatePattern.FindAllStringSubmatch(content, -1) {
+			documents = append(documents, interpolationPattern.ReplaceAllString(match[1], ""))
+		}
+	}
+
+	for _, document := range documents {
+		doc, err := parser.ParseQuery(&ast.Source{Name: path, Input: document})
+
+		if nil != err {
+			return err
+		}
+
+		for _, op := range doc.Operations {
+			if "" == op.Name {
+				return fmt.Errorf("%v: operations must be named", path)
+			}
+
+			self.operations = append(self.operations, op)
+		}
+
+		for _, fragment := range doc.Fragments {
+			self.fragments[fragment.Name] = fragment
+		}
+	}
+
+	return nil
+}
+
+func (self *Extractor) collect(
+	set ast.SelectionSet,
+	used map[string]*ast.FragmentDefinition,
+) error {
+	for _, selection := range set {
+		var err error
+
+		switch v := selection.(type) {
+		case *ast.Field:
+			err = self.collect(v.SelectionSet, used)
+		case *ast.InlineFragment:
+			err = self.collect(v.SelectionSet, used)
+		case *ast.FragmentSpread:
+			if _, found := used[v.Name]; !found {
+				fragment, found := self.fragments[v.Name]
+
+				if !found {
+					return fmt.Errorf("fragment %v isn't defined", v.Name)
+				}
+
+				used[v.Name] = fragment
+				err = self.collect(fragment.SelectionSet, used)
+			}
+		}
+
+		if nil != err {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Operations formatted with the fragments they use, ordered by name. The
+// text differs from the source, so the hashes don't match the ones computed
+// by clients from it
+func (self *Extractor) Operations() ([]Operation, error) {
+	out := make([]Operation, 0, len(self.operations))
+	names := make(map[string]struct{}, len(self.operations))
+
+	for _, op := range self.operations {
+		if _, found := names[op.Name]; found {
+			return nil, fmt.Errorf("operation %v is defined twice", op.Name)
+		}
+
+		names[op.Name] = struct{}{}
+		used := make(map[string]*ast.FragmentDefinition)
+
+		if err := self.collect(op.SelectionSet, used); nil != err {
+			return nil, fmt.Errorf("operation %v: %w", op.Name, err)
+		}
+
+		doc := &ast.QueryDocument{Operations: ast.OperationList{op}}
+
+		for _, name := range slices.Sorted(maps.Keys(used)) {
+			doc.Fragments = append(doc.Fragments, used[name])
+		}
+
+		var query strings.Builder
+		formatter.NewFormatter(&query, formatter.WithIndent("  ")).FormatQueryDocument(doc)
+
+		if operation, err := NewOperation(op.Name, query.String()); nil == err {
+			out = append(out, operation)
+		} else {
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(out, func(a Operation, b Operation) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return out, nil
+}
+
@@ -0,0 +1,145 @@
+package persisted
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (self *recorder) Header() http.Header {
+	return self.header
+}
+
+func (self *recorder) Write(b []byte) (int, error) {
+	return self.body.Write(b)
+}
+
+func (self *recorder) WriteHeader(status int) {
+	if 0 == self.status {
+		self.status = status
+	}
+}
+
+// Hash of the operation requested with GET, either persisted or sent in full
+func requestedHash(r *http.Request) string {
+	values := r.URL.Query()
+
+	if raw := values.Get("extensions"); "" != raw {
+		var extensions struct {
+			PersistedQuery struct {
+				Sha256 string `json:"sha256Hash"`
+			} `json:"persistedQuery"`
+		}
+
+		if nil == json.Unmarshal([]byte(raw), &extensions) &&
+			"" != extensions.PersistedQuery.Sha256 {
+			return extensions.PersistedQuery.Sha256
+		}
+	}
+
+	if query := values.Get("query"); "" != query {
+		return Hash(query)
+	}
+
+	return ""
+}
+
+func cacheable(r *recorder) bool {
+	var response struct {
+		Errors json.RawMessage `json:"errors"`
+	}
+
+	return http.StatusOK == r.status &&
+		nil == json.Unmarshal(r.body.Bytes(), &response) &&
+		0 == len(response.Errors)
+}
+
+func matches(header string, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		if v = strings.TrimSpace(v); "*" == v || etag == strings.TrimPrefix(v, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Operations selecting only the public root fields read the same data for
+// everyone
+func (self *Operation) public(fields []string) bool {
+	if !self.ReadOnly || 0 == len(self.Fields) {
+		return false
+	}
+
+	for _, field := range self.Fields {
+		if !slices.Contains(fields, field) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Responses to GET requests of registered read-only operations are validated
+// by ETag. The ones selecting only public root fields may be stored by shared
+// caches for max age, the rest are private and revalidated every time.
+// Failed ones aren't cached
+func (self *Registry) Cache(
+	maxAge time.Duration,
+	public []string,
+	next http.Handler,
+) http.Handler {
+	shared := fmt.Sprintf("public, max-age=%d", int64(maxAge.Seconds()))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op, found := self.Operation(requestedHash(r))
+
+		if http.MethodGet != r.Method || "" != r.Header.Get("Upgrade") ||
+			!found || !op.ReadOnly {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recorder{header: w.Header()}
+		next.ServeHTTP(rec, r)
+
+		if 0 == rec.status {
+			rec.status = http.StatusOK
+		}
+
+		if cacheable(rec) {
+			sum := sha256.Sum256(rec.body.Bytes())
+			etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+			w.Header().Set("ETag", etag)
+
+			if op.public(public) {
+				w.Header().Set("Cache-Control", shared)
+			} else {
+				w.Header().Set("Cache-Control", "private, no-cache")
+			}
+
+			if matches(r.Header.Get("If-None-Match"), etag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		} else {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+
+		w.WriteHeader(rec.status)
+		w.Write(rec.body.Bytes())
+	})
+}
+
@@ -0,0 +1,187 @@
+package persisted_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/muji40k/ozontestcomms/graphql/persisted"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const SOURCE = "import { gql } from '@apollo/client';\n" +
+	"const AUTHOR = gql`fragment Author on User { id email }`;\n" +
+	"export const POSTS = gql`\n" +
+	"  query Posts($limit: Int) { posts(limit: $limit) { data { id author { ...Author } } } }\n" +
+	"  ${AUTHOR}\n" +
+	"`;\n" +
+	"export const CREATE = gql`mutation CreatePost($title: String!) { createPost(title: $title) { id } }`;\n"
+
+func TestExtractResolvesFragments(t *testing.T) {
+	// Arrange
+	extractor := persisted.NewExtractor()
+
+	// Act
+	err := extractor.Add("posts.ts", SOURCE)
+	require.NoError(t, err)
+	operations, err := extractor.Operations()
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, operations, 2)
+	assert.Equal(t, "CreatePost", operations[0].Name, "Ordered by name")
+	assert.False(t, operations[0].ReadOnly)
+	assert.NotContains(t, operations[0].Query, "fragment")
+	assert.Equal(t, "Posts", operations[1].Name)
+	assert.True(t, operations[1].ReadOnly)
+	assert.Contains(t, operations[1].Query, "fragment Author on User")
+	assert.Equal(t, []string{"posts"}, operations[1].Fields)
+	assert.Equal(t, persisted.Hash(operations[1].Query), operations[1].Hash)
+}
+
+func TestExtractRejectsAnonymousOperations(t *testing.T) {
+	// Arrange
+	extractor := persisted.NewExtractor()
+
+	// Act
+	err := extractor.Add("query.graphql", "{ posts { data { id } } }")
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestExtractRejectsUnknownFragments(t *testing.T) {
+	// Arrange
+	extractor := persisted.NewExtractor()
+	require.NoError(t, extractor.Add("query.gql", "query Q { posts { ...Missing } }"))
+
+	// Act
+	_, err := extractor.Operations()
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestLoadHashesFiles(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	query := "query Posts {\n  posts {\n    data {\n      id\n    }\n  }\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Posts.graphql"), []byte(query), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("skipped"), 0o644))
+
+	// Act
+	registry, err := persisted.Load(dir)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, registry.Len())
+	found, ok := registry.Get(context.Background(), persisted.Hash(query))
+	assert.True(t, ok)
+	assert.Equal(t, query, found)
+	registry.Add(context.Background(), persisted.Hash("{ a }"), "{ a }")
+	_, ok = registry.Get(context.Background(), persisted.Hash("{ a }"))
+	assert.False(t, ok, "Registry isn't added to")
+}
+
+func TestLoadFailsOnInvalidDocument(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Broken.graphql"), []byte("query {"), 0o644))
+
+	// Act
+	_, err := persisted.Load(dir)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func get(handler http.Handler, query string, etag string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/query?query="+url.QueryEscape(query), nil)
+
+	if "" != etag {
+		r.Header.Set("If-None-Match", etag)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestOperationCollectsRootFields(t *testing.T) {
+	// Arrange
+	query := "query Q { a ...F ... on Query { b a } }\nfragment F on Query { c }"
+
+	// Act
+	operation, err := persisted.NewOperation("Q.graphql", query)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c", "b"}, operation.Fields)
+}
+
+func TestCacheValidatesByETag(t *testing.T) {
+	// Arrange
+	read := persisted.Operation{Query: "query Q { a }", Hash: persisted.Hash("query Q { a }"), ReadOnly: true, Fields: []string{"a"}}
+	registry := persisted.NewRegistry(read)
+	handler := registry.Cache(0, []string{"a"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"a":1}}`))
+	}))
+
+	// Act
+	first := get(handler, read.Query, "")
+	second := get(handler, read.Query, first.Header().Get("ETag"))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, `{"data":{"a":1}}`, first.Body.String())
+	assert.Equal(t, "public, max-age=0", first.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, first.Header().Get("ETag"))
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Empty(t, second.Body.String())
+}
+
+func TestCacheSkipsFailuresAndWrites(t *testing.T) {
+	// Arrange
+	read := persisted.Operation{Query: "query Q { a }", Hash: persisted.Hash("query Q { a }"), ReadOnly: true, Fields: []string{"a"}}
+	write := persisted.Operation{Query: "mutation M { a }", Hash: persisted.Hash("mutation M { a }")}
+	registry := persisted.NewRegistry(read, write)
+	handler := registry.Cache(0, []string{"a"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors":[{"message":"failed"}],"data":null}`))
+	}))
+
+	// Act
+	failed := get(handler, read.Query, "")
+	written := get(handler, write.Query, "")
+
+	// Assert
+	assert.Equal(t, "no-store", failed.Header().Get("Cache-Control"))
+	assert.Empty(t, failed.Header().Get("ETag"))
+	assert.Contains(t, failed.Body.String(), "failed")
+	assert.Empty(t, written.Header().Get("Cache-Control"), "Passed through")
+}
+
+func TestCacheKeepsUserDataPrivate(t *testing.T) {
+	// Arrange
+	read := persisted.Operation{Query: "query Q { a b }", Hash: persisted.Hash("query Q { a b }"), ReadOnly: true, Fields: []string{"a", "b"}}
+	registry := persisted.NewRegistry(read)
+	handler := registry.Cache(time.Minute, []string{"a"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"a":1,"b":2}}`))
+	}))
+
+	// Act
+	first := get(handler, read.Query, "")
+	second := get(handler, read.Query, first.Header().Get("ETag"))
+
+	// Assert
+	assert.Equal(t, "private, no-cache", first.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, first.Header().Get("ETag"))
+	assert.Equal(t, http.StatusNotModified, second.Code)
+}
+
@@ -0,0 +1,154 @@
+package persisted
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const EXTENSION = ".graphql"
+
+type Operation struct {
+	Name  string
+	Query string
+	// Sha256 of the query, as sent by clients in the persistedQuery extension
+	Hash string
+	// Only queries are read-only, mutations and subscriptions aren't
+	ReadOnly bool
+	// Root fields selected, including the ones of fragments
+	Fields []string
+}
+
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+func rootFields(
+	doc *ast.QueryDocument,
+	set ast.SelectionSet,
+	visited map[string]struct{},
+	out []string,
+) []string {
+	for _, selection := range set {
+		switch v := selection.(type) {
+		case *ast.Field:
+			if !slices.Contains(out, v.Name) {
+				out = append(out, v.Name)
+			}
+		case *ast.InlineFragment:
+			out = rootFields(doc, v.SelectionSet, visited, out)
+		case *ast.FragmentSpread:
+			if _, found := visited[v.Name]; found {
+				continue
+			}
+
+			visited[v.Name] = struct{}{}
+
+			if fragment := doc.Fragments.ForName(v.Name); nil != fragment {
+				out = rootFields(doc, fragment.SelectionSet, visited, out)
+			}
+		}
+	}
+
+	return out
+}
+
+func NewOperation(source string, query string) (Operation, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Name: source, Input: query})
+
+	if nil != err {
+		return Operation{}, err
+	}
+
+	if 0 == len(doc.Operations) {
+		return Operation{}, fmt.Errorf("%v: no operations", source)
+	}
+
+	out := Operation{
+		Name:     doc.Operations[0].Name,
+		Query:    query,
+		Hash:     Hash(query),
+		ReadOnly: true,
+	}
+
+	visited := make(map[string]struct{})
+
+	for _, op := range doc.Operations {
+		out.ReadOnly = out.ReadOnly && ast.Query == op.Operation
+		out.Fields = rootFields(doc, op.SelectionSet, visited, out.Fields)
+	}
+
+	return out, nil
+}
+
+// Operations clients are allowed to run. It's used as the cache of
+// automatic persisted queries, which is never added to
+type Registry struct {
+	operations map[string]Operation
+}
+
+func NewRegistry(operations ...Operation) *Registry {
+	out := &Registry{make(map[string]Operation, len(operations))}
+
+	for _, op := range operations {
+		out.operations[op.Hash] = op
+	}
+
+	return out
+}
+
+// Every file with EXTENSION under the directory is an operation, hashed
+// exactly as it's stored
+func Load(dir string) (*Registry, error) {
+	operations := make([]Operation, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if nil != err || d.IsDir() || EXTENSION != filepath.Ext(path) {
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+
+		if nil == err {
+			var op Operation
+
+			if op, err = NewOperation(path, string(content)); nil == err {
+				operations = append(operations, op)
+			}
+		}
+
+		return err
+	})
+
+	if nil != err {
+		return nil, err
+	}
+
+	return NewRegistry(operations...), nil
+}
+
+func (self *Registry) Len() int {
+	return len(self.operations)
+}
+
+func (self *Registry) Operation(hash string) (Operation, bool) {
+	op, found := self.operations[hash]
+	return op, found
+}
+
+func (self *Registry) Get(ctx context.Context, hash string) (string, bool) {
+	op, found := self.operations[hash]
+	return op.Query, found
+}
+
+// Queries sent along with their hashes aren't registered
+func (self *Registry) Add(ctx context.Context, hash string, query string) {}
+
@@ -2,12 +2,8 @@ package graphql
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
@@ -17,88 +13,158 @@ import (
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/muji40k/ozontestcomms/graphql/graph"
 	"github.com/muji40k/ozontestcomms/graphql/graph/dataloader"
+	"github.com/muji40k/ozontestcomms/graphql/persisted"
+	"github.com/muji40k/ozontestcomms/internal/application/health"
+	"github.com/muji40k/ozontestcomms/internal/application/httpserver"
 	"github.com/muji40k/ozontestcomms/internal/service/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/follow"
+	healthsrv "github.com/muji40k/ozontestcomms/internal/service/interface/health"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/moderation"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/notification"
 	"github.com/muji40k/ozontestcomms/internal/service/interface/post"
 	"github.com/muji40k/ozontestcomms/internal/service/interface/user"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/webhook"
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
+const DEFAULT_WATCH_INTERVAL time.Duration = time.Second
+const DEFAULT_CACHE_MAX_AGE time.Duration = time.Minute
+
+// Root fields reading the same data for everyone, operations selecting others
+// are cached privately
+var PUBLIC_FIELDS = []string{"post", "comment", "posts", "__typename"}
+
 type Context struct {
-	User    user.Service
-	Comment comment.Service
-	Post    post.Service
+	User         user.Service
+	Comment      comment.Service
+	Post         post.Service
+	Health       healthsrv.Service
+	Webhook      webhook.Service
+	Notification notification.Service
+	Follow       follow.Service
+	Moderation   moderation.Service
+}
+
+// Only operations of the registry are accepted when it's set
+type Persisted struct {
+	Registry *persisted.Registry
+	// Responses to GET requests of read-only operations are cached this long
+	MaxAge time.Duration
 }
 
 type Server struct {
-	host           string
-	port           string
 	loaderDuration time.Duration
+	watchInterval  time.Duration
+	persisted      Persisted
 	context        Context
-	server         *http.Server
+	server         *httpserver.Server
 }
 
-func New(host string, port string, loader time.Duration, context Context) *Server {
-	return &Server{host, port, loader, context, nil}
+func New(
+	host string,
+	port string,
+	loader time.Duration,
+	shutdown time.Duration,
+	watch time.Duration,
+	persisted Persisted,
+	context Context,
+) *Server {
+	return &Server{
+		loader,
+		watch,
+		persisted,
+		context,
+		httpserver.New(
+			host,
+			port,
+			shutdown,
+			health.New(context.Health, health.DEFAULT_READINESS_TIMEOUT),
+		),
+	}
 }
 
-func (self *Server) Run() {
+func (self *Server) websockets(next http.Handler) http.Handler {
+	detached := self.server.Detached(next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if "" == r.Header.Get("Upgrade") {
+			next.ServeHTTP(w, r)
+		} else {
+			ctx := transport.AppendCloseReason(r.Context(), "server is shutting down")
+			detached.ServeHTTP(w, r.WithContext(ctx))
+		}
+	})
+}
+
+func (self *Server) Handler() http.Handler {
 	resolver := graph.NewResolver(
 		self.context.User,
 		self.context.Comment,
 		self.context.Post,
+		self.context.Webhook,
+		self.context.Notification,
+		self.context.Follow,
+		self.context.Moderation,
+		self.watchInterval,
 	)
 
 	gqhandler := handler.New(
 		graph.NewExecutableSchema(graph.Config{Resolvers: &resolver}),
 	)
 
+	gqhandler.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+	})
 	gqhandler.AddTransport(transport.Options{})
 	gqhandler.AddTransport(transport.GET{})
 	gqhandler.AddTransport(transport.POST{})
 
 	gqhandler.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 
-	gqhandler.Use(extension.Introspection{})
-	gqhandler.Use(extension.AutomaticPersistedQuery{
-		Cache: lru.New[string](100),
-	})
+	if registry := self.persisted.Registry; nil == registry {
+		gqhandler.Use(extension.Introspection{})
+		gqhandler.Use(extension.AutomaticPersistedQuery{
+			Cache: lru.New[string](100),
+		})
+	} else {
+		gqhandler.Use(extension.AutomaticPersistedQuery{Cache: registry})
+		gqhandler.Use(persisted.Allowlist{Registry: registry})
+	}
 
-	handler := dataloader.Middleware(
+	var handler http.Handler = dataloader.Middleware(
 		func() *dataloader.Loaders {
 			return dataloader.NewLoaders(self.context.User, self.loaderDuration)
 		},
 		gqhandler,
 	)
 
+	if nil != self.persisted.Registry {
+		handler = self.persisted.Registry.Cache(
+			self.persisted.MaxAge,
+			PUBLIC_FIELDS,
+			handler,
+		)
+	}
+
 	mux := http.NewServeMux()
 	mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	mux.Handle("/query", handler)
-
-	address := fmt.Sprintf("%v:%v", self.host, self.port)
+	mux.Handle("/query", self.websockets(handler))
+	self.server.Probe().Register(mux)
 
-	self.server = &http.Server{
-		Addr:    address,
-		Handler: mux,
-	}
+	return mux
+}
 
-	go func() {
-		log.Printf("connect to http://%s/ for GraphQL playground", address)
-		if err := self.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Printf("listen error: %s", err)
-		}
-	}()
+func (self *Server) Run(ctx context.Context) error {
+	log.Printf("connect to http://%s/ for GraphQL playground", self.server.Address())
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	if nil != self.persisted.Registry {
+		log.Printf("only %d persisted queries are accepted", self.persisted.Registry.Len())
+	}
+	return self.server.Run(ctx, self.Handler())
 }
 
 func (self *Server) Clear() {
-	if nil == self.server {
-		return
-	}
-
-	self.server.Shutdown(context.Background())
+	self.server.Clear()
 	log.Print("server down")
 }
 
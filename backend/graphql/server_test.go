@@ -0,0 +1,291 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muji40k/ozontestcomms/builders/applications/graphql"
+	"github.com/muji40k/ozontestcomms/builders/services/domain"
+	"github.com/muji40k/ozontestcomms/graphql/persisted"
+	"github.com/muji40k/ozontestcomms/internal/application"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	postsrv "github.com/muji40k/ozontestcomms/internal/service/interface/post"
+	"github.com/muji40k/ozontestcomms/misc/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Post service, which blocks listing until released or cancelled
+type blockingPostService struct {
+	postsrv.Service
+	entered  chan struct{}
+	release  chan struct{}
+	observed chan error
+}
+
+func (self *blockingPostService) GetPosts(
+	ctx context.Context,
+	order postsrv.PostOrder,
+) (collection.Collection[result.Result[models.Post]], error) {
+	close(self.entered)
+
+	select {
+	case <-self.release:
+		self.observed <- nil
+		return self.Service.GetPosts(ctx, order)
+	case <-ctx.Done():
+		self.observed <- ctx.Err()
+		return nil, ctx.Err()
+	}
+}
+
+// Keep-alive pool may dial spare connections, which delay shutdown
+var client = &http.Client{
+	Transport: &http.Transport{DisableKeepAlives: true},
+}
+
+func freePort(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	return port
+}
+
+func setupApplication(
+	t *testing.T,
+	shutdown time.Duration,
+	wrap func(postsrv.Service) postsrv.Service,
+) (application.Application, string) {
+	repo := inmemory.New(nil)
+	svc, err := domain.NewLogicBuilder().
+		WithCommentRepository(repo).
+		WithPostRepository(repo).
+		WithUserRepository(repo).
+		Build()
+	require.NoError(t, err)
+
+	port := freePort(t)
+	app, err := graphql.NewServerBuilder().
+		WithHost("127.0.0.1").
+		WithPort(port).
+		WithLoaderDuration(time.Millisecond).
+		WithShutdownTimeout(shutdown).
+		WithCommentService(svc).
+		WithPostService(wrap(svc)).
+		WithUserService(svc).
+		WithHealthService(svc).
+		Build()
+	require.NoError(t, err)
+
+	return app, "http://127.0.0.1:" + port
+}
+
+func start(
+	t *testing.T,
+	app application.Application,
+	address string,
+) (context.CancelFunc, chan error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- app.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		resp, err := client.Get(address + "/readyz")
+
+		if nil != err {
+			return false
+		}
+
+		resp.Body.Close()
+		return http.StatusOK == resp.StatusCode
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return cancel, done
+}
+
+func query(address string) (*http.Response, error) {
+	return client.Post(
+		address+"/query",
+		"application/json",
+		strings.NewReader(`{"query": "{ posts(limit: 10) { data { id } } }"}`),
+	)
+}
+
+func wait(t *testing.T, done chan error) error {
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("application didn't stop")
+		return nil
+	}
+}
+
+func TestApplicationStartStop(t *testing.T) {
+	// Arrange
+	app, address := setupApplication(t, time.Second,
+		func(s postsrv.Service) postsrv.Service { return s },
+	)
+	defer app.Clear()
+	cancel, done := start(t, app, address)
+
+	// Act
+	resp, err := query(address)
+	require.NoError(t, err)
+	resp.Body.Close()
+	cancel()
+
+	// Assert
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NoError(t, wait(t, done))
+	_, err = client.Get(address + "/healthz")
+	assert.Error(t, err, "Listener is closed after shutdown")
+}
+
+func TestApplicationDrainsInFlightRequests(t *testing.T) {
+	// Arrange
+	blocking := &blockingPostService{
+		entered:  make(chan struct{}),
+		release:  make(chan struct{}),
+		observed: make(chan error, 1),
+	}
+	app, address := setupApplication(t, 5*time.Second,
+		func(s postsrv.Service) postsrv.Service {
+			blocking.Service = s
+			return blocking
+		},
+	)
+	defer app.Clear()
+	cancel, done := start(t, app, address)
+	responses := make(chan int, 1)
+
+	go func() {
+		if resp, err := query(address); nil == err {
+			resp.Body.Close()
+			responses <- resp.StatusCode
+		} else {
+			responses <- 0
+		}
+	}()
+
+	// Act
+	<-blocking.entered
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(blocking.release)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, <-responses)
+	assert.NoError(t, <-blocking.observed)
+	assert.NoError(t, wait(t, done))
+}
+
+func TestApplicationCancelsRequestsAfterDeadline(t *testing.T) {
+	// Arrange
+	blocking := &blockingPostService{
+		entered:  make(chan struct{}),
+		release:  make(chan struct{}),
+		observed: make(chan error, 1),
+	}
+	app, address := setupApplication(t, 100*time.Millisecond,
+		func(s postsrv.Service) postsrv.Service {
+			blocking.Service = s
+			return blocking
+		},
+	)
+	defer app.Clear()
+	cancel, done := start(t, app, address)
+
+	go func() {
+		if resp, err := query(address); nil == err {
+			resp.Body.Close()
+		}
+	}()
+
+	// Act
+	<-blocking.entered
+	cancel()
+
+	// Assert
+	err := wait(t, done)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.ErrorIs(t, <-blocking.observed, context.Canceled)
+}
+
+func TestApplicationPersistedQueries(t *testing.T) {
+	// Arrange
+	registered := "query Posts {\n  posts(limit: 10) {\n    data {\n      id\n    }\n  }\n}\n"
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Posts.graphql"), []byte(registered), 0o644))
+	repo := inmemory.New(nil)
+	svc, err := domain.NewLogicBuilder().
+		WithCommentRepository(repo).
+		WithPostRepository(repo).
+		WithUserRepository(repo).
+		Build()
+	require.NoError(t, err)
+	port := freePort(t)
+	app, err := graphql.NewServerBuilder().
+		WithHost("127.0.0.1").
+		WithPort(port).
+		WithLoaderDuration(time.Millisecond).
+		WithPersistedQueries(dir).
+		WithCacheMaxAge(time.Minute).
+		WithCommentService(svc).
+		WithPostService(svc).
+		WithUserService(svc).
+		WithHealthService(svc).
+		Build()
+	require.NoError(t, err)
+	defer app.Clear()
+	address := "http://127.0.0.1:" + port
+	cancel, done := start(t, app, address)
+	defer func() {
+		cancel()
+		wait(t, done)
+	}()
+	get := address + "/query?extensions=" + url.QueryEscape(
+		`{"persistedQuery":{"version":1,"sha256Hash":"`+persisted.Hash(registered)+`"}}`,
+	)
+
+	// Act
+	arbitrary, err := query(address)
+	require.NoError(t, err)
+	rejected, _ := io.ReadAll(arbitrary.Body)
+	arbitrary.Body.Close()
+	first, err := client.Get(get)
+	require.NoError(t, err)
+	first.Body.Close()
+	request, _ := http.NewRequest(http.MethodGet, get, nil)
+	request.Header.Set("If-None-Match", first.Header.Get("ETag"))
+	second, err := client.Do(request)
+	require.NoError(t, err)
+	second.Body.Close()
+
+	// Assert
+	assert.Contains(t, string(rejected), persisted.ERROR_CODE_NOT_REGISTERED)
+	assert.Equal(t, http.StatusOK, first.StatusCode)
+	assert.Equal(t, "public, max-age=60", first.Header.Get("Cache-Control"))
+	assert.NotEmpty(t, first.Header.Get("ETag"))
+	assert.Equal(t, http.StatusNotModified, second.StatusCode)
+}
+
@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func is[T error](err error) bool {
+	var target T
+	return errors.As(err, &target)
+}
+
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+func mapError(err error) error {
+	if nil == err {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case is[srverrors.ErrorEmpty](err), is[srverrors.ErrorIncorrect](err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case is[srverrors.ErrorNotFound](err), is[srverrors.ErrorIterEmpty](err),
+		is[repoerrors.ErrorNotFound](err):
+		return status.Error(codes.NotFound, err.Error())
+	case is[srverrors.ErrorViolation](err):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case is[srverrors.ErrorAuthentication](err):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case is[srverrors.ErrorAuthorization](err):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		// Internal details are not exposed
+		return status.Error(codes.Internal, "Internal error")
+	}
+}
+
@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/grpc/pb"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/post"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func parseId(what string, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+
+	if nil != err {
+		err = invalidArgument(fmt.Errorf("Malformed %v: %w", what, err))
+	}
+
+	return id, err
+}
+
+func parseIds(what string, values []string) ([]uuid.UUID, error) {
+	var err error
+	out := make([]uuid.UUID, len(values))
+
+	for i := 0; nil == err && len(values) > i; i++ {
+		out[i], err = parseId(what, values[i])
+	}
+
+	return out, err
+}
+
+func parseOptionalId(what string, value *string) (*uuid.UUID, error) {
+	if nil == value {
+		return nil, nil
+	}
+
+	id, err := parseId(what, *value)
+
+	return &id, err
+}
+
+func mapUser(value *models.User) *pb.User {
+	return &pb.User{
+		Id:    value.Id.String(),
+		Email: value.Email,
+	}
+}
+
+func mapPost(value *models.Post) *pb.Post {
+	return &pb.Post{
+		Id:              value.Id.String(),
+		AuthorId:        value.AuthorId.String(),
+		Title:           value.Title,
+		Content:         value.Content,
+		CommentsAllowed: value.CommentsAllowed,
+		CreatedAt:       timestamppb.New(value.CreationDate),
+	}
+}
+
+func mapComment(value *models.Comment) *pb.Comment {
+	return &pb.Comment{
+		Id:        value.Id.String(),
+		AuthorId:  value.AuthorId.String(),
+		Content:   value.Content,
+		CreatedAt: timestamppb.New(value.CreationDate),
+	}
+}
+
+func unmapPostOrder(order pb.Order) post.PostOrder {
+	switch order {
+	case pb.Order_ORDER_DATE_ASC:
+		return post.POST_ORDER_DATE_ASC
+	default:
+		return post.POST_ORDER_DATE_DESC
+	}
+}
+
+func unmapCommentOrder(order pb.Order) comment.CommentOrder {
+	switch order {
+	case pb.Order_ORDER_DATE_ASC:
+		return comment.COMMENT_ORDER_DATE_ASC
+	default:
+		return comment.COMMENT_ORDER_DATE_DESC
+	}
+}
+
+func unmapCreatePostRequest(req *pb.CreatePostRequest) post.PostCreationForm {
+	return post.PostCreationForm{
+		Title:         req.GetTitle(),
+		Content:       req.GetContent(),
+		AllowComments: nil == req.AllowComments || *req.AllowComments,
+	}
+}
+
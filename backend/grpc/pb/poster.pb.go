@@ -0,0 +1,1382 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: poster.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Order int32
+
+const (
+	Order_ORDER_DATE_DESC Order = 0
+	Order_ORDER_DATE_ASC  Order = 1
+)
+
+// Enum value maps for Order.
+var (
+	Order_name = map[int32]string{
+		0: "ORDER_DATE_DESC",
+		1: "ORDER_DATE_ASC",
+	}
+	Order_value = map[string]int32{
+		"ORDER_DATE_DESC": 0,
+		"ORDER_DATE_ASC":  1,
+	}
+)
+
+func (x Order) Enum() *Order {
+	p := new(Order)
+	*p = x
+	return p
+}
+
+func (x Order) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Order) Descriptor() protoreflect.EnumDescriptor {
+	return file_poster_proto_enumTypes[0].Descriptor()
+}
+
+func (Order) Type() protoreflect.EnumType {
+	return &file_poster_proto_enumTypes[0]
+}
+
+func (x Order) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Order.Descriptor instead.
+func (Order) EnumDescriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{0}
+}
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_poster_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type Post struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AuthorId        string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Title           string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Content         string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	CommentsAllowed bool                   `protobuf:"varint,5,opt,name=comments_allowed,json=commentsAllowed,proto3" json:"comments_allowed,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Post) Reset() {
+	*x = Post{}
+	mi := &file_poster_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Post) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Post) ProtoMessage() {}
+
+func (x *Post) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Post.ProtoReflect.Descriptor instead.
+func (*Post) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Post) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Post) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *Post) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Post) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Post) GetCommentsAllowed() bool {
+	if x != nil {
+		return x.CommentsAllowed
+	}
+	return false
+}
+
+func (x *Post) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type Comment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AuthorId      string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	mi := &file_poster_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Comment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Comment) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *Comment) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Comment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// Cursor pagination: pass end_id of a page as after to get the next one
+type Page struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	After         *string                `protobuf:"bytes,1,opt,name=after,proto3,oneof" json:"after,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Page) Reset() {
+	*x = Page{}
+	mi := &file_poster_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Page) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Page) ProtoMessage() {}
+
+func (x *Page) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Page.ProtoReflect.Descriptor instead.
+func (*Page) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Page) GetAfter() string {
+	if x != nil && x.After != nil {
+		return *x.After
+	}
+	return ""
+}
+
+func (x *Page) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type PostPage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []*Post                `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	EndId         *string                `protobuf:"bytes,2,opt,name=end_id,json=endId,proto3,oneof" json:"end_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostPage) Reset() {
+	*x = PostPage{}
+	mi := &file_poster_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostPage) ProtoMessage() {}
+
+func (x *PostPage) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostPage.ProtoReflect.Descriptor instead.
+func (*PostPage) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PostPage) GetData() []*Post {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *PostPage) GetEndId() string {
+	if x != nil && x.EndId != nil {
+		return *x.EndId
+	}
+	return ""
+}
+
+type CommentPage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []*Comment             `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	EndId         *string                `protobuf:"bytes,2,opt,name=end_id,json=endId,proto3,oneof" json:"end_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommentPage) Reset() {
+	*x = CommentPage{}
+	mi := &file_poster_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommentPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentPage) ProtoMessage() {}
+
+func (x *CommentPage) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentPage.ProtoReflect.Descriptor instead.
+func (*CommentPage) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CommentPage) GetData() []*Comment {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *CommentPage) GetEndId() string {
+	if x != nil && x.EndId != nil {
+		return *x.EndId
+	}
+	return ""
+}
+
+type GetPostsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          *Page                  `protobuf:"bytes,1,opt,name=page,proto3" json:"page,omitempty"`
+	Order         Order                  `protobuf:"varint,2,opt,name=order,proto3,enum=poster.v1.Order" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPostsRequest) Reset() {
+	*x = GetPostsRequest{}
+	mi := &file_poster_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPostsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPostsRequest) ProtoMessage() {}
+
+func (x *GetPostsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPostsRequest.ProtoReflect.Descriptor instead.
+func (*GetPostsRequest) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetPostsRequest) GetPage() *Page {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+func (x *GetPostsRequest) GetOrder() Order {
+	if x != nil {
+		return x.Order
+	}
+	return Order_ORDER_DATE_DESC
+}
+
+type GetPostsByIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPostsByIdRequest) Reset() {
+	*x = GetPostsByIdRequest{}
+	mi := &file_poster_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPostsByIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPostsByIdRequest) ProtoMessage() {}
+
+func (x *GetPostsByIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPostsByIdRequest.ProtoReflect.Descriptor instead.
+func (*GetPostsByIdRequest) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetPostsByIdRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type Posts struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []*Post                `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Posts) Reset() {
+	*x = Posts{}
+	mi := &file_poster_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Posts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Posts) ProtoMessage() {}
+
+func (x *Posts) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Posts.ProtoReflect.Descriptor instead.
+func (*Posts) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Posts) GetData() []*Post {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type CreatePostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	AllowComments *bool                  `protobuf:"varint,4,opt,name=allow_comments,json=allowComments,proto3,oneof" json:"allow_comments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePostRequest) Reset() {
+	*x = CreatePostRequest{}
+	mi := &file_poster_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePostRequest) ProtoMessage() {}
+
+func (x *CreatePostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePostRequest.ProtoReflect.Descriptor instead.
+func (*CreatePostRequest) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreatePostRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreatePostRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreatePostRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CreatePostRequest) GetAllowComments() bool {
+	if x != nil && x.AllowComments != nil {
+		return *x.AllowComments
+	}
+	return false
+}
+
+type UpdatePostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PostId        string                 `protobuf:"bytes,2,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	AllowComments *bool                  `protobuf:"varint,3,opt,name=allow_comments,json=allowComments,proto3,oneof" json:"allow_comments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePostRequest) Reset() {
+	*x = UpdatePostRequest{}
+	mi := &file_poster_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePostRequest) ProtoMessage() {}
+
+func (x *UpdatePostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePostRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePostRequest) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdatePostRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdatePostRequest) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *UpdatePostRequest) GetAllowComments() bool {
+	if x != nil && x.AllowComments != nil {
+		return *x.AllowComments
+	}
+	return false
+}
+
+type GetCommentsByIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentsByIdRequest) Reset() {
+	*x = GetCommentsByIdRequest{}
+	mi := &file_poster_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentsByIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsByIdRequest) ProtoMessage() {}
+
+func (x *GetCommentsByIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsByIdRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentsByIdRequest) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetCommentsByIdRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type Comments struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []*Comment             `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comments) Reset() {
+	*x = Comments{}
+	mi := &file_poster_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comments) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comments) ProtoMessage() {}
+
+func (x *Comments) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comments.ProtoReflect.Descriptor instead.
+func (*Comments) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Comments) GetData() []*Comment {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type GetCommentsByPostIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	Page          *Page                  `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	Order         Order                  `protobuf:"varint,3,opt,name=order,proto3,enum=poster.v1.Order" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentsByPostIdRequest) Reset() {
+	*x = GetCommentsByPostIdRequest{}
+	mi := &file_poster_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentsByPostIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsByPostIdRequest) ProtoMessage() {}
+
+func (x *GetCommentsByPostIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsByPostIdRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentsByPostIdRequest) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetCommentsByPostIdRequest) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *GetCommentsByPostIdRequest) GetPage() *Page {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+func (x *GetCommentsByPostIdRequest) GetOrder() Order {
+	if x != nil {
+		return x.Order
+	}
+	return Order_ORDER_DATE_DESC
+}
+
+type GetCommentsByCommentIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommentId     string                 `protobuf:"bytes,1,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	Page          *Page                  `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	Order         Order                  `protobuf:"varint,3,opt,name=order,proto3,enum=poster.v1.Order" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentsByCommentIdRequest) Reset() {
+	*x = GetCommentsByCommentIdRequest{}
+	mi := &file_poster_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentsByCommentIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsByCommentIdRequest) ProtoMessage() {}
+
+func (x *GetCommentsByCommentIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsByCommentIdRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentsByCommentIdRequest) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetCommentsByCommentIdRequest) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+func (x *GetCommentsByCommentIdRequest) GetPage() *Page {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+func (x *GetCommentsByCommentIdRequest) GetOrder() Order {
+	if x != nil {
+		return x.Order
+	}
+	return Order_ORDER_DATE_DESC
+}
+
+type CreatePostCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PostId        string                 `protobuf:"bytes,2,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePostCommentRequest) Reset() {
+	*x = CreatePostCommentRequest{}
+	mi := &file_poster_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePostCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePostCommentRequest) ProtoMessage() {}
+
+func (x *CreatePostCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePostCommentRequest.ProtoReflect.Descriptor instead.
+func (*CreatePostCommentRequest) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreatePostCommentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreatePostCommentRequest) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *CreatePostCommentRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type CreateCommentCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CommentId     string                 `protobuf:"bytes,2,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCommentCommentRequest) Reset() {
+	*x = CreateCommentCommentRequest{}
+	mi := &file_poster_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCommentCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCommentCommentRequest) ProtoMessage() {}
+
+func (x *CreateCommentCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCommentCommentRequest.ProtoReflect.Descriptor instead.
+func (*CreateCommentCommentRequest) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CreateCommentCommentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateCommentCommentRequest) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+func (x *CreateCommentCommentRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// Streams comments of the post created after subscription, or after the
+// specified comment to resume an interrupted feed
+type WatchCommentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	After         *string                `protobuf:"bytes,2,opt,name=after,proto3,oneof" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchCommentsRequest) Reset() {
+	*x = WatchCommentsRequest{}
+	mi := &file_poster_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCommentsRequest) ProtoMessage() {}
+
+func (x *WatchCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCommentsRequest.ProtoReflect.Descriptor instead.
+func (*WatchCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WatchCommentsRequest) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *WatchCommentsRequest) GetAfter() string {
+	if x != nil && x.After != nil {
+		return *x.After
+	}
+	return ""
+}
+
+type GetUsersByIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsersByIdRequest) Reset() {
+	*x = GetUsersByIdRequest{}
+	mi := &file_poster_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsersByIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersByIdRequest) ProtoMessage() {}
+
+func (x *GetUsersByIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersByIdRequest.ProtoReflect.Descriptor instead.
+func (*GetUsersByIdRequest) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetUsersByIdRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type Users struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []*User                `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Users) Reset() {
+	*x = Users{}
+	mi := &file_poster_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Users) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Users) ProtoMessage() {}
+
+func (x *Users) ProtoReflect() protoreflect.Message {
+	mi := &file_poster_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Users.ProtoReflect.Descriptor instead.
+func (*Users) Descriptor() ([]byte, []int) {
+	return file_poster_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Users) GetData() []*User {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_poster_proto protoreflect.FileDescriptor
+
+const file_poster_proto_rawDesc = "" +
+	"\n" +
+	"\fposter.proto\x12\tposter.v1\x1a\x1fgoogle/protobuf/timestamp.proto\",\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\"\xc9\x01\n" +
+	"\x04Post\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12)\n" +
+	"\x10comments_allowed\x18\x05 \x01(\bR\x0fcommentsAllowed\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x8b\x01\n" +
+	"\aComment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"A\n" +
+	"\x04Page\x12\x19\n" +
+	"\x05after\x18\x01 \x01(\tH\x00R\x05after\x88\x01\x01\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limitB\b\n" +
+	"\x06_after\"V\n" +
+	"\bPostPage\x12#\n" +
+	"\x04data\x18\x01 \x03(\v2\x0f.poster.v1.PostR\x04data\x12\x1a\n" +
+	"\x06end_id\x18\x02 \x01(\tH\x00R\x05endId\x88\x01\x01B\t\n" +
+	"\a_end_id\"\\\n" +
+	"\vCommentPage\x12&\n" +
+	"\x04data\x18\x01 \x03(\v2\x12.poster.v1.CommentR\x04data\x12\x1a\n" +
+	"\x06end_id\x18\x02 \x01(\tH\x00R\x05endId\x88\x01\x01B\t\n" +
+	"\a_end_id\"^\n" +
+	"\x0fGetPostsRequest\x12#\n" +
+	"\x04page\x18\x01 \x01(\v2\x0f.poster.v1.PageR\x04page\x12&\n" +
+	"\x05order\x18\x02 \x01(\x0e2\x10.poster.v1.OrderR\x05order\"'\n" +
+	"\x13GetPostsByIdRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\",\n" +
+	"\x05Posts\x12#\n" +
+	"\x04data\x18\x01 \x03(\v2\x0f.poster.v1.PostR\x04data\"\x9b\x01\n" +
+	"\x11CreatePostRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12*\n" +
+	"\x0eallow_comments\x18\x04 \x01(\bH\x00R\rallowComments\x88\x01\x01B\x11\n" +
+	"\x0f_allow_comments\"\x84\x01\n" +
+	"\x11UpdatePostRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\apost_id\x18\x02 \x01(\tR\x06postId\x12*\n" +
+	"\x0eallow_comments\x18\x03 \x01(\bH\x00R\rallowComments\x88\x01\x01B\x11\n" +
+	"\x0f_allow_comments\"*\n" +
+	"\x16GetCommentsByIdRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"2\n" +
+	"\bComments\x12&\n" +
+	"\x04data\x18\x01 \x03(\v2\x12.poster.v1.CommentR\x04data\"\x82\x01\n" +
+	"\x1aGetCommentsByPostIdRequest\x12\x17\n" +
+	"\apost_id\x18\x01 \x01(\tR\x06postId\x12#\n" +
+	"\x04page\x18\x02 \x01(\v2\x0f.poster.v1.PageR\x04page\x12&\n" +
+	"\x05order\x18\x03 \x01(\x0e2\x10.poster.v1.OrderR\x05order\"\x8b\x01\n" +
+	"\x1dGetCommentsByCommentIdRequest\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x01 \x01(\tR\tcommentId\x12#\n" +
+	"\x04page\x18\x02 \x01(\v2\x0f.poster.v1.PageR\x04page\x12&\n" +
+	"\x05order\x18\x03 \x01(\x0e2\x10.poster.v1.OrderR\x05order\"f\n" +
+	"\x18CreatePostCommentRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\apost_id\x18\x02 \x01(\tR\x06postId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\"o\n" +
+	"\x1bCreateCommentCommentRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x02 \x01(\tR\tcommentId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\"T\n" +
+	"\x14WatchCommentsRequest\x12\x17\n" +
+	"\apost_id\x18\x01 \x01(\tR\x06postId\x12\x19\n" +
+	"\x05after\x18\x02 \x01(\tH\x00R\x05after\x88\x01\x01B\b\n" +
+	"\x06_after\"'\n" +
+	"\x13GetUsersByIdRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\",\n" +
+	"\x05Users\x12#\n" +
+	"\x04data\x18\x01 \x03(\v2\x0f.poster.v1.UserR\x04data*0\n" +
+	"\x05Order\x12\x13\n" +
+	"\x0fORDER_DATE_DESC\x10\x00\x12\x12\n" +
+	"\x0eORDER_DATE_ASC\x10\x012\x86\x02\n" +
+	"\vPostService\x12;\n" +
+	"\bGetPosts\x12\x1a.poster.v1.GetPostsRequest\x1a\x13.poster.v1.PostPage\x12@\n" +
+	"\fGetPostsById\x12\x1e.poster.v1.GetPostsByIdRequest\x1a\x10.poster.v1.Posts\x12;\n" +
+	"\n" +
+	"CreatePost\x12\x1c.poster.v1.CreatePostRequest\x1a\x0f.poster.v1.Post\x12;\n" +
+	"\n" +
+	"UpdatePost\x12\x1c.poster.v1.UpdatePostRequest\x1a\x0f.poster.v1.Post2\xf7\x03\n" +
+	"\x0eCommentService\x12I\n" +
+	"\x0fGetCommentsById\x12!.poster.v1.GetCommentsByIdRequest\x1a\x13.poster.v1.Comments\x12T\n" +
+	"\x13GetCommentsByPostId\x12%.poster.v1.GetCommentsByPostIdRequest\x1a\x16.poster.v1.CommentPage\x12Z\n" +
+	"\x16GetCommentsByCommentId\x12(.poster.v1.GetCommentsByCommentIdRequest\x1a\x16.poster.v1.CommentPage\x12L\n" +
+	"\x11CreatePostComment\x12#.poster.v1.CreatePostCommentRequest\x1a\x12.poster.v1.Comment\x12R\n" +
+	"\x14CreateCommentComment\x12&.poster.v1.CreateCommentCommentRequest\x1a\x12.poster.v1.Comment\x12F\n" +
+	"\rWatchComments\x12\x1f.poster.v1.WatchCommentsRequest\x1a\x12.poster.v1.Comment0\x012O\n" +
+	"\vUserService\x12@\n" +
+	"\fGetUsersById\x12\x1e.poster.v1.GetUsersByIdRequest\x1a\x10.poster.v1.UsersB*Z(github.com/muji40k/ozontestcomms/grpc/pbb\x06proto3"
+
+var (
+	file_poster_proto_rawDescOnce sync.Once
+	file_poster_proto_rawDescData []byte
+)
+
+func file_poster_proto_rawDescGZIP() []byte {
+	file_poster_proto_rawDescOnce.Do(func() {
+		file_poster_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_poster_proto_rawDesc), len(file_poster_proto_rawDesc)))
+	})
+	return file_poster_proto_rawDescData
+}
+
+var file_poster_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_poster_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_poster_proto_goTypes = []any{
+	(Order)(0),                            // 0: poster.v1.Order
+	(*User)(nil),                          // 1: poster.v1.User
+	(*Post)(nil),                          // 2: poster.v1.Post
+	(*Comment)(nil),                       // 3: poster.v1.Comment
+	(*Page)(nil),                          // 4: poster.v1.Page
+	(*PostPage)(nil),                      // 5: poster.v1.PostPage
+	(*CommentPage)(nil),                   // 6: poster.v1.CommentPage
+	(*GetPostsRequest)(nil),               // 7: poster.v1.GetPostsRequest
+	(*GetPostsByIdRequest)(nil),           // 8: poster.v1.GetPostsByIdRequest
+	(*Posts)(nil),                         // 9: poster.v1.Posts
+	(*CreatePostRequest)(nil),             // 10: poster.v1.CreatePostRequest
+	(*UpdatePostRequest)(nil),             // 11: poster.v1.UpdatePostRequest
+	(*GetCommentsByIdRequest)(nil),        // 12: poster.v1.GetCommentsByIdRequest
+	(*Comments)(nil),                      // 13: poster.v1.Comments
+	(*GetCommentsByPostIdRequest)(nil),    // 14: poster.v1.GetCommentsByPostIdRequest
+	(*GetCommentsByCommentIdRequest)(nil), // 15: poster.v1.GetCommentsByCommentIdRequest
+	(*CreatePostCommentRequest)(nil),      // 16: poster.v1.CreatePostCommentRequest
+	(*CreateCommentCommentRequest)(nil),   // 17: poster.v1.CreateCommentCommentRequest
+	(*WatchCommentsRequest)(nil),          // 18: poster.v1.WatchCommentsRequest
+	(*GetUsersByIdRequest)(nil),           // 19: poster.v1.GetUsersByIdRequest
+	(*Users)(nil),                         // 20: poster.v1.Users
+	(*timestamppb.Timestamp)(nil),         // 21: google.protobuf.Timestamp
+}
+var file_poster_proto_depIdxs = []int32{
+	21, // 0: poster.v1.Post.created_at:type_name -> google.protobuf.Timestamp
+	21, // 1: poster.v1.Comment.created_at:type_name -> google.protobuf.Timestamp
+	2,  // 2: poster.v1.PostPage.data:type_name -> poster.v1.Post
+	3,  // 3: poster.v1.CommentPage.data:type_name -> poster.v1.Comment
+	4,  // 4: poster.v1.GetPostsRequest.page:type_name -> poster.v1.Page
+	0,  // 5: poster.v1.GetPostsRequest.order:type_name -> poster.v1.Order
+	2,  // 6: poster.v1.Posts.data:type_name -> poster.v1.Post
+	3,  // 7: poster.v1.Comments.data:type_name -> poster.v1.Comment
+	4,  // 8: poster.v1.GetCommentsByPostIdRequest.page:type_name -> poster.v1.Page
+	0,  // 9: poster.v1.GetCommentsByPostIdRequest.order:type_name -> poster.v1.Order
+	4,  // 10: poster.v1.GetCommentsByCommentIdRequest.page:type_name -> poster.v1.Page
+	0,  // 11: poster.v1.GetCommentsByCommentIdRequest.order:type_name -> poster.v1.Order
+	1,  // 12: poster.v1.Users.data:type_name -> poster.v1.User
+	7,  // 13: poster.v1.PostService.GetPosts:input_type -> poster.v1.GetPostsRequest
+	8,  // 14: poster.v1.PostService.GetPostsById:input_type -> poster.v1.GetPostsByIdRequest
+	10, // 15: poster.v1.PostService.CreatePost:input_type -> poster.v1.CreatePostRequest
+	11, // 16: poster.v1.PostService.UpdatePost:input_type -> poster.v1.UpdatePostRequest
+	12, // 17: poster.v1.CommentService.GetCommentsById:input_type -> poster.v1.GetCommentsByIdRequest
+	14, // 18: poster.v1.CommentService.GetCommentsByPostId:input_type -> poster.v1.GetCommentsByPostIdRequest
+	15, // 19: poster.v1.CommentService.GetCommentsByCommentId:input_type -> poster.v1.GetCommentsByCommentIdRequest
+	16, // 20: poster.v1.CommentService.CreatePostComment:input_type -> poster.v1.CreatePostCommentRequest
+	17, // 21: poster.v1.CommentService.CreateCommentComment:input_type -> poster.v1.CreateCommentCommentRequest
+	18, // 22: poster.v1.CommentService.WatchComments:input_type -> poster.v1.WatchCommentsRequest
+	19, // 23: poster.v1.UserService.GetUsersById:input_type -> poster.v1.GetUsersByIdRequest
+	5,  // 24: poster.v1.PostService.GetPosts:output_type -> poster.v1.PostPage
+	9,  // 25: poster.v1.PostService.GetPostsById:output_type -> poster.v1.Posts
+	2,  // 26: poster.v1.PostService.CreatePost:output_type -> poster.v1.Post
+	2,  // 27: poster.v1.PostService.UpdatePost:output_type -> poster.v1.Post
+	13, // 28: poster.v1.CommentService.GetCommentsById:output_type -> poster.v1.Comments
+	6,  // 29: poster.v1.CommentService.GetCommentsByPostId:output_type -> poster.v1.CommentPage
+	6,  // 30: poster.v1.CommentService.GetCommentsByCommentId:output_type -> poster.v1.CommentPage
+	3,  // 31: poster.v1.CommentService.CreatePostComment:output_type -> poster.v1.Comment
+	3,  // 32: poster.v1.CommentService.CreateCommentComment:output_type -> poster.v1.Comment
+	3,  // 33: poster.v1.CommentService.WatchComments:output_type -> poster.v1.Comment
+	20, // 34: poster.v1.UserService.GetUsersById:output_type -> poster.v1.Users
+	24, // [24:35] is the sub-list for method output_type
+	13, // [13:24] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_poster_proto_init() }
+func file_poster_proto_init() {
+	if File_poster_proto != nil {
+		return
+	}
+	file_poster_proto_msgTypes[3].OneofWrappers = []any{}
+	file_poster_proto_msgTypes[4].OneofWrappers = []any{}
+	file_poster_proto_msgTypes[5].OneofWrappers = []any{}
+	file_poster_proto_msgTypes[9].OneofWrappers = []any{}
+	file_poster_proto_msgTypes[10].OneofWrappers = []any{}
+	file_poster_proto_msgTypes[17].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_poster_proto_rawDesc), len(file_poster_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   3,
+		},
+		GoTypes:           file_poster_proto_goTypes,
+		DependencyIndexes: file_poster_proto_depIdxs,
+		EnumInfos:         file_poster_proto_enumTypes,
+		MessageInfos:      file_poster_proto_msgTypes,
+	}.Build()
+	File_poster_proto = out.File
+	file_poster_proto_goTypes = nil
+	file_poster_proto_depIdxs = nil
+}
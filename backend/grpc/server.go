@@ -0,0 +1,170 @@
+package grpc
+
+//go:generate protoc -I proto --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative poster.proto
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/muji40k/ozontestcomms/grpc/pb"
+	"github.com/muji40k/ozontestcomms/internal/application/health"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/comment"
+	healthsrv "github.com/muji40k/ozontestcomms/internal/service/interface/health"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/post"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/user"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DEFAULT_SHUTDOWN_TIMEOUT time.Duration = 10 * time.Second
+	DEFAULT_WATCH_INTERVAL   time.Duration = time.Second
+)
+
+type Context struct {
+	User    user.Service
+	Comment comment.Service
+	Post    post.Service
+	Health  healthsrv.Service
+}
+
+type Server struct {
+	host     string
+	port     string
+	shutdown time.Duration
+	probe    *health.Probe
+	close    context.CancelFunc
+	server   *grpclib.Server
+}
+
+func New(
+	host string,
+	port string,
+	shutdown time.Duration,
+	watch time.Duration,
+	services Context,
+) *Server {
+	closing, close := context.WithCancel(context.Background())
+	out := &Server{
+		host:     host,
+		port:     port,
+		shutdown: shutdown,
+		probe:    health.New(services.Health, health.DEFAULT_READINESS_TIMEOUT),
+		close:    close,
+		server:   grpclib.NewServer(),
+	}
+
+	pb.RegisterPostServiceServer(out.server, &postService{service: services.Post})
+	pb.RegisterCommentServiceServer(out.server, &commentService{
+		comment:  services.Comment,
+		post:     services.Post,
+		interval: watch,
+		batch:    WATCH_BATCH_SIZE,
+		closing:  closing,
+	})
+	pb.RegisterUserServiceServer(out.server, &userService{service: services.User})
+	grpc_health_v1.RegisterHealthServer(out.server, &healthService{probe: out.probe})
+	reflection.Register(out.server)
+
+	return out
+}
+
+func (self *Server) Address() string {
+	return net.JoinHostPort(self.host, self.port)
+}
+
+// Serves on the provided listener until the context is done, then stops
+// gracefully within the shutdown timeout
+func (self *Server) Serve(ctx context.Context, listener net.Listener) error {
+	served := make(chan error, 1)
+
+	go func() {
+		served <- self.server.Serve(listener)
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	self.probe.Shutdown()
+	// Streams never finish on their own
+	self.close()
+
+	stopped := make(chan struct{})
+
+	go func() {
+		self.server.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(self.shutdown)
+	defer timer.Stop()
+
+	var err error
+
+	select {
+	case <-stopped:
+	case <-timer.C:
+		self.server.Stop()
+		<-stopped
+		err = fmt.Errorf("Graceful shutdown failed: %w", context.DeadlineExceeded)
+	}
+
+	if serr := <-served; nil == err && nil != serr && grpclib.ErrServerStopped != serr {
+		err = serr
+	}
+
+	return err
+}
+
+func (self *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", self.Address())
+
+	if nil == err {
+		log.Printf("gRPC server listening on %s", self.Address())
+		err = self.Serve(ctx, listener)
+	}
+
+	return err
+}
+
+func (self *Server) Clear() {
+	self.close()
+	self.server.Stop()
+	log.Print("grpc server down")
+}
+
+type healthService struct {
+	grpc_health_v1.UnimplementedHealthServer
+	probe *health.Probe
+}
+
+func (self *healthService) Check(
+	ctx context.Context,
+	req *grpc_health_v1.HealthCheckRequest,
+) (*grpc_health_v1.HealthCheckResponse, error) {
+	out := grpc_health_v1.HealthCheckResponse_SERVING
+
+	switch req.GetService() {
+	case "", pb.PostService_ServiceDesc.ServiceName,
+		pb.CommentService_ServiceDesc.ServiceName,
+		pb.UserService_ServiceDesc.ServiceName:
+	default:
+		return nil, status.Error(codes.NotFound, "Unknown service")
+	}
+
+	if nil != self.probe.Ready(ctx) {
+		out = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: out}, nil
+}
+
@@ -0,0 +1,241 @@
+package grpc_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	grpcbuilder "github.com/muji40k/ozontestcomms/builders/applications/grpc"
+	"github.com/muji40k/ozontestcomms/builders/services/domain"
+	"github.com/muji40k/ozontestcomms/grpc/pb"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type clients struct {
+	post    pb.PostServiceClient
+	comment pb.CommentServiceClient
+	user    pb.UserServiceClient
+	health  grpc_health_v1.HealthClient
+}
+
+func setup(t *testing.T) (clients, models.User, context.CancelFunc, chan error) {
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo := inmemory.New(
+		func(adduser func(models.User), _ func(inmemory.Comment), _ func(models.Post)) {
+			adduser(user)
+		},
+	)
+	svc := common.Unwrap(domain.NewLogicBuilder().
+		WithCommentRepository(repo).
+		WithPostRepository(repo).
+		WithUserRepository(repo).
+		Build())
+	server := common.Unwrap(grpcbuilder.NewServerBuilder().
+		WithHost("127.0.0.1").
+		WithPort("0").
+		WithShutdownTimeout(time.Second).
+		WithWatchInterval(10 * time.Millisecond).
+		WithCommentService(svc).
+		WithPostService(svc).
+		WithUserService(svc).
+		WithHealthService(svc).
+		Build())
+	t.Cleanup(server.Clear)
+
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- server.Serve(ctx, listener)
+	}()
+	t.Cleanup(cancel)
+
+	conn, err := grpclib.NewClient("passthrough:///bufnet",
+		grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpclib.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return clients{
+		pb.NewPostServiceClient(conn),
+		pb.NewCommentServiceClient(conn),
+		pb.NewUserServiceClient(conn),
+		grpc_health_v1.NewHealthClient(conn),
+	}, user, cancel, done
+}
+
+func wait(t *testing.T, done chan error) error {
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("server didn't stop")
+		return nil
+	}
+}
+
+func TestGrpcPostsPagination(t *testing.T) {
+	// Arrange
+	c, user, _, _ := setup(t)
+	ctx := context.Background()
+	created := make([]*pb.Post, 3)
+
+	for i := range created {
+		created[i] = common.Unwrap(c.post.CreatePost(ctx, &pb.CreatePostRequest{
+			UserId:  user.Id.String(),
+			Title:   fmt.Sprint("title ", i),
+			Content: fmt.Sprint("content ", i),
+		}))
+	}
+
+	// Act
+	first, ferr := c.post.GetPosts(ctx, &pb.GetPostsRequest{
+		Order: pb.Order_ORDER_DATE_ASC,
+		Page:  &pb.Page{Limit: 2},
+	})
+	second, serr := c.post.GetPosts(ctx, &pb.GetPostsRequest{
+		Order: pb.Order_ORDER_DATE_ASC,
+		Page:  &pb.Page{After: first.EndId, Limit: 2},
+	})
+
+	// Assert
+	require.NoError(t, ferr)
+	require.NoError(t, serr)
+	assert.Equal(t, []string{created[0].Id, created[1].Id},
+		[]string{first.Data[0].Id, first.Data[1].Id})
+	require.Len(t, second.Data, 1)
+	assert.Equal(t, created[2].Id, second.Data[0].Id)
+	assert.Equal(t, created[2].Id, second.GetEndId())
+}
+
+func TestGrpcComments(t *testing.T) {
+	// Arrange
+	c, user, _, _ := setup(t)
+	ctx := context.Background()
+	post := common.Unwrap(c.post.CreatePost(ctx, &pb.CreatePostRequest{
+		UserId: user.Id.String(), Title: "title", Content: "content",
+	}))
+
+	// Act
+	root, rerr := c.comment.CreatePostComment(ctx, &pb.CreatePostCommentRequest{
+		UserId: user.Id.String(), PostId: post.Id, Content: "root",
+	})
+	require.NoError(t, rerr)
+	reply, aerr := c.comment.CreateCommentComment(ctx, &pb.CreateCommentCommentRequest{
+		UserId: user.Id.String(), CommentId: root.Id, Content: "reply",
+	})
+	require.NoError(t, aerr)
+	replies, lerr := c.comment.GetCommentsByCommentId(ctx, &pb.GetCommentsByCommentIdRequest{
+		CommentId: root.Id,
+	})
+	users, uerr := c.user.GetUsersById(ctx, &pb.GetUsersByIdRequest{
+		Ids: []string{user.Id.String()},
+	})
+
+	// Assert
+	require.NoError(t, lerr)
+	require.NoError(t, uerr)
+	require.Len(t, replies.Data, 1)
+	assert.Equal(t, reply.Id, replies.Data[0].Id)
+	require.Len(t, users.Data, 1)
+	assert.Equal(t, user.Email, users.Data[0].Email)
+}
+
+func TestGrpcErrors(t *testing.T) {
+	// Arrange
+	c, user, _, _ := setup(t)
+	ctx := context.Background()
+	allow := false
+	post := common.Unwrap(c.post.CreatePost(ctx, &pb.CreatePostRequest{
+		UserId:        user.Id.String(),
+		Title:         "title",
+		Content:       "content",
+		AllowComments: &allow,
+	}))
+
+	// Act
+	_, notFound := c.comment.GetCommentsByPostId(ctx, &pb.GetCommentsByPostIdRequest{
+		PostId: uuid.NewString(),
+	})
+	_, malformed := c.post.GetPostsById(ctx, &pb.GetPostsByIdRequest{
+		Ids: []string{"not-an-id"},
+	})
+	_, limit := c.post.GetPosts(ctx, &pb.GetPostsRequest{Page: &pb.Page{Limit: 100000}})
+	_, disabled := c.comment.CreatePostComment(ctx, &pb.CreatePostCommentRequest{
+		UserId: user.Id.String(), PostId: post.Id, Content: "comment",
+	})
+
+	// Assert
+	assert.Equal(t, codes.NotFound, status.Code(notFound))
+	assert.Equal(t, codes.InvalidArgument, status.Code(malformed))
+	assert.Equal(t, codes.InvalidArgument, status.Code(limit))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(disabled))
+}
+
+// Comments are created before watching, since in-memory reads aren't
+// synchronized with writes
+func TestGrpcWatchComments(t *testing.T) {
+	// Arrange
+	c, user, cancel, done := setup(t)
+	ctx := context.Background()
+	post := common.Unwrap(c.post.CreatePost(ctx, &pb.CreatePostRequest{
+		UserId: user.Id.String(), Title: "title", Content: "content",
+	}))
+	before := common.Unwrap(c.comment.CreatePostComment(ctx, &pb.CreatePostCommentRequest{
+		UserId: user.Id.String(), PostId: post.Id, Content: "before",
+	}))
+	created := common.Unwrap(c.comment.CreatePostComment(ctx, &pb.CreatePostCommentRequest{
+		UserId: user.Id.String(), PostId: post.Id, Content: "after",
+	}))
+	health := common.Unwrap(c.health.Check(ctx, &grpc_health_v1.HealthCheckRequest{}))
+
+	// Act
+	stream := common.Unwrap(c.comment.WatchComments(ctx, &pb.WatchCommentsRequest{
+		PostId: post.Id,
+		After:  &before.Id,
+	}))
+	received, rerr := stream.Recv()
+	cancel()
+	_, closed := stream.Recv()
+
+	// Assert
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, health.Status)
+	require.NoError(t, rerr)
+	assert.Equal(t, created.Id, received.Id)
+	assert.Equal(t, "after", received.Content)
+	assert.Equal(t, codes.Unavailable, status.Code(closed))
+	assert.NoError(t, wait(t, done))
+}
+
+func TestGrpcWatchUnknownPost(t *testing.T) {
+	// Arrange
+	c, _, _, _ := setup(t)
+	stream := common.Unwrap(c.comment.WatchComments(context.Background(),
+		&pb.WatchCommentsRequest{PostId: uuid.NewString()},
+	))
+
+	// Act
+	_, err := stream.Recv()
+
+	// Assert
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
@@ -0,0 +1,423 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/grpc/pb"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/singlewrap"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/post"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/user"
+	"github.com/muji40k/ozontestcomms/misc/result"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DEFAULT_PAGE_LIMIT int32 = 20
+	MAX_PAGE_LIMIT     int32 = 100
+	WATCH_BATCH_SIZE   uint  = 100
+)
+
+func collect[T any, F any](
+	col collection.Collection[result.Result[T]],
+	mapf func(*T) F,
+) ([]F, error) {
+	return pagination.Collect(collection.Map(col,
+		func(v *result.Result[T]) result.Result[F] {
+			return result.Map(v, mapf)
+		},
+	))
+}
+
+func paginate[T any, F any](
+	col collection.Collection[result.Result[T]],
+	page *pb.Page,
+	mapf func(*T) F,
+	id func(F) string,
+) ([]F, *string, error) {
+	var out []F
+	var end *string
+	if nil == page {
+		page = new(pb.Page)
+	}
+
+	limit := page.GetLimit()
+	after, err := parseOptionalId("page.after", page.After)
+
+	if 0 == limit {
+		limit = DEFAULT_PAGE_LIMIT
+	} else if 0 > limit {
+		err = invalidArgument(errors.New("Limit is negative"))
+	} else if MAX_PAGE_LIMIT < limit {
+		err = invalidArgument(fmt.Errorf("Limit exceeds %v", MAX_PAGE_LIMIT))
+	}
+
+	if nil == err {
+		err = pagination.Apply(col, after, limit)
+	}
+
+	if nil == err {
+		out, err = collect(col, mapf)
+	}
+
+	if l := len(out); nil == err && 0 != l {
+		v := id(out[l-1])
+		end = &v
+	}
+
+	return out, end, err
+}
+
+func single[T any](
+	col collection.Collection[result.Result[T]],
+	err error,
+) (T, error) {
+	var out T
+	res, err := singlewrap.Unwrap(col, err)
+
+	if nil == err {
+		out, err = res.Unwrap()
+	}
+
+	return out, err
+}
+
+type postService struct {
+	pb.UnimplementedPostServiceServer
+	service post.Service
+}
+
+func (self *postService) GetPosts(
+	ctx context.Context,
+	req *pb.GetPostsRequest,
+) (*pb.PostPage, error) {
+	out := new(pb.PostPage)
+	col, err := self.service.GetPosts(ctx, unmapPostOrder(req.GetOrder()))
+
+	if nil == err {
+		out.Data, out.EndId, err = paginate(col, req.GetPage(), mapPost, (*pb.Post).GetId)
+	}
+
+	return out, mapError(err)
+}
+
+func (self *postService) GetPostsById(
+	ctx context.Context,
+	req *pb.GetPostsByIdRequest,
+) (*pb.Posts, error) {
+	out := new(pb.Posts)
+	ids, err := parseIds("id", req.GetIds())
+
+	if nil == err {
+		col, cerr := self.service.GetPostsById(ctx, ids...)
+		err = cerr
+
+		if nil == err {
+			out.Data, err = collect(col, mapPost)
+		}
+	}
+
+	return out, mapError(err)
+}
+
+func (self *postService) CreatePost(
+	ctx context.Context,
+	req *pb.CreatePostRequest,
+) (*pb.Post, error) {
+	var created models.Post
+	userId, err := parseId("user_id", req.GetUserId())
+
+	if nil == err {
+		created, err = self.service.CreatePost(ctx, userId, unmapCreatePostRequest(req))
+	}
+
+	if nil == err {
+		return mapPost(&created), nil
+	} else {
+		return nil, mapError(err)
+	}
+}
+
+func (self *postService) UpdatePost(
+	ctx context.Context,
+	req *pb.UpdatePostRequest,
+) (*pb.Post, error) {
+	var value models.Post
+	var postId uuid.UUID
+	userId, err := parseId("user_id", req.GetUserId())
+
+	if nil == err {
+		postId, err = parseId("post_id", req.GetPostId())
+	}
+
+	if nil == err {
+		value, err = single(self.service.GetPostsById(ctx, postId))
+	}
+
+	if nil == err {
+		if nil != req.AllowComments {
+			value.CommentsAllowed = *req.AllowComments
+		}
+
+		value, err = self.service.UpdatePost(ctx, userId, value)
+	}
+
+	if nil == err {
+		return mapPost(&value), nil
+	} else {
+		return nil, mapError(err)
+	}
+}
+
+type commentService struct {
+	pb.UnimplementedCommentServiceServer
+	comment  comment.Service
+	post     post.Service
+	interval time.Duration
+	batch    uint
+	closing  context.Context
+}
+
+func (self *commentService) GetCommentsById(
+	ctx context.Context,
+	req *pb.GetCommentsByIdRequest,
+) (*pb.Comments, error) {
+	out := new(pb.Comments)
+	ids, err := parseIds("id", req.GetIds())
+
+	if nil == err {
+		col, cerr := self.comment.GetCommentsById(ctx, ids...)
+		err = cerr
+
+		if nil == err {
+			out.Data, err = collect(col, mapComment)
+		}
+	}
+
+	return out, mapError(err)
+}
+
+func (self *commentService) GetCommentsByPostId(
+	ctx context.Context,
+	req *pb.GetCommentsByPostIdRequest,
+) (*pb.CommentPage, error) {
+	var col collection.Collection[result.Result[models.Comment]]
+	out := new(pb.CommentPage)
+	postId, err := parseId("post_id", req.GetPostId())
+
+	if nil == err {
+		col, err = self.comment.GetCommentsByPostId(
+			ctx,
+			postId,
+			unmapCommentOrder(req.GetOrder()),
+		)
+	}
+
+	if nil == err {
+		out.Data, out.EndId, err = paginate(col, req.GetPage(), mapComment, (*pb.Comment).GetId)
+	}
+
+	return out, mapError(err)
+}
+
+func (self *commentService) GetCommentsByCommentId(
+	ctx context.Context,
+	req *pb.GetCommentsByCommentIdRequest,
+) (*pb.CommentPage, error) {
+	var col collection.Collection[result.Result[models.Comment]]
+	out := new(pb.CommentPage)
+	commentId, err := parseId("comment_id", req.GetCommentId())
+
+	if nil == err {
+		col, err = self.comment.GetCommentsByCommentId(
+			ctx,
+			commentId,
+			unmapCommentOrder(req.GetOrder()),
+		)
+	}
+
+	if nil == err {
+		out.Data, out.EndId, err = paginate(col, req.GetPage(), mapComment, (*pb.Comment).GetId)
+	}
+
+	return out, mapError(err)
+}
+
+func (self *commentService) CreatePostComment(
+	ctx context.Context,
+	req *pb.CreatePostCommentRequest,
+) (*pb.Comment, error) {
+	var created models.Comment
+	var postId uuid.UUID
+	userId, err := parseId("user_id", req.GetUserId())
+
+	if nil == err {
+		postId, err = parseId("post_id", req.GetPostId())
+	}
+
+	if nil == err {
+		created, err = self.comment.CreatePostComment(ctx, userId, postId,
+			comment.CommentForm{Content: req.GetContent()},
+		)
+	}
+
+	if nil == err {
+		return mapComment(&created), nil
+	} else {
+		return nil, mapError(err)
+	}
+}
+
+func (self *commentService) CreateCommentComment(
+	ctx context.Context,
+	req *pb.CreateCommentCommentRequest,
+) (*pb.Comment, error) {
+	var created models.Comment
+	var commentId uuid.UUID
+	userId, err := parseId("user_id", req.GetUserId())
+
+	if nil == err {
+		commentId, err = parseId("comment_id", req.GetCommentId())
+	}
+
+	if nil == err {
+		created, err = self.comment.CreateCommentComment(ctx, userId, commentId,
+			comment.CommentForm{Content: req.GetContent()},
+		)
+	}
+
+	if nil == err {
+		return mapComment(&created), nil
+	} else {
+		return nil, mapError(err)
+	}
+}
+
+// Latest comment of the post, new ones are streamed after it
+func (self *commentService) latest(
+	ctx context.Context,
+	postId uuid.UUID,
+) (*uuid.UUID, error) {
+	var out *uuid.UUID
+	col, err := self.comment.GetCommentsByPostId(
+		ctx,
+		postId,
+		comment.COMMENT_ORDER_DATE_DESC,
+	)
+
+	if nil == err {
+		col.Limit(1)
+		var comments []models.Comment
+
+		if comments, err = pagination.Collect(col); nil == err && 0 != len(comments) {
+			out = &comments[0].Id
+		}
+	}
+
+	return out, err
+}
+
+// Comments of the post after the cursor in creation order
+func (self *commentService) next(
+	ctx context.Context,
+	postId uuid.UUID,
+	after *uuid.UUID,
+) ([]models.Comment, error) {
+	var out []models.Comment
+	col, err := self.comment.GetCommentsByPostId(
+		ctx,
+		postId,
+		comment.COMMENT_ORDER_DATE_ASC,
+	)
+
+	if nil == err && nil != after {
+		err = col.After(*after)
+	}
+
+	if nil == err {
+		col.Limit(self.batch)
+		out, err = pagination.Collect(col)
+	}
+
+	return out, err
+}
+
+func (self *commentService) WatchComments(
+	req *pb.WatchCommentsRequest,
+	stream pb.CommentService_WatchCommentsServer,
+) error {
+	ctx := stream.Context()
+	var cursor *uuid.UUID
+	postId, err := parseId("post_id", req.GetPostId())
+
+	if nil == err {
+		_, err = single(self.post.GetPostsById(ctx, postId))
+	}
+
+	if nil == err {
+		if nil != req.After {
+			cursor, err = parseOptionalId("after", req.After)
+		} else {
+			cursor, err = self.latest(ctx, postId)
+		}
+	}
+
+	ticker := time.NewTicker(self.interval)
+	defer ticker.Stop()
+
+	for nil == err {
+		var comments []models.Comment
+		comments, err = self.next(ctx, postId, cursor)
+
+		for i := 0; nil == err && len(comments) > i; i++ {
+			err = stream.Send(mapComment(&comments[i]))
+			cursor = &comments[i].Id
+		}
+
+		// Full batch means there might be more pending right away
+		if nil == err && uint(len(comments)) < self.batch {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				err = ctx.Err()
+			case <-self.closing.Done():
+				err = status.Error(codes.Unavailable, "Server is shutting down")
+			}
+		}
+	}
+
+	return mapError(err)
+}
+
+type userService struct {
+	pb.UnimplementedUserServiceServer
+	service user.Service
+}
+
+func (self *userService) GetUsersById(
+	ctx context.Context,
+	req *pb.GetUsersByIdRequest,
+) (*pb.Users, error) {
+	out := new(pb.Users)
+	ids, err := parseIds("id", req.GetIds())
+
+	if nil == err {
+		col, cerr := self.service.GetUsersById(ctx, ids...)
+		err = cerr
+
+		if nil == err {
+			out.Data, err = collect(col, mapUser)
+		}
+	}
+
+	return out, mapError(err)
+}
+
@@ -1,7 +1,12 @@
 package application
 
+import "context"
+
+// Run serves until ctx is cancelled or serving fails, shutting the
+// application down gracefully in the former case. Clear releases whatever
+// is left and must be safe to call after Run has returned.
 type Application interface {
-	Run()
+	Run(ctx context.Context) error
 	Clear()
 }
 
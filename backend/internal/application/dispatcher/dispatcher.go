@@ -0,0 +1,142 @@
+package dispatcher
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/outbox"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+const (
+	DEFAULT_INTERVAL   = time.Second
+	DEFAULT_BATCH_SIZE = 100
+)
+
+// Deliveries and failures by event type, published along with the other
+// expvar variables
+var stats = expvar.NewMap("events")
+
+// Called for events of the type it's registered for. Delivery is at least
+// once: an event is delivered again to all of its handlers if any of them
+// fails or the process stops before the event is acknowledged, so handlers
+// must tolerate duplicates
+type Handler func(ctx context.Context, event *models.Event) error
+
+// Delivers pending events of the outbox to in-process handlers, oldest
+// first. Failed events are retried on the next pass, events after them are
+// delivered meanwhile, so order is kept only while handlers succeed
+type Dispatcher struct {
+	outbox   outbox.Repository
+	interval time.Duration
+	batch    int32
+	handlers map[string][]Handler
+}
+
+func New(repo outbox.Repository, interval time.Duration, batch int32) *Dispatcher {
+	return &Dispatcher{repo, interval, batch, make(map[string][]Handler)}
+}
+
+// Must be called before Run
+func (self *Dispatcher) Register(kind string, handler Handler) {
+	self.handlers[kind] = append(self.handlers[kind], handler)
+}
+
+func (self *Dispatcher) deliver(ctx context.Context, event *models.Event) (err error) {
+	defer func() {
+		if v := recover(); nil != v {
+			err = fmt.Errorf("handler panicked: %v", v)
+		}
+	}()
+
+	for _, handler := range self.handlers[event.Type] {
+		if err = handler(ctx, event); nil != err {
+			break
+		}
+	}
+
+	return err
+}
+
+// Single pass over events pending at the moment, acknowledged page by page.
+// Returns number of delivered events
+func (self *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	// Acknowledged events are gone, so only a failed one can be the cursor
+	var failed *uuid.UUID
+	delivered := 0
+	more := true
+	var err error
+
+	for nil == err && more {
+		var col collection.Collection[result.Result[models.Event]]
+		var page []models.Event
+		acknowledged := make([]uuid.UUID, 0, self.batch)
+		col, err = self.outbox.GetPendingEvents(ctx)
+
+		if nil == err {
+			err = pagination.Apply(col, failed, self.batch)
+		}
+
+		if nil == err {
+			page, err = pagination.Collect(col)
+			more = self.batch == int32(len(page))
+		}
+
+		for i := 0; nil == err && len(page) > i; i++ {
+			event := &page[i]
+
+			if err = ctx.Err(); nil != err {
+				break
+			} else if derr := self.deliver(ctx, event); nil == derr {
+				acknowledged = append(acknowledged, event.Id)
+				stats.Add(event.Type+".delivered", 1)
+			} else {
+				failed = &event.Id
+				stats.Add(event.Type+".failed", 1)
+				log.Printf("event %v of type %q failed, retrying later: %v", event.Id, event.Type, derr)
+			}
+		}
+
+		if 0 != len(acknowledged) {
+			if aerr := self.outbox.AcknowledgeEvents(ctx, acknowledged...); nil == err {
+				err = aerr
+			}
+		}
+
+		if nil == err {
+			delivered += len(acknowledged)
+		}
+	}
+
+	return delivered, err
+}
+
+func (self *Dispatcher) Run(ctx context.Context) error {
+	log.Printf("dispatching events every %v", self.interval)
+	ticker := time.NewTicker(self.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := self.Dispatch(ctx); nil != err && nil == ctx.Err() {
+			log.Printf("event dispatch failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (self *Dispatcher) Clear() {
+	log.Print("event dispatcher down")
+}
+
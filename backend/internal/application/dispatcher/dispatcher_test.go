@@ -0,0 +1,220 @@
+package dispatcher_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/application/dispatcher"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/outbox"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Outbox, which loses acknowledgements as if the process stopped right
+// before them
+type crashingOutbox struct {
+	outbox.Repository
+}
+
+func (self crashingOutbox) AcknowledgeEvents(context.Context, ...uuid.UUID) error {
+	return errors.New("crashed")
+}
+
+type recorder struct {
+	ids  []uuid.UUID
+	fail map[uuid.UUID]int
+}
+
+func newRecorder() *recorder {
+	return &recorder{nil, make(map[uuid.UUID]int)}
+}
+
+func (self *recorder) handle(_ context.Context, event *models.Event) error {
+	if 0 < self.fail[event.Id] {
+		self.fail[event.Id]--
+		return errors.New("handler failed")
+	}
+
+	self.ids = append(self.ids, event.EntityId)
+
+	return nil
+}
+
+func setup(t *testing.T) (*inmemory.Repository, models.User) {
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo := inmemory.New(func(adduser func(models.User), _ func(inmemory.Comment), _ func(models.Post)) {
+		adduser(user)
+	}).WithOutbox()
+
+	return repo, user
+}
+
+func createPosts(t *testing.T, repo *inmemory.Repository, user models.User, amount int) []uuid.UUID {
+	out := make([]uuid.UUID, amount)
+
+	for i := range out {
+		post := common.Unwrap(repo.CreatePost(context.Background(), common.Unwrap(
+			domainOM.PostDefault(
+				user.Id,
+				nullable.None[bool](),
+				nullable.None[string](),
+				nullable.Some(time.Now()),
+			).Build(),
+		)))
+		out[i] = post.Id
+	}
+
+	return out
+}
+
+func pending(t *testing.T, repo outbox.Repository) []models.Event {
+	return common.Unwrap(pagination.Collect(
+		common.Unwrap(repo.GetPendingEvents(context.Background())),
+	))
+}
+
+func TestDispatchDeliversInOrder(t *testing.T) {
+	// Arrange
+	repo, user := setup(t)
+	posts := createPosts(t, repo, user, 5)
+	rec := newRecorder()
+	disp := dispatcher.New(repo, time.Second, 2)
+	disp.Register(events.POST_CREATED, rec.handle)
+
+	// Act
+	delivered, err := disp.Dispatch(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 5, delivered, "All pages are dispatched")
+	assert.Equal(t, posts, rec.ids)
+	assert.Empty(t, pending(t, repo))
+}
+
+func TestDispatchRetriesFailed(t *testing.T) {
+	// Arrange
+	repo, user := setup(t)
+	posts := createPosts(t, repo, user, 3)
+	failing := pending(t, repo)[0].Id
+	rec := newRecorder()
+	rec.fail[failing] = 1
+	disp := dispatcher.New(repo, time.Second, 1)
+	disp.Register(events.POST_CREATED, rec.handle)
+
+	// Act
+	first, ferr := disp.Dispatch(context.Background())
+	left := pending(t, repo)
+	second, serr := disp.Dispatch(context.Background())
+
+	// Assert
+	require.NoError(t, ferr)
+	require.NoError(t, serr)
+	assert.Equal(t, 2, first, "Events after the failed one aren't blocked")
+	require.Len(t, left, 1)
+	assert.Equal(t, failing, left[0].Id)
+	assert.Equal(t, 1, second)
+	assert.Equal(t, []uuid.UUID{posts[1], posts[2], posts[0]}, rec.ids)
+	assert.Empty(t, pending(t, repo))
+}
+
+func TestDispatchRedeliversUnacknowledged(t *testing.T) {
+	// Arrange
+	repo, user := setup(t)
+	posts := createPosts(t, repo, user, 2)
+	rec := newRecorder()
+	crashed := dispatcher.New(crashingOutbox{repo}, time.Second, 10)
+	crashed.Register(events.POST_CREATED, rec.handle)
+	restarted := dispatcher.New(repo, time.Second, 10)
+	restarted.Register(events.POST_CREATED, rec.handle)
+
+	// Act
+	_, cerr := crashed.Dispatch(context.Background())
+	delivered, err := restarted.Dispatch(context.Background())
+
+	// Assert
+	assert.Error(t, cerr)
+	require.NoError(t, err)
+	assert.Equal(t, 2, delivered)
+	assert.Equal(t, append(posts, posts...), rec.ids, "At least once")
+	assert.Empty(t, pending(t, repo))
+}
+
+func TestDispatchPanicIsFailure(t *testing.T) {
+	// Arrange
+	repo, user := setup(t)
+	createPosts(t, repo, user, 1)
+	disp := dispatcher.New(repo, time.Second, 10)
+	disp.Register(events.POST_CREATED, func(context.Context, *models.Event) error {
+		panic("boom")
+	})
+
+	// Act
+	delivered, err := disp.Dispatch(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Len(t, pending(t, repo), 1)
+}
+
+func TestDispatchWithoutHandlers(t *testing.T) {
+	// Arrange
+	repo, user := setup(t)
+	createPosts(t, repo, user, 2)
+	disp := dispatcher.New(repo, time.Second, 10)
+
+	// Act
+	delivered, err := disp.Dispatch(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, delivered)
+	assert.Empty(t, pending(t, repo))
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	// Arrange
+	repo, user := setup(t)
+	rec := make(chan uuid.UUID, 10)
+	disp := dispatcher.New(repo, 10*time.Millisecond, 10)
+	disp.Register(events.POST_CREATED, func(_ context.Context, event *models.Event) error {
+		rec <- event.EntityId
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	// Act
+	go func() {
+		done <- disp.Run(ctx)
+	}()
+	posts := createPosts(t, repo, user, 1)
+
+	// Assert
+	select {
+	case id := <-rec:
+		assert.Equal(t, posts[0], id, "Events written while running are picked up")
+	case <-time.After(time.Second):
+		t.Fatal("event wasn't dispatched")
+	}
+
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("dispatcher didn't stop")
+	}
+}
+
@@ -0,0 +1,90 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	healthsrv "github.com/muji40k/ozontestcomms/internal/service/interface/health"
+)
+
+const DEFAULT_READINESS_TIMEOUT time.Duration = 2 * time.Second
+
+// Liveness and readiness endpoints shared by http based applications.
+// Liveness only reports that the process serves requests, readiness
+// additionally checks the service (if any) and turns negative as soon as
+// the owner starts shutting down.
+type Probe struct {
+	service  healthsrv.Service
+	timeout  time.Duration
+	shutdown atomic.Bool
+}
+
+func New(service healthsrv.Service, timeout time.Duration) *Probe {
+	if 0 >= timeout {
+		timeout = DEFAULT_READINESS_TIMEOUT
+	}
+
+	return &Probe{service: service, timeout: timeout}
+}
+
+type status struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func write(w http.ResponseWriter, code int, v status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (self *Probe) Shutdown() {
+	self.shutdown.Store(true)
+}
+
+func (self *Probe) Ready(ctx context.Context) error {
+	if self.shutdown.Load() {
+		return errShuttingDown
+	}
+
+	if nil == self.service {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, self.timeout)
+	defer cancel()
+
+	return self.service.Ping(ctx)
+}
+
+func (self *Probe) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		write(w, http.StatusOK, status{Status: "ok"})
+	})
+}
+
+func (self *Probe) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := self.Ready(r.Context()); nil == err {
+			write(w, http.StatusOK, status{Status: "ok"})
+		} else {
+			write(w, http.StatusServiceUnavailable, status{
+				Status: "unavailable",
+				Error:  err.Error(),
+			})
+		}
+	})
+}
+
+func (self *Probe) Register(mux *http.ServeMux) {
+	mux.Handle("/healthz", self.LiveHandler())
+	mux.Handle("/readyz", self.ReadyHandler())
+}
+
+var errShuttingDown = errors.New("Application is shutting down")
+
@@ -0,0 +1,166 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/muji40k/ozontestcomms/internal/application/health"
+)
+
+const DEFAULT_SHUTDOWN_TIMEOUT time.Duration = 10 * time.Second
+
+// Lifecycle shared by http based applications: serve until the context
+// is cancelled, then stop accepting connections, report not ready, let
+// in-flight requests finish until deadline and cancel the rest.
+type Server struct {
+	address string
+	timeout time.Duration
+	probe   *health.Probe
+	server  *http.Server
+
+	// Parent of every request context, cancelled when in-flight requests
+	// didn't manage to finish before shutdown deadline
+	base       context.Context
+	cancelBase context.CancelFunc
+
+	// Cancelled as soon as shutdown begins, closes detached connections,
+	// which are not tracked by http.Server.Shutdown
+	closing       context.Context
+	cancelClosing context.CancelFunc
+	detached      sync.WaitGroup
+}
+
+func New(
+	host string,
+	port string,
+	timeout time.Duration,
+	probe *health.Probe,
+) *Server {
+	out := &Server{
+		address: net.JoinHostPort(host, port),
+		timeout: timeout,
+		probe:   probe,
+	}
+
+	out.base, out.cancelBase = context.WithCancel(context.Background())
+	out.closing, out.cancelClosing = context.WithCancel(context.Background())
+
+	return out
+}
+
+func (self *Server) Address() string {
+	return self.address
+}
+
+func (self *Server) Probe() *health.Probe {
+	return self.probe
+}
+
+// Wraps handlers of long living connections (e.g. websockets), so that their
+// contexts are cancelled at the beginning of shutdown and shutdown waits for
+// them to close.
+func (self *Server) Detached(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		self.detached.Add(1)
+		defer self.detached.Done()
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		stop := context.AfterFunc(self.closing, cancel)
+		defer stop()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (self *Server) Run(ctx context.Context, handler http.Handler) error {
+	self.server = &http.Server{
+		Addr:    self.address,
+		Handler: handler,
+		BaseContext: func(net.Listener) context.Context {
+			return self.base
+		},
+	}
+
+	listener, err := net.Listen("tcp", self.address)
+
+	if nil != err {
+		return err
+	}
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- self.server.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		err = self.shutdown()
+	case err = <-served:
+		self.cancelClosing()
+		self.cancelBase()
+
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+	}
+
+	return err
+}
+
+func (self *Server) shutdown() error {
+	self.probe.Shutdown()
+	self.cancelClosing()
+
+	ctx, cancel := context.WithTimeout(context.Background(), self.timeout)
+	defer cancel()
+
+	err := self.server.Shutdown(ctx)
+
+	if nil == err {
+		detached := make(chan struct{})
+
+		go func() {
+			self.detached.Wait()
+			close(detached)
+		}()
+
+		select {
+		case <-detached:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+
+	// Whatever is still running gets its context cancelled
+	self.cancelBase()
+
+	if nil != err {
+		self.server.Close()
+		err = fmt.Errorf("Graceful shutdown failed: %w", err)
+	}
+
+	return err
+}
+
+func (self *Server) Clear() {
+	if nil == self.server {
+		return
+	}
+
+	self.probe.Shutdown()
+	self.cancelClosing()
+	self.cancelBase()
+
+	if err := self.server.Close(); nil != err && !errors.Is(err, net.ErrClosed) {
+		log.Printf("close error: %s", err)
+	}
+}
+
@@ -0,0 +1,68 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/muji40k/ozontestcomms/internal/application"
+)
+
+type unit struct {
+	name string
+	app  application.Application
+}
+
+// Runs several applications side by side. As soon as any of them stops,
+// whether failed or not, the rest are asked to shut down. Applications are
+// not cleared, that is left to the owner.
+type Supervisor struct {
+	units []unit
+}
+
+func New() *Supervisor {
+	return &Supervisor{make([]unit, 0)}
+}
+
+func (self *Supervisor) Add(name string, app application.Application) {
+	self.units = append(self.units, unit{name, app})
+}
+
+var errStopped = errors.New("Application stopped unexpectedly")
+
+func (self *Supervisor) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, len(self.units))
+
+	for _, u := range self.units {
+		go func() {
+			err := u.app.Run(ctx)
+
+			if nil == err && nil == ctx.Err() {
+				err = errStopped
+			}
+
+			if nil != err {
+				err = fmt.Errorf("%v: %w", u.name, err)
+				log.Printf("stopping all applications: %v", err)
+			}
+
+			cancel()
+			done <- err
+		}()
+	}
+
+	errs := make([]error, 0)
+
+	for range self.units {
+		if err := <-done; nil != err {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
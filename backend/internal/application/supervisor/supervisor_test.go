@@ -0,0 +1,119 @@
+package supervisor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/muji40k/ozontestcomms/internal/application/supervisor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Application, which serves until cancelled or fails after delay
+type fakeApplication struct {
+	fail    error
+	delay   time.Duration
+	stopped chan struct{}
+}
+
+func newFake(fail error, delay time.Duration) *fakeApplication {
+	return &fakeApplication{fail, delay, make(chan struct{})}
+}
+
+func (self *fakeApplication) Run(ctx context.Context) error {
+	defer close(self.stopped)
+
+	if 0 == self.delay {
+		<-ctx.Done()
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-time.After(self.delay):
+		return self.fail
+	}
+}
+
+func (self *fakeApplication) Clear() {}
+
+func stopped(app *fakeApplication) bool {
+	select {
+	case <-app.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+func run(t *testing.T, ctx context.Context, sv *supervisor.Supervisor) error {
+	done := make(chan error, 1)
+
+	go func() {
+		done <- sv.Run(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor didn't stop")
+		return nil
+	}
+}
+
+func TestSupervisorStopsAllOnCancel(t *testing.T) {
+	// Arrange
+	first, second := newFake(nil, 0), newFake(nil, 0)
+	sv := supervisor.New()
+	sv.Add("first", first)
+	sv.Add("second", second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act
+	err := run(t, ctx, sv)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, stopped(first))
+	assert.True(t, stopped(second))
+}
+
+func TestSupervisorStopsAllOnFailure(t *testing.T) {
+	// Arrange
+	failure := errors.New("failure")
+	failing, serving := newFake(failure, time.Millisecond), newFake(nil, 0)
+	sv := supervisor.New()
+	sv.Add("failing", failing)
+	sv.Add("serving", serving)
+
+	// Act
+	err := run(t, context.Background(), sv)
+
+	// Assert
+	require.Error(t, err)
+	assert.ErrorIs(t, err, failure)
+	assert.Contains(t, err.Error(), "failing")
+	assert.True(t, stopped(serving))
+}
+
+func TestSupervisorStopsAllOnUnexpectedExit(t *testing.T) {
+	// Arrange
+	exiting, serving := newFake(nil, time.Millisecond), newFake(nil, 0)
+	sv := supervisor.New()
+	sv.Add("exiting", exiting)
+	sv.Add("serving", serving)
+
+	// Act
+	err := run(t, context.Background(), sv)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exiting")
+	assert.True(t, stopped(serving))
+}
+
@@ -0,0 +1,260 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"expvar"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/domain/webhooks"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	webhookrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/webhook"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+const USER_AGENT = "poster-webhooks"
+
+// Response bodies are ignored, only this much is read so that connections
+// can be reused
+const RESPONSE_LIMIT = 4096
+
+type Options struct {
+	Interval time.Duration
+	// Of a single request
+	Timeout time.Duration
+	// Delivery is dead once this many attempts failed
+	MaxAttempts int
+	// Delay after the first failed attempt, doubled after every next one
+	// up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	BatchSize      int32
+	// Receivers on internal addresses are refused otherwise, see
+	// webhooks.Internal
+	AllowInternal bool
+}
+
+var DEFAULT_OPTIONS = Options{
+	Interval:       time.Second,
+	Timeout:        10 * time.Second,
+	MaxAttempts:    8,
+	InitialBackoff: 10 * time.Second,
+	MaxBackoff:     time.Hour,
+	BatchSize:      100,
+}
+
+// Attempts by outcome, published along with the other expvar variables
+var stats = expvar.NewMap("webhooks")
+
+// Sends due deliveries to their webhooks with signed POST requests. Any
+// 2xx response is a success, everything else including redirects is
+// retried with exponential backoff until the attempts are exhausted
+type Sender struct {
+	repo    webhookrepo.Repository
+	client  *http.Client
+	options Options
+}
+
+// Checks the address a name is resolved to right before connecting, so
+// that names resolving to internal addresses are refused as well
+func refuseInternal(network string, address string, _ syscall.RawConn) error {
+	addr, err := netip.ParseAddrPort(address)
+
+	if nil == err && webhooks.Internal(addr.Addr()) {
+		err = fmt.Errorf("%v is an internal address", addr.Addr())
+	}
+
+	return err
+}
+
+func New(repo webhookrepo.Repository, options Options) *Sender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if !options.AllowInternal {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   refuseInternal,
+		}
+		transport.DialContext = dialer.DialContext
+		// A proxy would connect on the sender's behalf, past the check
+		transport.Proxy = nil
+	}
+
+	return &Sender{
+		repo: repo,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   options.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		options: options,
+	}
+}
+
+// Delay before the next attempt after the given number of failed ones
+func (self *Sender) backoff(attempts int) time.Duration {
+	out := self.options.InitialBackoff
+
+	for i := 1; attempts > i && self.options.MaxBackoff > out; i++ {
+		out *= 2
+	}
+
+	return min(out, self.options.MaxBackoff)
+}
+
+// Status code and error of the attempt, error is empty on success
+func (self *Sender) post(
+	ctx context.Context,
+	webhook *models.Webhook,
+	delivery *models.WebhookDelivery,
+) (int, string) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		webhook.Url,
+		bytes.NewReader(delivery.Payload),
+	)
+
+	if nil != err {
+		return 0, err.Error()
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", USER_AGENT)
+	request.Header.Set(webhooks.HEADER_EVENT, delivery.EventType)
+	request.Header.Set(webhooks.HEADER_DELIVERY, delivery.Id.String())
+	request.Header.Set(webhooks.HEADER_TIMESTAMP, timestamp)
+	request.Header.Set(
+		webhooks.HEADER_SIGNATURE,
+		webhooks.Sign(webhook.Secret, timestamp, delivery.Payload),
+	)
+
+	response, err := self.client.Do(request)
+
+	if nil != err {
+		return 0, err.Error()
+	}
+
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, RESPONSE_LIMIT))
+
+	if 200 <= response.StatusCode && 300 > response.StatusCode {
+		return response.StatusCode, ""
+	}
+
+	return response.StatusCode, response.Status
+}
+
+func (self *Sender) attempt(
+	ctx context.Context,
+	webhook *models.Webhook,
+	delivery models.WebhookDelivery,
+) error {
+	delivery.StatusCode, delivery.Error = self.post(ctx, webhook, &delivery)
+	delivery.Attempts++
+	delivery.LastAttempt = time.Now()
+
+	if "" == delivery.Error {
+		delivery.Status = models.DELIVERY_STATUS_DELIVERED
+		stats.Add("delivered", 1)
+	} else if self.options.MaxAttempts <= delivery.Attempts {
+		delivery.Status = models.DELIVERY_STATUS_DEAD
+		stats.Add("dead", 1)
+		log.Printf("delivery %v to webhook %v is dead after %v attempts: %v",
+			delivery.Id, webhook.Id, delivery.Attempts, delivery.Error)
+	} else {
+		delivery.NextAttempt = delivery.LastAttempt.Add(self.backoff(delivery.Attempts))
+		stats.Add("failed", 1)
+	}
+
+	_, err := self.repo.UpdateDelivery(ctx, delivery)
+
+	return err
+}
+
+// Single batch of deliveries due at the moment, sent one by one. Returns
+// number of attempts made
+func (self *Sender) Send(ctx context.Context) (int, error) {
+	var page []models.WebhookDelivery
+	hooks := make(map[uuid.UUID]*models.Webhook)
+	attempted := 0
+	col, err := self.repo.GetDueDeliveries(ctx, time.Now())
+
+	if nil == err {
+		err = pagination.Apply(col, nil, self.options.BatchSize)
+	}
+
+	if nil == err {
+		page, err = pagination.Collect(col)
+	}
+
+	for i := 0; nil == err && len(page) > i; i++ {
+		delivery := page[i]
+		webhook, found := hooks[delivery.WebhookId]
+
+		if !found {
+			var wcol collection.Collection[result.Result[models.Webhook]]
+			var matched []models.Webhook
+			wcol, err = self.repo.GetWebhooksById(ctx, delivery.WebhookId)
+
+			if nil == err {
+				matched, _ = pagination.Collect(wcol)
+			}
+
+			// Removed meanwhile along with its deliveries
+			if 1 == len(matched) {
+				webhook = &matched[0]
+			}
+
+			hooks[delivery.WebhookId] = webhook
+		}
+
+		if nil == err && nil != webhook {
+			if err = ctx.Err(); nil == err {
+				err = self.attempt(ctx, webhook, delivery)
+				attempted++
+			}
+		}
+	}
+
+	return attempted, err
+}
+
+func (self *Sender) Run(ctx context.Context) error {
+	log.Printf("sending webhooks every %v", self.options.Interval)
+	ticker := time.NewTicker(self.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := self.Send(ctx); nil != err && nil == ctx.Err() {
+			log.Printf("webhook sending failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (self *Sender) Clear() {
+	self.client.CloseIdleConnections()
+	log.Print("webhook sender down")
+}
+
@@ -0,0 +1,285 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/application/webhooks"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	webhookdomain "github.com/muji40k/ozontestcomms/internal/domain/webhooks"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	webhooksrv "github.com/muji40k/ozontestcomms/internal/service/interface/webhook"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+// Receiver answering with the given codes in turn, the last one repeats
+type receiver struct {
+	mutex    sync.Mutex
+	codes    []int
+	requests []request
+}
+
+func (self *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	self.requests = append(self.requests, request{r.Header.Clone(), body})
+	code := self.codes[0]
+
+	if 1 < len(self.codes) {
+		self.codes = self.codes[1:]
+	}
+
+	w.WriteHeader(code)
+}
+
+type fixture struct {
+	repo   *inmemory.Repository
+	logic  *webhookdomain.Logic
+	user   models.User
+	server *httptest.Server
+	hook   models.Webhook
+}
+
+func setup(t *testing.T, rec *receiver) *fixture {
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo := inmemory.New(func(adduser func(models.User), _ func(inmemory.Comment), _ func(models.Post)) {
+		adduser(user)
+	})
+	logic := webhookdomain.New(webhookdomain.Context{Webhook: repo, Post: repo, User: repo}).
+		WithInternalAddresses(true)
+	server := httptest.NewServer(rec)
+	t.Cleanup(server.Close)
+	hook := common.Unwrap(logic.CreateWebhook(context.Background(), user.Id, webhooksrv.WebhookCreationForm{
+		Url:    server.URL,
+		Events: []string{events.POST_CREATED},
+	}))
+
+	return &fixture{repo, logic, user, server, hook}
+}
+
+func (self *fixture) publish(t *testing.T) models.Post {
+	post := common.Unwrap(self.repo.CreatePost(context.Background(), common.Unwrap(
+		domainOM.PostDefault(
+			self.user.Id,
+			nullable.None[bool](),
+			nullable.None[string](),
+			nullable.Some(time.Now()),
+		).Build(),
+	)))
+	event := common.Unwrap(events.PostCreated(&post))
+	require.NoError(t, self.logic.Handle(context.Background(), &event))
+
+	return post
+}
+
+func (self *fixture) deliveries(t *testing.T, status ...models.DeliveryStatus) []models.WebhookDelivery {
+	return common.Unwrap(pagination.Collect(common.Unwrap(
+		self.logic.GetDeliveries(context.Background(), self.user.Id, self.hook.Id, status...),
+	)))
+}
+
+func options(attempts int, initial time.Duration, max time.Duration) webhooks.Options {
+	out := webhooks.DEFAULT_OPTIONS
+	out.Timeout = time.Second
+	out.MaxAttempts = attempts
+	out.InitialBackoff = initial
+	out.MaxBackoff = max
+	// Receivers are local servers
+	out.AllowInternal = true
+
+	return out
+}
+
+func TestSendSignsPayload(t *testing.T) {
+	// Arrange
+	rec := &receiver{codes: []int{http.StatusNoContent}}
+	f := setup(t, rec)
+	post := f.publish(t)
+	sender := webhooks.New(f.repo, options(3, time.Hour, time.Hour))
+
+	// Act
+	attempted, err := sender.Send(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+	require.Len(t, rec.requests, 1)
+	req := rec.requests[0]
+	assert.True(t, webhookdomain.Verify(
+		f.hook.Secret,
+		req.header.Get(webhookdomain.HEADER_TIMESTAMP),
+		req.body,
+		req.header.Get(webhookdomain.HEADER_SIGNATURE),
+	))
+	assert.False(t, webhookdomain.Verify(
+		"other",
+		req.header.Get(webhookdomain.HEADER_TIMESTAMP),
+		req.body,
+		req.header.Get(webhookdomain.HEADER_SIGNATURE),
+	), "Signature depends on the secret")
+	assert.Equal(t, events.POST_CREATED, req.header.Get(webhookdomain.HEADER_EVENT))
+
+	var envelope webhookdomain.Envelope
+	var payload events.Post
+	require.NoError(t, json.Unmarshal(req.body, &envelope))
+	require.NoError(t, json.Unmarshal(envelope.Data, &payload))
+	assert.Equal(t, events.POST_CREATED, envelope.Type)
+	assert.Equal(t, post.Id, payload.Id)
+
+	delivered := f.deliveries(t)
+	require.Len(t, delivered, 1)
+	assert.Equal(t, req.header.Get(webhookdomain.HEADER_DELIVERY), delivered[0].Id.String())
+	assert.Equal(t, models.DELIVERY_STATUS_DELIVERED, delivered[0].Status)
+	assert.Equal(t, 1, delivered[0].Attempts)
+	assert.Equal(t, http.StatusNoContent, delivered[0].StatusCode)
+}
+
+func TestSendRetriesWithBackoff(t *testing.T) {
+	// Arrange
+	rec := &receiver{codes: []int{500, 500, 500, 200}}
+	f := setup(t, rec)
+	f.publish(t)
+	sender := webhooks.New(f.repo, options(5, time.Millisecond, 2*time.Millisecond))
+	delays := make([]time.Duration, 0)
+
+	// Act
+	for range 4 {
+		_, err := sender.Send(context.Background())
+		require.NoError(t, err)
+		delivery := f.deliveries(t)[0]
+
+		if models.DELIVERY_STATUS_PENDING == delivery.Status {
+			delays = append(delays, delivery.NextAttempt.Sub(delivery.LastAttempt))
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Assert
+	assert.Len(t, rec.requests, 4)
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 2 * time.Millisecond},
+		delays, "Doubled up to the maximum",
+	)
+	delivery := f.deliveries(t)[0]
+	assert.Equal(t, models.DELIVERY_STATUS_DELIVERED, delivery.Status)
+	assert.Equal(t, 4, delivery.Attempts)
+	assert.Empty(t, delivery.Error)
+}
+
+func TestSendWaitsForBackoff(t *testing.T) {
+	// Arrange
+	rec := &receiver{codes: []int{503}}
+	f := setup(t, rec)
+	f.publish(t)
+	sender := webhooks.New(f.repo, options(5, time.Hour, time.Hour))
+
+	// Act
+	first, ferr := sender.Send(context.Background())
+	second, serr := sender.Send(context.Background())
+
+	// Assert
+	require.NoError(t, ferr)
+	require.NoError(t, serr)
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 0, second, "Next attempt isn't due yet")
+	delivery := f.deliveries(t)[0]
+	assert.Equal(t, 503, delivery.StatusCode)
+	assert.Equal(t, "503 Service Unavailable", delivery.Error)
+}
+
+func TestSendDeadLetters(t *testing.T) {
+	// Arrange
+	rec := &receiver{codes: []int{500, 500, 200}}
+	f := setup(t, rec)
+	f.publish(t)
+	f.publish(t)
+	sender := webhooks.New(f.repo, options(1, time.Hour, time.Hour))
+	ctx := context.Background()
+
+	// Act
+	_, err := sender.Send(ctx)
+	dead := f.deliveries(t, models.DELIVERY_STATUS_DEAD)
+	var retried models.WebhookDelivery
+	var rerr error
+
+	if 0 != len(dead) {
+		retried, rerr = f.logic.RetryDelivery(ctx, f.user.Id, dead[0].Id)
+	}
+
+	_, serr := sender.Send(ctx)
+	_, aerr := f.logic.RetryDelivery(ctx, f.user.Id, dead[0].Id)
+	_, oerr := f.logic.RetryDelivery(ctx, uuid.New(), dead[1].Id)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, dead, 2, "Out of attempts after the first failure")
+	require.NoError(t, rerr)
+	assert.Equal(t, models.DELIVERY_STATUS_PENDING, retried.Status)
+	assert.Equal(t, 0, retried.Attempts)
+	require.NoError(t, serr)
+	assert.Len(t, rec.requests, 3)
+	assert.Len(t, f.deliveries(t, models.DELIVERY_STATUS_DELIVERED), 1)
+	assert.Len(t, f.deliveries(t, models.DELIVERY_STATUS_DEAD), 1)
+	assert.Error(t, aerr, "Only dead deliveries are retried")
+	assert.Error(t, oerr, "Only by the owner")
+}
+
+func TestSendRefusesInternalAddresses(t *testing.T) {
+	// Arrange
+	rec := &receiver{codes: []int{200}}
+	f := setup(t, rec)
+	f.publish(t)
+	options := options(1, time.Hour, time.Hour)
+	options.AllowInternal = false
+	sender := webhooks.New(f.repo, options)
+
+	// Act
+	_, err := sender.Send(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, rec.requests)
+	dead := f.deliveries(t, models.DELIVERY_STATUS_DEAD)
+	require.Len(t, dead, 1)
+	assert.Contains(t, dead[0].Error, "127.0.0.1 is an internal address")
+	assert.Zero(t, dead[0].StatusCode)
+}
+
+func TestSendSkipsUnsubscribed(t *testing.T) {
+	// Arrange
+	rec := &receiver{codes: []int{200}}
+	f := setup(t, rec)
+	common.Unwrap(f.logic.CreateWebhook(context.Background(), f.user.Id, webhooksrv.WebhookCreationForm{
+		Url:    f.server.URL,
+		Events: []string{events.COMMENT_CREATED},
+	}))
+	f.publish(t)
+	sender := webhooks.New(f.repo, options(1, time.Hour, time.Hour))
+
+	// Act
+	attempted, err := sender.Send(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempted, "Only the webhook subscribed to the event")
+}
+
@@ -0,0 +1,118 @@
+// Domain events recorded by repositories in the same transaction as the
+// write, with json payloads, so that they can be stored and delivered
+// outside of the process
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+)
+
+const (
+	USER_CREATED    = "user.created"
+	POST_CREATED    = "post.created"
+	POST_UPDATED    = "post.updated"
+	COMMENT_CREATED = "comment.created"
+)
+
+var TYPES = []string{USER_CREATED, POST_CREATED, POST_UPDATED, COMMENT_CREATED}
+
+// Payload of USER_CREATED, password is left out
+type User struct {
+	Id    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+}
+
+// Payload of POST_CREATED and POST_UPDATED
+type Post struct {
+	Id              uuid.UUID `json:"id"`
+	AuthorId        uuid.UUID `json:"author_id"`
+	Title           string    `json:"title"`
+	Content         string    `json:"content"`
+	CommentsAllowed bool      `json:"comments_allowed"`
+	CreationDate    time.Time `json:"creation_date"`
+}
+
+// Payload of COMMENT_CREATED. Post is the root of the thread, parent is
+// the comment replied to, none for top level comments
+type Comment struct {
+	Id           uuid.UUID  `json:"id"`
+	AuthorId     uuid.UUID  `json:"author_id"`
+	PostId       uuid.UUID  `json:"post_id"`
+	ParentId     *uuid.UUID `json:"parent_id,omitempty"`
+	Content      string     `json:"content"`
+	CreationDate time.Time  `json:"creation_date"`
+}
+
+func newEvent(kind string, entity uuid.UUID, payload any) (models.Event, error) {
+	out := models.Event{
+		Type:         kind,
+		EntityId:     entity,
+		CreationDate: time.Now(),
+	}
+	id, err := uuid.NewRandom()
+
+	if nil == err {
+		out.Id = id
+		out.Payload, err = json.Marshal(payload)
+	}
+
+	return out, err
+}
+
+func UserCreated(user *models.User) (models.Event, error) {
+	return newEvent(USER_CREATED, user.Id, User{user.Id, user.Email})
+}
+
+func mapPost(post *models.Post) Post {
+	return Post{
+		Id:              post.Id,
+		AuthorId:        post.AuthorId,
+		Title:           post.Title,
+		Content:         post.Content,
+		CommentsAllowed: post.CommentsAllowed,
+		CreationDate:    post.CreationDate,
+	}
+}
+
+func PostCreated(post *models.Post) (models.Event, error) {
+	return newEvent(POST_CREATED, post.Id, mapPost(post))
+}
+
+func PostUpdated(post *models.Post) (models.Event, error) {
+	return newEvent(POST_UPDATED, post.Id, mapPost(post))
+}
+
+// Target of the comment is internal to repositories, so the post and the
+// parent comment are passed separately
+func CommentCreated(
+	comment *models.Comment,
+	postId uuid.UUID,
+	parentId *uuid.UUID,
+) (models.Event, error) {
+	return newEvent(COMMENT_CREATED, comment.Id, Comment{
+		Id:           comment.Id,
+		AuthorId:     comment.AuthorId,
+		PostId:       postId,
+		ParentId:     parentId,
+		Content:      comment.Content,
+		CreationDate: comment.CreationDate,
+	})
+}
+
+// Payload of the event, T must match its type
+func Decode[T any](event *models.Event) (T, error) {
+	var out T
+	err := json.Unmarshal(event.Payload, &out)
+
+	if nil != err {
+		err = fmt.Errorf("event %v of type %q: %w", event.Id, event.Type, err)
+	}
+
+	return out, err
+}
+
@@ -0,0 +1,91 @@
+package follows
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	followrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/follow"
+	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
+	followsrv "github.com/muji40k/ozontestcomms/internal/service/interface/follow"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+type Context struct {
+	Follows followrepo.Repository
+}
+
+type Logic struct {
+	Context
+}
+
+func New(context Context) *Logic {
+	return &Logic{context}
+}
+
+func mapRepoError[T any](v T, err error) (T, error) {
+	if nil == err {
+		return v, nil
+	} else if cerr := (repoerrors.ErrorNotFound{}); errors.As(err, &cerr) {
+		return v, srverrors.NotFound(cerr.What...)
+	} else {
+		return v, srverrors.Internal(srverrors.DataAccess(err))
+	}
+}
+
+func mapFollowKind(kind followsrv.FollowKind) models.FollowKind {
+	switch kind {
+	case followsrv.FOLLOW_KIND_AUTHOR:
+		return models.FOLLOW_KIND_AUTHOR
+	case followsrv.FOLLOW_KIND_POST:
+		return models.FOLLOW_KIND_POST
+	default:
+		panic("Unknown kind")
+	}
+}
+
+func (self *Logic) Follow(
+	ctx context.Context,
+	userId uuid.UUID,
+	kind followsrv.FollowKind,
+	targetId uuid.UUID,
+) (bool, error) {
+	if followsrv.FOLLOW_KIND_AUTHOR == kind && userId == targetId {
+		return false, srverrors.Incorrect("users can't follow themselves")
+	}
+
+	return mapRepoError(self.Follows.CreateFollow(ctx, models.Follow{
+		UserId:       userId,
+		Kind:         mapFollowKind(kind),
+		TargetId:     targetId,
+		CreationDate: time.Now(),
+	}))
+}
+
+func (self *Logic) Unfollow(
+	ctx context.Context,
+	userId uuid.UUID,
+	kind followsrv.FollowKind,
+	targetId uuid.UUID,
+) (bool, error) {
+	return mapRepoError(self.Follows.DeleteFollow(ctx, userId, mapFollowKind(kind), targetId))
+}
+
+func (self *Logic) GetFeed(
+	ctx context.Context,
+	userId uuid.UUID,
+) (collection.Collection[result.Result[models.Post]], error) {
+	return mapRepoError(self.Follows.GetFeed(ctx, userId))
+}
+
+func (self *Logic) GetWatchedPosts(
+	ctx context.Context,
+	userId uuid.UUID,
+) (collection.Collection[result.Result[models.Post]], error) {
+	return mapRepoError(self.Follows.GetWatchedPosts(ctx, userId))
+}
+
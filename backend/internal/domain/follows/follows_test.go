@@ -0,0 +1,97 @@
+package follows
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	followsrv "github.com/muji40k/ozontestcomms/internal/service/interface/follow"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Reader and the author of the post
+func setup(t *testing.T) (*Logic, models.User, models.User, models.Post) {
+	reader := common.Unwrap(domainOM.UserRandom().Build())
+	author := common.Unwrap(domainOM.UserRandom().Build())
+	post := common.Unwrap(domainOM.PostDefault(
+		author.Id,
+		nullable.Some(true),
+		nullable.None[string](),
+		nullable.Some(time.Now()),
+	).Build())
+	repo := inmemory.New(func(adduser func(models.User), _ func(inmemory.Comment), addpost func(models.Post)) {
+		adduser(reader)
+		adduser(author)
+		addpost(post)
+	})
+
+	return New(Context{Follows: repo}), reader, author, post
+}
+
+func TestFollow(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	logic, reader, author, post := setup(t)
+
+	// Act
+	followed, ferr := logic.Follow(ctx, reader.Id, followsrv.FOLLOW_KIND_AUTHOR, author.Id)
+	watched, werr := logic.Follow(ctx, reader.Id, followsrv.FOLLOW_KIND_POST, post.Id)
+	again, aerr := logic.Follow(ctx, reader.Id, followsrv.FOLLOW_KIND_POST, post.Id)
+	feed, fcerr := logic.GetFeed(ctx, reader.Id)
+
+	// Assert
+	require.NoError(t, ferr)
+	require.NoError(t, werr)
+	require.NoError(t, aerr)
+	require.NoError(t, fcerr)
+	assert.True(t, followed)
+	assert.True(t, watched)
+	assert.False(t, again)
+	assert.Equal(t, []models.Post{post}, common.Unwrap(pagination.Collect(feed)))
+}
+
+func TestFollowErrors(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	logic, reader, _, _ := setup(t)
+
+	// Act
+	_, serr := logic.Follow(ctx, reader.Id, followsrv.FOLLOW_KIND_AUTHOR, reader.Id)
+	_, nerr := logic.Follow(ctx, reader.Id, followsrv.FOLLOW_KIND_POST, uuid.New())
+
+	// Assert
+	assert.ErrorAs(t, serr, &srverrors.ErrorIncorrect{})
+	assert.ErrorAs(t, nerr, &srverrors.ErrorNotFound{})
+}
+
+func TestUnfollow(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	logic, reader, author, post := setup(t)
+	common.Unwrap(logic.Follow(ctx, reader.Id, followsrv.FOLLOW_KIND_AUTHOR, author.Id))
+	common.Unwrap(logic.Follow(ctx, reader.Id, followsrv.FOLLOW_KIND_POST, post.Id))
+
+	// Act
+	unfollowed, err := logic.Unfollow(ctx, reader.Id, followsrv.FOLLOW_KIND_AUTHOR, author.Id)
+	again, aerr := logic.Unfollow(ctx, reader.Id, followsrv.FOLLOW_KIND_AUTHOR, author.Id)
+	feed := common.Unwrap(pagination.Collect(common.Unwrap(logic.GetFeed(ctx, reader.Id))))
+	watched := common.Unwrap(pagination.Collect(common.Unwrap(logic.GetWatchedPosts(ctx, reader.Id))))
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, aerr)
+	assert.True(t, unfollowed)
+	assert.False(t, again)
+	assert.Empty(t, feed)
+	assert.Equal(t, []models.Post{post}, watched)
+}
+
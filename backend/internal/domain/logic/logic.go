@@ -3,14 +3,20 @@ package logic
 import (
 	"context"
 	"errors"
+	"expvar"
 	"fmt"
+	"log"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/domain/moderation"
 	"github.com/muji40k/ozontestcomms/internal/repository/collection"
 	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
 	commrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	healthrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/health"
+	moderationrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/moderation"
 	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
 	usrrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/user"
 	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
@@ -26,12 +32,48 @@ type Context struct {
 	User    usrrepo.Repository
 }
 
+// Maximum content lengths in bytes
+type Limits struct {
+	CommentContent int
+	PostTitle      int
+	PostContent    int
+}
+
+var DEFAULT_LIMITS = Limits{
+	CommentContent: models.COMMENT_CONTENT_LENGTH_LIMIT,
+	PostTitle:      models.POST_TITLE_LENGTH_LIMIT,
+	PostContent:    models.POST_CONTENT_LENGTH_LIMIT,
+}
+
+// Content is checked by pipeline before it's created, reviews of flagged and
+// hidden content are put to the queue
+type Moderation struct {
+	Pipeline *moderation.Pipeline
+	Reviews  moderationrepo.Repository
+}
+
+// Flagged content by outcome of recording its review, published along with
+// the other expvar variables
+var stats = expvar.NewMap("moderation")
+
 type Logic struct {
 	Context
+	limits     Limits
+	moderation *Moderation
 }
 
 func New(context Context) *Logic {
-	return &Logic{context}
+	return NewWithLimits(context, DEFAULT_LIMITS)
+}
+
+func NewWithLimits(context Context, limits Limits) *Logic {
+	return &Logic{context, limits, nil}
+}
+
+// Without moderation all content is accepted
+func (self *Logic) WithModeration(moderation Moderation) *Logic {
+	self.moderation = &moderation
+	return self
 }
 
 func mapRepoError[T any](v T, err error) (T, error) {
@@ -44,6 +86,89 @@ func mapRepoError[T any](v T, err error) (T, error) {
 	}
 }
 
+func newReview(
+	content *moderation.Content,
+	verdict moderation.Verdict,
+	contentId uuid.UUID,
+	commentsAllowed bool,
+) models.Review {
+	return models.Review{
+		Kind:            content.Kind,
+		Action:          verdict.Action,
+		ContentId:       contentId,
+		AuthorId:        content.AuthorId,
+		TargetId:        content.TargetId,
+		Title:           content.Title,
+		Content:         content.Text,
+		CommentsAllowed: commentsAllowed,
+		Reasons:         verdict.Reasons,
+		CreationDate:    content.Date,
+	}
+}
+
+// Rejected and hidden content isn't created, the hidden one is kept in review
+// until a moderator approves it
+func (self *Logic) moderate(
+	ctx context.Context,
+	content *moderation.Content,
+	commentsAllowed bool,
+) (moderation.Verdict, error) {
+	var verdict moderation.Verdict
+	var err error
+
+	if nil == self.moderation {
+		return verdict, nil
+	}
+
+	if verdict, err = self.moderation.Pipeline.Check(ctx, content); nil != err {
+		err = srverrors.Internal(err)
+	}
+
+	if nil == err {
+		switch verdict.Action {
+		case models.MODERATION_ACTION_REJECT:
+			err = srverrors.Violation(verdict.Reasons...)
+		case models.MODERATION_ACTION_HIDE:
+			_, err = mapRepoError(self.moderation.Reviews.CreateReview(
+				ctx,
+				newReview(content, verdict, uuid.UUID{}, commentsAllowed),
+			))
+
+			if nil == err {
+				err = srverrors.Violation("content is held for moderator review")
+			}
+		}
+	}
+
+	return verdict, err
+}
+
+// Flagged content stays published even if the review isn't recorded, the
+// failure is only logged, so that clients don't retry and duplicate it
+func (self *Logic) flag(
+	ctx context.Context,
+	content *moderation.Content,
+	verdict moderation.Verdict,
+	contentId uuid.UUID,
+	commentsAllowed bool,
+) {
+	if models.MODERATION_ACTION_FLAG != verdict.Action {
+		return
+	}
+
+	_, err := self.moderation.Reviews.CreateReview(
+		ctx,
+		newReview(content, verdict, contentId, commentsAllowed),
+	)
+
+	if nil == err {
+		stats.Add("flagged", 1)
+	} else {
+		stats.Add("flag_failed", 1)
+		log.Printf("review of flagged content %v isn't recorded: %v", contentId, err)
+	}
+}
+
 func mapPostOrder(order postsrv.PostOrder) postrepo.PostOrder {
 	switch order {
 	case postsrv.POST_ORDER_DATE_ASC:
@@ -116,13 +241,25 @@ func (self *Logic) GetCommentsByCommentId(
 	)
 }
 
-func (self *Logic) CreatePostComment(
+func (self *Logic) checkComment(content string) error {
+	if "" == content {
+		return srverrors.Empty("comment.content")
+	} else if self.limits.CommentContent < len(content) {
+		return srverrors.Incorrect(fmt.Sprintf(
+			"comment.content exceeded max length [%v]",
+			self.limits.CommentContent,
+		))
+	}
+
+	return nil
+}
+
+func (self *Logic) checkPostComment(
 	ctx context.Context,
 	userId uuid.UUID,
 	postId uuid.UUID,
-	form commsrv.CommentForm,
-) (models.Comment, error) {
-	var out models.Comment
+	content string,
+) error {
 	var post models.Post
 	_, err := mapRepoError(self.User.GetUsersById(ctx, userId))
 
@@ -142,14 +279,31 @@ func (self *Logic) CreatePostComment(
 	}
 
 	if nil == err {
-		if "" == form.Content {
-			err = srverrors.Empty("comment.content")
-		} else if models.COMMENT_CONTENT_LENGTH_LIMIT < len(form.Content) {
-			err = srverrors.Incorrect(fmt.Sprintf(
-				"comment.content exceeded max length [%v]",
-				models.COMMENT_CONTENT_LENGTH_LIMIT,
-			))
-		}
+		err = self.checkComment(content)
+	}
+
+	return err
+}
+
+func (self *Logic) CreatePostComment(
+	ctx context.Context,
+	userId uuid.UUID,
+	postId uuid.UUID,
+	form commsrv.CommentForm,
+) (models.Comment, error) {
+	var out models.Comment
+	err := self.checkPostComment(ctx, userId, postId, form.Content)
+	content := moderation.Content{
+		Kind:     models.REVIEW_KIND_POST_COMMENT,
+		AuthorId: userId,
+		TargetId: postId,
+		Text:     form.Content,
+		Date:     time.Now(),
+	}
+	var verdict moderation.Verdict
+
+	if nil == err {
+		verdict, err = self.moderate(ctx, &content, false)
 	}
 
 	if nil == err {
@@ -157,14 +311,32 @@ func (self *Logic) CreatePostComment(
 			AuthorId:     userId,
 			TargetId:     postId,
 			Content:      form.Content,
-			CreationDate: time.Now(),
+			CreationDate: content.Date,
 		}
 		out, err = mapRepoError(self.Comment.CreatePostComment(ctx, out))
 	}
 
+	if nil == err {
+		self.flag(ctx, &content, verdict, out.Id, false)
+	}
+
 	return out, err
 }
 
+func (self *Logic) checkCommentComment(
+	ctx context.Context,
+	userId uuid.UUID,
+	content string,
+) error {
+	_, err := mapRepoError(self.User.GetUsersById(ctx, userId))
+
+	if nil == err {
+		err = self.checkComment(content)
+	}
+
+	return err
+}
+
 func (self *Logic) CreateCommentComment(
 	ctx context.Context,
 	userId uuid.UUID,
@@ -172,17 +344,18 @@ func (self *Logic) CreateCommentComment(
 	form commsrv.CommentForm,
 ) (models.Comment, error) {
 	var out models.Comment
-	_, err := mapRepoError(self.User.GetUsersById(ctx, userId))
+	err := self.checkCommentComment(ctx, userId, form.Content)
+	content := moderation.Content{
+		Kind:     models.REVIEW_KIND_COMMENT_COMMENT,
+		AuthorId: userId,
+		TargetId: commentID,
+		Text:     form.Content,
+		Date:     time.Now(),
+	}
+	var verdict moderation.Verdict
 
 	if nil == err {
-		if "" == form.Content {
-			err = srverrors.Empty("comment.content")
-		} else if models.COMMENT_CONTENT_LENGTH_LIMIT < len(form.Content) {
-			err = srverrors.Incorrect(fmt.Sprintf(
-				"comment.content exceeded max length [%v]",
-				models.COMMENT_CONTENT_LENGTH_LIMIT,
-			))
-		}
+		verdict, err = self.moderate(ctx, &content, false)
 	}
 
 	if nil == err {
@@ -190,11 +363,15 @@ func (self *Logic) CreateCommentComment(
 			AuthorId:     userId,
 			TargetId:     commentID,
 			Content:      form.Content,
-			CreationDate: time.Now(),
+			CreationDate: content.Date,
 		}
 		out, err = mapRepoError(self.Comment.CreateCommentComment(ctx, out))
 	}
 
+	if nil == err {
+		self.flag(ctx, &content, verdict, out.Id, false)
+	}
+
 	return out, err
 }
 
@@ -212,46 +389,121 @@ func (self *Logic) GetPostsById(
 	return mapRepoError(self.Post.GetPostsById(ctx, ids...))
 }
 
-func (self *Logic) CreatePost(
+func (self *Logic) checkPost(
 	ctx context.Context,
 	userId uuid.UUID,
-	form postsrv.PostCreationForm,
-) (models.Post, error) {
-	var out models.Post
+	title string,
+	content string,
+) error {
 	_, err := mapRepoError(self.User.GetUsersById(ctx, userId))
 
 	if nil == err {
-		if "" == form.Content {
+		if "" == content {
 			err = srverrors.Empty("post.content")
-		} else if "" == form.Title {
+		} else if "" == title {
 			err = srverrors.Empty("post.title")
-		} else if models.POST_CONTENT_LENGTH_LIMIT < len(form.Content) {
+		} else if self.limits.PostContent < len(content) {
 			err = srverrors.Incorrect(fmt.Sprintf(
 				"post.content exceeded max length [%v]",
-				models.POST_CONTENT_LENGTH_LIMIT,
+				self.limits.PostContent,
 			))
-		} else if models.POST_TITLE_LENGTH_LIMIT < len(form.Title) {
+		} else if self.limits.PostTitle < len(title) {
 			err = srverrors.Incorrect(fmt.Sprintf(
 				"post.title exceeded max length [%v]",
-				models.POST_TITLE_LENGTH_LIMIT,
+				self.limits.PostTitle,
 			))
 		}
 	}
 
+	return err
+}
+
+func (self *Logic) CreatePost(
+	ctx context.Context,
+	userId uuid.UUID,
+	form postsrv.PostCreationForm,
+) (models.Post, error) {
+	var out models.Post
+	err := self.checkPost(ctx, userId, form.Title, form.Content)
+	content := moderation.Content{
+		Kind:     models.REVIEW_KIND_POST,
+		AuthorId: userId,
+		Title:    form.Title,
+		Text:     form.Content,
+		Date:     time.Now(),
+	}
+	var verdict moderation.Verdict
+
+	if nil == err {
+		verdict, err = self.moderate(ctx, &content, form.AllowComments)
+	}
+
 	if nil == err {
 		out = models.Post{
 			AuthorId:        userId,
 			Title:           form.Title,
 			Content:         form.Content,
 			CommentsAllowed: form.AllowComments,
-			CreationDate:    time.Now(),
+			CreationDate:    content.Date,
 		}
 		out, err = mapRepoError(self.Post.CreatePost(ctx, out))
 	}
 
+	if nil == err {
+		self.flag(ctx, &content, verdict, out.Id, form.AllowComments)
+	}
+
 	return out, err
 }
 
+// Creates hidden content approved by a moderator with its original date.
+// It's checked as new content is, since the target could have changed while
+// the content was held, but isn't moderated again
+func (self *Logic) Publish(ctx context.Context, review models.Review) error {
+	var err error
+
+	switch review.Kind {
+	case models.REVIEW_KIND_POST:
+		err = self.checkPost(ctx, review.AuthorId, review.Title, review.Content)
+
+		if nil == err {
+			_, err = mapRepoError(self.Post.CreatePost(ctx, models.Post{
+				AuthorId:        review.AuthorId,
+				Title:           review.Title,
+				Content:         review.Content,
+				CommentsAllowed: review.CommentsAllowed,
+				CreationDate:    review.CreationDate,
+			}))
+		}
+	case models.REVIEW_KIND_POST_COMMENT:
+		err = self.checkPostComment(ctx, review.AuthorId, review.TargetId, review.Content)
+
+		if nil == err {
+			_, err = mapRepoError(self.Comment.CreatePostComment(ctx, models.Comment{
+				AuthorId:     review.AuthorId,
+				TargetId:     review.TargetId,
+				Content:      review.Content,
+				CreationDate: review.CreationDate,
+			}))
+		}
+	case models.REVIEW_KIND_COMMENT_COMMENT:
+		err = self.checkCommentComment(ctx, review.AuthorId, review.Content)
+
+		if nil == err {
+			_, err = mapRepoError(self.Comment.CreateCommentComment(ctx, models.Comment{
+				AuthorId:     review.AuthorId,
+				TargetId:     review.TargetId,
+				Content:      review.Content,
+				CreationDate: review.CreationDate,
+			}))
+		}
+	default:
+		panic("Unknown kind")
+	}
+
+	return err
+}
+
 func (self *Logic) UpdatePost(
 	ctx context.Context,
 	userId uuid.UUID,
@@ -270,15 +522,15 @@ func (self *Logic) UpdatePost(
 			err = srverrors.Empty("post.content")
 		} else if "" == post.Title {
 			err = srverrors.Empty("post.title")
-		} else if models.POST_CONTENT_LENGTH_LIMIT < len(post.Content) {
+		} else if self.limits.PostContent < len(post.Content) {
 			err = srverrors.Incorrect(fmt.Sprintf(
 				"post.content exceeded max length [%v]",
-				models.POST_CONTENT_LENGTH_LIMIT,
+				self.limits.PostContent,
 			))
-		} else if models.POST_TITLE_LENGTH_LIMIT < len(post.Title) {
+		} else if self.limits.PostTitle < len(post.Title) {
 			err = srverrors.Incorrect(fmt.Sprintf(
 				"post.title exceeded max length [%v]",
-				models.POST_TITLE_LENGTH_LIMIT,
+				self.limits.PostTitle,
 			))
 		}
 	}
@@ -297,3 +549,22 @@ func (self *Logic) GetUsersById(
 	return mapRepoError(self.User.GetUsersById(ctx, ids...))
 }
 
+func (self *Logic) Ping(ctx context.Context) error {
+	var err error
+	checked := make([]any, 0, 3)
+
+	for _, repo := range []any{self.Comment, self.Post, self.User} {
+		if nil != err || slices.Contains(checked, repo) {
+			continue
+		}
+
+		checked = append(checked, repo)
+
+		if pinger, ok := repo.(healthrepo.Pinger); ok {
+			_, err = mapRepoError(struct{}{}, pinger.Ping(ctx))
+		}
+	}
+
+	return err
+}
+
@@ -10,14 +10,17 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/domain/moderation"
 	"github.com/muji40k/ozontestcomms/internal/repository/collection"
 	"github.com/muji40k/ozontestcomms/internal/repository/collection/iterator"
 	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
 	"github.com/muji40k/ozontestcomms/internal/repository/implementations/mock/comment"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/mock/moderation"
 	"github.com/muji40k/ozontestcomms/internal/repository/implementations/mock/post"
 	"github.com/muji40k/ozontestcomms/internal/repository/implementations/mock/user"
 	commrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
 	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	usrrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/user"
 	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
 	commsrv "github.com/muji40k/ozontestcomms/internal/service/interface/comment"
 	postsrv "github.com/muji40k/ozontestcomms/internal/service/interface/post"
@@ -347,6 +350,56 @@ func TestLogicCreatePostCommentContentOutOfSize(t *testing.T) {
 	assert.ErrorAs(t, err, &srverrors.ErrorIncorrect{})
 }
 
+func TestLogicCreatePostCommentContentOutOfConfiguredSize(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	_, handle := setupService(ctrl)
+	limits := DEFAULT_LIMITS
+	limits.CommentContent = 10
+	l := NewWithLimits(Context{
+		Comment: handle.comment,
+		Post:    handle.post,
+		User:    handle.user,
+	}, limits)
+
+	author := common.Unwrap(domainOM.UserRandom().Build())
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	post := common.Unwrap(domainOM.PostDefault(
+		author.Id,
+		nullable.Some(true),
+		nullable.None[string](),
+		nullable.None[time.Time](),
+	).Build())
+
+	handle.user.EXPECT().
+		GetUsersById(context.Background(), user.Id).
+		Return(collection.Map(
+			collection.Slice([]models.User{user}),
+			func(v *models.User) result.Result[models.User] {
+				return result.Ok(*v)
+			},
+		), nil).MinTimes(1)
+
+	handle.post.EXPECT().
+		GetPostsById(context.Background(), post.Id).
+		Return(collection.Map(
+			collection.Slice([]models.Post{post}),
+			func(v *models.Post) result.Result[models.Post] {
+				return result.Ok(*v)
+			},
+		), nil).MinTimes(1)
+
+	// Act
+	_, err := l.CreatePostComment(context.Background(), user.Id, post.Id, commsrv.CommentForm{
+		Content: strings.Repeat("a", 11),
+	})
+
+	// Assert
+	assert.Error(t, err)
+	assert.ErrorAs(t, err, &srverrors.ErrorIncorrect{})
+}
+
 func TestLogicCreatePostCommentContentEmpty(t *testing.T) {
 	// Arrange
 	ctrl := gomock.NewController(t)
@@ -447,3 +500,230 @@ func TestLogicCreatePostCommentCommentsNotAllowed(t *testing.T) {
 	assert.ErrorAs(t, err, &srverrors.ErrorViolation{})
 }
 
+type commentRepository = commrepo.Repository
+type postRepository = postrepo.Repository
+type userRepository = usrrepo.Repository
+
+type pingingRepository struct {
+	commentRepository
+	postRepository
+	userRepository
+	ping func(context.Context) error
+}
+
+func (self *pingingRepository) Ping(ctx context.Context) error {
+	return self.ping(ctx)
+}
+
+func setupPingingService(
+	ctrl *gomock.Controller,
+	ping func(context.Context) error,
+) *Logic {
+	repo := &pingingRepository{
+		mock_comment.NewMockRepository(ctrl),
+		mock_post.NewMockRepository(ctrl),
+		mock_user.NewMockRepository(ctrl),
+		ping,
+	}
+
+	return New(Context{
+		Comment: repo,
+		Post:    repo,
+		User:    repo,
+	})
+}
+
+func TestLogicPingNoPingers(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	l, _ := setupService(ctrl)
+
+	// Act
+	err := l.Ping(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestLogicPingSharedRepository(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	calls := 0
+	l := setupPingingService(ctrl, func(context.Context) error {
+		calls++
+		return nil
+	})
+
+	// Act
+	err := l.Ping(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestLogicPingError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	l := setupPingingService(ctrl, func(context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	// Act
+	err := l.Ping(context.Background())
+
+	// Assert
+	assert.Error(t, err)
+	assert.ErrorAs(t, err, &srverrors.ErrorInternal{})
+	assert.ErrorAs(t, err, &srverrors.ErrorDataAccess{})
+}
+
+func setupModeratedService(
+	ctrl *gomock.Controller,
+	action models.ModerationAction,
+) (*Logic, mockHandle, *mock_moderation.MockRepository) {
+	l, handle := setupService(ctrl)
+	reviews := mock_moderation.NewMockRepository(ctrl)
+
+	return l.WithModeration(Moderation{
+		Pipeline: moderation.NewPipeline(moderation.NewBannedWords(action, "spam")),
+		Reviews:  reviews,
+	}), handle, reviews
+}
+
+func expectUser(handle mockHandle, user models.User) {
+	handle.user.EXPECT().
+		GetUsersById(context.Background(), user.Id).
+		Return(collection.Map(
+			collection.Slice([]models.User{user}),
+			func(v *models.User) result.Result[models.User] {
+				return result.Ok(*v)
+			},
+		), nil).MinTimes(1)
+}
+
+func TestLogicCreatePostModerationRejected(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	l, handle, _ := setupModeratedService(ctrl, models.MODERATION_ACTION_REJECT)
+
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	expectUser(handle, user)
+
+	// Act
+	_, err := l.CreatePost(context.Background(), user.Id, postsrv.PostCreationForm{
+		Title:   "Offer",
+		Content: "Buy SPAM now",
+	})
+
+	// Assert
+	assert.ErrorAs(t, err, &srverrors.ErrorViolation{})
+	assert.ErrorContains(t, err, "banned words: spam")
+}
+
+func TestLogicCreatePostModerationHidden(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	l, handle, reviews := setupModeratedService(ctrl, models.MODERATION_ACTION_HIDE)
+
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	expectUser(handle, user)
+
+	reviews.EXPECT().
+		CreateReview(context.Background(), FuncMatcher[models.Review](func(v *models.Review) bool {
+			return models.REVIEW_KIND_POST == v.Kind &&
+				models.MODERATION_ACTION_HIDE == v.Action &&
+				uuid.UUID{} == v.ContentId && user.Id == v.AuthorId &&
+				"Offer" == v.Title && "Buy spam now" == v.Content &&
+				v.CommentsAllowed && 1 == len(v.Reasons)
+		})).
+		Return(models.Review{Id: uuid.New()}, nil).Times(1)
+
+	// Act
+	_, err := l.CreatePost(context.Background(), user.Id, postsrv.PostCreationForm{
+		Title:         "Offer",
+		Content:       "Buy spam now",
+		AllowComments: true,
+	})
+
+	// Assert
+	assert.ErrorAs(t, err, &srverrors.ErrorViolation{})
+}
+
+func TestLogicCreateCommentCommentModerationFlagged(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	l, handle, reviews := setupModeratedService(ctrl, models.MODERATION_ACTION_FLAG)
+
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	target := uuid.New()
+	comment := common.Unwrap(domainOM.CommentDefault(
+		user.Id,
+		target,
+		nullable.Some("spam"),
+		nullable.None[time.Time](),
+	).Build())
+	expectUser(handle, user)
+
+	handle.comment.EXPECT().
+		CreateCommentComment(context.Background(), gomock.Any()).
+		Return(comment, nil).Times(1)
+
+	reviews.EXPECT().
+		CreateReview(context.Background(), FuncMatcher[models.Review](func(v *models.Review) bool {
+			return models.REVIEW_KIND_COMMENT_COMMENT == v.Kind &&
+				models.MODERATION_ACTION_FLAG == v.Action &&
+				comment.Id == v.ContentId && target == v.TargetId
+		})).
+		Return(models.Review{Id: uuid.New()}, nil).Times(1)
+
+	// Act
+	res, err := l.CreateCommentComment(context.Background(), user.Id, target, commsrv.CommentForm{
+		Content: "spam",
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, comment, res)
+}
+
+func TestLogicCreatePostKeepsFlaggedOnReviewFailure(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	l, handle, reviews := setupModeratedService(ctrl, models.MODERATION_ACTION_FLAG)
+
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	post := common.Unwrap(domainOM.PostDefault(
+		user.Id,
+		nullable.Some(true),
+		nullable.Some("Buy spam now"),
+		nullable.None[time.Time](),
+	).Build())
+	expectUser(handle, user)
+
+	handle.post.EXPECT().
+		CreatePost(context.Background(), gomock.Any()).
+		Return(post, nil).Times(1)
+
+	reviews.EXPECT().
+		CreateReview(context.Background(), gomock.Any()).
+		Return(models.Review{}, errors.New("review storage is down")).Times(1)
+
+	// Act
+	res, err := l.CreatePost(context.Background(), user.Id, postsrv.PostCreationForm{
+		Title:   "Offer",
+		Content: "Buy spam now",
+	})
+
+	// Assert
+	assert.NoError(t, err, "Post is published, so the failure isn't reported")
+	assert.Equal(t, post, res)
+}
+
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Change recorded along with the write it describes, see the events
+// package for types and payloads
+type Event struct {
+	Id   uuid.UUID
+	Type string
+	// User, post or comment the event is about
+	EntityId     uuid.UUID
+	Payload      json.RawMessage
+	CreationDate time.Time
+}
+
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FollowKind uint
+
+const (
+	FOLLOW_KIND_AUTHOR FollowKind = iota
+	FOLLOW_KIND_POST
+)
+
+// Author, whose posts make the feed of the user, or post watched by the
+// user
+type Follow struct {
+	UserId       uuid.UUID
+	Kind         FollowKind
+	TargetId     uuid.UUID
+	CreationDate time.Time
+}
+
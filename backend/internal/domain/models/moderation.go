@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Ordered by severity
+type ModerationAction uint
+
+const (
+	MODERATION_ACTION_ACCEPT ModerationAction = iota
+	MODERATION_ACTION_FLAG
+	MODERATION_ACTION_HIDE
+	MODERATION_ACTION_REJECT
+)
+
+type ReviewKind uint
+
+const (
+	REVIEW_KIND_POST ReviewKind = iota
+	REVIEW_KIND_POST_COMMENT
+	REVIEW_KIND_COMMENT_COMMENT
+)
+
+// Content waiting for a moderator. Flagged content is published already,
+// hidden one is kept only here until it's approved
+type Review struct {
+	Id     uuid.UUID
+	Kind   ReviewKind
+	Action ModerationAction
+	// Published post or comment, zero while hidden
+	ContentId uuid.UUID
+	AuthorId  uuid.UUID
+	// Commented post or comment, zero for posts
+	TargetId        uuid.UUID
+	Title           string
+	Content         string
+	CommentsAllowed bool
+	Reasons         []string
+	CreationDate    time.Time
+}
+
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationKind uint
+
+const (
+	NOTIFICATION_KIND_POST_REPLY NotificationKind = iota
+	NOTIFICATION_KIND_COMMENT_REPLY
+	NOTIFICATION_KIND_MENTION
+)
+
+// Comment the user is notified about, either a reply to their post or
+// comment or a mention of their email
+type Notification struct {
+	Id     uuid.UUID
+	UserId uuid.UUID
+	Kind   NotificationKind
+	// Author of the comment
+	ActorId   uuid.UUID
+	PostId    uuid.UUID
+	CommentId uuid.UUID
+	Read      bool
+	// When the notification is made rather than the comment, so that the
+	// ones made of retried events still follow the earlier ones
+	CreationDate time.Time
+}
+
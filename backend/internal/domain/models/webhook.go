@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const WEBHOOK_URL_LENGTH_LIMIT int = 2000
+
+// Subscription of an integrator to events, see the events package for
+// types. Limited to events of a single post if PostId is set
+type Webhook struct {
+	Id      uuid.UUID
+	OwnerId uuid.UUID
+	Url     string
+	// Key the payloads are signed with
+	Secret       string
+	Events       []string
+	PostId       uuid.NullUUID
+	CreationDate time.Time
+}
+
+type DeliveryStatus uint
+
+const (
+	DELIVERY_STATUS_PENDING DeliveryStatus = iota
+	DELIVERY_STATUS_DELIVERED
+	// Out of attempts, kept until retried by the owner
+	DELIVERY_STATUS_DEAD
+)
+
+// Single event sent to a single webhook. Payload is fixed on creation, so
+// that every attempt sends the same body
+type WebhookDelivery struct {
+	Id        uuid.UUID
+	WebhookId uuid.UUID
+	EventId   uuid.UUID
+	EventType string
+	Payload   json.RawMessage
+	Status    DeliveryStatus
+	Attempts  int
+	// Outcome of the last attempt, zero status code if there was no
+	// response
+	StatusCode   int
+	Error        string
+	LastAttempt  time.Time
+	NextAttempt  time.Time
+	CreationDate time.Time
+}
+
@@ -0,0 +1,125 @@
+package moderation
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+)
+
+// Content containing any of the words, compared case-insensitively
+type BannedWords struct {
+	action models.ModerationAction
+	words  map[string]struct{}
+}
+
+func NewBannedWords(action models.ModerationAction, words ...string) *BannedWords {
+	out := &BannedWords{action, make(map[string]struct{}, len(words))}
+
+	for _, word := range words {
+		out.words[strings.ToLower(word)] = struct{}{}
+	}
+
+	return out
+}
+
+func (self *BannedWords) Check(ctx context.Context, content *Content) (Verdict, error) {
+	found := make([]string, 0)
+	split := func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(content.Title+" "+content.Text), split) {
+		if _, banned := self.words[word]; banned && !slices.Contains(found, word) {
+			found = append(found, word)
+		}
+	}
+
+	if 0 == len(found) {
+		return Verdict{}, nil
+	}
+
+	return Verdict{self.action, []string{"banned words: " + strings.Join(found, ", ")}}, nil
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// Content with more links than allowed
+type LinkLimit struct {
+	action models.ModerationAction
+	max    int
+}
+
+func NewLinkLimit(action models.ModerationAction, max int) *LinkLimit {
+	return &LinkLimit{action, max}
+}
+
+func (self *LinkLimit) Check(ctx context.Context, content *Content) (Verdict, error) {
+	links := len(linkPattern.FindAllStringIndex(content.Title+" "+content.Text, -1))
+
+	if self.max >= links {
+		return Verdict{}, nil
+	}
+
+	return Verdict{self.action, []string{
+		fmt.Sprintf("%v links, at most %v allowed", links, self.max),
+	}}, nil
+}
+
+// Same content of an author repeated more than allowed in the span. Letter
+// case and whitespace are ignored
+type RepeatedContent struct {
+	action models.ModerationAction
+	max    int
+	window *window
+}
+
+func NewRepeatedContent(action models.ModerationAction, max int, span time.Duration) *RepeatedContent {
+	return &RepeatedContent{action, max, newWindow(span)}
+}
+
+func (self *RepeatedContent) Check(ctx context.Context, content *Content) (Verdict, error) {
+	text := strings.Join(strings.Fields(strings.ToLower(content.Title+" "+content.Text)), " ")
+	sum := sha256.Sum256([]byte(text))
+	key := content.AuthorId.String() + string(sum[:])
+
+	if repeats := self.window.hit(key, content.Date); self.max > repeats {
+		return Verdict{}, nil
+	}
+
+	return Verdict{self.action, []string{
+		fmt.Sprintf("same content posted more than %v times in %v", self.max, self.window.span),
+	}}, nil
+}
+
+// Comments of an author over the limit in the span, posts aren't counted
+type CommentRate struct {
+	action models.ModerationAction
+	max    int
+	window *window
+}
+
+func NewCommentRate(action models.ModerationAction, max int, span time.Duration) *CommentRate {
+	return &CommentRate{action, max, newWindow(span)}
+}
+
+func (self *CommentRate) Check(ctx context.Context, content *Content) (Verdict, error) {
+	if models.REVIEW_KIND_POST == content.Kind {
+		return Verdict{}, nil
+	}
+
+	if self.max > self.window.hit(content.AuthorId.String(), content.Date) {
+		return Verdict{}, nil
+	}
+
+	return Verdict{self.action, []string{
+		fmt.Sprintf("more than %v comments in %v", self.max, self.window.span),
+	}}, nil
+}
+
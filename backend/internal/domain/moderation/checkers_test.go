@@ -0,0 +1,141 @@
+package moderation
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/test/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func comment(author uuid.UUID, text string, date time.Time) *Content {
+	return &Content{
+		Kind:     models.REVIEW_KIND_POST_COMMENT,
+		AuthorId: author,
+		TargetId: uuid.New(),
+		Text:     text,
+		Date:     date,
+	}
+}
+
+func TestBannedWords(t *testing.T) {
+	// Arrange
+	checker := NewBannedWords(models.MODERATION_ACTION_HIDE, "Spam", "scam")
+	author := uuid.New()
+
+	// Act
+	clean := common.Unwrap(checker.Check(context.Background(), comment(author, "spammer", time.Now())))
+	banned := common.Unwrap(checker.Check(context.Background(), comment(author, "SPAM, scam and spam!", time.Now())))
+
+	// Assert
+	assert.Equal(t, Verdict{}, clean)
+	assert.Equal(t, Verdict{models.MODERATION_ACTION_HIDE, []string{"banned words: spam, scam"}}, banned)
+}
+
+func TestLinkLimit(t *testing.T) {
+	// Arrange
+	checker := NewLinkLimit(models.MODERATION_ACTION_REJECT, 1)
+	author := uuid.New()
+
+	// Act
+	one := common.Unwrap(checker.Check(context.Background(), comment(author, "see https://a.org", time.Now())))
+	two := common.Unwrap(checker.Check(context.Background(), comment(author, "http://a.org www.b.org", time.Now())))
+
+	// Assert
+	assert.Equal(t, models.MODERATION_ACTION_ACCEPT, one.Action)
+	assert.Equal(t, models.MODERATION_ACTION_REJECT, two.Action)
+}
+
+func TestRepeatedContent(t *testing.T) {
+	// Arrange
+	checker := NewRepeatedContent(models.MODERATION_ACTION_FLAG, 2, time.Minute)
+	author := uuid.New()
+	now := time.Now()
+	actions := make([]models.ModerationAction, 0)
+
+	// Act
+	for i, text := range []string{"Hello  there", "hello there", "HELLO there", "other"} {
+		verdict := common.Unwrap(checker.Check(context.Background(), comment(author, text, now.Add(time.Duration(i)))))
+		actions = append(actions, verdict.Action)
+	}
+
+	other := common.Unwrap(checker.Check(context.Background(), comment(uuid.New(), "hello there", now)))
+	later := common.Unwrap(checker.Check(context.Background(), comment(author, "hello there", now.Add(time.Minute+time.Second))))
+
+	// Assert
+	assert.Equal(t, []models.ModerationAction{
+		models.MODERATION_ACTION_ACCEPT,
+		models.MODERATION_ACTION_ACCEPT,
+		models.MODERATION_ACTION_FLAG,
+		models.MODERATION_ACTION_ACCEPT,
+	}, actions)
+	assert.Equal(t, models.MODERATION_ACTION_ACCEPT, other.Action)
+	assert.Equal(t, models.MODERATION_ACTION_ACCEPT, later.Action)
+}
+
+func TestCommentRate(t *testing.T) {
+	// Arrange
+	checker := NewCommentRate(models.MODERATION_ACTION_REJECT, 1, time.Minute)
+	author := uuid.New()
+	now := time.Now()
+	post := &Content{Kind: models.REVIEW_KIND_POST, AuthorId: author, Date: now}
+
+	// Act
+	common.Unwrap(checker.Check(context.Background(), post))
+	first := common.Unwrap(checker.Check(context.Background(), comment(author, "a", now)))
+	second := common.Unwrap(checker.Check(context.Background(), comment(author, "b", now.Add(time.Second))))
+	later := common.Unwrap(checker.Check(context.Background(), comment(author, "c", now.Add(2*time.Minute))))
+
+	// Assert
+	assert.Equal(t, models.MODERATION_ACTION_ACCEPT, first.Action)
+	assert.Equal(t, models.MODERATION_ACTION_REJECT, second.Action)
+	assert.Equal(t, models.MODERATION_ACTION_ACCEPT, later.Action)
+}
+
+func TestWindowSweep(t *testing.T) {
+	// Arrange
+	w := newWindow(time.Second)
+	now := time.Now()
+
+	// Act
+	for i := uint(1); SWEEP_PERIOD > i; i++ {
+		w.hit(strings.Repeat("k", int(i%8)+1), now)
+	}
+
+	w.hit("late", now.Add(time.Minute))
+
+	// Assert
+	require.Equal(t, 1, len(w.events))
+	assert.Len(t, w.events["late"], 1)
+}
+
+func TestPipeline(t *testing.T) {
+	// Arrange
+	pipeline := NewPipeline(
+		NewBannedWords(models.MODERATION_ACTION_FLAG, "spam"),
+		NewLinkLimit(models.MODERATION_ACTION_HIDE, 0),
+		NewBannedWords(models.MODERATION_ACTION_REJECT, "scam"),
+		NewBannedWords(models.MODERATION_ACTION_FLAG, "scam"),
+	)
+	author := uuid.New()
+
+	// Act
+	clean := common.Unwrap(pipeline.Check(context.Background(), comment(author, "hello", time.Now())))
+	hidden := common.Unwrap(pipeline.Check(context.Background(), comment(author, "spam www.a.org", time.Now())))
+	rejected := common.Unwrap(pipeline.Check(context.Background(), comment(author, "spam scam", time.Now())))
+
+	// Assert
+	assert.Equal(t, Verdict{}, clean)
+	assert.Equal(t, models.MODERATION_ACTION_HIDE, hidden.Action)
+	assert.Len(t, hidden.Reasons, 2)
+	assert.Equal(t, Verdict{models.MODERATION_ACTION_REJECT, []string{
+		"banned words: spam",
+		"banned words: scam",
+	}}, rejected)
+}
+
@@ -0,0 +1,71 @@
+package moderation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+)
+
+// Actions by their configuration names
+var ACTIONS = map[string]models.ModerationAction{
+	"flag":   models.MODERATION_ACTION_FLAG,
+	"hide":   models.MODERATION_ACTION_HIDE,
+	"reject": models.MODERATION_ACTION_REJECT,
+}
+
+// Post or comment being created
+type Content struct {
+	Kind     models.ReviewKind
+	AuthorId uuid.UUID
+	// Commented post or comment, zero for posts
+	TargetId uuid.UUID
+	Title    string
+	Text     string
+	Date     time.Time
+}
+
+type Verdict struct {
+	Action  models.ModerationAction
+	Reasons []string
+}
+
+// Checkers may keep state of the content they saw, e.g. to limit rate
+type Checker interface {
+	Check(ctx context.Context, content *Content) (Verdict, error)
+}
+
+// Checkers are run in order until one rejects the content, the most severe
+// action is taken with reasons of every checker which didn't accept it
+type Pipeline struct {
+	checkers []Checker
+}
+
+func NewPipeline(checkers ...Checker) *Pipeline {
+	return &Pipeline{checkers}
+}
+
+func (self *Pipeline) Len() int {
+	return len(self.checkers)
+}
+
+func (self *Pipeline) Check(ctx context.Context, content *Content) (Verdict, error) {
+	var out Verdict
+
+	for i := 0; models.MODERATION_ACTION_REJECT != out.Action && len(self.checkers) > i; i++ {
+		verdict, err := self.checkers[i].Check(ctx, content)
+
+		if nil != err {
+			return Verdict{}, err
+		}
+
+		if models.MODERATION_ACTION_ACCEPT != verdict.Action {
+			out.Action = max(out.Action, verdict.Action)
+			out.Reasons = append(out.Reasons, verdict.Reasons...)
+		}
+	}
+
+	return out, nil
+}
+
@@ -0,0 +1,105 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	moderationrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/moderation"
+	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/singlewrap"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// Creates approved hidden content, checking it as new content but without
+// moderating it again
+type Publisher interface {
+	Publish(ctx context.Context, review models.Review) error
+}
+
+type Context struct {
+	Reviews   moderationrepo.Repository
+	Publisher Publisher
+}
+
+// Review queue of the moderators
+type Logic struct {
+	Context
+	moderators []uuid.UUID
+}
+
+func New(context Context, moderators ...uuid.UUID) *Logic {
+	return &Logic{context, moderators}
+}
+
+func mapRepoError[T any](v T, err error) (T, error) {
+	if nil == err {
+		return v, nil
+	} else if cerr := (repoerrors.ErrorNotFound{}); errors.As(err, &cerr) {
+		return v, srverrors.NotFound(cerr.What...)
+	} else {
+		return v, srverrors.Internal(srverrors.DataAccess(err))
+	}
+}
+
+func (self *Logic) authorize(moderatorId uuid.UUID) error {
+	if slices.Contains(self.moderators, moderatorId) {
+		return nil
+	}
+
+	return srverrors.Authorization(errors.New("Not a moderator"))
+}
+
+func (self *Logic) GetReviews(
+	ctx context.Context,
+	moderatorId uuid.UUID,
+) (collection.Collection[result.Result[models.Review]], error) {
+	if err := self.authorize(moderatorId); nil != err {
+		return nil, err
+	}
+
+	return mapRepoError(self.Reviews.GetReviews(ctx))
+}
+
+// Review is removed first, so that concurrent approvals publish the content
+// once. It's put back if publishing fails, failure to put it back is reported
+// along, since the content is lost then
+func (self *Logic) ResolveReview(
+	ctx context.Context,
+	moderatorId uuid.UUID,
+	reviewId uuid.UUID,
+	approve bool,
+) error {
+	var review models.Review
+	err := self.authorize(moderatorId)
+
+	if nil == err {
+		var res result.Result[models.Review]
+		res, err = singlewrap.Unwrap(
+			mapRepoError(self.Reviews.GetReviewsById(ctx, reviewId)),
+		)
+
+		if nil == err {
+			review, err = mapRepoError(res.Unwrap())
+		}
+	}
+
+	if nil == err {
+		_, err = mapRepoError(struct{}{}, self.Reviews.DeleteReview(ctx, reviewId))
+	}
+
+	if nil == err && approve && models.MODERATION_ACTION_HIDE == review.Action {
+		if err = self.Publisher.Publish(ctx, review); nil != err {
+			if _, rerr := mapRepoError(self.Reviews.CreateReview(ctx, review)); nil != rerr {
+				err = errors.Join(err, rerr)
+			}
+		}
+	}
+
+	return err
+}
+
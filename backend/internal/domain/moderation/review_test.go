@@ -0,0 +1,219 @@
+package moderation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	domainlogic "github.com/muji40k/ozontestcomms/internal/domain/logic"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/domain/moderation"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	mock_moderation "github.com/muji40k/ozontestcomms/internal/repository/implementations/mock/moderation"
+	commrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/misc/result"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// Moderator and the author of the post
+func setup(t *testing.T) (*moderation.Logic, *inmemory.Repository, models.User, models.Post) {
+	moderator := common.Unwrap(domainOM.UserRandom().Build())
+	author := common.Unwrap(domainOM.UserRandom().Build())
+	post := common.Unwrap(domainOM.PostDefault(
+		author.Id,
+		nullable.Some(true),
+		nullable.None[string](),
+		nullable.Some(time.Now()),
+	).Build())
+	repo := inmemory.New(func(adduser func(models.User), _ func(inmemory.Comment), addpost func(models.Post)) {
+		adduser(moderator)
+		adduser(author)
+		addpost(post)
+	})
+
+	publisher := domainlogic.New(domainlogic.Context{Comment: repo, Post: repo, User: repo})
+
+	return moderation.New(
+		moderation.Context{Reviews: repo, Publisher: publisher},
+		moderator.Id,
+	), repo, moderator, post
+}
+
+func TestGetReviewsNotModerator(t *testing.T) {
+	// Arrange
+	logic, _, _, post := setup(t)
+
+	// Act
+	_, err := logic.GetReviews(context.Background(), post.AuthorId)
+	rerr := logic.ResolveReview(context.Background(), post.AuthorId, uuid.New(), true)
+
+	// Assert
+	assert.ErrorAs(t, err, &srverrors.ErrorAuthorization{})
+	assert.ErrorAs(t, rerr, &srverrors.ErrorAuthorization{})
+}
+
+func TestResolveReviewApproveHidden(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	logic, repo, moderator, post := setup(t)
+	review := common.Unwrap(repo.CreateReview(ctx, models.Review{
+		Kind:         models.REVIEW_KIND_POST_COMMENT,
+		Action:       models.MODERATION_ACTION_HIDE,
+		AuthorId:     post.AuthorId,
+		TargetId:     post.Id,
+		Content:      "held",
+		Reasons:      []string{"banned words: held"},
+		CreationDate: time.Now(),
+	}))
+
+	// Act
+	queue := common.Unwrap(pagination.Collect(common.Unwrap(logic.GetReviews(ctx, moderator.Id))))
+	err := logic.ResolveReview(ctx, moderator.Id, review.Id, true)
+	left := common.Unwrap(pagination.Collect(common.Unwrap(logic.GetReviews(ctx, moderator.Id))))
+	comments := common.Unwrap(pagination.Collect(common.Unwrap(
+		repo.GetCommentsByPostId(ctx, post.Id, commrepo.COMMENT_ORDER_DATE_ASC),
+	)))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []models.Review{review}, queue)
+	assert.Empty(t, left)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "held", comments[0].Content)
+	assert.Equal(t, post.AuthorId, comments[0].AuthorId)
+}
+
+func TestResolveReviewDismissHidden(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	logic, repo, moderator, post := setup(t)
+	review := common.Unwrap(repo.CreateReview(ctx, models.Review{
+		Kind:         models.REVIEW_KIND_POST,
+		Action:       models.MODERATION_ACTION_HIDE,
+		AuthorId:     post.AuthorId,
+		Title:        "held",
+		Content:      "held",
+		CreationDate: time.Now(),
+	}))
+
+	// Act
+	err := logic.ResolveReview(ctx, moderator.Id, review.Id, false)
+	again := logic.ResolveReview(ctx, moderator.Id, review.Id, true)
+	posts := common.Unwrap(pagination.Collect(common.Unwrap(
+		repo.GetPosts(ctx, postrepo.POST_ORDER_DATE_ASC),
+	)))
+
+	// Assert
+	require.NoError(t, err)
+	assert.ErrorAs(t, again, &srverrors.ErrorNotFound{})
+	assert.Equal(t, []models.Post{post}, posts)
+}
+
+func TestResolveReviewApproveFlagged(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	logic, repo, moderator, post := setup(t)
+	review := common.Unwrap(repo.CreateReview(ctx, models.Review{
+		Kind:         models.REVIEW_KIND_POST,
+		Action:       models.MODERATION_ACTION_FLAG,
+		ContentId:    post.Id,
+		AuthorId:     post.AuthorId,
+		Title:        post.Title,
+		Content:      post.Content,
+		CreationDate: post.CreationDate,
+	}))
+
+	// Act
+	err := logic.ResolveReview(ctx, moderator.Id, review.Id, true)
+	posts := common.Unwrap(pagination.Collect(common.Unwrap(
+		repo.GetPosts(ctx, postrepo.POST_ORDER_DATE_ASC),
+	)))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []models.Post{post}, posts)
+}
+
+func TestResolveReviewApproveToClosedPost(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	logic, repo, moderator, post := setup(t)
+	review := common.Unwrap(repo.CreateReview(ctx, models.Review{
+		Kind:         models.REVIEW_KIND_POST_COMMENT,
+		Action:       models.MODERATION_ACTION_HIDE,
+		AuthorId:     post.AuthorId,
+		TargetId:     post.Id,
+		Content:      "held",
+		CreationDate: time.Now(),
+	}))
+	closed := post
+	closed.CommentsAllowed = false
+	common.Unwrap(repo.UpdatePost(ctx, closed))
+
+	// Act
+	err := logic.ResolveReview(ctx, moderator.Id, review.Id, true)
+	left := common.Unwrap(pagination.Collect(common.Unwrap(logic.GetReviews(ctx, moderator.Id))))
+	comments := common.Unwrap(pagination.Collect(common.Unwrap(
+		repo.GetCommentsByPostId(ctx, post.Id, commrepo.COMMENT_ORDER_DATE_ASC),
+	)))
+
+	// Assert
+	assert.ErrorAs(t, err, &srverrors.ErrorViolation{})
+	require.Len(t, left, 1, "Review is put back")
+	assert.Equal(t, "held", left[0].Content)
+	assert.Empty(t, comments)
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, models.Review) error {
+	return srverrors.Violation("comments to selected post are not allowed")
+}
+
+func TestResolveReviewReportsLostReview(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	reviews := mock_moderation.NewMockRepository(ctrl)
+	moderator := uuid.New()
+	review := models.Review{
+		Id:     uuid.New(),
+		Kind:   models.REVIEW_KIND_POST_COMMENT,
+		Action: models.MODERATION_ACTION_HIDE,
+	}
+	logic := moderation.New(
+		moderation.Context{Reviews: reviews, Publisher: failingPublisher{}},
+		moderator,
+	)
+
+	reviews.EXPECT().
+		GetReviewsById(ctx, review.Id).
+		Return(collection.Slice([]result.Result[models.Review]{result.Ok(review)}), nil).
+		Times(1)
+	reviews.EXPECT().DeleteReview(ctx, review.Id).Return(nil).Times(1)
+	reviews.EXPECT().
+		CreateReview(ctx, review).
+		Return(models.Review{}, errors.New("review storage is down")).
+		Times(1)
+
+	// Act
+	err := logic.ResolveReview(ctx, moderator, review.Id, true)
+
+	// Assert
+	assert.ErrorAs(t, err, &srverrors.ErrorViolation{})
+	assert.ErrorAs(t, err, &srverrors.ErrorDataAccess{})
+	assert.ErrorContains(t, err, "review storage is down")
+}
+
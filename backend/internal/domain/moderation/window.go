@@ -0,0 +1,52 @@
+package moderation
+
+import (
+	"sync"
+	"time"
+)
+
+const SWEEP_PERIOD uint = 1024
+
+// Moments of the recent events by key. Keys without events in the span are
+// swept from time to time, so that memory is bound by the recent activity
+type window struct {
+	span   time.Duration
+	mutex  sync.Mutex
+	events map[string][]time.Time
+	hits   uint
+}
+
+func newWindow(span time.Duration) *window {
+	return &window{span: span, events: make(map[string][]time.Time)}
+}
+
+func (self *window) recent(events []time.Time, now time.Time) []time.Time {
+	from := now.Add(-self.span)
+	i := 0
+
+	for len(events) > i && !events[i].After(from) {
+		i++
+	}
+
+	return events[i:]
+}
+
+// Records the event, returns the number of events of the key before it
+func (self *window) hit(key string, now time.Time) int {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.hits++; 0 == self.hits%SWEEP_PERIOD {
+		for k, v := range self.events {
+			if 0 == len(self.recent(v, now)) {
+				delete(self.events, k)
+			}
+		}
+	}
+
+	events := self.recent(self.events[key], now)
+	self.events[key] = append(events, now)
+
+	return len(events)
+}
+
@@ -0,0 +1,198 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	commrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	notifrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/notification"
+	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	usrrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/user"
+	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/singlewrap"
+	notifsrv "github.com/muji40k/ozontestcomms/internal/service/interface/notification"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// Only this many distinct mentions of a comment are looked up
+const MENTION_LIMIT = 10
+
+// Email after an @, which doesn't continue a word or another email
+var mention = regexp.MustCompile(
+	`(?:^|[^\w.+\-@])@([\w.+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]+)`,
+)
+
+type Context struct {
+	Notification notifrepo.Repository
+	Comment      commrepo.Repository
+	Post         postrepo.Repository
+	User         usrrepo.Repository
+}
+
+type Logic struct {
+	Context
+}
+
+func New(context Context) *Logic {
+	return &Logic{context}
+}
+
+func mapRepoError[T any](v T, err error) (T, error) {
+	if nil == err {
+		return v, nil
+	} else if cerr := (repoerrors.ErrorNotFound{}); errors.As(err, &cerr) {
+		return v, srverrors.NotFound(cerr.What...)
+	} else {
+		return v, srverrors.Internal(srverrors.DataAccess(err))
+	}
+}
+
+func single[T any](col collection.Collection[result.Result[T]], err error) (T, error) {
+	var out T
+	res, err := singlewrap.Unwrap(col, err)
+
+	if nil == err {
+		out, err = res.Unwrap()
+	}
+
+	return out, err
+}
+
+func mapNotificationOrder(order notifsrv.NotificationOrder) notifrepo.NotificationOrder {
+	switch order {
+	case notifsrv.NOTIFICATION_ORDER_DATE_ASC:
+		return notifrepo.NOTIFICATION_ORDER_DATE_ASC
+	case notifsrv.NOTIFICATION_ORDER_DATE_DESC:
+		return notifrepo.NOTIFICATION_ORDER_DATE_DESC
+	default:
+		panic("Unknown order")
+	}
+}
+
+// Distinct emails mentioned in the content, in order of appearance
+func Mentions(content string) []string {
+	out := make([]string, 0)
+
+	for _, match := range mention.FindAllStringSubmatch(content, -1) {
+		if !slices.Contains(out, match[1]) {
+			out = append(out, match[1])
+		}
+
+		if MENTION_LIMIT == len(out) {
+			break
+		}
+	}
+
+	return out
+}
+
+func (self *Logic) GetNotifications(
+	ctx context.Context,
+	userId uuid.UUID,
+	unreadOnly bool,
+	order notifsrv.NotificationOrder,
+) (collection.Collection[result.Result[models.Notification]], error) {
+	return mapRepoError(self.Notification.GetNotificationsByUserId(
+		ctx, userId, unreadOnly, mapNotificationOrder(order),
+	))
+}
+
+func (self *Logic) CountUnread(ctx context.Context, userId uuid.UUID) (int, error) {
+	return mapRepoError(self.Notification.CountUnreadNotifications(ctx, userId))
+}
+
+func (self *Logic) MarkRead(
+	ctx context.Context,
+	userId uuid.UUID,
+	ids ...uuid.UUID,
+) (int, error) {
+	return mapRepoError(self.Notification.MarkNotificationsRead(ctx, userId, ids...))
+}
+
+// Author of the post or comment replied to along with the kind of reply
+func (self *Logic) replied(
+	ctx context.Context,
+	payload *events.Comment,
+) (uuid.UUID, models.NotificationKind, error) {
+	if nil == payload.ParentId {
+		post, err := single(self.Post.GetPostsById(ctx, payload.PostId))
+		return post.AuthorId, models.NOTIFICATION_KIND_POST_REPLY, err
+	} else {
+		parent, err := single(self.Comment.GetCommentsById(ctx, *payload.ParentId))
+		return parent.AuthorId, models.NOTIFICATION_KIND_COMMENT_REPLY, err
+	}
+}
+
+// Event handler, which notifies the author of the post or comment replied
+// to and every user mentioned. Meant to be registered in the event
+// dispatcher for COMMENT_CREATED, a user is notified about a comment once
+// and never about their own one
+func (self *Logic) Handle(ctx context.Context, event *models.Event) error {
+	var payload events.Comment
+	var mentioned []models.User
+	var err error
+	notifications := make([]models.Notification, 0)
+	now := time.Now()
+	add := func(userId uuid.UUID, kind models.NotificationKind) {
+		if userId != payload.AuthorId &&
+			!slices.ContainsFunc(notifications, func(v models.Notification) bool {
+				return userId == v.UserId
+			}) {
+			notifications = append(notifications, models.Notification{
+				UserId:       userId,
+				Kind:         kind,
+				ActorId:      payload.AuthorId,
+				PostId:       payload.PostId,
+				CommentId:    payload.Id,
+				CreationDate: now,
+			})
+		}
+	}
+
+	if events.COMMENT_CREATED != event.Type {
+		err = fmt.Errorf("event type %q isn't notified about", event.Type)
+	}
+
+	if nil == err {
+		payload, err = events.Decode[events.Comment](event)
+	}
+
+	if nil == err {
+		var authorId uuid.UUID
+		var kind models.NotificationKind
+
+		if authorId, kind, err = self.replied(ctx, &payload); nil == err {
+			add(authorId, kind)
+		}
+	}
+
+	if emails := Mentions(payload.Content); nil == err && 0 != len(emails) {
+		var col collection.Collection[result.Result[models.User]]
+		col, err = self.User.GetUsersByEmail(ctx, emails...)
+
+		if nil == err {
+			mentioned, err = pagination.Collect(col)
+		}
+	}
+
+	for _, user := range mentioned {
+		add(user.Id, models.NOTIFICATION_KIND_MENTION)
+	}
+
+	if nil == err && 0 != len(notifications) {
+		err = self.Notification.CreateNotifications(ctx, notifications...)
+	}
+
+	return err
+}
+
@@ -0,0 +1,204 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	notifsrv "github.com/muji40k/ozontestcomms/internal/service/interface/notification"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixture struct {
+	logic *Logic
+	repo  *inmemory.Repository
+	users []models.User
+	post  models.Post
+}
+
+// Post of the first user, every user is distinct
+func setup(t *testing.T, users int) *fixture {
+	out := &fixture{users: make([]models.User, users)}
+
+	for i := range out.users {
+		out.users[i] = common.Unwrap(domainOM.UserRandom().Build())
+	}
+
+	out.post = common.Unwrap(domainOM.PostDefault(
+		out.users[0].Id,
+		nullable.Some(true),
+		nullable.None[string](),
+		nullable.Some(time.Now()),
+	).Build())
+	out.repo = inmemory.New(func(adduser func(models.User), _ func(inmemory.Comment), addpost func(models.Post)) {
+		for _, v := range out.users {
+			adduser(v)
+		}
+
+		addpost(out.post)
+	})
+	out.logic = New(Context{
+		Notification: out.repo,
+		Comment:      out.repo,
+		Post:         out.repo,
+		User:         out.repo,
+	})
+
+	return out
+}
+
+// Comment of the user along with its event, replying to the parent if set
+func (self *fixture) comment(
+	t *testing.T,
+	author models.User,
+	parentId *uuid.UUID,
+	content string,
+) (models.Comment, models.Event) {
+	ctx := context.Background()
+	value := models.Comment{AuthorId: author.Id, Content: content, CreationDate: time.Now()}
+	var err error
+
+	if nil == parentId {
+		value.TargetId = self.post.Id
+		value, err = self.repo.CreatePostComment(ctx, value)
+	} else {
+		value.TargetId = *parentId
+		value, err = self.repo.CreateCommentComment(ctx, value)
+	}
+
+	require.NoError(t, err)
+
+	return value, common.Unwrap(events.CommentCreated(&value, self.post.Id, parentId))
+}
+
+func (self *fixture) notifications(t *testing.T, user models.User) []models.Notification {
+	return common.Unwrap(pagination.Collect(common.Unwrap(self.logic.GetNotifications(
+		context.Background(), user.Id, false, notifsrv.NOTIFICATION_ORDER_DATE_ASC,
+	))))
+}
+
+func TestMentions(t *testing.T) {
+	for _, c := range []struct {
+		content  string
+		expected []string
+	}{
+		{"@a@mail.com hi", []string{"a@mail.com"}},
+		{"hi @a.b+c@sub.mail.com, and @b@mail.ru.", []string{"a.b+c@sub.mail.com", "b@mail.ru"}},
+		{"@a@mail.com @a@mail.com (@c@mail.com)", []string{"a@mail.com", "c@mail.com"}},
+		{"x@a@mail.com a@mail.com @ @nobody", []string{}},
+	} {
+		t.Run(c.content, func(t *testing.T) {
+			assert.Equal(t, c.expected, Mentions(c.content))
+		})
+	}
+}
+
+func TestHandlePostReply(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := setup(t, 2)
+	reply, event := f.comment(t, f.users[1], nil, "first")
+	_, own := f.comment(t, f.users[0], nil, "own")
+
+	// Act
+	err := f.logic.Handle(ctx, &event)
+	oerr := f.logic.Handle(ctx, &own)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, oerr)
+	notified := f.notifications(t, f.users[0])
+	require.Len(t, notified, 1, "Not about own comments")
+	assert.Equal(t, models.NOTIFICATION_KIND_POST_REPLY, notified[0].Kind)
+	assert.Equal(t, f.users[1].Id, notified[0].ActorId)
+	assert.Equal(t, f.post.Id, notified[0].PostId)
+	assert.Equal(t, reply.Id, notified[0].CommentId)
+	assert.False(t, notified[0].Read)
+	assert.Empty(t, f.notifications(t, f.users[1]))
+}
+
+func TestHandleRetriedEventFollowsCursor(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := setup(t, 2)
+	_, earlier := f.comment(t, f.users[1], nil, "earlier")
+	_, later := f.comment(t, f.users[1], nil, "later")
+	require.NoError(t, f.logic.Handle(ctx, &later))
+	cursor := f.notifications(t, f.users[0])[0].Id
+
+	// Act
+	err := f.logic.Handle(ctx, &earlier)
+	col := common.Unwrap(f.logic.GetNotifications(
+		ctx, f.users[0].Id, false, notifsrv.NOTIFICATION_ORDER_DATE_ASC,
+	))
+	aerr := pagination.Apply(col, &cursor, 10)
+	added := common.Unwrap(pagination.Collect(col))
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, aerr)
+	require.Len(t, added, 1, "Retried event is seen after the cursor")
+	assert.NotEqual(t, cursor, added[0].Id)
+}
+
+func TestHandleCommentReplyAndMentions(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := setup(t, 4)
+	parent, _ := f.comment(t, f.users[1], nil, "parent")
+	_, event := f.comment(t, f.users[2], &parent.Id, "@"+f.users[1].Email+
+		" @"+f.users[3].Email+" @"+f.users[2].Email+" @unknown@mail.com",
+	)
+
+	// Act
+	err := f.logic.Handle(ctx, &event)
+	rerr := f.logic.Handle(ctx, &event)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, rerr)
+	replied := f.notifications(t, f.users[1])
+	require.Len(t, replied, 1, "Reply takes precedence over the mention")
+	assert.Equal(t, models.NOTIFICATION_KIND_COMMENT_REPLY, replied[0].Kind)
+	mentioned := f.notifications(t, f.users[3])
+	require.Len(t, mentioned, 1, "Redelivered event notifies once")
+	assert.Equal(t, models.NOTIFICATION_KIND_MENTION, mentioned[0].Kind)
+	assert.Empty(t, f.notifications(t, f.users[2]))
+	assert.Empty(t, f.notifications(t, f.users[0]), "Only the direct parent is replied to")
+}
+
+func TestMarkRead(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := setup(t, 2)
+
+	for range 3 {
+		_, event := f.comment(t, f.users[1], nil, "reply")
+		require.NoError(t, f.logic.Handle(ctx, &event))
+	}
+
+	notified := f.notifications(t, f.users[0])
+
+	// Act
+	marked, err := f.logic.MarkRead(ctx, f.users[0].Id, notified[0].Id)
+	foreign, ferr := f.logic.MarkRead(ctx, f.users[1].Id, notified[1].Id)
+	unread, cerr := f.logic.CountUnread(ctx, f.users[0].Id)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, ferr)
+	require.NoError(t, cerr)
+	assert.Equal(t, 1, marked)
+	assert.Equal(t, 0, foreign, "Naive authorization")
+	assert.Equal(t, 2, unread)
+}
+
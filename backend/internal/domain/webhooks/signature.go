@@ -0,0 +1,35 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Headers of every request sent to a webhook. Timestamp is in unix
+// seconds and is signed along with the body, so that receivers can reject
+// replayed requests
+const (
+	HEADER_EVENT     = "X-Poster-Event"
+	HEADER_DELIVERY  = "X-Poster-Delivery"
+	HEADER_TIMESTAMP = "X-Poster-Timestamp"
+	HEADER_SIGNATURE = "X-Poster-Signature"
+)
+
+const SIGNATURE_PREFIX = "sha256="
+
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret, hex
+// encoded and prefixed with the algorithm
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return SIGNATURE_PREFIX + hex.EncodeToString(mac.Sum(nil))
+}
+
+func Verify(secret string, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
@@ -0,0 +1,347 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	usrrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/user"
+	webhookrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/webhook"
+	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/singlewrap"
+	webhooksrv "github.com/muji40k/ozontestcomms/internal/service/interface/webhook"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// Event types webhooks can subscribe to
+var EVENTS = []string{events.POST_CREATED, events.POST_UPDATED, events.COMMENT_CREATED}
+
+const SECRET_SIZE = 32
+
+type Context struct {
+	Webhook webhookrepo.Repository
+	Post    postrepo.Repository
+	User    usrrepo.Repository
+}
+
+type Logic struct {
+	Context
+	allowInternal bool
+}
+
+func New(context Context) *Logic {
+	return &Logic{context, false}
+}
+
+// Receivers on internal addresses are refused otherwise, local test servers
+// need it
+func (self *Logic) WithInternalAddresses(allow bool) *Logic {
+	self.allowInternal = allow
+	return self
+}
+
+// Shared address space of carrier-grade NAT, not covered by netip
+var sharedAddresses = netip.MustParsePrefix("100.64.0.0/10")
+
+// Loopback, private, link-local and other addresses that aren't reachable
+// from the internet. Webhooks pointing at them could be used to probe the
+// internal network, as their owners see the outcome of every delivery
+func Internal(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() ||
+		sharedAddresses.Contains(addr) ||
+		netip.AddrFrom4([4]byte{255, 255, 255, 255}) == addr
+}
+
+// Body of every request sent to a webhook, data is the payload of the
+// event as described in the events package
+type Envelope struct {
+	Id           uuid.UUID       `json:"id"`
+	Type         string          `json:"type"`
+	CreationDate time.Time       `json:"created_at"`
+	Data         json.RawMessage `json:"data"`
+}
+
+func mapRepoError[T any](v T, err error) (T, error) {
+	if nil == err {
+		return v, nil
+	} else if cerr := (repoerrors.ErrorNotFound{}); errors.As(err, &cerr) {
+		return v, srverrors.NotFound(cerr.What...)
+	} else {
+		return v, srverrors.Internal(srverrors.DataAccess(err))
+	}
+}
+
+func single[T any](col collection.Collection[result.Result[T]], err error) (T, error) {
+	var out T
+	res, err := singlewrap.Unwrap(mapRepoError(col, err))
+
+	if nil == err {
+		out, err = mapRepoError(res.Unwrap())
+	}
+
+	return out, err
+}
+
+func newSecret() (string, error) {
+	buf := make([]byte, SECRET_SIZE)
+	_, err := rand.Read(buf)
+
+	return hex.EncodeToString(buf), err
+}
+
+// Only literal addresses and local names are checked here, the names
+// resolving to internal addresses are refused by the sender
+func (self *Logic) validateUrl(value string) error {
+	var parsed *url.URL
+	var err error
+
+	if "" == value {
+		return srverrors.Empty("webhook.url")
+	} else if models.WEBHOOK_URL_LENGTH_LIMIT < len(value) {
+		return srverrors.Incorrect(fmt.Sprintf(
+			"webhook.url exceeded max length [%v]",
+			models.WEBHOOK_URL_LENGTH_LIMIT,
+		))
+	} else if parsed, err = url.Parse(value); nil != err ||
+		("http" != parsed.Scheme && "https" != parsed.Scheme) ||
+		"" == parsed.Host {
+		return srverrors.Incorrect("webhook.url must be an absolute http(s) url")
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+
+	if self.allowInternal {
+		return nil
+	} else if addr, err := netip.ParseAddr(host); nil == err && Internal(addr) {
+		return srverrors.Incorrect("webhook.url must not point at an internal address")
+	} else if "localhost" == host || strings.HasSuffix(host, ".localhost") {
+		return srverrors.Incorrect("webhook.url must not point at an internal address")
+	}
+
+	return nil
+}
+
+func validateEvents(kinds []string) ([]string, error) {
+	if 0 == len(kinds) {
+		return nil, srverrors.Empty("webhook.events")
+	}
+
+	for _, kind := range kinds {
+		if !slices.Contains(EVENTS, kind) {
+			return nil, srverrors.Incorrect(fmt.Sprintf("webhook.events: unknown event %q", kind))
+		}
+	}
+
+	out := slices.Clone(kinds)
+	slices.Sort(out)
+
+	return slices.Compact(out), nil
+}
+
+// Webhook of the user, naive authorization
+func (self *Logic) getOwned(
+	ctx context.Context,
+	userId uuid.UUID,
+	webhookId uuid.UUID,
+) (models.Webhook, error) {
+	out, err := single(self.Webhook.GetWebhooksById(ctx, webhookId))
+
+	if nil == err && userId != out.OwnerId {
+		err = srverrors.Authorization(errors.New("Naive authorization"))
+	}
+
+	return out, err
+}
+
+func (self *Logic) GetWebhooks(
+	ctx context.Context,
+	userId uuid.UUID,
+) (collection.Collection[result.Result[models.Webhook]], error) {
+	return mapRepoError(self.Webhook.GetWebhooksByOwnerId(ctx, userId))
+}
+
+func (self *Logic) CreateWebhook(
+	ctx context.Context,
+	userId uuid.UUID,
+	form webhooksrv.WebhookCreationForm,
+) (models.Webhook, error) {
+	var out models.Webhook
+	var kinds []string
+	_, err := mapRepoError(self.User.GetUsersById(ctx, userId))
+
+	if nil == err {
+		err = self.validateUrl(form.Url)
+	}
+
+	if nil == err {
+		kinds, err = validateEvents(form.Events)
+	}
+
+	if nil == err && nil != form.PostId {
+		_, err = single(self.Post.GetPostsById(ctx, *form.PostId))
+	}
+
+	if nil == err {
+		out = models.Webhook{
+			OwnerId:      userId,
+			Url:          form.Url,
+			Events:       kinds,
+			CreationDate: time.Now(),
+		}
+
+		if nil != form.PostId {
+			out.PostId = uuid.NullUUID{UUID: *form.PostId, Valid: true}
+		}
+
+		if out.Secret, err = newSecret(); nil != err {
+			err = srverrors.Internal(err)
+		}
+	}
+
+	if nil == err {
+		out, err = mapRepoError(self.Webhook.CreateWebhook(ctx, out))
+	}
+
+	return out, err
+}
+
+func (self *Logic) DeleteWebhook(
+	ctx context.Context,
+	userId uuid.UUID,
+	webhookId uuid.UUID,
+) error {
+	_, err := self.getOwned(ctx, userId, webhookId)
+
+	if nil == err {
+		_, err = mapRepoError(struct{}{}, self.Webhook.DeleteWebhook(ctx, webhookId))
+	}
+
+	return err
+}
+
+func (self *Logic) GetDeliveries(
+	ctx context.Context,
+	userId uuid.UUID,
+	webhookId uuid.UUID,
+	status ...models.DeliveryStatus,
+) (collection.Collection[result.Result[models.WebhookDelivery]], error) {
+	var out collection.Collection[result.Result[models.WebhookDelivery]]
+	_, err := self.getOwned(ctx, userId, webhookId)
+
+	if nil == err {
+		out, err = mapRepoError(self.Webhook.GetDeliveriesByWebhookId(ctx, webhookId))
+	}
+
+	if nil == err && 0 != len(status) {
+		out = collection.Filter(out, func(v *result.Result[models.WebhookDelivery]) bool {
+			return v.IsError() || slices.Contains(status, v.Value.Status)
+		})
+	}
+
+	return out, err
+}
+
+func (self *Logic) RetryDelivery(
+	ctx context.Context,
+	userId uuid.UUID,
+	deliveryId uuid.UUID,
+) (models.WebhookDelivery, error) {
+	out, err := single(self.Webhook.GetDeliveriesById(ctx, deliveryId))
+
+	if nil == err {
+		_, err = self.getOwned(ctx, userId, out.WebhookId)
+	}
+
+	if nil == err && models.DELIVERY_STATUS_DEAD != out.Status {
+		err = srverrors.Violation("only dead deliveries can be retried")
+	}
+
+	if nil == err {
+		out.Status = models.DELIVERY_STATUS_PENDING
+		out.Attempts = 0
+		out.NextAttempt = time.Now()
+		out, err = mapRepoError(self.Webhook.UpdateDelivery(ctx, out))
+	}
+
+	return out, err
+}
+
+func postOf(event *models.Event) (uuid.UUID, error) {
+	switch event.Type {
+	case events.POST_CREATED, events.POST_UPDATED:
+		return event.EntityId, nil
+	case events.COMMENT_CREATED:
+		payload, err := events.Decode[events.Comment](event)
+		return payload.PostId, err
+	default:
+		return uuid.Nil, fmt.Errorf("event type %q isn't sent to webhooks", event.Type)
+	}
+}
+
+// Event handler, which schedules a delivery of the event to every webhook
+// subscribed to it. Meant to be registered in the event dispatcher for
+// EVENTS, redelivered events are scheduled once
+func (self *Logic) Handle(ctx context.Context, event *models.Event) error {
+	var subscribed []models.Webhook
+	var payload []byte
+	postId, err := postOf(event)
+
+	if nil == err {
+		var col collection.Collection[result.Result[models.Webhook]]
+		col, err = self.Webhook.GetWebhooksByEvent(ctx, event.Type, postId)
+
+		if nil == err {
+			subscribed, err = pagination.Collect(col)
+		}
+	}
+
+	if nil == err && 0 != len(subscribed) {
+		payload, err = json.Marshal(Envelope{
+			Id:           event.Id,
+			Type:         event.Type,
+			CreationDate: event.CreationDate.UTC(),
+			Data:         event.Payload,
+		})
+	}
+
+	if nil == err && 0 != len(subscribed) {
+		now := time.Now()
+		deliveries := make([]models.WebhookDelivery, len(subscribed))
+
+		for i, webhook := range subscribed {
+			deliveries[i] = models.WebhookDelivery{
+				WebhookId:    webhook.Id,
+				EventId:      event.Id,
+				EventType:    event.Type,
+				Payload:      payload,
+				Status:       models.DELIVERY_STATUS_PENDING,
+				NextAttempt:  now,
+				CreationDate: now,
+			}
+		}
+
+		err = self.Webhook.CreateDeliveries(ctx, deliveries...)
+	}
+
+	return err
+}
+
@@ -0,0 +1,185 @@
+package webhooks
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	webhooksrv "github.com/muji40k/ozontestcomms/internal/service/interface/webhook"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setup(t *testing.T) (*Logic, *inmemory.Repository, models.User, models.Post) {
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	post := common.Unwrap(domainOM.PostDefault(
+		user.Id,
+		nullable.Some(true),
+		nullable.None[string](),
+		nullable.Some(time.Now()),
+	).Build())
+	repo := inmemory.New(func(adduser func(models.User), _ func(inmemory.Comment), addpost func(models.Post)) {
+		adduser(user)
+		addpost(post)
+	})
+
+	return New(Context{Webhook: repo, Post: repo, User: repo}), repo, user, post
+}
+
+func form(url string, postId *uuid.UUID, kinds ...string) webhooksrv.WebhookCreationForm {
+	return webhooksrv.WebhookCreationForm{Url: url, Events: kinds, PostId: postId}
+}
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := Sign("secret", "100", body)
+
+	assert.True(t, strings.HasPrefix(signature, SIGNATURE_PREFIX))
+	assert.True(t, Verify("secret", "100", body, signature))
+	assert.False(t, Verify("secret", "101", body, signature), "Timestamp is signed")
+	assert.False(t, Verify("secret", "100", []byte(`{"id":2}`), signature))
+}
+
+func TestCreateWebhook(t *testing.T) {
+	// Arrange
+	logic, _, user, post := setup(t)
+
+	// Act
+	hook, err := logic.CreateWebhook(context.Background(), user.Id, form(
+		"https://example.com/hook",
+		&post.Id,
+		events.COMMENT_CREATED, events.POST_UPDATED, events.COMMENT_CREATED,
+	))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, hook.Secret, 2*SECRET_SIZE)
+	assert.Equal(t, []string{events.COMMENT_CREATED, events.POST_UPDATED}, hook.Events)
+	assert.Equal(t, uuid.NullUUID{UUID: post.Id, Valid: true}, hook.PostId)
+}
+
+func TestCreateWebhookValidation(t *testing.T) {
+	logic, _, user, _ := setup(t)
+	unknown := uuid.New()
+
+	for _, c := range []struct {
+		name string
+		form webhooksrv.WebhookCreationForm
+		err  any
+	}{
+		{"empty url", form("", nil, events.POST_CREATED), &srverrors.ErrorEmpty{}},
+		{"scheme", form("ftp://example.com", nil, events.POST_CREATED), &srverrors.ErrorIncorrect{}},
+		{"relative", form("/hook", nil, events.POST_CREATED), &srverrors.ErrorIncorrect{}},
+		{"long url", form("http://example.com/"+strings.Repeat("a", models.WEBHOOK_URL_LENGTH_LIMIT), nil, events.POST_CREATED), &srverrors.ErrorIncorrect{}},
+		{"no events", form("http://example.com", nil), &srverrors.ErrorEmpty{}},
+		{"user events", form("http://example.com", nil, events.USER_CREATED), &srverrors.ErrorIncorrect{}},
+		{"unknown post", form("http://example.com", &unknown, events.POST_CREATED), &srverrors.ErrorNotFound{}},
+		{"loopback", form("http://127.0.0.1:5432", nil, events.POST_CREATED), &srverrors.ErrorIncorrect{}},
+		{"localhost", form("http://LocalHost./hook", nil, events.POST_CREATED), &srverrors.ErrorIncorrect{}},
+		{"metadata", form("http://169.254.169.254/", nil, events.POST_CREATED), &srverrors.ErrorIncorrect{}},
+		{"private", form("https://10.0.0.1/hook", nil, events.POST_CREATED), &srverrors.ErrorIncorrect{}},
+		{"mapped", form("http://[::ffff:192.168.0.1]/", nil, events.POST_CREATED), &srverrors.ErrorIncorrect{}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := logic.CreateWebhook(context.Background(), user.Id, c.form)
+			assert.ErrorAs(t, err, c.err)
+		})
+	}
+}
+
+func TestCreateWebhookAllowInternal(t *testing.T) {
+	// Arrange
+	logic, _, user, _ := setup(t)
+	logic.WithInternalAddresses(true)
+
+	// Act
+	_, err := logic.CreateWebhook(context.Background(), user.Id, form(
+		"http://127.0.0.1:8080/hook",
+		nil,
+		events.POST_CREATED,
+	))
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestInternal(t *testing.T) {
+	for addr, expected := range map[string]bool{
+		"127.0.0.1":          true,
+		"::1":                true,
+		"10.1.2.3":           true,
+		"172.16.0.1":         true,
+		"192.168.1.1":        true,
+		"169.254.169.254":    true,
+		"100.64.0.1":         true,
+		"0.0.0.0":            true,
+		"255.255.255.255":    true,
+		"fe80::1":            true,
+		"fd00::1":            true,
+		"::ffff:10.0.0.1":    true,
+		"93.184.216.34":      false,
+		"2606:4700:4700::64": false,
+	} {
+		t.Run(addr, func(t *testing.T) {
+			assert.Equal(t, expected, Internal(netip.MustParseAddr(addr)))
+		})
+	}
+}
+
+func TestDeleteWebhookNaiveAuthorization(t *testing.T) {
+	// Arrange
+	logic, _, user, _ := setup(t)
+	hook := common.Unwrap(logic.CreateWebhook(context.Background(), user.Id,
+		form("http://example.com", nil, events.POST_CREATED),
+	))
+
+	// Act
+	oerr := logic.DeleteWebhook(context.Background(), uuid.New(), hook.Id)
+	err := logic.DeleteWebhook(context.Background(), user.Id, hook.Id)
+
+	// Assert
+	assert.ErrorAs(t, oerr, &srverrors.ErrorAuthorization{})
+	assert.NoError(t, err)
+}
+
+func TestHandleMatchesPost(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	logic, repo, user, post := setup(t)
+	every := common.Unwrap(logic.CreateWebhook(ctx, user.Id,
+		form("http://example.com", nil, events.COMMENT_CREATED),
+	))
+	single := common.Unwrap(logic.CreateWebhook(ctx, user.Id,
+		form("http://example.com", &post.Id, events.COMMENT_CREATED),
+	))
+	comment := models.Comment{Id: uuid.New(), AuthorId: user.Id, Content: "c"}
+	other := common.Unwrap(events.CommentCreated(&comment, uuid.New(), nil))
+	own := common.Unwrap(events.CommentCreated(&comment, post.Id, nil))
+
+	// Act
+	oerr := logic.Handle(ctx, &other)
+	err := logic.Handle(ctx, &own)
+	rerr := logic.Handle(ctx, &own)
+
+	// Assert
+	require.NoError(t, oerr)
+	require.NoError(t, err)
+	require.NoError(t, rerr)
+	assert.Len(t, common.Unwrap(pagination.Collect(common.Unwrap(repo.GetDeliveriesByWebhookId(ctx, every.Id)))), 2)
+	deliveries := common.Unwrap(pagination.Collect(common.Unwrap(repo.GetDeliveriesByWebhookId(ctx, single.Id))))
+	require.Len(t, deliveries, 1, "Redelivered event is scheduled once")
+	assert.Equal(t, own.Id, deliveries[0].EventId)
+	assert.Contains(t, string(deliveries[0].Payload), `"type":"comment.created"`)
+}
+
@@ -0,0 +1,249 @@
+// Streaming dump of posts with their comment trees and its restoration
+// into any repository implementation
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	commrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	"github.com/muji40k/ozontestcomms/internal/seed"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// Number of posts or comments read at once, only pages along the current
+// path of the comment tree are kept in memory
+const PAGE_SIZE int32 = 100
+
+// Selects posts, every selected post is exported with all of its comments.
+// Zero dates and empty authors don't restrict anything
+type Filter struct {
+	// Inclusive
+	From time.Time
+	// Exclusive
+	To      time.Time
+	Authors []uuid.UUID
+}
+
+func (self *Filter) match(post *models.Post) bool {
+	return (self.From.IsZero() || !post.CreationDate.Before(self.From)) &&
+		(0 == len(self.Authors) || slices.Contains(self.Authors, post.AuthorId))
+}
+
+// Posts are read oldest first, so nothing after the range is matched
+func (self *Filter) passed(post *models.Post) bool {
+	return !self.To.IsZero() && !post.CreationDate.Before(self.To)
+}
+
+// Accepts RFC 3339 timestamps and plain dates
+func ParseTime(value string) (time.Time, error) {
+	if "" == value {
+		return time.Time{}, nil
+	}
+
+	out, err := time.Parse(time.RFC3339, value)
+
+	if nil != err {
+		out, err = time.Parse(time.DateOnly, value)
+	}
+
+	if nil != err {
+		err = fmt.Errorf("time %q: expected RFC 3339 timestamp or date", value)
+	}
+
+	return out, err
+}
+
+type exporter struct {
+	ctx     context.Context
+	repos   seed.Repositories
+	encoder encoder
+	users   map[uuid.UUID]struct{}
+	stats   seed.Stats
+}
+
+// Reads collection page by page, the page is collected before f is called
+// for its values, so no iterator is held open meanwhile
+func paged[T any](
+	get func() (collection.Collection[result.Result[T]], error),
+	id func(*T) uuid.UUID,
+	f func(*T) (bool, error),
+) error {
+	var after *uuid.UUID
+	next, more := true, true
+	var err error
+
+	for nil == err && next && more {
+		var col collection.Collection[result.Result[T]]
+		var page []T
+		col, err = get()
+
+		if nil == err {
+			err = pagination.Apply(col, after, PAGE_SIZE)
+		}
+
+		if nil == err {
+			page, err = pagination.Collect(col)
+			more = PAGE_SIZE == int32(len(page))
+		}
+
+		for i := 0; nil == err && next && len(page) > i; i++ {
+			next, err = f(&page[i])
+		}
+
+		if nil == err && next && more {
+			last := id(&page[len(page)-1])
+			after = &last
+		}
+	}
+
+	return err
+}
+
+func (self *exporter) user(id uuid.UUID) error {
+	if _, found := self.users[id]; found {
+		return nil
+	}
+
+	var users []models.User
+	col, err := self.repos.User.GetUsersById(self.ctx, id)
+
+	if nil == err {
+		users, err = pagination.Collect(col)
+	}
+
+	if nil == err {
+		self.users[id] = struct{}{}
+		self.stats.Users++
+		err = self.encoder.write(&Row{
+			Kind:  KIND_USER,
+			Id:    users[0].Id,
+			Email: users[0].Email,
+		})
+	}
+
+	return err
+}
+
+func (self *exporter) comments(
+	get func() (collection.Collection[result.Result[models.Comment]], error),
+	post uuid.UUID,
+	parent *uuid.UUID,
+) error {
+	return paged(get, func(v *models.Comment) uuid.UUID { return v.Id },
+		func(comment *models.Comment) (bool, error) {
+			if err := self.ctx.Err(); nil != err {
+				return false, err
+			}
+
+			err := self.user(comment.AuthorId)
+
+			if nil == err {
+				self.stats.Comments++
+				err = self.encoder.write(&Row{
+					Kind:      KIND_COMMENT,
+					Id:        comment.Id,
+					PostId:    &post,
+					ParentId:  parent,
+					AuthorId:  &comment.AuthorId,
+					Content:   comment.Content,
+					CreatedAt: &comment.CreationDate,
+				})
+			}
+
+			if nil == err {
+				err = self.comments(func() (collection.Collection[result.Result[models.Comment]], error) {
+					return self.repos.Comment.GetCommentsByCommentId(
+						self.ctx,
+						comment.Id,
+						commrepo.COMMENT_ORDER_DATE_ASC,
+					)
+				}, post, &comment.Id)
+			}
+
+			return true, err
+		},
+	)
+}
+
+func (self *exporter) post(post *models.Post) error {
+	err := self.user(post.AuthorId)
+
+	if nil == err {
+		self.stats.Posts++
+		err = self.encoder.write(&Row{
+			Kind:            KIND_POST,
+			Id:              post.Id,
+			AuthorId:        &post.AuthorId,
+			Title:           post.Title,
+			Content:         post.Content,
+			CommentsAllowed: &post.CommentsAllowed,
+			CreatedAt:       &post.CreationDate,
+		})
+	}
+
+	if nil == err {
+		err = self.comments(func() (collection.Collection[result.Result[models.Comment]], error) {
+			return self.repos.Comment.GetCommentsByPostId(
+				self.ctx,
+				post.Id,
+				commrepo.COMMENT_ORDER_DATE_ASC,
+			)
+		}, post.Id, nil)
+	}
+
+	return err
+}
+
+// Writes matching posts oldest first, each followed by its comment tree
+// depth first. Repositories are read directly, so that comments the services
+// don't show (e.g. of posts with comments disabled) are dumped too. Output
+// written before an error is left as is
+func Export(
+	ctx context.Context,
+	repos seed.Repositories,
+	w io.Writer,
+	format Format,
+	filter Filter,
+) (seed.Stats, error) {
+	e := exporter{
+		ctx:   ctx,
+		repos: repos,
+		users: make(map[uuid.UUID]struct{}),
+	}
+	var err error
+	e.encoder, err = newEncoder(w, format)
+
+	if nil == err {
+		err = paged(func() (collection.Collection[result.Result[models.Post]], error) {
+			return repos.Post.GetPosts(ctx, postrepo.POST_ORDER_DATE_ASC)
+		}, func(v *models.Post) uuid.UUID { return v.Id },
+			func(post *models.Post) (bool, error) {
+				if err := ctx.Err(); nil != err {
+					return false, err
+				} else if filter.passed(post) {
+					return false, nil
+				} else if !filter.match(post) {
+					return true, nil
+				}
+
+				return true, e.post(post)
+			},
+		)
+	}
+
+	if nil == err {
+		err = e.encoder.flush()
+	}
+
+	return e.stats, err
+}
+
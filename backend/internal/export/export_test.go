@@ -0,0 +1,262 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/export"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	"github.com/muji40k/ozontestcomms/internal/seed"
+	"github.com/muji40k/ozontestcomms/test/common"
+	"github.com/muji40k/ozontestcomms/test/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func repositories() seed.Repositories {
+	repo := inmemory.New(nil)
+
+	return seed.Repositories{User: repo, Post: repo, Comment: repo}
+}
+
+func dump(
+	t *testing.T,
+	repos seed.Repositories,
+	format export.Format,
+	filter export.Filter,
+) (*bytes.Buffer, seed.Stats) {
+	var out bytes.Buffer
+	stats, err := export.Export(context.Background(), repos, &out, format, filter)
+	require.NoError(t, err)
+
+	return &out, stats
+}
+
+func rows(t *testing.T, r io.Reader) []export.Row {
+	var out []export.Row
+	decoder := json.NewDecoder(r)
+
+	for decoder.More() {
+		var row export.Row
+		require.NoError(t, decoder.Decode(&row))
+		out = append(out, row)
+	}
+
+	return out
+}
+
+// Replaces ids with positions of their first appearance, so that dumps of
+// different repositories can be compared
+func normalize(values []export.Row) []export.Row {
+	positions := make(map[uuid.UUID]uuid.UUID)
+	id := func(v uuid.UUID) uuid.UUID {
+		if _, found := positions[v]; !found {
+			positions[v] = uuid.UUID{byte(len(positions) >> 8), byte(len(positions))}
+		}
+
+		return positions[v]
+	}
+	ref := func(v *uuid.UUID) *uuid.UUID {
+		if nil == v {
+			return nil
+		}
+
+		out := id(*v)
+		return &out
+	}
+	out := make([]export.Row, len(values))
+
+	for i, v := range values {
+		v.Id = id(v.Id)
+		v.PostId = ref(v.PostId)
+		v.ParentId = ref(v.ParentId)
+		v.AuthorId = ref(v.AuthorId)
+
+		if nil != v.CreatedAt {
+			created := v.CreatedAt.UTC()
+			v.CreatedAt = &created
+		}
+
+		out[i] = v
+	}
+
+	return out
+}
+
+func TestExportThread(t *testing.T) {
+	// Arrange
+	repos := repositories()
+	loaded := fixtures.Load(t, repos, fixtures.THREAD)
+
+	// Act
+	out, stats := dump(t, repos, export.FORMAT_NDJSON, export.Filter{})
+
+	// Assert
+	values := rows(t, out)
+	assert.Equal(t, seed.Stats{Users: 3, Posts: 2, Comments: 4}, stats)
+	require.Len(t, values, 9)
+
+	kinds := make([]export.Kind, len(values))
+	ids := make([]uuid.UUID, len(values))
+
+	for i, v := range values {
+		kinds[i] = v.Kind
+		ids[i] = v.Id
+	}
+
+	assert.Equal(t, []export.Kind{
+		export.KIND_USER, export.KIND_POST,
+		export.KIND_USER, export.KIND_COMMENT, export.KIND_COMMENT, export.KIND_COMMENT,
+		export.KIND_USER, export.KIND_COMMENT,
+		export.KIND_POST,
+	}, kinds)
+	assert.Equal(t, []uuid.UUID{
+		loaded.Users["alice"].Id,
+		loaded.Posts["welcome"].Id,
+		loaded.Users["bob"].Id,
+		loaded.Comments["hello"].Id,
+		loaded.Comments["hello-reply"].Id,
+		loaded.Comments["hello-reply-reply"].Id,
+		loaded.Users["carol"].Id,
+		loaded.Comments["question"].Id,
+		loaded.Posts["rules"].Id,
+	}, ids)
+	assert.Equal(t, loaded.Comments["hello"].Id, *values[4].ParentId)
+	assert.Nil(t, values[7].ParentId)
+	assert.Equal(t, loaded.Posts["welcome"].Id, *values[5].PostId)
+	assert.False(t, *values[8].CommentsAllowed)
+	assert.Equal(t, "alice@poster.test", values[0].Email)
+}
+
+func TestExportCommentsOfClosedPost(t *testing.T) {
+	// Arrange
+	repos := repositories()
+	loaded := fixtures.Load(t, repos, fixtures.THREAD)
+	closed := loaded.Posts["welcome"]
+	closed.CommentsAllowed = false
+	common.Unwrap(repos.Post.UpdatePost(context.Background(), closed))
+
+	// Act
+	out, stats := dump(t, repos, export.FORMAT_NDJSON, export.Filter{})
+
+	// Assert
+	values := rows(t, out)
+	assert.Equal(t, seed.Stats{Users: 3, Posts: 2, Comments: 4}, stats)
+	require.Len(t, values, 9)
+	assert.False(t, *values[1].CommentsAllowed)
+	assert.Equal(t, loaded.Comments["hello"].Id, values[3].Id)
+}
+
+func TestExportFilter(t *testing.T) {
+	// Arrange
+	repos := repositories()
+	loaded := fixtures.Load(t, repos, fixtures.FEED)
+	filter := export.Filter{
+		From:    common.Unwrap(export.ParseTime("2025-03-04")),
+		To:      common.Unwrap(export.ParseTime("2025-03-07T09:00:00Z")),
+		Authors: []uuid.UUID{loaded.Users["alice"].Id},
+	}
+
+	// Act
+	out, stats := dump(t, repos, export.FORMAT_NDJSON, filter)
+
+	// Assert
+	values := rows(t, out)
+	assert.Equal(t, seed.Stats{Users: 1, Posts: 1}, stats)
+	require.Len(t, values, 2)
+	assert.Equal(t, loaded.Users["alice"].Id, values[0].Id)
+	assert.Equal(t, loaded.Posts["p3"].Id, values[1].Id)
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range export.FORMATS {
+		t.Run(string(format), func(t *testing.T) {
+			// Arrange
+			repos := repositories()
+			fixtures.Load(t, repos, fixtures.THREAD)
+			options := seed.DEFAULT_OPTIONS
+			// More than a page of posts and replies
+			options.Posts = int(export.PAGE_SIZE) + 20
+			options.Now = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+			common.Unwrap(seed.Generate(context.Background(), repos, options))
+			source, exported := dump(t, repos, format, export.Filter{})
+			expected, _ := dump(t, repos, export.FORMAT_NDJSON, export.Filter{})
+			target := repositories()
+
+			// Act
+			imported, err := export.Import(context.Background(), target, source, format)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, exported, imported)
+			actual, _ := dump(t, target, export.FORMAT_NDJSON, export.Filter{})
+			assert.Equal(t, normalize(rows(t, expected)), normalize(rows(t, actual)))
+		})
+	}
+}
+
+func TestImportKeepsExistingUsers(t *testing.T) {
+	// Arrange
+	repos := repositories()
+	fixtures.Load(t, repos, fixtures.FEED)
+	source, _ := dump(t, repos, export.FORMAT_CSV, export.Filter{})
+
+	// Act
+	stats, err := export.Import(context.Background(), repos, source, export.FORMAT_CSV)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, seed.Stats{Posts: 7}, stats)
+	_, exported := dump(t, repos, export.FORMAT_NDJSON, export.Filter{})
+	assert.Equal(t, seed.Stats{Users: 2, Posts: 14}, exported)
+}
+
+func TestImportErrors(t *testing.T) {
+	parent := uuid.New()
+
+	for name, tc := range map[string]struct {
+		format export.Format
+		input  string
+		err    string
+	}{
+		"unknown parent": {
+			export.FORMAT_NDJSON,
+			`{"kind":"comment","id":"` + uuid.NewString() + `","parent_id":"` + parent.String() + `"}`,
+			"row 1: parent comment " + parent.String(),
+		},
+		"unknown kind": {
+			export.FORMAT_NDJSON,
+			`{"kind":"like","id":"` + uuid.NewString() + `"}`,
+			`row 1: unknown kind "like"`,
+		},
+		"unknown field": {
+			export.FORMAT_NDJSON,
+			`{"kind":"user","id":"` + uuid.NewString() + `","password":"secret"}`,
+			"row 1: json: unknown field",
+		},
+		"wrong header": {
+			export.FORMAT_CSV,
+			"kind,id\n",
+			"unexpected header",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			repos := repositories()
+
+			// Act
+			_, err := export.Import(context.Background(), repos, strings.NewReader(tc.input), tc.format)
+
+			// Assert
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
@@ -0,0 +1,242 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	// One json object per line
+	FORMAT_NDJSON Format = "ndjson"
+	// Header line followed by rows of COLUMNS
+	FORMAT_CSV Format = "csv"
+)
+
+var FORMATS = []Format{FORMAT_NDJSON, FORMAT_CSV}
+
+func (self Format) ContentType() string {
+	switch self {
+	case FORMAT_CSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+func ParseFormat(value string) (Format, error) {
+	if format := Format(value); slices.Contains(FORMATS, format) {
+		return format, nil
+	}
+
+	return "", fmt.Errorf("unknown format %q, expected one of: %v", value, FORMATS)
+}
+
+type Kind string
+
+const (
+	KIND_USER    Kind = "user"
+	KIND_POST    Kind = "post"
+	KIND_COMMENT Kind = "comment"
+)
+
+// Single exported entity. Every post is followed by its comment tree in
+// depth-first order, every author is listed before their first content
+type Row struct {
+	Kind Kind      `json:"kind"`
+	Id   uuid.UUID `json:"id"`
+	// Post the comment belongs to
+	PostId *uuid.UUID `json:"post_id,omitempty"`
+	// Comment replied to, none for top level comments
+	ParentId        *uuid.UUID `json:"parent_id,omitempty"`
+	AuthorId        *uuid.UUID `json:"author_id,omitempty"`
+	Email           string     `json:"email,omitempty"`
+	Title           string     `json:"title,omitempty"`
+	Content         string     `json:"content,omitempty"`
+	CommentsAllowed *bool      `json:"comments_allowed,omitempty"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+}
+
+var COLUMNS = []string{
+	"kind",
+	"id",
+	"post_id",
+	"parent_id",
+	"author_id",
+	"email",
+	"title",
+	"content",
+	"comments_allowed",
+	"created_at",
+}
+
+type encoder interface {
+	write(*Row) error
+	flush() error
+}
+
+type decoder interface {
+	// Returns io.EOF after the last row
+	read() (*Row, error)
+}
+
+func newEncoder(w io.Writer, format Format) (encoder, error) {
+	switch format {
+	case FORMAT_NDJSON:
+		return &jsonEncoder{json.NewEncoder(w)}, nil
+	case FORMAT_CSV:
+		out := &csvEncoder{csv.NewWriter(w)}
+		return out, out.w.Write(COLUMNS)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func newDecoder(r io.Reader, format Format) (decoder, error) {
+	switch format {
+	case FORMAT_NDJSON:
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+
+		return &jsonDecoder{decoder}, nil
+	case FORMAT_CSV:
+		reader := csv.NewReader(r)
+		// Rows must have as many fields as the header, which is checked below
+		reader.ReuseRecord = true
+		header, err := reader.Read()
+
+		if nil == err && !slices.Equal(COLUMNS, header) {
+			err = fmt.Errorf("unexpected header %v, expected %v", header, COLUMNS)
+		}
+
+		return &csvDecoder{reader}, err
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+type jsonEncoder struct {
+	e *json.Encoder
+}
+
+func (self *jsonEncoder) write(row *Row) error {
+	return self.e.Encode(row)
+}
+
+func (self *jsonEncoder) flush() error {
+	return nil
+}
+
+type jsonDecoder struct {
+	d *json.Decoder
+}
+
+func (self *jsonDecoder) read() (*Row, error) {
+	out := new(Row)
+
+	if err := self.d.Decode(out); nil != err {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func formatId(id *uuid.UUID) string {
+	if nil == id {
+		return ""
+	}
+
+	return id.String()
+}
+
+func (self *csvEncoder) write(row *Row) error {
+	allowed := ""
+	created := ""
+
+	if nil != row.CommentsAllowed {
+		allowed = strconv.FormatBool(*row.CommentsAllowed)
+	}
+
+	if nil != row.CreatedAt {
+		created = row.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	return self.w.Write([]string{
+		string(row.Kind),
+		row.Id.String(),
+		formatId(row.PostId),
+		formatId(row.ParentId),
+		formatId(row.AuthorId),
+		row.Email,
+		row.Title,
+		row.Content,
+		allowed,
+		created,
+	})
+}
+
+func (self *csvEncoder) flush() error {
+	self.w.Flush()
+	return self.w.Error()
+}
+
+type csvDecoder struct {
+	r *csv.Reader
+}
+
+func parseId(value string, err *error) *uuid.UUID {
+	if "" == value || nil != *err {
+		return nil
+	}
+
+	id, perr := uuid.Parse(value)
+	*err = perr
+
+	return &id
+}
+
+func (self *csvDecoder) read() (*Row, error) {
+	record, err := self.r.Read()
+
+	if nil != err {
+		return nil, err
+	}
+
+	out := &Row{
+		Kind:    Kind(record[0]),
+		Email:   record[5],
+		Title:   record[6],
+		Content: record[7],
+	}
+	out.Id, err = uuid.Parse(record[1])
+	out.PostId = parseId(record[2], &err)
+	out.ParentId = parseId(record[3], &err)
+	out.AuthorId = parseId(record[4], &err)
+
+	if nil == err && "" != record[8] {
+		var allowed bool
+		allowed, err = strconv.ParseBool(record[8])
+		out.CommentsAllowed = &allowed
+	}
+
+	if nil == err && "" != record[9] {
+		var created time.Time
+		created, err = time.Parse(time.RFC3339Nano, record[9])
+		out.CreatedAt = &created
+	}
+
+	return out, err
+}
+
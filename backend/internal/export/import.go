@@ -0,0 +1,210 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/builders/domain"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	"github.com/muji40k/ozontestcomms/internal/seed"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+)
+
+type importer struct {
+	ctx   context.Context
+	repos seed.Repositories
+	// Ids of the dump mapped to the ones assigned by the repository
+	ids   map[uuid.UUID]uuid.UUID
+	stats seed.Stats
+}
+
+func (self *importer) lookup(id *uuid.UUID, what string) (uuid.UUID, error) {
+	if nil == id {
+		return uuid.Nil, fmt.Errorf("no %v", what)
+	} else if out, found := self.ids[*id]; found {
+		return out, nil
+	} else {
+		return uuid.Nil, fmt.Errorf("%v %v isn't imported before", what, *id)
+	}
+}
+
+func (self *importer) date(row *Row) (out time.Time, err error) {
+	if nil == row.CreatedAt {
+		err = errors.New("no creation date")
+	} else {
+		out = *row.CreatedAt
+	}
+
+	return out, err
+}
+
+// Users already present with the same id are kept, others are registered
+// with a random password, since passwords aren't exported
+func (self *importer) user(row *Row) error {
+	var user models.User
+	col, err := self.repos.User.GetUsersById(self.ctx, row.Id)
+
+	if nil == err {
+		var users []models.User
+		users, err = pagination.Collect(col)
+
+		if nil == err {
+			user = users[0]
+		}
+	}
+
+	if errors.As(err, &repoerrors.ErrorNotFound{}) {
+		user, err = domain.NewUserBuilder().
+			WithId(uuid.Nil).
+			WithEmail(row.Email).
+			WithPassword(uuid.NewString()).
+			Build()
+
+		if nil == err {
+			user, err = self.repos.User.CreateUser(self.ctx, user)
+		}
+
+		if nil == err {
+			self.stats.Users++
+		}
+	}
+
+	if nil == err {
+		self.ids[row.Id] = user.Id
+	}
+
+	return err
+}
+
+func (self *importer) post(row *Row) error {
+	var post models.Post
+	var created time.Time
+	allowed := nil == row.CommentsAllowed || *row.CommentsAllowed
+	author, err := self.lookup(row.AuthorId, "author")
+
+	if nil == err {
+		created, err = self.date(row)
+	}
+
+	if nil == err {
+		post, err = domain.NewPostBuilder().
+			WithId(uuid.Nil).
+			WithAuthorId(author).
+			WithTitle(row.Title).
+			WithContent(row.Content).
+			WithCommentsAllowed(allowed).
+			WithCreationDate(created).
+			Build()
+	}
+
+	if nil == err {
+		post, err = self.repos.Post.CreatePost(self.ctx, post)
+	}
+
+	if nil == err {
+		self.stats.Posts++
+		self.ids[row.Id] = post.Id
+	}
+
+	return err
+}
+
+func (self *importer) comment(row *Row) error {
+	var comment models.Comment
+	var author uuid.UUID
+	var created time.Time
+	var target uuid.UUID
+	var err error
+	create := self.repos.Comment.CreatePostComment
+
+	if nil == row.ParentId {
+		target, err = self.lookup(row.PostId, "post")
+	} else {
+		target, err = self.lookup(row.ParentId, "parent comment")
+		create = self.repos.Comment.CreateCommentComment
+	}
+
+	if nil == err {
+		author, err = self.lookup(row.AuthorId, "author")
+	}
+
+	if nil == err {
+		created, err = self.date(row)
+	}
+
+	if nil == err {
+		comment, err = domain.NewCommentBuilder().
+			WithId(uuid.Nil).
+			WithAuthorId(author).
+			WithTargetId(target).
+			WithContent(row.Content).
+			WithCreationDate(created).
+			Build()
+	}
+
+	if nil == err {
+		comment, err = create(self.ctx, comment)
+	}
+
+	if nil == err {
+		self.stats.Comments++
+		self.ids[row.Id] = comment.Id
+	}
+
+	return err
+}
+
+// Restores output of Export row by row, content gets new ids and keeps its
+// dates. Stops at the first error, rows imported before it stay in the
+// repository
+func Import(
+	ctx context.Context,
+	repos seed.Repositories,
+	r io.Reader,
+	format Format,
+) (seed.Stats, error) {
+	i := importer{
+		ctx:   ctx,
+		repos: repos,
+		ids:   make(map[uuid.UUID]uuid.UUID),
+	}
+	decoder, err := newDecoder(r, format)
+
+	for line := 1; nil == err; line++ {
+		var row *Row
+		row, err = decoder.read()
+
+		if nil == err {
+			err = ctx.Err()
+		}
+
+		if nil == err {
+			switch row.Kind {
+			case KIND_USER:
+				err = i.user(row)
+			case KIND_POST:
+				err = i.post(row)
+			case KIND_COMMENT:
+				err = i.comment(row)
+			default:
+				err = fmt.Errorf("unknown kind %q", row.Kind)
+			}
+		}
+
+		if nil != err && io.EOF != err {
+			err = fmt.Errorf("row %v: %w", line, err)
+		}
+	}
+
+	if io.EOF == err {
+		err = nil
+	}
+
+	return i.stats, err
+}
+
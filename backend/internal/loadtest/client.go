@@ -0,0 +1,70 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type client struct {
+	http *http.Client
+	url  string
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Any transport, status or GraphQL error is reported as failure
+func (self *client) do(
+	ctx context.Context,
+	query string,
+	variables map[string]any,
+	out any,
+) error {
+	var resp *http.Response
+	var decoded response
+	body, err := json.Marshal(map[string]any{
+		"query":     query,
+		"variables": variables,
+	})
+
+	if nil == err {
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, self.url, bytes.NewReader(body))
+
+		if nil == err {
+			req.Header.Set("Content-Type", "application/json")
+			resp, err = self.http.Do(req)
+		}
+	}
+
+	if nil == err {
+		defer resp.Body.Close()
+
+		if http.StatusOK != resp.StatusCode {
+			io.Copy(io.Discard, resp.Body)
+			err = fmt.Errorf("status %v", resp.StatusCode)
+		} else {
+			err = json.NewDecoder(resp.Body).Decode(&decoded)
+		}
+	}
+
+	if nil == err && 0 != len(decoded.Errors) {
+		err = errors.New(decoded.Errors[0].Message)
+	}
+
+	if nil == err && nil != out {
+		err = json.Unmarshal(decoded.Data, out)
+	}
+
+	return err
+}
+
@@ -0,0 +1,373 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Operation string
+
+const (
+	// First page of posts with their authors
+	OP_POSTS Operation = "posts"
+	// Page of the thread with replies to every comment, after a random
+	// cursor seen before
+	OP_COMMENTS Operation = "comments"
+	// New comment in the thread
+	OP_COMMENT_POST Operation = "commentPost"
+)
+
+var OPERATIONS = []Operation{OP_POSTS, OP_COMMENTS, OP_COMMENT_POST}
+
+// Relative weights of operations
+type Mix map[Operation]int
+
+var DEFAULT_MIX = Mix{OP_POSTS: 4, OP_COMMENTS: 5, OP_COMMENT_POST: 1}
+
+// Parses comma separated list of operation=weight, e.g. "posts=1,comments=3"
+func ParseMix(value string) (Mix, error) {
+	out := make(Mix)
+
+	for _, item := range strings.Split(value, ",") {
+		name, weight, found := strings.Cut(strings.TrimSpace(item), "=")
+		op := Operation(name)
+		w, err := strconv.Atoi(weight)
+
+		if !found || nil != err || 0 > w {
+			return nil, fmt.Errorf("malformed mix item %q, expected operation=weight", item)
+		} else if _, dup := out[op]; dup {
+			return nil, fmt.Errorf("operation %q listed twice", name)
+		} else if !isOperation(op) {
+			return nil, fmt.Errorf("unknown operation %q, expected one of: %v", name, OPERATIONS)
+		}
+
+		out[op] = w
+	}
+
+	return out, nil
+}
+
+func isOperation(op Operation) bool {
+	for _, v := range OPERATIONS {
+		if v == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (self Mix) String() string {
+	items := make([]string, 0, len(self))
+
+	for _, op := range OPERATIONS {
+		if w, found := self[op]; found {
+			items = append(items, fmt.Sprintf("%v=%v", op, w))
+		}
+	}
+
+	return strings.Join(items, ",")
+}
+
+func (self Mix) pick(rng *rand.Rand) Operation {
+	total := 0
+
+	for _, w := range self {
+		total += w
+	}
+
+	n := rng.IntN(total)
+
+	for _, op := range OPERATIONS {
+		if n -= self[op]; 0 > n {
+			return op
+		}
+	}
+
+	panic("Unreachable")
+}
+
+type Options struct {
+	Concurrency int
+	// Run stops when either is reached, zero disables the limit
+	Duration time.Duration
+	Requests int
+	Mix      Mix
+	PageSize int
+	// Author of created content
+	User uuid.UUID
+	// Thread under load, a new one is created if nil
+	Post uuid.UUID
+	// Comments added to the thread before run
+	Prepare int
+	Seed    uint64
+}
+
+var DEFAULT_OPTIONS = Options{
+	Concurrency: 8,
+	Duration:    10 * time.Second,
+	Mix:         DEFAULT_MIX,
+	PageSize:    20,
+	Prepare:     1000,
+	Seed:        1,
+}
+
+func (self *Options) validate() error {
+	total := 0
+
+	for _, w := range self.Mix {
+		total += w
+	}
+
+	switch {
+	case 0 >= self.Concurrency:
+		return fmt.Errorf("concurrency must be positive")
+	case 0 >= self.Duration && 0 >= self.Requests:
+		return fmt.Errorf("either duration or number of requests must be set")
+	case 0 == total:
+		return fmt.Errorf("operation mix is empty")
+	case 0 >= self.PageSize:
+		return fmt.Errorf("page size must be positive")
+	case 0 > self.Prepare:
+		return fmt.Errorf("number of prepared comments can't be negative")
+	}
+
+	return nil
+}
+
+const QUERY_POSTS = `
+    query ($limit: Int!) {
+        posts(limit: $limit) { data { id title author { id } } end_id }
+    }
+`
+
+const QUERY_COMMENTS = `
+    query ($post: UUID!, $after: UUID, $limit: Int!) {
+        post(id: $post) {
+            comments(after: $after, limit: $limit) {
+                data { id content author { id } comments(limit: $limit) { data { id } } }
+                end_id
+            }
+        }
+    }
+`
+
+const MUTATION_CREATE_POST = `
+    mutation ($user: UUID!) {
+        createPost(user_id: $user, input: {title: "Load test", content: "Thread under load"}) { id }
+    }
+`
+
+const MUTATION_COMMENT_POST = `
+    mutation ($user: UUID!, $post: UUID!, $content: String!) {
+        commentPost(user_id: $user, post_id: $post, input: {content: $content}) { id }
+    }
+`
+
+type runner struct {
+	client  client
+	options Options
+	// Cursors into the thread
+	mutex    sync.Mutex
+	comments []uuid.UUID
+}
+
+func (self *runner) remember(id uuid.UUID) {
+	self.mutex.Lock()
+	self.comments = append(self.comments, id)
+	self.mutex.Unlock()
+}
+
+// Nil cursor (first page) is picked as often as any other
+func (self *runner) cursor(rng *rand.Rand) *uuid.UUID {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	i := rng.IntN(len(self.comments) + 1)
+
+	if len(self.comments) == i {
+		return nil
+	}
+
+	return &self.comments[i]
+}
+
+func (self *runner) commentPost(ctx context.Context, n int) error {
+	var out struct {
+		CommentPost struct{ Id uuid.UUID } `json:"commentPost"`
+	}
+	err := self.client.do(ctx, MUTATION_COMMENT_POST, map[string]any{
+		"user":    self.options.User,
+		"post":    self.options.Post,
+		"content": fmt.Sprintf("Load test comment %v", n),
+	}, &out)
+
+	if nil == err {
+		self.remember(out.CommentPost.Id)
+	}
+
+	return err
+}
+
+func (self *runner) execute(ctx context.Context, op Operation, rng *rand.Rand, n int) error {
+	switch op {
+	case OP_POSTS:
+		return self.client.do(ctx, QUERY_POSTS, map[string]any{
+			"limit": self.options.PageSize,
+		}, nil)
+	case OP_COMMENTS:
+		return self.client.do(ctx, QUERY_COMMENTS, map[string]any{
+			"post":  self.options.Post,
+			"after": self.cursor(rng),
+			"limit": self.options.PageSize,
+		}, nil)
+	case OP_COMMENT_POST:
+		return self.commentPost(ctx, n)
+	default:
+		panic("Unknown operation")
+	}
+}
+
+// Creates the thread if needed and fills it in parallel
+func (self *runner) prepare(ctx context.Context) error {
+	var err error
+
+	if uuid.Nil == self.options.Post {
+		var out struct {
+			CreatePost struct{ Id uuid.UUID } `json:"createPost"`
+		}
+		err = self.client.do(ctx, MUTATION_CREATE_POST, map[string]any{
+			"user": self.options.User,
+		}, &out)
+		self.options.Post = out.CreatePost.Id
+	}
+
+	if nil != err {
+		return fmt.Errorf("create thread: %w", err)
+	}
+
+	jobs := make(chan int)
+	errs := make(chan error, self.options.Concurrency)
+	wg := sync.WaitGroup{}
+
+	for range self.options.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for n := range jobs {
+				if err := self.commentPost(ctx, n); nil != err {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	for n := 0; nil == err && self.options.Prepare > n; n++ {
+		select {
+		case jobs <- n:
+		case err = <-errs:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if nil == err && 0 != len(errs) {
+		err = <-errs
+	}
+
+	if nil != err {
+		err = fmt.Errorf("prepare thread: %w", err)
+	}
+
+	return err
+}
+
+func (self *runner) run(ctx context.Context) Report {
+	var issued sync.WaitGroup
+	reports := make([]Report, self.options.Concurrency)
+	requests := make(chan int)
+	start := time.Now()
+
+	if 0 < self.options.Duration {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, self.options.Duration)
+		defer cancel()
+	}
+
+	for i := range reports {
+		reports[i] = newReport()
+		issued.Add(1)
+		go func(report *Report, rng *rand.Rand) {
+			defer issued.Done()
+
+			for n := range requests {
+				op := self.options.Mix.pick(rng)
+				started := time.Now()
+				err := self.execute(ctx, op, rng, n)
+
+				// Requests cut by the end of the run aren't counted
+				if nil == ctx.Err() {
+					report.Operations[op].record(time.Since(started), err)
+				}
+			}
+		}(&reports[i], rand.New(rand.NewPCG(self.options.Seed, uint64(i))))
+	}
+
+	for n := 0; 0 >= self.options.Requests || self.options.Requests > n; n++ {
+		select {
+		case requests <- self.options.Prepare + n:
+			continue
+		case <-ctx.Done():
+		}
+
+		break
+	}
+
+	close(requests)
+	issued.Wait()
+
+	out := newReport()
+	out.Elapsed = time.Since(start)
+
+	for i := range reports {
+		out.merge(&reports[i])
+	}
+
+	out.sort()
+
+	return out
+}
+
+// Prepares the thread and runs operations against the GraphQL endpoint
+// (e.g. http://127.0.0.1/query) until duration or number of requests is
+// reached. Returns error only if the run couldn't start
+func Run(ctx context.Context, http *http.Client, url string, options Options) (Report, error) {
+	if err := options.validate(); nil != err {
+		return Report{}, err
+	}
+
+	r := runner{
+		client:   client{http, url},
+		options:  options,
+		comments: make([]uuid.UUID, 0, options.Prepare),
+	}
+
+	if err := r.prepare(ctx); nil != err {
+		return Report{}, err
+	}
+
+	return r.run(ctx), nil
+}
+
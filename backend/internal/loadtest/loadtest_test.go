@@ -0,0 +1,136 @@
+package loadtest_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/loadtest"
+	"github.com/muji40k/ozontestcomms/test/e2e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMix(t *testing.T) {
+	// Arrange
+	valid := "posts=1, comments=3,commentPost=0"
+	invalid := []string{"posts", "posts=-1", "posts=1,posts=2", "likes=1", ""}
+
+	// Act
+	mix, err := loadtest.ParseMix(valid)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, loadtest.Mix{
+		loadtest.OP_POSTS:        1,
+		loadtest.OP_COMMENTS:     3,
+		loadtest.OP_COMMENT_POST: 0,
+	}, mix)
+	assert.Equal(t, "posts=1,comments=3,commentPost=0", mix.String())
+
+	for _, v := range invalid {
+		_, err := loadtest.ParseMix(v)
+		assert.Error(t, err, v)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	// Arrange
+	stats := loadtest.OperationStats{Count: 10, Errors: 1}
+
+	for i := range 10 {
+		stats.Latencies = append(stats.Latencies, time.Duration(i+1)*time.Millisecond)
+	}
+
+	// Act
+	p50 := stats.Percentile(50)
+	p90 := stats.Percentile(90)
+	p100 := stats.Percentile(100)
+	p0 := stats.Percentile(0)
+	short := loadtest.OperationStats{Latencies: stats.Latencies[:6]}
+	shortP90 := short.Percentile(90)
+
+	// Assert
+	assert.Equal(t, 5*time.Millisecond, p50)
+	assert.Equal(t, 9*time.Millisecond, p90)
+	assert.Equal(t, 10*time.Millisecond, p100)
+	assert.Equal(t, time.Millisecond, p0)
+	assert.Equal(t, 6*time.Millisecond, shortP90, "Rank is rounded up")
+	assert.InDelta(t, 0.1, stats.ErrorRate(), 1e-9)
+}
+
+func TestRunInMemory(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 1)
+	options := loadtest.DEFAULT_OPTIONS
+	options.User = api.Users[0].Id
+	options.Duration = 0
+	options.Requests = 200
+	options.Prepare = 50
+	options.PageSize = 5
+	var out bytes.Buffer
+
+	// Act
+	report, err := loadtest.Run(context.Background(), http.DefaultClient, api.URL(), options)
+
+	// Assert
+	require.NoError(t, err)
+	total := report.Total()
+	assert.Equal(t, 200, total.Count)
+	assert.Zero(t, total.Errors, "%v", total.Sample)
+
+	for _, op := range loadtest.OPERATIONS {
+		assert.NotZero(t, report.Operations[op].Count, op)
+	}
+
+	comments := api.Must(`
+        query { posts(limit: 1) { data { comments(limit: 1000) { data { id } } } } }
+    `, nil)
+	assert.Equal(t, 50+report.Operations[loadtest.OP_COMMENT_POST].Count,
+		comments.Len("posts.data.0.comments.data"))
+
+	require.NoError(t, report.Write(&out))
+	assert.Contains(t, out.String(), "commentPost")
+}
+
+func TestRunCountsErrors(t *testing.T) {
+	// Arrange
+	api := e2e.NewGraphQL(t, 1)
+	options := loadtest.DEFAULT_OPTIONS
+	options.User = api.Users[0].Id
+	options.Post = uuid.Must(uuid.NewRandom())
+	options.Duration = 0
+	options.Requests = 40
+	options.Prepare = 0
+	options.Mix = loadtest.Mix{loadtest.OP_POSTS: 1, loadtest.OP_COMMENTS: 1}
+
+	// Act
+	report, err := loadtest.Run(context.Background(), http.DefaultClient, api.URL(), options)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Zero(t, report.Operations[loadtest.OP_POSTS].Errors)
+	comments := report.Operations[loadtest.OP_COMMENTS]
+	assert.Equal(t, comments.Count, comments.Errors)
+	assert.Error(t, comments.Sample)
+}
+
+func TestRunFailsToPrepare(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	options := loadtest.DEFAULT_OPTIONS
+
+	// Act
+	_, err := loadtest.Run(context.Background(), server.Client(), server.URL, options)
+
+	// Assert
+	assert.ErrorContains(t, err, "create thread")
+}
+
@@ -0,0 +1,136 @@
+package loadtest
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"text/tabwriter"
+	"time"
+)
+
+type OperationStats struct {
+	Count     int
+	Errors    int
+	Latencies []time.Duration
+	// First error met, for diagnostics
+	Sample error
+}
+
+func (self *OperationStats) record(latency time.Duration, err error) {
+	self.Count++
+	self.Latencies = append(self.Latencies, latency)
+
+	if nil != err {
+		self.Errors++
+
+		if nil == self.Sample {
+			self.Sample = err
+		}
+	}
+}
+
+func (self *OperationStats) merge(other *OperationStats) {
+	self.Count += other.Count
+	self.Errors += other.Errors
+	self.Latencies = append(self.Latencies, other.Latencies...)
+
+	if nil == self.Sample {
+		self.Sample = other.Sample
+	}
+}
+
+func (self *OperationStats) ErrorRate() float64 {
+	if 0 == self.Count {
+		return 0
+	}
+
+	return float64(self.Errors) / float64(self.Count)
+}
+
+// Nearest-rank percentile, p is in [0, 100]. Latencies must be sorted
+func (self *OperationStats) Percentile(p float64) time.Duration {
+	if 0 == len(self.Latencies) {
+		return 0
+	}
+
+	// Multiplied first, so that exact ranks aren't rounded up
+	i := int(math.Ceil(p*float64(len(self.Latencies))/100)) - 1
+
+	return self.Latencies[min(max(i, 0), len(self.Latencies)-1)]
+}
+
+type Report struct {
+	Elapsed    time.Duration
+	Operations map[Operation]*OperationStats
+}
+
+func newReport() Report {
+	out := Report{Operations: make(map[Operation]*OperationStats)}
+
+	for _, op := range OPERATIONS {
+		out.Operations[op] = new(OperationStats)
+	}
+
+	return out
+}
+
+func (self *Report) merge(other *Report) {
+	for op, stats := range other.Operations {
+		self.Operations[op].merge(stats)
+	}
+}
+
+func (self *Report) sort() {
+	for _, stats := range self.Operations {
+		slices.Sort(stats.Latencies)
+	}
+}
+
+func (self *Report) Total() OperationStats {
+	var out OperationStats
+
+	for _, op := range OPERATIONS {
+		out.merge(self.Operations[op])
+	}
+
+	slices.Sort(out.Latencies)
+
+	return out
+}
+
+func (self *Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "operation\tcount\trps\terrors\tp50\tp90\tp99\tmax\t\n")
+	line := func(name string, stats *OperationStats) {
+		fmt.Fprintf(tw, "%v\t%v\t%.1f\t%.2f%%\t%v\t%v\t%v\t%v\t\n",
+			name,
+			stats.Count,
+			float64(stats.Count)/self.Elapsed.Seconds(),
+			100*stats.ErrorRate(),
+			stats.Percentile(50).Round(time.Microsecond),
+			stats.Percentile(90).Round(time.Microsecond),
+			stats.Percentile(99).Round(time.Microsecond),
+			stats.Percentile(100).Round(time.Microsecond),
+		)
+	}
+
+	for _, op := range OPERATIONS {
+		if stats := self.Operations[op]; 0 != stats.Count {
+			line(string(op), stats)
+		}
+	}
+
+	total := self.Total()
+	line("total", &total)
+	err := tw.Flush()
+
+	for _, op := range OPERATIONS {
+		if sample := self.Operations[op].Sample; nil == err && nil != sample {
+			_, err = fmt.Fprintf(w, "%v: first error: %v\n", op, sample)
+		}
+	}
+
+	return err
+}
+
@@ -0,0 +1,134 @@
+package collection_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection/iterator"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	id    uuid.UUID
+	value int
+}
+
+// Collection over a slice honouring After and Limit
+type cursorCollection struct {
+	items []item
+	after int
+	limit *uint
+}
+
+func newItems(values ...int) []item {
+	out := make([]item, len(values))
+
+	for i, v := range values {
+		out[i] = item{uuid.Must(uuid.NewRandom()), v}
+	}
+
+	return out
+}
+
+func (self *cursorCollection) After(id uuid.UUID) error {
+	i := slices.IndexFunc(self.items, func(v item) bool { return v.id == id })
+
+	if -1 == i {
+		return repoerrors.NotFound("item")
+	}
+
+	self.after = i + 1
+
+	return nil
+}
+
+func (self *cursorCollection) Limit(n uint) {
+	self.limit = &n
+}
+
+func (self *cursorCollection) Get() (iterator.Iterator[item], error) {
+	items := self.items[self.after:]
+
+	if nil != self.limit {
+		items = items[:min(len(items), int(*self.limit))]
+	}
+
+	return iterator.Slice(items), nil
+}
+
+func values(t *testing.T, col collection.Collection[item]) []int {
+	iter, err := col.Get()
+	require.NoError(t, err)
+
+	return iterator.Collect(iterator.Map(iter, func(v *item) int {
+		return v.value
+	}))
+}
+
+func even(v *item) bool {
+	return 0 == v.value%2
+}
+
+func TestFilterLimitsFilteredValues(t *testing.T) {
+	// Arrange
+	items := newItems(1, 2, 3, 4, 5, 6, 7, 8)
+	col := collection.Filter[item](&cursorCollection{items: items}, even)
+
+	// Act
+	err := col.After(items[1].id)
+	col.Limit(2)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 6}, values(t, col))
+}
+
+func TestFilterForwardsAfterErrors(t *testing.T) {
+	// Arrange
+	col := collection.Filter[item](&cursorCollection{items: newItems(1)}, even)
+
+	// Act
+	err := col.After(uuid.Must(uuid.NewRandom()))
+
+	// Assert
+	assert.ErrorAs(t, err, &repoerrors.ErrorNotFound{})
+}
+
+func TestConcat(t *testing.T) {
+	// Arrange
+	first := newItems(1, 2)
+	second := newItems(3, 4, 5)
+	full := collection.Concat[item](
+		&cursorCollection{items: first},
+		&cursorCollection{items: second},
+	)
+	page := collection.Concat[item](
+		&cursorCollection{items: first},
+		&cursorCollection{items: second},
+	)
+	late := collection.Concat[item](
+		&cursorCollection{items: first},
+		&cursorCollection{items: second},
+	)
+
+	// Act
+	full.Limit(10)
+	pageErr := page.After(first[0].id)
+	page.Limit(3)
+	lateErr := late.After(second[0].id)
+	missingErr := collection.Concat[item](&cursorCollection{items: first}).
+		After(second[0].id)
+
+	// Assert
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, values(t, full))
+	assert.NoError(t, pageErr)
+	assert.Equal(t, []int{2, 3, 4}, values(t, page))
+	assert.NoError(t, lateErr)
+	assert.Equal(t, []int{4, 5}, values(t, late))
+	assert.ErrorAs(t, missingErr, &repoerrors.ErrorNotFound{})
+}
+
@@ -0,0 +1,72 @@
+package collection
+
+import (
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection/iterator"
+)
+
+// Cursor may point into any part, parts before it are skipped. Limit is
+// passed to every part as no part yields more than the whole
+type concatCollection[T any] struct {
+	cols  []Collection[T]
+	start int
+	limit *uint
+}
+
+func Concat[T any](cols ...Collection[T]) Collection[T] {
+	return &concatCollection[T]{cols, 0, nil}
+}
+
+func (self *concatCollection[T]) After(id uuid.UUID) error {
+	var err error
+
+	for i, col := range self.cols {
+		if err = col.After(id); nil == err {
+			self.start = i
+			break
+		}
+	}
+
+	return err
+}
+
+func (self *concatCollection[T]) Limit(n uint) {
+	if nil == self.limit {
+		self.limit = new(uint)
+	}
+
+	*self.limit = n
+
+	for _, col := range self.cols {
+		col.Limit(n)
+	}
+}
+
+func (self *concatCollection[T]) Get() (iterator.Iterator[T], error) {
+	var err error
+	iters := make([]iterator.Iterator[T], 0, len(self.cols)-self.start)
+
+	for _, col := range self.cols[self.start:] {
+		var iter iterator.Iterator[T]
+
+		// Parts got already are released, as nobody else can close them
+		if iter, err = col.Get(); nil != err {
+			for _, iter := range iters {
+				iter.Close()
+			}
+
+			return nil, err
+		}
+
+		iters = append(iters, iter)
+	}
+
+	out := iterator.Chain(iters...)
+
+	if nil != self.limit {
+		out = iterator.Take(out, *self.limit)
+	}
+
+	return out, nil
+}
+
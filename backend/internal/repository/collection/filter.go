@@ -0,0 +1,47 @@
+package collection
+
+import (
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection/iterator"
+)
+
+// Limit applies to the filtered values, so it isn't passed to the
+// underlying collection, which is read until enough values are found
+type filterCollection[T any] struct {
+	col   Collection[T]
+	f     func(*T) bool
+	limit *uint
+}
+
+func Filter[T any](col Collection[T], predicate func(*T) bool) Collection[T] {
+	return &filterCollection[T]{col, predicate, nil}
+}
+
+func (self *filterCollection[T]) After(id uuid.UUID) error {
+	return self.col.After(id)
+}
+
+func (self *filterCollection[T]) Limit(n uint) {
+	if nil == self.limit {
+		self.limit = new(uint)
+	}
+
+	*self.limit = n
+}
+
+func (self *filterCollection[T]) Get() (iterator.Iterator[T], error) {
+	iter, err := self.col.Get()
+
+	if nil != err {
+		return nil, err
+	}
+
+	iter = iterator.Filter(iter, self.f)
+
+	if nil != self.limit {
+		iter = iterator.Take(iter, *self.limit)
+	}
+
+	return iter, nil
+}
+
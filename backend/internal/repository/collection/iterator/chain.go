@@ -0,0 +1,40 @@
+package iterator
+
+import "errors"
+
+type chainIterator[T any] struct {
+	iters []Iterator[T]
+}
+
+// Values of every iterator one after another
+func Chain[T any](iters ...Iterator[T]) Iterator[T] {
+	return &chainIterator[T]{iters}
+}
+
+func (self *chainIterator[T]) Next() (T, bool) {
+	for 0 != len(self.iters) {
+		if v, next := self.iters[0].Next(); next {
+			return v, true
+		}
+
+		self.iters[0].Close()
+		self.iters = self.iters[1:]
+	}
+
+	var empty T
+	return empty, false
+}
+
+// Exhausted iterators are closed on the way, the rest is closed here
+func (self *chainIterator[T]) Close() error {
+	errs := make([]error, len(self.iters))
+
+	for i, iter := range self.iters {
+		errs[i] = iter.Close()
+	}
+
+	self.iters = nil
+
+	return errors.Join(errs...)
+}
+
@@ -0,0 +1,36 @@
+package iterator
+
+type chunkIterator[T any] struct {
+	iter Iterator[T]
+	size uint
+}
+
+// Consecutive slices of size values, the last one may be shorter
+func Chunk[T any](iter Iterator[T], size uint) Iterator[[]T] {
+	if 0 == size {
+		panic("Chunk size must be positive")
+	}
+
+	return &chunkIterator[T]{iter, size}
+}
+
+func (self *chunkIterator[T]) Next() ([]T, bool) {
+	out := make([]T, 0, self.size)
+
+	for v, next := self.iter.Next(); next; v, next = self.iter.Next() {
+		if out = append(out, v); uint(len(out)) == self.size {
+			break
+		}
+	}
+
+	if 0 == len(out) {
+		return nil, false
+	}
+
+	return out, true
+}
+
+func (self *chunkIterator[T]) Close() error {
+	return self.iter.Close()
+}
+
@@ -7,6 +7,10 @@ func (e emptyIterator[T]) Next() (T, bool) {
 	return empty, false
 }
 
+func (e emptyIterator[T]) Close() error {
+	return nil
+}
+
 func EmptyIterator[T any]() Iterator[T] {
 	return emptyIterator[T]{}
 }
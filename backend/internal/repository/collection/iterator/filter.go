@@ -0,0 +1,26 @@
+package iterator
+
+type filterIterator[T any] struct {
+	iter Iterator[T]
+	f    func(*T) bool
+}
+
+// Only values the predicate holds for
+func Filter[T any](iter Iterator[T], predicate func(*T) bool) Iterator[T] {
+	return &filterIterator[T]{iter, predicate}
+}
+
+func (self *filterIterator[T]) Next() (T, bool) {
+	v, next := self.iter.Next()
+
+	for next && !self.f(&v) {
+		v, next = self.iter.Next()
+	}
+
+	return v, next
+}
+
+func (self *filterIterator[T]) Close() error {
+	return self.iter.Close()
+}
+
@@ -1,8 +1,14 @@
 package iterator
 
-import "slices"
+import (
+	"slices"
 
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// Reads the iterator to the end and closes it
 func Count[T any](iter Iterator[T]) uint {
+	defer iter.Close()
 	i := uint(0)
 
 	for range Values(iter) {
@@ -12,7 +18,26 @@ func Count[T any](iter Iterator[T]) uint {
 	return i
 }
 
+// Reads the iterator to the end and closes it
 func Collect[T any](iter Iterator[T]) []T {
+	defer iter.Close()
 	return slices.Collect(Values(iter))
 }
 
+// Values up to the first error, the iterator is closed without reading
+// the rest
+func TryCollect[T any](iter Iterator[result.Result[T]]) ([]T, error) {
+	defer iter.Close()
+	out := make([]T, 0)
+
+	for v, err := range Results(iter) {
+		if nil != err {
+			return nil, err
+		}
+
+		out = append(out, v)
+	}
+
+	return out, nil
+}
+
@@ -2,10 +2,15 @@ package iterator
 
 import "iter"
 
+// Iterators may hold resources (e.g. database rows) until read to the end,
+// so the consumer must Close every iterator it gets. Close may be called
+// any number of times, also after the end
 type Iterator[T any] interface {
 	Next() (T, bool)
+	Close() error
 }
 
+// Doesn't close the iterator
 func Values[T any](iter Iterator[T]) iter.Seq[T] {
 	return func(yield func(T) bool) {
 		for v, next := iter.Next(); next; v, next = iter.Next() {
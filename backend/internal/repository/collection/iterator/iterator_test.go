@@ -0,0 +1,262 @@
+package iterator_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/muji40k/ozontestcomms/internal/repository/collection/iterator"
+	"github.com/muji40k/ozontestcomms/misc/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func numbers(n int) iterator.Iterator[int] {
+	return iterator.RangeIterator(0, n)
+}
+
+// Counts values read from the underlying iterator
+type counting struct {
+	iterator.Iterator[int]
+	read   int
+	closed int
+}
+
+func (self *counting) Close() error {
+	self.closed++
+	return self.Iterator.Close()
+}
+
+func (self *counting) Next() (int, bool) {
+	v, next := self.Iterator.Next()
+
+	if next {
+		self.read++
+	}
+
+	return v, next
+}
+
+func TestFilter(t *testing.T) {
+	// Arrange
+	iter := numbers(10)
+
+	// Act
+	out := iterator.Collect(iterator.Filter(iter, func(v *int) bool {
+		return 0 == *v%3
+	}))
+
+	// Assert
+	assert.Equal(t, []int{0, 3, 6, 9}, out)
+}
+
+func TestTake(t *testing.T) {
+	// Arrange
+	source := &counting{Iterator: numbers(10)}
+
+	// Act
+	out := iterator.Collect(iterator.Take[int](source, 3))
+	short := iterator.Collect(iterator.Take(numbers(2), 5))
+	none := iterator.Collect(iterator.Take(numbers(2), 0))
+
+	// Assert
+	assert.Equal(t, []int{0, 1, 2}, out)
+	assert.Equal(t, 3, source.read, "Values past n aren't read")
+	assert.Equal(t, []int{0, 1}, short)
+	assert.Empty(t, none)
+}
+
+func TestSkip(t *testing.T) {
+	// Arrange
+	iter := numbers(5)
+
+	// Act
+	out := iterator.Collect(iterator.Skip(iter, 3))
+	none := iterator.Collect(iterator.Skip(numbers(2), 5))
+
+	// Assert
+	assert.Equal(t, []int{3, 4}, out)
+	assert.Empty(t, none)
+}
+
+func TestChain(t *testing.T) {
+	// Arrange
+	iters := []iterator.Iterator[int]{
+		numbers(2),
+		iterator.EmptyIterator[int](),
+		iterator.Slice([]int{7, 8}),
+	}
+
+	// Act
+	out := iterator.Collect(iterator.Chain(iters...))
+	empty := iterator.Collect(iterator.Chain[int]())
+
+	// Assert
+	assert.Equal(t, []int{0, 1, 7, 8}, out)
+	assert.Empty(t, empty)
+}
+
+func TestZip(t *testing.T) {
+	// Arrange
+	a := numbers(3)
+	b := iterator.Slice([]string{"a", "b"})
+
+	// Act
+	out := iterator.Collect(iterator.Zip(a, b))
+
+	// Assert
+	assert.Equal(t, []iterator.Pair[int, string]{{0, "a"}, {1, "b"}}, out)
+}
+
+func TestChunk(t *testing.T) {
+	// Arrange
+	iter := numbers(7)
+
+	// Act
+	out := iterator.Collect(iterator.Chunk(iter, 3))
+	empty := iterator.Collect(iterator.Chunk(numbers(0), 3))
+
+	// Assert
+	assert.Equal(t, [][]int{{0, 1, 2}, {3, 4, 5}, {6}}, out)
+	assert.Empty(t, empty)
+	assert.Panics(t, func() { iterator.Chunk(numbers(1), 0) })
+}
+
+func TestTryCollect(t *testing.T) {
+	// Arrange
+	fail := errors.New("fail")
+	source := &counting{Iterator: numbers(5)}
+	values := iterator.Map[int](source, func(v *int) result.Result[int] {
+		if 2 == *v {
+			return result.Err[int](fail)
+		}
+
+		return result.Ok(*v)
+	})
+
+	// Act
+	out, err := iterator.TryCollect(values)
+	ok, okErr := iterator.TryCollect(iterator.Map(numbers(2), func(v *int) result.Result[int] {
+		return result.Ok(*v)
+	}))
+
+	// Assert
+	assert.ErrorIs(t, err, fail)
+	assert.Nil(t, out)
+	assert.Equal(t, 3, source.read, "Values past the error aren't read")
+	assert.NoError(t, okErr)
+	assert.Equal(t, []int{0, 1}, ok)
+}
+
+func TestPeekable(t *testing.T) {
+	// Arrange
+	iter := iterator.NewPeekable(numbers(2))
+
+	// Act
+	first, _ := iter.Peek()
+	again, _ := iter.Peek()
+	next, _ := iter.Next()
+	rest := iterator.Collect[int](iter)
+	_, end := iter.Peek()
+
+	// Assert
+	assert.Equal(t, 0, first)
+	assert.Equal(t, 0, again)
+	assert.Equal(t, 0, next)
+	assert.Equal(t, []int{1}, rest)
+	assert.False(t, end)
+}
+
+func TestSeqConversions(t *testing.T) {
+	// Arrange
+	fail := errors.New("fail")
+	seq2 := func(yield func(string, error) bool) {
+		_ = yield("a", nil) && yield("", fail) && yield("c", nil)
+	}
+
+	// Act
+	values := iterator.Collect(iterator.FromSeq(slices.Values([]int{1, 2})))
+	results := iterator.Collect(iterator.FromSeq2(seq2))
+	var pairs []string
+
+	for v, err := range iterator.Results(iterator.Slice(results)) {
+		if nil == err {
+			pairs = append(pairs, v)
+		} else {
+			pairs = append(pairs, err.Error())
+		}
+	}
+
+	var positions []int
+
+	for i := range iterator.Enumerate(numbers(3)) {
+		positions = append(positions, i)
+	}
+
+	// Assert
+	assert.Equal(t, []int{1, 2}, values)
+	assert.Equal(t, []string{"a", "fail", "c"}, pairs)
+	assert.Equal(t, []int{0, 1, 2}, positions)
+}
+
+func TestCloseIsForwarded(t *testing.T) {
+	// Arrange
+	sources := make([]*counting, 6)
+
+	for i := range sources {
+		sources[i] = &counting{Iterator: numbers(3)}
+	}
+
+	iters := []iterator.Iterator[int]{
+		iterator.Take[int](sources[0], 1),
+		iterator.Filter[int](sources[1], func(*int) bool { return true }),
+		iterator.Chain[int](sources[2], sources[3]),
+	}
+	zip := iterator.Zip[int, int](sources[4], sources[5])
+	stopped := false
+	seq := iterator.FromSeq(func(yield func(int) bool) {
+		defer func() { stopped = true }()
+		_ = yield(1) && yield(2)
+	})
+
+	// Act
+	for _, iter := range iters {
+		iter.Next()
+		assert.NoError(t, iter.Close())
+	}
+
+	zip.Next()
+	zerr := zip.Close()
+	seq.Next()
+	serr := seq.Close()
+
+	// Assert
+	for i, source := range sources {
+		assert.Equal(t, 1, source.closed, "source %v", i)
+	}
+
+	assert.NoError(t, zerr)
+	assert.NoError(t, serr)
+	assert.True(t, stopped, "Sequence is stopped on Close")
+}
+
+func TestConsumersClose(t *testing.T) {
+	// Arrange
+	collected := &counting{Iterator: numbers(3)}
+	counted := &counting{Iterator: numbers(3)}
+	failed := &counting{Iterator: numbers(3)}
+
+	// Act
+	iterator.Collect[int](collected)
+	iterator.Count[int](counted)
+	_, err := iterator.TryCollect(iterator.Map[int](failed, func(*int) result.Result[int] {
+		return result.Err[int](errors.New("fail"))
+	}))
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, 1, collected.closed)
+	assert.Equal(t, 1, counted.closed)
+	assert.Equal(t, 1, failed.closed)
+	assert.Equal(t, 1, failed.read)
+}
+
@@ -20,3 +20,7 @@ func (self *mapIterator[T, F]) Next() (F, bool) {
 	}
 }
 
+func (self *mapIterator[T, F]) Close() error {
+	return self.iter.Close()
+}
+
@@ -0,0 +1,34 @@
+package iterator
+
+// Iterator that can look at the next value without consuming it
+type Peekable[T any] struct {
+	iter   Iterator[T]
+	peeked bool
+	value  T
+	next   bool
+}
+
+func NewPeekable[T any](iter Iterator[T]) *Peekable[T] {
+	return &Peekable[T]{iter: iter}
+}
+
+func (self *Peekable[T]) Peek() (T, bool) {
+	if !self.peeked {
+		self.value, self.next = self.iter.Next()
+		self.peeked = true
+	}
+
+	return self.value, self.next
+}
+
+func (self *Peekable[T]) Next() (T, bool) {
+	v, next := self.Peek()
+	self.peeked = false
+
+	return v, next
+}
+
+func (self *Peekable[T]) Close() error {
+	return self.iter.Close()
+}
+
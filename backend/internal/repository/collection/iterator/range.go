@@ -14,6 +14,11 @@ func (self *rangeIterator) Next() (int, bool) {
 	return out, true
 }
 
+func (self *rangeIterator) Close() error {
+	self.i = self.end
+	return nil
+}
+
 func RangeIterator(start, end int) Iterator[int] {
 	return &rangeIterator{min(start, end), max(start, end)}
 }
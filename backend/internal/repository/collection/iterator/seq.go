@@ -0,0 +1,74 @@
+package iterator
+
+import (
+	"iter"
+
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// Values with their errors, e.g. for `for v, err := range ...`
+func Results[T any](iter Iterator[result.Result[T]]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for v := range Values(iter) {
+			if !yield(v.Unwrap()) {
+				return
+			}
+		}
+	}
+}
+
+// Values with their positions
+func Enumerate[T any](iter Iterator[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+
+		for v := range Values(iter) {
+			if !yield(i, v) {
+				return
+			}
+
+			i++
+		}
+	}
+}
+
+type seqIterator[T any] struct {
+	next func() (T, bool)
+	stop func()
+}
+
+func (self *seqIterator[T]) Next() (T, bool) {
+	v, next := self.next()
+
+	if !next {
+		self.stop()
+	}
+
+	return v, next
+}
+
+func (self *seqIterator[T]) Close() error {
+	self.stop()
+	return nil
+}
+
+// Sequence is pulled on demand and released once exhausted or closed
+func FromSeq[T any](seq iter.Seq[T]) Iterator[T] {
+	next, stop := iter.Pull(seq)
+
+	return &seqIterator[T]{next, stop}
+}
+
+// Same as FromSeq for values paired with errors
+func FromSeq2[T any](seq iter.Seq2[T, error]) Iterator[result.Result[T]] {
+	next, stop := iter.Pull2(seq)
+
+	return &seqIterator[result.Result[T]]{
+		func() (result.Result[T], bool) {
+			v, err, next := next()
+			return result.Result[T]{Value: v, Error: err}, next
+		},
+		stop,
+	}
+}
+
@@ -18,6 +18,11 @@ func (self *sliceIterator[T]) Next() (T, bool) {
 	return v, true
 }
 
+func (self *sliceIterator[T]) Close() error {
+	self.i = len(self.values)
+	return nil
+}
+
 func Slice[T any](values []T) Iterator[T] {
 	return &sliceIterator[T]{values, 0}
 }
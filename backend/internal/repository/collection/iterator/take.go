@@ -0,0 +1,52 @@
+package iterator
+
+type takeIterator[T any] struct {
+	iter Iterator[T]
+	n    uint
+}
+
+// At most n first values, the rest isn't read
+func Take[T any](iter Iterator[T], n uint) Iterator[T] {
+	return &takeIterator[T]{iter, n}
+}
+
+func (self *takeIterator[T]) Next() (T, bool) {
+	if 0 == self.n {
+		var empty T
+		return empty, false
+	}
+
+	self.n--
+
+	return self.iter.Next()
+}
+
+func (self *takeIterator[T]) Close() error {
+	return self.iter.Close()
+}
+
+type skipIterator[T any] struct {
+	iter Iterator[T]
+	n    uint
+}
+
+// Values after n first ones, skipped on the first call
+func Skip[T any](iter Iterator[T], n uint) Iterator[T] {
+	return &skipIterator[T]{iter, n}
+}
+
+func (self *skipIterator[T]) Next() (T, bool) {
+	for ; 0 != self.n; self.n-- {
+		if _, next := self.iter.Next(); !next {
+			self.n = 0
+			break
+		}
+	}
+
+	return self.iter.Next()
+}
+
+func (self *skipIterator[T]) Close() error {
+	return self.iter.Close()
+}
+
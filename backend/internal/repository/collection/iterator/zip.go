@@ -0,0 +1,34 @@
+package iterator
+
+import "errors"
+
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+type zipIterator[A any, B any] struct {
+	a Iterator[A]
+	b Iterator[B]
+}
+
+// Pairs of values at the same positions, ends with the shorter iterator
+func Zip[A any, B any](a Iterator[A], b Iterator[B]) Iterator[Pair[A, B]] {
+	return &zipIterator[A, B]{a, b}
+}
+
+func (self *zipIterator[A, B]) Next() (Pair[A, B], bool) {
+	var out Pair[A, B]
+	var next bool
+
+	if out.First, next = self.a.Next(); next {
+		out.Second, next = self.b.Next()
+	}
+
+	return out, next
+}
+
+func (self *zipIterator[A, B]) Close() error {
+	return errors.Join(self.a.Close(), self.b.Close())
+}
+
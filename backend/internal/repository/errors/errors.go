@@ -4,14 +4,23 @@ import "fmt"
 
 // Any other error should be treated as repository internal
 type ErrorNotFound struct{ What []string }
+type ErrorDuplicate struct{ What []string }
 
 // Creators
 func NotFound(what ...string) ErrorNotFound {
 	return ErrorNotFound{what}
 }
 
+func Duplicate(what ...string) ErrorDuplicate {
+	return ErrorDuplicate{what}
+}
+
 // Error implementation
 func (e ErrorNotFound) Error() string {
 	return fmt.Sprintf("Unable to find: %v", e.What)
 }
 
+func (e ErrorDuplicate) Error() string {
+	return fmt.Sprintf("Already exists: %v", e.What)
+}
+
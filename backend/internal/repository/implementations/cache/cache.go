@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	commrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	healthrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/health"
+	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	usrrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/user"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// Read-through cache of entities by id in front of other repositories. Only
+// writes made through it invalidate the entries, writes of other processes
+// are seen once the entries expire
+type Repository struct {
+	post     postrepo.Repository
+	user     usrrepo.Repository
+	comment  commrepo.Repository
+	posts    *entityCache[models.Post]
+	users    *entityCache[models.User]
+	comments *entityCache[models.Comment]
+}
+
+func New(
+	post postrepo.Repository,
+	user usrrepo.Repository,
+	comment commrepo.Repository,
+	size int,
+	ttl time.Duration,
+) *Repository {
+	return &Repository{
+		post,
+		user,
+		comment,
+		newEntityCache("post", size, ttl, func(v *models.Post) uuid.UUID {
+			return v.Id
+		}),
+		newEntityCache("user", size, ttl, func(v *models.User) uuid.UUID {
+			return v.Id
+		}),
+		newEntityCache("comment", size, ttl, func(v *models.Comment) uuid.UUID {
+			return v.Id
+		}),
+	}
+}
+
+func (self *Repository) Ping(ctx context.Context) error {
+	pinged := make(map[healthrepo.Pinger]struct{})
+
+	for _, repo := range []any{self.post, self.user, self.comment} {
+		if pinger, ok := repo.(healthrepo.Pinger); ok {
+			if _, found := pinged[pinger]; !found {
+				pinged[pinger] = struct{}{}
+
+				if err := pinger.Ping(ctx); nil != err {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (self *Repository) CreatePost(
+	ctx context.Context,
+	post models.Post,
+) (models.Post, error) {
+	out, err := self.post.CreatePost(ctx, post)
+
+	if nil == err {
+		self.posts.invalidate(out.Id)
+	}
+
+	return out, err
+}
+
+func (self *Repository) GetPosts(
+	ctx context.Context,
+	order postrepo.PostOrder,
+) (collection.Collection[result.Result[models.Post]], error) {
+	return self.post.GetPosts(ctx, order)
+}
+
+func (self *Repository) GetPostsById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.Post]], error) {
+	return newIdCollection(ctx, self.posts, self.post.GetPostsById, ids), nil
+}
+
+func (self *Repository) UpdatePost(
+	ctx context.Context,
+	post models.Post,
+) (models.Post, error) {
+	out, err := self.post.UpdatePost(ctx, post)
+
+	// Dropped on failure as well, the write may have happened anyway
+	self.posts.invalidate(post.Id)
+
+	return out, err
+}
+
+func (self *Repository) CreateUser(
+	ctx context.Context,
+	user models.User,
+) (models.User, error) {
+	out, err := self.user.CreateUser(ctx, user)
+
+	if nil == err {
+		self.users.invalidate(out.Id)
+	}
+
+	return out, err
+}
+
+func (self *Repository) GetUsersById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.User]], error) {
+	return newIdCollection(ctx, self.users, self.user.GetUsersById, ids), nil
+}
+
+func (self *Repository) GetUsersByEmail(
+	ctx context.Context,
+	emails ...string,
+) (collection.Collection[result.Result[models.User]], error) {
+	return self.user.GetUsersByEmail(ctx, emails...)
+}
+
+// Comments may be written under posts and comments, so the target is dropped
+// from both caches
+func (self *Repository) createComment(
+	ctx context.Context,
+	comment models.Comment,
+	create func(context.Context, models.Comment) (models.Comment, error),
+) (models.Comment, error) {
+	out, err := create(ctx, comment)
+
+	if nil == err {
+		self.comments.invalidate(out.Id, comment.TargetId)
+		self.posts.invalidate(comment.TargetId)
+	}
+
+	return out, err
+}
+
+func (self *Repository) CreatePostComment(
+	ctx context.Context,
+	comment models.Comment,
+) (models.Comment, error) {
+	return self.createComment(ctx, comment, self.comment.CreatePostComment)
+}
+
+func (self *Repository) CreateCommentComment(
+	ctx context.Context,
+	comment models.Comment,
+) (models.Comment, error) {
+	return self.createComment(ctx, comment, self.comment.CreateCommentComment)
+}
+
+func (self *Repository) GetCommentsById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.Comment]], error) {
+	return newIdCollection(ctx, self.comments, self.comment.GetCommentsById, ids), nil
+}
+
+func (self *Repository) GetCommentsByPostId(
+	ctx context.Context,
+	postId uuid.UUID,
+	order commrepo.CommentOrder,
+) (collection.Collection[result.Result[models.Comment]], error) {
+	return self.comment.GetCommentsByPostId(ctx, postId, order)
+}
+
+func (self *Repository) GetCommentsByCommentId(
+	ctx context.Context,
+	commentId uuid.UUID,
+	order commrepo.CommentOrder,
+) (collection.Collection[result.Result[models.Comment]], error) {
+	return self.comment.GetCommentsByCommentId(ctx, commentId, order)
+}
+
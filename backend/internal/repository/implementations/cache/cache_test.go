@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRepository(t *testing.T) (*Repository, models.User) {
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo := inmemory.New(
+		func(adduser func(models.User), _ func(inmemory.Comment), _ func(models.Post)) {
+			adduser(user)
+		},
+	)
+
+	return New(repo, repo, repo, 100, time.Minute), user
+}
+
+func getPost(t *testing.T, repo *Repository, id uuid.UUID) models.Post {
+	values := common.Unwrap(pagination.Collect(
+		common.Unwrap(repo.GetPostsById(context.Background(), id)),
+	))
+	require.Len(t, values, 1)
+
+	return values[0]
+}
+
+func hits(name string) int64 {
+	if v, ok := stats.Get(name + ".hits").(*expvar.Int); ok {
+		return v.Value()
+	}
+
+	return 0
+}
+
+func TestGetPostsByIdIsCached(t *testing.T) {
+	// Arrange
+	repo, user := newRepository(t)
+	post := common.Unwrap(repo.CreatePost(context.Background(), common.Unwrap(
+		domainOM.PostDefault(user.Id, nullable.None[bool](), nullable.None[string](), nil).Build(),
+	)))
+	getPost(t, repo, post.Id)
+	before := hits("post")
+
+	// Act
+	cached := getPost(t, repo, post.Id)
+
+	// Assert
+	assert.Equal(t, post, cached)
+	assert.Equal(t, before+1, hits("post"))
+}
+
+func TestUpdatePostIsReadBack(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo, user := newRepository(t)
+	post := common.Unwrap(repo.CreatePost(ctx, common.Unwrap(
+		domainOM.PostDefault(user.Id, nullable.None[bool](), nullable.None[string](), nil).Build(),
+	)))
+	getPost(t, repo, post.Id)
+	post.Title = "updated"
+
+	// Act
+	updated := common.Unwrap(repo.UpdatePost(ctx, post))
+	read := getPost(t, repo, post.Id)
+
+	// Assert
+	assert.Equal(t, updated, read)
+	assert.Equal(t, "updated", read.Title)
+}
+
+func TestCommentsAreReadBack(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo, user := newRepository(t)
+	post := common.Unwrap(repo.CreatePost(ctx, common.Unwrap(
+		domainOM.PostDefault(user.Id, nullable.None[bool](), nullable.None[string](), nil).Build(),
+	)))
+	comment := common.Unwrap(repo.CreatePostComment(ctx, common.Unwrap(
+		domainOM.CommentDefault(user.Id, post.Id, nullable.None[string](), nil).Build(),
+	)))
+	missing := uuid.New()
+	common.Unwrap(repo.GetCommentsById(ctx, comment.Id, missing))
+
+	// Act
+	reply := common.Unwrap(repo.CreateCommentComment(ctx, common.Unwrap(
+		domainOM.CommentDefault(user.Id, comment.Id, nullable.None[string](), nil).Build(),
+	)))
+	read := common.Unwrap(pagination.Collect(
+		common.Unwrap(repo.GetCommentsById(ctx, comment.Id, reply.Id)),
+	))
+
+	// Assert
+	assert.Equal(t, []models.Comment{comment, reply}, read)
+}
+
+func TestReadStartedBeforeWriteIsNotCached(t *testing.T) {
+	// Arrange
+	id := uuid.New()
+	entities := newEntityCache("test", 10, time.Minute, func(v *models.Post) uuid.UUID {
+		return v.Id
+	})
+	stale := models.Post{Id: id, Title: "stale"}
+
+	// Act
+	version := entities.version()
+	entities.invalidate(id)
+	entities.put(version, stale)
+	_, found := entities.get(id)
+
+	// Assert
+	assert.False(t, found, "Value read before the write is dropped")
+}
+
+func TestConcurrentUpdatesAreReadBack(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo, user := newRepository(t)
+	post := common.Unwrap(repo.CreatePost(ctx, common.Unwrap(
+		domainOM.PostDefault(user.Id, nullable.None[bool](), nullable.None[string](), nil).Build(),
+	)))
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					common.Unwrap(pagination.Collect(
+						common.Unwrap(repo.GetPostsById(ctx, post.Id)),
+					))
+				}
+			}
+		}()
+	}
+
+	// Act
+	for i := range 100 {
+		post.Content = uuid.NewString()
+		common.Unwrap(repo.UpdatePost(ctx, post))
+
+		// Assert
+		assert.Equal(t, post.Content, getPost(t, repo, post.Id).Content, "Update %v", i)
+	}
+
+	close(done)
+	wg.Wait()
+}
+
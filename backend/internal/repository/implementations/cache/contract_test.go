@@ -0,0 +1,34 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/cache"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	"github.com/muji40k/ozontestcomms/test/common"
+	"github.com/muji40k/ozontestcomms/test/contract"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	contract.RunRepository(t, func(t *testing.T) contract.Subject {
+		author := common.Unwrap(domainOM.UserRandom().Build())
+		repo := inmemory.New(
+			func(adduser func(models.User), _ func(inmemory.Comment), _ func(models.Post)) {
+				adduser(author)
+			},
+		).WithOutbox()
+		cached := cache.New(repo, repo, repo, 100, time.Minute)
+
+		return contract.Subject{
+			Comment: cached,
+			Post:    cached,
+			User:    cached,
+			Author:  author,
+			Outbox:  repo,
+		}
+	})
+}
+
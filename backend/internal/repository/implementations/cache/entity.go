@@ -0,0 +1,228 @@
+package cache
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection/iterator"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// expvar variables
+var stats = expvar.NewMap("cache")
+
+type loader[T any] func(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[T]], error)
+
+// Entities of a kind by id. Reads made before a write may finish after it,
+// so values read are put only if nothing was written since the read
+// started, otherwise they could replace the fresh value until they expire
+type entityCache[T any] struct {
+	name       string
+	values     *expirable.LRU[uuid.UUID, T]
+	id         func(*T) uuid.UUID
+	mutex      sync.Mutex
+	generation uint64
+}
+
+func newEntityCache[T any](
+	name string,
+	size int,
+	ttl time.Duration,
+	id func(*T) uuid.UUID,
+) *entityCache[T] {
+	return &entityCache[T]{
+		name:   name,
+		values: expirable.NewLRU[uuid.UUID, T](size, nil, ttl),
+		id:     id,
+	}
+}
+
+func (self *entityCache[T]) get(id uuid.UUID) (T, bool) {
+	v, found := self.values.Get(id)
+
+	if found {
+		stats.Add(self.name+".hits", 1)
+	} else {
+		stats.Add(self.name+".misses", 1)
+	}
+
+	return v, found
+}
+
+func (self *entityCache[T]) version() uint64 {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return self.generation
+}
+
+func (self *entityCache[T]) put(version uint64, values ...T) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if version == self.generation {
+		for i := range values {
+			self.values.Add(self.id(&values[i]), values[i])
+		}
+	}
+}
+
+func (self *entityCache[T]) invalidate(ids ...uuid.UUID) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.generation++
+
+	for _, id := range ids {
+		self.values.Remove(id)
+	}
+}
+
+// Values of the ids in the order given, missing ones are read through with
+// a single call and cached. Results of the underlying collection are kept
+// as they are, so unknown ids fail the same way
+func (self *entityCache[T]) lookup(
+	ctx context.Context,
+	load loader[T],
+	ids []uuid.UUID,
+) ([]result.Result[T], error) {
+	out := make([]result.Result[T], len(ids))
+	missing := make([]int, 0)
+
+	for i, id := range ids {
+		if v, found := self.get(id); found {
+			out[i] = result.Ok(v)
+		} else {
+			missing = append(missing, i)
+		}
+	}
+
+	if 0 == len(missing) {
+		return out, nil
+	}
+
+	version := self.version()
+	loaded, err := self.load(ctx, load, missing, ids)
+
+	if nil == err {
+		read := make([]T, 0, len(loaded))
+
+		for i, v := range loaded {
+			out[missing[i]] = v
+
+			if value, err := v.Unwrap(); nil == err {
+				read = append(read, value)
+			}
+		}
+
+		self.put(version, read...)
+	}
+
+	return out, err
+}
+
+func (self *entityCache[T]) load(
+	ctx context.Context,
+	load loader[T],
+	missing []int,
+	ids []uuid.UUID,
+) ([]result.Result[T], error) {
+	var iter iterator.Iterator[result.Result[T]]
+	requested := make([]uuid.UUID, len(missing))
+
+	for i, j := range missing {
+		requested[i] = ids[j]
+	}
+
+	col, err := load(ctx, requested...)
+
+	if nil == err {
+		iter, err = col.Get()
+	}
+
+	if nil != err {
+		return nil, err
+	}
+
+	out := iterator.Collect(iter)
+
+	if len(out) != len(requested) {
+		return nil, fmt.Errorf("%v: %v values read for %v ids", self.name, len(out), len(requested))
+	}
+
+	return out, nil
+}
+
+// Values by ids, looked up on Get
+type idCollection[T any] struct {
+	ctx   context.Context
+	cache *entityCache[T]
+	load  loader[T]
+	ids   []uuid.UUID
+	after *uuid.UUID
+	limit *uint
+}
+
+func newIdCollection[T any](
+	ctx context.Context,
+	cache *entityCache[T],
+	load loader[T],
+	ids []uuid.UUID,
+) collection.Collection[result.Result[T]] {
+	return &idCollection[T]{ctx: ctx, cache: cache, load: load, ids: ids}
+}
+
+func (self *idCollection[T]) After(id uuid.UUID) error {
+	if !slices.Contains(self.ids, id) {
+		return repoerrors.NotFound(fmt.Sprintf("%v with id: %v", self.cache.name, id))
+	}
+
+	if nil == self.after {
+		self.after = new(uuid.UUID)
+	}
+
+	*self.after = id
+
+	return nil
+}
+
+func (self *idCollection[T]) Limit(n uint) {
+	if nil == self.limit {
+		self.limit = new(uint)
+	}
+
+	*self.limit = n
+}
+
+func (self *idCollection[T]) Get() (iterator.Iterator[result.Result[T]], error) {
+	s := 0
+	e := len(self.ids)
+
+	if nil != self.after {
+		s = slices.Index(self.ids, *self.after) + 1
+	}
+
+	if nil != self.limit {
+		e = min(e, s+int(*self.limit))
+	}
+
+	out, err := self.cache.lookup(self.ctx, self.load, self.ids[s:e])
+
+	if nil != err {
+		return nil, err
+	}
+
+	return iterator.Slice(out), nil
+}
+
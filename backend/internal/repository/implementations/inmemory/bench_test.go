@@ -0,0 +1,182 @@
+package inmemory_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/require"
+)
+
+var SIZES = []int{1_000, 10_000, 100_000}
+
+const PAGE = 20
+
+type dataset struct {
+	repo   *inmemory.Repository
+	thread models.Post
+	// Comments of the thread in creation order
+	comments []models.Comment
+}
+
+// Thread with n comments among n posts with a comment each, dates are
+// shuffled so that insertion isn't always at the end
+func newDataset(b *testing.B, n int) *dataset {
+	ctx := context.Background()
+	rng := rand.New(rand.NewPCG(uint64(n), 0))
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	date := func() *nullable.Nullable[time.Time] {
+		return nullable.Some(start.Add(time.Duration(rng.Int64N(int64(365 * 24 * time.Hour)))))
+	}
+	author := common.Unwrap(domainOM.UserRandom().Build())
+	out := &dataset{repo: inmemory.New(
+		func(adduser func(models.User), _ func(inmemory.Comment), _ func(models.Post)) {
+			adduser(author)
+		},
+	)}
+	thread, err := out.repo.CreatePost(ctx, common.Unwrap(
+		domainOM.PostDefault(author.Id, nil, nil, date()).Build(),
+	))
+	require.NoError(b, err)
+	out.thread = thread
+
+	for range n {
+		p, err := out.repo.CreatePost(ctx, common.Unwrap(
+			domainOM.PostDefault(author.Id, nil, nil, date()).Build(),
+		))
+		require.NoError(b, err)
+
+		for _, target := range []uuid.UUID{thread.Id, p.Id} {
+			_, err = out.repo.CreatePostComment(ctx, common.Unwrap(
+				domainOM.CommentDefault(author.Id, target, nil, date()).Build(),
+			))
+			require.NoError(b, err)
+		}
+	}
+
+	col, err := out.repo.GetCommentsByPostId(ctx, thread.Id, comment.COMMENT_ORDER_DATE_ASC)
+	require.NoError(b, err)
+	out.comments, err = pagination.Collect(col)
+	require.NoError(b, err)
+	require.Len(b, out.comments, n)
+
+	return out
+}
+
+func BenchmarkGetCommentsByPostId(b *testing.B) {
+	ctx := context.Background()
+
+	for _, n := range SIZES {
+		data := newDataset(b, n)
+		cursor := data.comments[n/2].Id
+
+		for _, after := range []*uuid.UUID{nil, &cursor} {
+			name := fmt.Sprintf("comments=%v/first", n)
+
+			if nil != after {
+				name = fmt.Sprintf("comments=%v/middle", n)
+			}
+
+			b.Run(name, func(b *testing.B) {
+				for b.Loop() {
+					col, err := data.repo.GetCommentsByPostId(ctx, data.thread.Id, comment.COMMENT_ORDER_DATE_DESC)
+
+					if nil == err {
+						err = pagination.Apply(col, after, PAGE)
+					}
+
+					if nil == err {
+						_, err = pagination.Collect(col)
+					}
+
+					if nil != err {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// Readers share the lock, so throughput should grow with GOMAXPROCS
+func BenchmarkGetCommentsByPostIdParallel(b *testing.B) {
+	ctx := context.Background()
+	data := newDataset(b, SIZES[len(SIZES)-1])
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			col, err := data.repo.GetCommentsByPostId(ctx, data.thread.Id, comment.COMMENT_ORDER_DATE_DESC)
+
+			if nil == err {
+				err = pagination.Apply(col, nil, PAGE)
+			}
+
+			if nil == err {
+				_, err = pagination.Collect(col)
+			}
+
+			if nil != err {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkGetPosts(b *testing.B) {
+	ctx := context.Background()
+
+	for _, n := range SIZES {
+		data := newDataset(b, n)
+
+		b.Run(fmt.Sprintf("posts=%v", n), func(b *testing.B) {
+			for b.Loop() {
+				col, err := data.repo.GetPosts(ctx, post.POST_ORDER_DATE_DESC)
+
+				if nil == err {
+					err = pagination.Apply(col, nil, PAGE)
+				}
+
+				if nil == err {
+					_, err = pagination.Collect(col)
+				}
+
+				if nil != err {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCreatePostComment(b *testing.B) {
+	ctx := context.Background()
+
+	for _, n := range SIZES {
+		data := newDataset(b, n)
+		value := common.Unwrap(domainOM.CommentDefault(
+			data.thread.AuthorId, data.thread.Id, nil, nil,
+		).Build())
+
+		b.Run(fmt.Sprintf("comments=%v", n), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := data.repo.CreatePostComment(ctx, value); nil != err {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
@@ -3,6 +3,7 @@ package inmemory
 import (
 	"fmt"
 	"slices"
+	"sync"
 
 	"github.com/google/uuid"
 	"github.com/muji40k/ozontestcomms/internal/repository/collection"
@@ -18,6 +19,7 @@ func errorNotFound(id uuid.UUID) error {
 }
 
 type peekCollection[T any] struct {
+	lock   sync.Locker
 	order  []uuid.UUID
 	target *map[uuid.UUID]T
 	after  *uuid.UUID
@@ -43,9 +45,12 @@ func (self *peekCollection[T]) Get() (iterator.Iterator[result.Result[T]], error
 	e := len(self.order)
 
 	if nil != self.after {
-		s = slices.Index(self.order, *self.after)
+		s = slices.Index(self.order, *self.after) + 1
 	}
 
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
 	if nil != self.limit {
 		e = min(e, s+int(*self.limit))
 	}
@@ -71,19 +76,31 @@ func (self *peekCollection[T]) Limit(n uint) {
 	*self.limit = n
 }
 
-type filter[T any] func(*T) bool
-type sorter[T any] func(T, T) int
-
-type localCollection[T any] struct {
+// Page of the index, values are copied out by id on Get
+type indexCollection[T any] struct {
+	lock   sync.Locker
 	target *map[uuid.UUID]T
-	filter filter[T]
-	sorter sorter[T]
+	index  *index
+	key    func(*T) entry
+	desc   bool
 	after  *uuid.UUID
 	limit  *uint
 }
 
-func (self *localCollection[T]) After(id uuid.UUID) error {
-	if v, found := (*(self.target))[id]; found && self.filter(&v) {
+func (self *indexCollection[T]) find(id uuid.UUID) (entry, bool) {
+	if v, found := (*self.target)[id]; found {
+		e := self.key(&v)
+		return e, self.index.contains(e)
+	}
+
+	return entry{}, false
+}
+
+func (self *indexCollection[T]) After(id uuid.UUID) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if _, found := self.find(id); found {
 		if nil == self.after {
 			self.after = new(uuid.UUID)
 		}
@@ -96,58 +113,39 @@ func (self *localCollection[T]) After(id uuid.UUID) error {
 	}
 }
 
-type pair[T any] struct {
-	id uuid.UUID
-	v  T
-}
-
-func (self *localCollection[T]) Get() (iterator.Iterator[T], error) {
-	var err error
-	tmp := make([]pair[T], 0)
-
-	for id, v := range *self.target {
-		if self.filter(&v) {
-			tmp = append(tmp, pair[T]{id, v})
-		}
-	}
-
-	if nil != self.sorter {
-		slices.SortStableFunc(tmp, func(a, b pair[T]) int {
-			return self.sorter(a.v, b.v)
-		})
-	}
+func (self *indexCollection[T]) Get() (iterator.Iterator[T], error) {
+	var after *entry
+	limit := -1
 
-	i, j := 0, len(tmp)
+	self.lock.Lock()
+	defer self.lock.Unlock()
 
+	// Entry is looked up again, the index could change since After
 	if nil != self.after {
-		for id := *self.after; len(tmp) > i && tmp[i].id != id; i++ {
-		}
+		e, found := self.find(*self.after)
 
-		if len(tmp) != i {
-			i++
-		} else {
-			err = errorNotFound(*self.after)
+		if !found {
+			return nil, errorNotFound(*self.after)
 		}
-	}
 
-	if nil == err && nil != self.limit {
-		j = min(j, i+int(*self.limit))
+		after = &e
 	}
 
-	if nil != err {
-		return nil, err
-	} else {
-		out := make([]T, j-i)
+	if nil != self.limit {
+		limit = int(*self.limit)
+	}
 
-		for k, v := range tmp[i:j] {
-			out[k] = v.v
-		}
+	ids := self.index.page(after, self.desc, limit)
+	out := make([]T, len(ids))
 
-		return newIterator(out), nil
+	for i, id := range ids {
+		out[i] = (*self.target)[id]
 	}
+
+	return newIterator(out), nil
 }
 
-func (self *localCollection[T]) Limit(n uint) {
+func (self *indexCollection[T]) Limit(n uint) {
 	if nil == self.limit {
 		self.limit = new(uint)
 	}
@@ -172,26 +170,30 @@ func (self *localIterator[T]) Next() (T, bool) {
 	return out, true
 }
 
+func (self *localIterator[T]) Close() error {
+	self.i = len(self.buf)
+	return nil
+}
+
 func newIterator[T any](buf []T) iterator.Iterator[T] {
 	return &localIterator[T]{0, buf}
 }
 
 func newPeekCollection[T any](
+	lock sync.Locker,
 	buf *map[uuid.UUID]T,
 	ids []uuid.UUID,
 ) collection.Collection[result.Result[T]] {
-	return &peekCollection[T]{ids, buf, nil, nil}
+	return &peekCollection[T]{lock, ids, buf, nil, nil}
 }
 
-func newCollection[T any](
+func newIndexCollection[T any](
+	lock sync.Locker,
 	buf *map[uuid.UUID]T,
-	filter filter[T],
-	sorter sorter[T],
+	index *index,
+	key func(*T) entry,
+	desc bool,
 ) collection.Collection[T] {
-	if nil == filter {
-		filter = func(*T) bool { return true }
-	}
-
-	return &localCollection[T]{buf, filter, sorter, nil, nil}
+	return &indexCollection[T]{lock, buf, index, key, desc, nil, nil}
 }
 
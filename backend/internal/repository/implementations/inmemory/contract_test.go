@@ -0,0 +1,35 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	"github.com/muji40k/ozontestcomms/test/common"
+	"github.com/muji40k/ozontestcomms/test/contract"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	contract.RunRepository(t, func(t *testing.T) contract.Subject {
+		author := common.Unwrap(domainOM.UserRandom().Build())
+		repo := inmemory.New(
+			func(adduser func(models.User), _ func(inmemory.Comment), _ func(models.Post)) {
+				adduser(author)
+			},
+		).WithOutbox()
+
+		return contract.Subject{
+			Comment:      repo,
+			Post:         repo,
+			User:         repo,
+			Author:       author,
+			Outbox:       repo,
+			Webhook:      repo,
+			Notification: repo,
+			Follow:       repo,
+			Moderation:   repo,
+		}
+	})
+}
+
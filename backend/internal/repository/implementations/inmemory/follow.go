@@ -0,0 +1,134 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+type followKey struct {
+	kind   models.FollowKind
+	target uuid.UUID
+}
+
+func (self *Repository) applyFollows(record *Record) {
+	if v := record.Follow; nil != v {
+		follows, found := self.follows[v.UserId]
+
+		if !found {
+			follows = make(map[followKey]models.Follow)
+			self.follows[v.UserId] = follows
+		}
+
+		follows[followKey{v.Kind, v.TargetId}] = *v
+	}
+
+	if v := record.Unfollow; nil != v {
+		delete(self.follows[v.UserId], followKey{v.Kind, v.TargetId})
+	}
+}
+
+func (self *Repository) CreateFollow(
+	ctx context.Context,
+	follow models.Follow,
+) (bool, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if _, found := self.follows[follow.UserId][followKey{follow.Kind, follow.TargetId}]; found {
+		return false, nil
+	}
+
+	_, err := find(self.users, follow.UserId, "follower")
+
+	if nil == err {
+		switch follow.Kind {
+		case models.FOLLOW_KIND_AUTHOR:
+			_, err = find(self.users, follow.TargetId, "followed author")
+		case models.FOLLOW_KIND_POST:
+			_, err = find(self.posts, follow.TargetId, "followed post")
+		default:
+			panic("Unknown kind")
+		}
+	}
+
+	if nil == err {
+		err = self.commit(&Record{Follow: &follow})
+	}
+
+	return nil == err, err
+}
+
+func (self *Repository) DeleteFollow(
+	ctx context.Context,
+	userId uuid.UUID,
+	kind models.FollowKind,
+	targetId uuid.UUID,
+) (bool, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	follow, found := self.follows[userId][followKey{kind, targetId}]
+
+	if !found {
+		return false, nil
+	}
+
+	err := self.commit(&Record{Unfollow: &follow})
+
+	return nil == err, err
+}
+
+// Posts newest first, the ones of the kind followed by the user are kept.
+// Follows are taken at the moment, so that collection doesn't depend on
+// later changes
+func (self *Repository) followedPosts(
+	userId uuid.UUID,
+	kind models.FollowKind,
+	target func(*models.Post) uuid.UUID,
+) collection.Collection[result.Result[models.Post]] {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	followed := make(map[uuid.UUID]struct{})
+
+	for key := range self.follows[userId] {
+		if kind == key.kind {
+			followed[key.target] = struct{}{}
+		}
+	}
+
+	return collection.Map(
+		collection.Filter(
+			newIndexCollection(self.mutex.RLocker(), &self.posts, self.postIndex, postKey, true),
+			func(v *models.Post) bool {
+				_, found := followed[target(v)]
+				return found
+			},
+		),
+		func(v *models.Post) result.Result[models.Post] {
+			return result.Ok(*v)
+		},
+	)
+}
+
+func (self *Repository) GetFeed(
+	ctx context.Context,
+	userId uuid.UUID,
+) (collection.Collection[result.Result[models.Post]], error) {
+	return self.followedPosts(userId, models.FOLLOW_KIND_AUTHOR, func(v *models.Post) uuid.UUID {
+		return v.AuthorId
+	}), nil
+}
+
+func (self *Repository) GetWatchedPosts(
+	ctx context.Context,
+	userId uuid.UUID,
+) (collection.Collection[result.Result[models.Post]], error) {
+	return self.followedPosts(userId, models.FOLLOW_KIND_POST, func(v *models.Post) uuid.UUID {
+		return v.Id
+	}), nil
+}
+
@@ -0,0 +1,147 @@
+package inmemory
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entries are kept free of pointers, so that moving them is a plain copy
+type entry struct {
+	date int64
+	id   uuid.UUID
+}
+
+func newEntry(date time.Time, id uuid.UUID) entry {
+	return entry{date.UnixNano(), id}
+}
+
+// By date, ties are broken by id so that the order is total
+func (self entry) compare(other entry) int {
+	if c := cmp.Compare(self.date, other.date); 0 != c {
+		return c
+	}
+
+	return bytes.Compare(self.id[:], other.id[:])
+}
+
+// Blocks are split when they grow twice as large, so that an insertion
+// moves at most that many entries
+const BLOCK_SIZE = 256
+
+// Ids ordered by creation date, so that listing doesn't sort and only
+// touches the requested page. Entries are stored in sorted blocks, every
+// block follows the previous one
+type index struct {
+	blocks [][]entry
+}
+
+// Block and offset of the entry or of the place it should be inserted at
+func (self *index) search(e entry) (int, int, bool) {
+	if 0 == len(self.blocks) {
+		return 0, 0, false
+	}
+
+	b, _ := slices.BinarySearchFunc(self.blocks, e, func(block []entry, e entry) int {
+		return block[len(block)-1].compare(e)
+	})
+	b = min(b, len(self.blocks)-1)
+	i, found := slices.BinarySearchFunc(self.blocks[b], e, entry.compare)
+
+	return b, i, found
+}
+
+func (self *index) contains(e entry) bool {
+	_, _, found := self.search(e)
+	return found
+}
+
+func (self *index) insert(e entry) {
+	if 0 == len(self.blocks) {
+		self.blocks = append(self.blocks, []entry{e})
+		return
+	}
+
+	b, i, found := self.search(e)
+
+	if found {
+		return
+	}
+
+	block := slices.Insert(self.blocks[b], i, e)
+
+	if 2*BLOCK_SIZE <= len(block) {
+		tail := slices.Clone(block[BLOCK_SIZE:])
+		self.blocks[b] = block[:BLOCK_SIZE:BLOCK_SIZE]
+		self.blocks = slices.Insert(self.blocks, b+1, tail)
+	} else {
+		self.blocks[b] = block
+	}
+}
+
+func (self *index) remove(e entry) {
+	b, i, found := self.search(e)
+
+	if !found {
+		return
+	}
+
+	if block := slices.Delete(self.blocks[b], i, i+1); 0 == len(block) {
+		self.blocks = slices.Delete(self.blocks, b, b+1)
+	} else {
+		self.blocks[b] = block
+	}
+}
+
+// Up to limit ids following the entry (or from the start if nil) in the
+// order, reversed if desc. Negative limit means no limit
+func (self *index) page(after *entry, desc bool, limit int) []uuid.UUID {
+	out := make([]uuid.UUID, 0, max(limit, 0))
+	b, i := 0, 0
+
+	if desc {
+		b = len(self.blocks) - 1
+
+		if 0 <= b {
+			i = len(self.blocks[b]) - 1
+		}
+	}
+
+	if nil != after {
+		var found bool
+
+		if b, i, found = self.search(*after); found && desc {
+			i--
+		} else if found {
+			i++
+		}
+	}
+
+	for 0 != limit && 0 <= b && len(self.blocks) > b {
+		block := self.blocks[b]
+
+		if desc {
+			for ; 0 != limit && 0 <= i; i-- {
+				out = append(out, block[i].id)
+				limit--
+			}
+
+			if b--; 0 <= b {
+				i = len(self.blocks[b]) - 1
+			}
+		} else {
+			for ; 0 != limit && len(block) > i; i++ {
+				out = append(out, block[i].id)
+				limit--
+			}
+
+			b, i = b+1, 0
+		}
+	}
+
+	return out
+}
+
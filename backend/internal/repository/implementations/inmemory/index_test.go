@@ -0,0 +1,77 @@
+package inmemory
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ids(entries []entry) []uuid.UUID {
+	out := make([]uuid.UUID, len(entries))
+
+	for i, e := range entries {
+		out[i] = e.id
+	}
+
+	return out
+}
+
+func TestIndexMatchesSortedOrder(t *testing.T) {
+	// Arrange
+	rng := rand.New(rand.NewPCG(1, 2))
+	idx := new(index)
+	var reference []entry
+
+	for range 5 * BLOCK_SIZE {
+		// Few distinct dates, so that ties are ordered by id
+		e := entry{rng.Int64N(100), uuid.Must(uuid.NewRandom())}
+		idx.insert(e)
+		reference = append(reference, e)
+	}
+
+	for _, e := range reference[:BLOCK_SIZE] {
+		idx.remove(e)
+	}
+
+	reference = reference[BLOCK_SIZE:]
+	slices.SortFunc(reference, entry.compare)
+	reversed := slices.Clone(reference)
+	slices.Reverse(reversed)
+
+	// Act
+	asc := idx.page(nil, false, -1)
+	desc := idx.page(nil, true, -1)
+
+	// Assert
+	require.Greater(t, len(idx.blocks), 1)
+	assert.Equal(t, ids(reference), asc)
+	assert.Equal(t, ids(reversed), desc)
+
+	for _, i := range []int{0, BLOCK_SIZE - 1, BLOCK_SIZE, len(reference) - 2, len(reference) - 1} {
+		assert.Equal(t, ids(reference[i+1:min(i+11, len(reference))]),
+			idx.page(&reference[i], false, 10), "asc after %v", i)
+		assert.Equal(t, ids(reversed[i+1:min(i+11, len(reversed))]),
+			idx.page(&reversed[i], true, 10), "desc after %v", i)
+	}
+}
+
+func TestIndexEmpty(t *testing.T) {
+	// Arrange
+	idx := new(index)
+	e := entry{1, uuid.Must(uuid.NewRandom())}
+
+	// Act
+	idx.insert(e)
+	idx.remove(e)
+
+	// Assert
+	assert.False(t, idx.contains(e))
+	assert.Empty(t, idx.page(nil, false, -1))
+	assert.Empty(t, idx.page(nil, true, 10))
+	assert.Empty(t, idx.page(&e, true, 10))
+}
+
@@ -2,10 +2,11 @@ package inmemory
 
 import (
 	"context"
+	"slices"
 	"sync"
-	"time"
 
 	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
 	"github.com/muji40k/ozontestcomms/internal/domain/models"
 	"github.com/muji40k/ozontestcomms/internal/repository/collection"
 	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
@@ -24,39 +25,74 @@ type Repository struct {
 	comments map[uuid.UUID]models.Comment
 	posts    map[uuid.UUID]models.Post
 	targets  map[uuid.UUID]Target
-	mutex    sync.Mutex
+	// Target ids by post and comment ids
+	postTargets    map[uuid.UUID]uuid.UUID
+	commentTargets map[uuid.UUID]uuid.UUID
+	// Comments of every target and all posts in creation order
+	replies   map[uuid.UUID]*index
+	postIndex *index
+	// Pending events in creation order, recorded only with the outbox on
+	events     map[uuid.UUID]models.Event
+	eventIndex *index
+	outbox     bool
+	// Webhooks by owner, their deliveries newest first and pending
+	// deliveries by the next attempt
+	webhooks          map[uuid.UUID]models.Webhook
+	ownerWebhooks     map[uuid.UUID]*index
+	deliveries        map[uuid.UUID]models.WebhookDelivery
+	webhookDeliveries map[uuid.UUID]*index
+	deliveryKeys      map[deliveryKey]uuid.UUID
+	dueIndex          *index
+	// Notifications of every user, a user is notified about a comment once
+	notifications     map[uuid.UUID]models.Notification
+	userNotifications map[uuid.UUID]*index
+	notificationKeys  map[notificationKey]uuid.UUID
+	// Authors and posts followed by every user
+	follows map[uuid.UUID]map[followKey]models.Follow
+	// Unresolved reviews in creation order
+	reviews     map[uuid.UUID]models.Review
+	reviewIndex *index
+	// Writers hold it exclusively, collections hold it shared while the
+	// page is copied out, so iterators never see concurrent changes
+	mutex sync.RWMutex
+	// Called with every change before it's applied, under the mutex
+	journal func(*Record) error
 }
 
-func postOrder(order post.PostOrder) func(*time.Time, *time.Time) int {
+func postDescending(order post.PostOrder) bool {
 	switch order {
 	case post.POST_ORDER_DATE_ASC:
-		return func(a *time.Time, b *time.Time) int {
-			return a.Compare(*b)
-		}
+		return false
 	case post.POST_ORDER_DATE_DESC:
-		return func(a *time.Time, b *time.Time) int {
-			return b.Compare(*a)
-		}
+		return true
 	default:
 		panic("Unknown order")
 	}
 }
 
-func commentOrder(order comment.CommentOrder) func(*time.Time, *time.Time) int {
+func commentDescending(order comment.CommentOrder) bool {
 	switch order {
 	case comment.COMMENT_ORDER_DATE_ASC:
-		return func(a *time.Time, b *time.Time) int {
-			return a.Compare(*b)
-		}
+		return false
 	case comment.COMMENT_ORDER_DATE_DESC:
-		return func(a *time.Time, b *time.Time) int {
-			return b.Compare(*a)
-		}
+		return true
 	default:
 		panic("Unknown order")
 	}
 }
 
+func postKey(v *models.Post) entry {
+	return newEntry(v.CreationDate, v.Id)
+}
+
+func commentKey(v *models.Comment) entry {
+	return newEntry(v.CreationDate, v.Id)
+}
+
+func eventKey(v *models.Event) entry {
+	return newEntry(v.CreationDate, v.Id)
+}
+
 func find[T any](m map[uuid.UUID]T, id uuid.UUID, what string) (T, error) {
 	if v, found := m[id]; found {
 		return v, nil
@@ -85,15 +121,39 @@ type Comment struct {
 }
 
 func New(init func(func(models.User), func(Comment), func(models.Post))) *Repository {
-	users := make(map[uuid.UUID]models.User)
-	comments := make(map[uuid.UUID]models.Comment)
-	posts := make(map[uuid.UUID]models.Post)
-	targets := make(map[uuid.UUID]Target)
+	out := &Repository{
+		users:          make(map[uuid.UUID]models.User),
+		comments:       make(map[uuid.UUID]models.Comment),
+		posts:          make(map[uuid.UUID]models.Post),
+		targets:        make(map[uuid.UUID]Target),
+		postTargets:    make(map[uuid.UUID]uuid.UUID),
+		commentTargets: make(map[uuid.UUID]uuid.UUID),
+		replies:        make(map[uuid.UUID]*index),
+		postIndex:      new(index),
+		events:         make(map[uuid.UUID]models.Event),
+		eventIndex:     new(index),
+
+		webhooks:          make(map[uuid.UUID]models.Webhook),
+		ownerWebhooks:     make(map[uuid.UUID]*index),
+		deliveries:        make(map[uuid.UUID]models.WebhookDelivery),
+		webhookDeliveries: make(map[uuid.UUID]*index),
+		deliveryKeys:      make(map[deliveryKey]uuid.UUID),
+		dueIndex:          new(index),
+
+		notifications:     make(map[uuid.UUID]models.Notification),
+		userNotifications: make(map[uuid.UUID]*index),
+		notificationKeys:  make(map[notificationKey]uuid.UUID),
+
+		follows: make(map[uuid.UUID]map[followKey]models.Follow),
+
+		reviews:     make(map[uuid.UUID]models.Review),
+		reviewIndex: new(index),
+	}
 
 	if nil != init {
 		init(
 			func(v models.User) {
-				users[v.Id] = v
+				out.apply(&Record{User: &v})
 			},
 			func(v Comment) {
 				if v.Target.Comment.Valid && v.Target.Post.Valid ||
@@ -101,40 +161,114 @@ func New(init func(func(models.User), func(Comment), func(models.Post))) *Reposi
 					return
 				}
 
-				id, err := findFreeUUID(targets)
+				parent, err := out.attach(v.Target)
 
-				if nil != err {
-					return
+				if nil == err {
+					v.Value.TargetId = parent
+					_, err = out.attach(Target{Comment: uuid.NullUUID{UUID: v.Value.Id, Valid: true}})
 				}
 
-				v.Value.TargetId = id
-				comments[v.Value.Id] = v.Value
-				targets[id] = v.Target
+				if nil == err {
+					out.apply(&Record{Comment: &v.Value})
+				}
 			},
 			func(v models.Post) {
-				posts[v.Id] = v
+				if _, err := out.attach(Target{Post: uuid.NullUUID{UUID: v.Id, Valid: true}}); nil == err {
+					out.apply(&Record{Post: &v})
+				}
 			},
 		)
 	}
 
-	return &Repository{users, comments, posts, targets, sync.Mutex{}}
+	return out
+}
+
+// Records events with every following change, events restored from a
+// snapshot or log are kept either way
+func (self *Repository) WithOutbox() *Repository {
+	self.outbox = true
+	return self
+}
+
+// Target id of the post or comment, created if there's none yet
+func (self *Repository) attach(target Target) (uuid.UUID, error) {
+	var id uuid.UUID
+	var found bool
+	var err error
+
+	if target.Post.Valid {
+		id, found = self.postTargets[target.Post.UUID]
+	} else {
+		id, found = self.commentTargets[target.Comment.UUID]
+	}
+
+	if !found {
+		id, err = findFreeUUID(self.targets)
+
+		if nil == err {
+			self.apply(&Record{Target: &TargetEntry{id, target}})
+		}
+	}
+
+	return id, err
+}
+
+// Event is attached to the record, so that it's journaled and applied
+// along with the change
+func (self *Repository) record(
+	record *Record,
+	event func() (models.Event, error),
+) error {
+	if !self.outbox {
+		return nil
+	}
+
+	v, err := event()
+
+	if nil == err {
+		record.Event = &v
+	}
+
+	return err
+}
+
+// Post the target belongs to, walking up the comment tree
+func (self *Repository) postOf(targetId uuid.UUID) uuid.UUID {
+	target := self.targets[targetId]
+
+	for !target.Post.Valid {
+		target = self.targets[self.comments[target.Comment.UUID].TargetId]
+	}
+
+	return target.Post.UUID
+}
+
+// Changes are journaled first, so that nothing is applied if it fails
+func (self *Repository) commit(record *Record) error {
+	var err error
+
+	if nil != self.journal {
+		err = self.journal(record)
+	}
+
+	if nil == err {
+		self.apply(record)
+	}
+
+	return err
 }
 
 func (self *Repository) createComment(
 	comment models.Comment,
 	finder func(*uuid.UUID) error,
-	selector func(*Target) uuid.NullUUID,
+	targets map[uuid.UUID]uuid.UUID,
 ) (models.Comment, error) {
-	var locked = false
 	var targetId uuid.UUID
 	var currentId uuid.UUID
 	var id uuid.UUID
 
-	defer func() {
-		if locked {
-			self.mutex.Unlock()
-		}
-	}()
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
 
 	err := finder(&comment.TargetId)
 
@@ -143,23 +277,9 @@ func (self *Repository) createComment(
 	}
 
 	if nil == err {
-		locked = true
-		self.mutex.Lock()
-	}
-
-	if nil == err {
-		found := false
-
-		for id, v := range self.targets {
-			t := selector(&v)
-			if t.Valid && t.UUID == comment.TargetId {
-				targetId = id
-				found = true
-				break
-			}
-		}
-
-		if !found {
+		if id, found := targets[comment.TargetId]; found {
+			targetId = id
+		} else {
 			err = repoerrors.NotFound("target id")
 		}
 	}
@@ -175,12 +295,27 @@ func (self *Repository) createComment(
 	if nil == err {
 		comment.Id = id
 		comment.TargetId = targetId
-		self.comments[id] = comment
-		self.targets[currentId] = Target{
-			Comment: uuid.NullUUID{
-				UUID:  id,
-				Valid: true,
-			},
+		record := Record{
+			Comment: &comment,
+			Target: &TargetEntry{currentId, Target{
+				Comment: uuid.NullUUID{
+					UUID:  id,
+					Valid: true,
+				},
+			}},
+		}
+		err = self.record(&record, func() (models.Event, error) {
+			var parent *uuid.UUID
+
+			if target := self.targets[targetId]; target.Comment.Valid {
+				parent = &target.Comment.UUID
+			}
+
+			return events.CommentCreated(&comment, self.postOf(targetId), parent)
+		})
+
+		if nil == err {
+			err = self.commit(&record)
 		}
 	}
 
@@ -197,9 +332,7 @@ func (self *Repository) CreatePostComment(
 			_, err := find(self.posts, *id, "comments post")
 			return err
 		},
-		func(t *Target) uuid.NullUUID {
-			return t.Post
-		},
+		self.postTargets,
 	)
 }
 
@@ -213,9 +346,7 @@ func (self *Repository) CreateCommentComment(
 			_, err := find(self.comments, *id, "comments root comment")
 			return err
 		},
-		func(t *Target) uuid.NullUUID {
-			return t.Comment
-		},
+		self.commentTargets,
 	)
 }
 
@@ -223,41 +354,43 @@ func (self *Repository) GetCommentsById(
 	ctx context.Context,
 	ids ...uuid.UUID,
 ) (collection.Collection[result.Result[models.Comment]], error) {
-	return newPeekCollection(&self.comments, ids), nil
+	return newPeekCollection(self.mutex.RLocker(), &self.comments, ids), nil
 }
 
-func (self *Repository) GetCommentsByPostId(
-	ctx context.Context,
-	postId uuid.UUID,
+func (self *Repository) replyCollection(
+	targets map[uuid.UUID]uuid.UUID,
+	id uuid.UUID,
+	what string,
 	order comment.CommentOrder,
 ) (collection.Collection[result.Result[models.Comment]], error) {
-	cmp := commentOrder(order)
-	var targetId uuid.UUID
-	found := false
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+	targetId, found := targets[id]
 
-	for id, v := range self.targets {
-		if v.Post.Valid && v.Post.UUID == postId {
-			targetId = id
-			found = true
-			break
-		}
+	if !found {
+		return nil, repoerrors.NotFound(what)
 	}
 
-	if !found {
-		return nil, repoerrors.NotFound("comments post id")
-	} else {
-		return collection.Map(newCollection(
+	return collection.Map(
+		newIndexCollection(
+			self.mutex.RLocker(),
 			&self.comments,
-			func(v *models.Comment) bool {
-				return v.TargetId == targetId
-			},
-			func(a, b models.Comment) int {
-				return cmp(&a.CreationDate, &b.CreationDate)
-			},
-		), func(v *models.Comment) result.Result[models.Comment] {
+			self.replies[targetId],
+			commentKey,
+			commentDescending(order),
+		),
+		func(v *models.Comment) result.Result[models.Comment] {
 			return result.Ok(*v)
-		}), nil
-	}
+		},
+	), nil
+}
+
+func (self *Repository) GetCommentsByPostId(
+	ctx context.Context,
+	postId uuid.UUID,
+	order comment.CommentOrder,
+) (collection.Collection[result.Result[models.Comment]], error) {
+	return self.replyCollection(self.postTargets, postId, "comments post id", order)
 }
 
 func (self *Repository) GetCommentsByCommentId(
@@ -265,56 +398,26 @@ func (self *Repository) GetCommentsByCommentId(
 	commentId uuid.UUID,
 	order comment.CommentOrder,
 ) (collection.Collection[result.Result[models.Comment]], error) {
-	cmp := commentOrder(order)
-	var targetId uuid.UUID
-	found := false
-
-	for id, v := range self.targets {
-		if v.Comment.Valid && v.Comment.UUID == commentId {
-			targetId = id
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		return nil, repoerrors.NotFound("comments root comment id")
-	} else {
-		return collection.Map(newCollection(
-			&self.comments,
-			func(v *models.Comment) bool {
-				return v.TargetId == targetId
-			},
-			func(a, b models.Comment) int {
-				return cmp(&a.CreationDate, &b.CreationDate)
-			},
-		), func(v *models.Comment) result.Result[models.Comment] {
-			return result.Ok(*v)
-		}), nil
-	}
+	return self.replyCollection(
+		self.commentTargets,
+		commentId,
+		"comments root comment id",
+		order,
+	)
 }
 
 func (self *Repository) CreatePost(
 	ctx context.Context,
 	post models.Post,
 ) (models.Post, error) {
-	var locked = false
 	var targetID uuid.UUID
 	var id uuid.UUID
 
-	defer func() {
-		if locked {
-			self.mutex.Unlock()
-		}
-	}()
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
 
 	_, err := find(self.users, post.AuthorId, "post author")
 
-	if nil == err {
-		locked = true
-		self.mutex.Lock()
-	}
-
 	if nil == err {
 		id, err = findFreeUUID(self.posts)
 	}
@@ -325,12 +428,21 @@ func (self *Repository) CreatePost(
 
 	if nil == err {
 		post.Id = id
-		self.posts[id] = post
-		self.targets[targetID] = Target{
-			Post: uuid.NullUUID{
-				UUID:  id,
-				Valid: true,
-			},
+		record := Record{
+			Post: &post,
+			Target: &TargetEntry{targetID, Target{
+				Post: uuid.NullUUID{
+					UUID:  id,
+					Valid: true,
+				},
+			}},
+		}
+		err = self.record(&record, func() (models.Event, error) {
+			return events.PostCreated(&post)
+		})
+
+		if nil == err {
+			err = self.commit(&record)
 		}
 	}
 
@@ -341,12 +453,8 @@ func (self *Repository) GetPosts(
 	ctx context.Context,
 	order post.PostOrder,
 ) (collection.Collection[result.Result[models.Post]], error) {
-	cmp := postOrder(order)
-
 	return collection.Map(
-		newCollection(&self.posts, nil, func(a, b models.Post) int {
-			return cmp(&a.CreationDate, &b.CreationDate)
-		}),
+		newIndexCollection(self.mutex.RLocker(), &self.posts, self.postIndex, postKey, postDescending(order)),
 		func(v *models.Post) result.Result[models.Post] {
 			return result.Ok(*v)
 		},
@@ -357,39 +465,117 @@ func (self *Repository) GetPostsById(
 	ctx context.Context,
 	ids ...uuid.UUID,
 ) (collection.Collection[result.Result[models.Post]], error) {
-	return newPeekCollection(&self.posts, ids), nil
+	return newPeekCollection(self.mutex.RLocker(), &self.posts, ids), nil
 }
 
 func (self *Repository) UpdatePost(
 	ctx context.Context,
 	post models.Post,
 ) (models.Post, error) {
-	var locked = false
-
-	defer func() {
-		if locked {
-			self.mutex.Unlock()
-		}
-	}()
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
 
+	record := Record{Post: &post}
 	_, err := find(self.posts, post.Id, "post")
 
 	if nil == err {
-		locked = true
-		self.mutex.Lock()
+		err = self.record(&record, func() (models.Event, error) {
+			return events.PostUpdated(&post)
+		})
 	}
 
 	if nil == err {
-		self.posts[post.Id] = post
+		err = self.commit(&record)
 	}
 
 	return post, err
 }
 
+func (self *Repository) CreateUser(
+	ctx context.Context,
+	user models.User,
+) (models.User, error) {
+	var err error
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	for _, v := range self.users {
+		if v.Email == user.Email {
+			err = repoerrors.Duplicate("user email")
+			break
+		}
+	}
+
+	if nil == err {
+		user.Id, err = findFreeUUID(self.users)
+	}
+
+	record := Record{User: &user}
+
+	if nil == err {
+		err = self.record(&record, func() (models.Event, error) {
+			return events.UserCreated(&user)
+		})
+	}
+
+	if nil == err {
+		err = self.commit(&record)
+	}
+
+	return user, err
+}
+
 func (self *Repository) GetUsersById(
 	ctx context.Context,
 	ids ...uuid.UUID,
 ) (collection.Collection[result.Result[models.User]], error) {
-	return newPeekCollection(&self.users, ids), nil
+	return newPeekCollection(self.mutex.RLocker(), &self.users, ids), nil
+}
+
+func (self *Repository) GetUsersByEmail(
+	ctx context.Context,
+	emails ...string,
+) (collection.Collection[result.Result[models.User]], error) {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+	ids := make([]uuid.UUID, 0, len(emails))
+
+	for id, v := range self.users {
+		if slices.Contains(emails, v.Email) {
+			ids = append(ids, id)
+		}
+	}
+
+	return newPeekCollection(self.mutex.RLocker(), &self.users, ids), nil
+}
+
+func (self *Repository) GetPendingEvents(
+	ctx context.Context,
+) (collection.Collection[result.Result[models.Event]], error) {
+	return collection.Map(
+		newIndexCollection(self.mutex.RLocker(), &self.events, self.eventIndex, eventKey, false),
+		func(v *models.Event) result.Result[models.Event] {
+			return result.Ok(*v)
+		},
+	), nil
+}
+
+func (self *Repository) AcknowledgeEvents(ctx context.Context, ids ...uuid.UUID) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	known := slices.DeleteFunc(slices.Clone(ids), func(id uuid.UUID) bool {
+		_, found := self.events[id]
+		return !found
+	})
+
+	if 0 == len(known) {
+		return nil
+	}
+
+	return self.commit(&Record{Acknowledged: known})
+}
+
+func (self *Repository) Ping(ctx context.Context) error {
+	return nil
 }
 
@@ -0,0 +1,88 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+func reviewKey(v *models.Review) entry {
+	return newEntry(v.CreationDate, v.Id)
+}
+
+func (self *Repository) applyReviews(record *Record) {
+	if v := record.Review; nil != v {
+		self.reviews[v.Id] = *v
+		self.reviewIndex.insert(reviewKey(v))
+	}
+
+	if nil != record.Resolved {
+		if v, found := self.reviews[*record.Resolved]; found {
+			self.reviewIndex.remove(reviewKey(&v))
+			delete(self.reviews, v.Id)
+		}
+	}
+}
+
+func (self *Repository) CreateReview(
+	ctx context.Context,
+	review models.Review,
+) (models.Review, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	_, err := find(self.users, review.AuthorId, "review author")
+
+	if nil == err {
+		review.Id, err = findFreeUUID(self.reviews)
+	}
+
+	if nil == err {
+		review.Reasons = slices.Clone(review.Reasons)
+		err = self.commit(&Record{Review: &review})
+	}
+
+	return review, err
+}
+
+func (self *Repository) DeleteReview(ctx context.Context, id uuid.UUID) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	_, err := find(self.reviews, id, "review")
+
+	if nil == err {
+		err = self.commit(&Record{Resolved: &id})
+	}
+
+	return err
+}
+
+func (self *Repository) GetReviewsById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.Review]], error) {
+	return newPeekCollection(self.mutex.RLocker(), &self.reviews, ids), nil
+}
+
+func (self *Repository) GetReviews(
+	ctx context.Context,
+) (collection.Collection[result.Result[models.Review]], error) {
+	return collection.Map(
+		newIndexCollection(
+			self.mutex.RLocker(),
+			&self.reviews,
+			self.reviewIndex,
+			reviewKey,
+			false,
+		),
+		func(v *models.Review) result.Result[models.Review] {
+			return result.Ok(*v)
+		},
+	), nil
+}
+
@@ -0,0 +1,170 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/notification"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+type notificationKey struct {
+	user    uuid.UUID
+	comment uuid.UUID
+}
+
+func notificationEntry(v *models.Notification) entry {
+	return newEntry(v.CreationDate, v.Id)
+}
+
+func notificationDescending(order notification.NotificationOrder) bool {
+	switch order {
+	case notification.NOTIFICATION_ORDER_DATE_ASC:
+		return false
+	case notification.NOTIFICATION_ORDER_DATE_DESC:
+		return true
+	default:
+		panic("Unknown order")
+	}
+}
+
+func (self *Repository) applyNotifications(record *Record) {
+	for i := range record.Notifications {
+		v := &record.Notifications[i]
+		self.notifications[v.Id] = *v
+		self.notificationKeys[notificationKey{v.UserId, v.CommentId}] = v.Id
+		indexOf(self.userNotifications, v.UserId).insert(notificationEntry(v))
+	}
+}
+
+func (self *Repository) CreateNotifications(
+	ctx context.Context,
+	notifications ...models.Notification,
+) error {
+	var err error
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	record := Record{Notifications: make([]models.Notification, 0, len(notifications))}
+
+	for i := 0; nil == err && len(notifications) > i; i++ {
+		v := notifications[i]
+		key := notificationKey{v.UserId, v.CommentId}
+		_, found := self.notificationKeys[key]
+
+		if !found {
+			found = slices.ContainsFunc(record.Notifications, func(n models.Notification) bool {
+				return key == notificationKey{n.UserId, n.CommentId}
+			})
+		}
+
+		if found {
+			continue
+		}
+
+		_, err = find(self.users, v.UserId, "notification user")
+
+		if nil == err {
+			v.Id, err = findFreeUUID(self.notifications)
+		}
+
+		if nil == err {
+			record.Notifications = append(record.Notifications, v)
+		}
+	}
+
+	if nil == err && 0 != len(record.Notifications) {
+		err = self.commit(&record)
+	}
+
+	return err
+}
+
+func (self *Repository) MarkNotificationsRead(
+	ctx context.Context,
+	userId uuid.UUID,
+	ids ...uuid.UUID,
+) (int, error) {
+	var err error
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	record := Record{Notifications: make([]models.Notification, 0)}
+
+	if 0 == len(ids) {
+		if index, found := self.userNotifications[userId]; found {
+			ids = index.page(nil, false, -1)
+		}
+	}
+
+	for _, id := range ids {
+		if v, found := self.notifications[id]; found && userId == v.UserId && !v.Read &&
+			!slices.ContainsFunc(record.Notifications, func(n models.Notification) bool {
+				return id == n.Id
+			}) {
+			v.Read = true
+			record.Notifications = append(record.Notifications, v)
+		}
+	}
+
+	if 0 != len(record.Notifications) {
+		err = self.commit(&record)
+	}
+
+	return len(record.Notifications), err
+}
+
+func (self *Repository) GetNotificationsById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.Notification]], error) {
+	return newPeekCollection(self.mutex.RLocker(), &self.notifications, ids), nil
+}
+
+func (self *Repository) GetNotificationsByUserId(
+	ctx context.Context,
+	userId uuid.UUID,
+	unreadOnly bool,
+	order notification.NotificationOrder,
+) (collection.Collection[result.Result[models.Notification]], error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	out := newIndexCollection(
+		self.mutex.RLocker(),
+		&self.notifications,
+		indexOf(self.userNotifications, userId),
+		notificationEntry,
+		notificationDescending(order),
+	)
+
+	if unreadOnly {
+		out = collection.Filter(out, func(v *models.Notification) bool {
+			return !v.Read
+		})
+	}
+
+	return collection.Map(out, func(v *models.Notification) result.Result[models.Notification] {
+		return result.Ok(*v)
+	}), nil
+}
+
+func (self *Repository) CountUnreadNotifications(
+	ctx context.Context,
+	userId uuid.UUID,
+) (int, error) {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+	out := 0
+
+	if index, found := self.userNotifications[userId]; found {
+		for _, id := range index.page(nil, false, -1) {
+			if !self.notifications[id].Read {
+				out++
+			}
+		}
+	}
+
+	return out, nil
+}
+
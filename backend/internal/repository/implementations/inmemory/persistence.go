@@ -0,0 +1,360 @@
+package inmemory
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+)
+
+type Format string
+
+const (
+	FORMAT_JSON Format = "json"
+	FORMAT_GOB  Format = "gob"
+)
+
+var FORMATS = []Format{FORMAT_JSON, FORMAT_GOB}
+
+func encode(w io.Writer, format Format, state *State) error {
+	switch format {
+	case FORMAT_JSON:
+		return json.NewEncoder(w).Encode(state)
+	case FORMAT_GOB:
+		return gob.NewEncoder(w).Encode(state)
+	default:
+		return fmt.Errorf("Unknown snapshot format %q", format)
+	}
+}
+
+func decode(r io.Reader, format Format, state *State) error {
+	switch format {
+	case FORMAT_JSON:
+		return json.NewDecoder(r).Decode(state)
+	case FORMAT_GOB:
+		return gob.NewDecoder(r).Decode(state)
+	default:
+		return fmt.Errorf("Unknown snapshot format %q", format)
+	}
+}
+
+// Written to a temporary file first, so that a crash never leaves a
+// partial snapshot behind
+func writeSnapshot(path string, format Format, state *State) error {
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+
+	if nil == err {
+		w := bufio.NewWriter(file)
+		err = encode(w, format, state)
+
+		if nil == err {
+			err = w.Flush()
+		}
+
+		if nil == err {
+			err = file.Sync()
+		}
+
+		if cerr := file.Close(); nil == err {
+			err = cerr
+		}
+	}
+
+	if nil == err {
+		err = os.Rename(tmp, path)
+	}
+
+	return err
+}
+
+func readSnapshot(path string, format Format) (*State, error) {
+	file, err := os.Open(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if nil != err {
+		return nil, err
+	}
+
+	defer file.Close()
+	state := new(State)
+
+	if err = decode(bufio.NewReader(file), format, state); nil != err {
+		return nil, fmt.Errorf("snapshot %v: %w", path, err)
+	}
+
+	return state, nil
+}
+
+// Write log holds one json encoded record per line. The last line may be
+// torn by a crash, such a line is dropped on replay
+func replayLog(path string, apply func(*Record)) error {
+	content, err := os.ReadFile(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if nil != err {
+		return err
+	}
+
+	for n := 1; nil == err && 0 != len(content); n++ {
+		var line []byte
+		i := bytes.IndexByte(content, '\n')
+
+		if -1 == i {
+			log.Printf("in-memory log %v: dropping incomplete record at line %v", path, n)
+			break
+		}
+
+		line, content = content[:i], content[i+1:]
+		record := new(Record)
+
+		if err = json.Unmarshal(line, record); nil == err {
+			apply(record)
+		} else {
+			err = fmt.Errorf("write log %v, line %v: %w", path, n, err)
+		}
+	}
+
+	return err
+}
+
+type writeLog struct {
+	path string
+	file *os.File
+}
+
+func openLog(path string) (*writeLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+
+	if nil != err {
+		return nil, err
+	}
+
+	return &writeLog{path, file}, nil
+}
+
+func (self *writeLog) append(record *Record) error {
+	line, err := json.Marshal(record)
+
+	if nil == err {
+		_, err = self.file.Write(append(line, '\n'))
+	}
+
+	if nil == err {
+		err = self.file.Sync()
+	}
+
+	return err
+}
+
+// Records of the current log are moved aside, records written from now on
+// go to a new one. Log left aside by a failed snapshot is kept and extended
+func (self *writeLog) rotate() error {
+	err := self.file.Close()
+
+	if nil == err {
+		if _, serr := os.Stat(rotated(self.path)); nil == serr {
+			err = appendFile(rotated(self.path), self.path)
+
+			if nil == err {
+				err = os.Remove(self.path)
+			}
+		} else {
+			err = os.Rename(self.path, rotated(self.path))
+		}
+	}
+
+	if nil == err {
+		self.file, err = os.OpenFile(
+			self.path,
+			os.O_WRONLY|os.O_CREATE|os.O_APPEND,
+			0o600,
+		)
+	}
+
+	return err
+}
+
+func appendFile(dst string, src string) error {
+	content, err := os.ReadFile(src)
+
+	if nil == err {
+		var file *os.File
+		file, err = os.OpenFile(dst, os.O_WRONLY|os.O_APPEND, 0o600)
+
+		if nil == err {
+			_, err = file.Write(content)
+
+			if nil == err {
+				err = file.Sync()
+			}
+
+			if cerr := file.Close(); nil == err {
+				err = cerr
+			}
+		}
+	}
+
+	return err
+}
+
+func (self *writeLog) close() error {
+	return self.file.Close()
+}
+
+func rotated(path string) string {
+	return path + ".1"
+}
+
+type PersistenceOptions struct {
+	// Snapshot file, persistence is disabled if empty
+	Snapshot string
+	Format   Format
+	// Optional write log, changes made since the last snapshot are
+	// recovered from it after a crash
+	Log string
+	// Optional period of snapshots in addition to the one taken on Clear
+	Interval time.Duration
+}
+
+// Keeps the repository content on disk: restores it on open and writes
+// snapshots periodically and when cleared
+type Persister struct {
+	repo     *Repository
+	options  PersistenceOptions
+	log      *writeLog
+	mutex    sync.Mutex
+	stop     chan struct{}
+	stopped  sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// Restores the repository from the snapshot and write log, init is used
+// only if there's no snapshot yet
+func Open(
+	options PersistenceOptions,
+	init func(func(models.User), func(Comment), func(models.Post)),
+) (*Repository, *Persister, error) {
+	var repo *Repository
+	var wlog *writeLog
+	state, err := readSnapshot(options.Snapshot, options.Format)
+
+	if nil == err {
+		if nil == state {
+			// Initial content is written at once, the log doesn't hold it
+			repo = New(init)
+			state = new(State)
+			*state = repo.state()
+			err = writeSnapshot(options.Snapshot, options.Format, state)
+		} else {
+			repo = New(nil)
+			repo.restore(state)
+		}
+	}
+
+	if nil == err && "" != options.Log {
+		err = replayLog(rotated(options.Log), repo.apply)
+
+		if nil == err {
+			err = replayLog(options.Log, repo.apply)
+		}
+
+		if nil == err {
+			wlog, err = openLog(options.Log)
+		}
+	}
+
+	if nil != err {
+		return nil, nil, err
+	}
+
+	out := &Persister{repo: repo, options: options, log: wlog, stop: make(chan struct{})}
+
+	if nil != wlog {
+		repo.journal = wlog.append
+	}
+
+	if 0 < options.Interval {
+		out.stopped.Add(1)
+		go out.run()
+	}
+
+	return repo, out, nil
+}
+
+func (self *Persister) run() {
+	defer self.stopped.Done()
+	ticker := time.NewTicker(self.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-self.stop:
+			return
+		case <-ticker.C:
+			if err := self.Snapshot(); nil != err {
+				log.Printf("in-memory snapshot failed: %v", err)
+			}
+		}
+	}
+}
+
+// Captures the repository and rotates the log at once, so that every
+// record not in the snapshot stays in one of the logs
+func (self *Persister) Snapshot() error {
+	var state State
+	var err error
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.repo.mutex.Lock()
+	state = self.repo.state()
+
+	if nil != self.log {
+		err = self.log.rotate()
+	}
+	self.repo.mutex.Unlock()
+
+	if nil == err {
+		err = writeSnapshot(self.options.Snapshot, self.options.Format, &state)
+	}
+
+	if nil == err && nil != self.log {
+		err = os.Remove(rotated(self.options.Log))
+	}
+
+	return err
+}
+
+// Stops periodic snapshots and takes the final one
+func (self *Persister) Clear() {
+	self.stopOnce.Do(func() {
+		close(self.stop)
+		self.stopped.Wait()
+
+		if err := self.Snapshot(); nil != err {
+			log.Printf("in-memory snapshot failed: %v", err)
+		}
+
+		if nil != self.log {
+			self.repo.mutex.Lock()
+			self.repo.journal = nil
+			self.log.close()
+			self.repo.mutex.Unlock()
+		}
+	})
+}
+
@@ -0,0 +1,351 @@
+package inmemory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Monotonic clock reading and location don't survive encoding
+func now() *nullable.Nullable[time.Time] {
+	return nullable.Some(time.Now().UTC().Round(0))
+}
+
+func options(t *testing.T, format Format, withLog bool) PersistenceOptions {
+	dir := t.TempDir()
+	out := PersistenceOptions{
+		Snapshot: filepath.Join(dir, "snapshot"),
+		Format:   format,
+	}
+
+	if withLog {
+		out.Log = filepath.Join(dir, "log")
+	}
+
+	return out
+}
+
+func withUser(user models.User) func(func(models.User), func(Comment), func(models.Post)) {
+	return func(adduser func(models.User), _ func(Comment), _ func(models.Post)) {
+		adduser(user)
+	}
+}
+
+func open(
+	t *testing.T,
+	options PersistenceOptions,
+	init func(func(models.User), func(Comment), func(models.Post)),
+) (*Repository, *Persister) {
+	repo, persister, err := Open(options, init)
+	require.NoError(t, err)
+
+	return repo, persister
+}
+
+func createPost(t *testing.T, repo *Repository, user models.User) models.Post {
+	return common.Unwrap(repo.CreatePost(context.Background(), common.Unwrap(
+		domainOM.PostDefault(user.Id, nullable.None[bool](), nullable.None[string](), now()).Build(),
+	)))
+}
+
+func posts(t *testing.T, repo *Repository) []models.Post {
+	return common.Unwrap(pagination.Collect(
+		common.Unwrap(repo.GetPosts(context.Background(), post.POST_ORDER_DATE_ASC)),
+	))
+}
+
+func TestPersistenceRoundTrip(t *testing.T) {
+	for _, format := range FORMATS {
+		t.Run(string(format), func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			opts := options(t, format, false)
+			user := common.Unwrap(domainOM.UserRandom().Build())
+			repo, persister := open(t, opts, withUser(user))
+			created := createPost(t, repo, user)
+			comment := common.Unwrap(repo.CreatePostComment(ctx, common.Unwrap(
+				domainOM.CommentDefault(user.Id, created.Id, nullable.None[string](), now()).Build(),
+			)))
+
+			// Act
+			persister.Clear()
+			restored, rpersister := open(t, opts, nil)
+			defer rpersister.Clear()
+			reply, err := restored.CreateCommentComment(ctx, common.Unwrap(
+				domainOM.CommentDefault(user.Id, comment.Id, nullable.None[string](), now()).Build(),
+			))
+
+			// Assert
+			assert.Equal(t, []models.Post{created}, posts(t, restored))
+			assert.Equal(t, user, restored.users[user.Id])
+			assert.Equal(t, comment, restored.comments[comment.Id])
+			assert.NoError(t, err, "Targets are restored as well")
+			assert.Equal(t, reply, restored.comments[reply.Id])
+		})
+	}
+}
+
+func TestPersistenceReplaysLogAfterCrash(t *testing.T) {
+	// Arrange
+	opts := options(t, FORMAT_JSON, true)
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo, persister := open(t, opts, withUser(user))
+	first := createPost(t, repo, user)
+	require.NoError(t, persister.Snapshot())
+	second := createPost(t, repo, user)
+
+	// Act
+	persister.log.close()
+	restored, rpersister := open(t, opts, nil)
+	defer rpersister.Clear()
+
+	// Assert
+	assert.ElementsMatch(t, []models.Post{first, second}, posts(t, restored))
+}
+
+func TestPersistenceRecoversRotatedLog(t *testing.T) {
+	// Arrange
+	opts := options(t, FORMAT_GOB, true)
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo, persister := open(t, opts, withUser(user))
+	first := createPost(t, repo, user)
+
+	// Act
+	require.NoError(t, persister.log.rotate(), "Crash before snapshot is written")
+	second := createPost(t, repo, user)
+	persister.log.close()
+	restored, rpersister := open(t, opts, nil)
+	defer rpersister.Clear()
+
+	// Assert
+	assert.ElementsMatch(t, []models.Post{first, second}, posts(t, restored))
+	assert.Equal(t, user, restored.users[user.Id], "Initial content is in snapshot")
+}
+
+func TestPersistenceDropsTornRecord(t *testing.T) {
+	// Arrange
+	opts := options(t, FORMAT_JSON, true)
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo, persister := open(t, opts, withUser(user))
+	created := createPost(t, repo, user)
+	persister.log.close()
+	file := common.Unwrap(os.OpenFile(opts.Log, os.O_WRONLY|os.O_APPEND, 0))
+	common.Unwrap(file.WriteString(`{"Post":{"Id":`))
+	file.Close()
+
+	// Act
+	restored, rpersister, err := Open(opts, nil)
+
+	// Assert
+	require.NoError(t, err)
+	defer rpersister.Clear()
+	assert.Equal(t, []models.Post{created}, posts(t, restored))
+}
+
+func TestPersistencePeriodicSnapshot(t *testing.T) {
+	// Arrange
+	opts := options(t, FORMAT_JSON, false)
+	opts.Interval = 10 * time.Millisecond
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo, persister := open(t, opts, withUser(user))
+	defer persister.Clear()
+
+	// Act
+	created := createPost(t, repo, user)
+
+	// Assert
+	assert.Eventually(t, func() bool {
+		state, err := readSnapshot(opts.Snapshot, opts.Format)
+		return nil == err && nil != state && 1 == len(state.Posts) &&
+			created == state.Posts[0]
+	}, time.Second, 10*time.Millisecond)
+}
+
+func pendingEvents(t *testing.T, repo *Repository) []uuid.UUID {
+	values := common.Unwrap(pagination.Collect(
+		common.Unwrap(repo.GetPendingEvents(context.Background())),
+	))
+	out := make([]uuid.UUID, len(values))
+
+	for i, v := range values {
+		out[i] = v.Id
+	}
+
+	return out
+}
+
+func TestPersistenceKeepsPendingEvents(t *testing.T) {
+	// Arrange
+	opts := options(t, FORMAT_GOB, true)
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo, persister := open(t, opts, withUser(user))
+	repo.WithOutbox()
+	createPost(t, repo, user)
+	createPost(t, repo, user)
+	snapshotted := pendingEvents(t, repo)
+	require.NoError(t, repo.AcknowledgeEvents(context.Background(), snapshotted[0]))
+	require.NoError(t, persister.Snapshot())
+	createPost(t, repo, user)
+	logged := pendingEvents(t, repo)
+	require.NoError(t, repo.AcknowledgeEvents(context.Background(), logged[0]))
+
+	// Act
+	persister.log.close()
+	restored, rpersister := open(t, opts, nil)
+	defer rpersister.Clear()
+
+	// Assert
+	assert.Equal(t, []uuid.UUID{logged[1]}, pendingEvents(t, restored))
+	assert.Equal(t, repo.events[logged[1]].Payload, restored.events[logged[1]].Payload)
+}
+
+func createWebhook(t *testing.T, repo *Repository, user models.User) models.Webhook {
+	return common.Unwrap(repo.CreateWebhook(context.Background(), models.Webhook{
+		OwnerId:      user.Id,
+		Url:          "http://127.0.0.1/hook",
+		Events:       []string{"post.created"},
+		CreationDate: time.Now().UTC().Round(0),
+	}))
+}
+
+func TestPersistenceKeepsWebhooks(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	opts := options(t, FORMAT_JSON, true)
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo, persister := open(t, opts, withUser(user))
+	kept := createWebhook(t, repo, user)
+	deleted := createWebhook(t, repo, user)
+	require.NoError(t, persister.Snapshot())
+
+	for _, v := range []models.Webhook{kept, deleted} {
+		require.NoError(t, repo.CreateDeliveries(ctx, models.WebhookDelivery{
+			WebhookId:    v.Id,
+			EventId:      uuid.New(),
+			Payload:      []byte(`{}`),
+			NextAttempt:  time.Now().UTC().Round(0),
+			CreationDate: time.Now().UTC().Round(0),
+		}))
+	}
+
+	require.NoError(t, repo.DeleteWebhook(ctx, deleted.Id))
+
+	// Act
+	persister.log.close()
+	restored, rpersister := open(t, opts, nil)
+	defer rpersister.Clear()
+
+	// Assert
+	assert.Equal(t, map[uuid.UUID]models.Webhook{kept.Id: kept}, restored.webhooks)
+	assert.Len(t, restored.deliveries, 1, "Deliveries are removed with the webhook")
+	assert.Equal(t, repo.deliveries, restored.deliveries)
+	assert.Len(t, restored.dueIndex.page(nil, false, -1), 1)
+}
+
+func TestPersistenceKeepsReadNotifications(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	opts := options(t, FORMAT_JSON, true)
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo, persister := open(t, opts, withUser(user))
+	notification := models.Notification{
+		UserId:       user.Id,
+		ActorId:      user.Id,
+		PostId:       uuid.New(),
+		CreationDate: time.Now().UTC().Round(0),
+	}
+
+	for range 2 {
+		notification.CommentId = uuid.New()
+		require.NoError(t, repo.CreateNotifications(ctx, notification))
+	}
+
+	require.NoError(t, persister.Snapshot())
+	common.Unwrap(repo.MarkNotificationsRead(ctx, user.Id))
+
+	// Act
+	persister.log.close()
+	restored, rpersister := open(t, opts, nil)
+	defer rpersister.Clear()
+
+	// Assert
+	assert.Equal(t, repo.notifications, restored.notifications)
+	assert.Equal(t, 0, common.Unwrap(restored.CountUnreadNotifications(ctx, user.Id)))
+	assert.Len(t, restored.notificationKeys, 2)
+}
+
+func TestPersistenceKeepsUnfollows(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	opts := options(t, FORMAT_JSON, true)
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	followed := common.Unwrap(domainOM.UserRandom().Build())
+	repo, persister := open(t, opts, func(adduser func(models.User), _ func(Comment), _ func(models.Post)) {
+		adduser(user)
+		adduser(followed)
+	})
+	follow := models.Follow{
+		UserId:       user.Id,
+		Kind:         models.FOLLOW_KIND_AUTHOR,
+		TargetId:     followed.Id,
+		CreationDate: time.Now().UTC().Round(0),
+	}
+	common.Unwrap(repo.CreateFollow(ctx, follow))
+	require.NoError(t, persister.Snapshot())
+	common.Unwrap(repo.DeleteFollow(ctx, user.Id, follow.Kind, follow.TargetId))
+	follow.UserId, follow.TargetId = followed.Id, user.Id
+	common.Unwrap(repo.CreateFollow(ctx, follow))
+
+	// Act
+	persister.log.close()
+	restored, rpersister := open(t, opts, nil)
+	defer rpersister.Clear()
+
+	// Assert
+	assert.Equal(t, repo.follows, restored.follows)
+	assert.Empty(t, restored.follows[user.Id])
+	assert.Len(t, restored.follows[followed.Id], 1)
+}
+
+func TestPersistenceKeepsResolvedReviews(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	opts := options(t, FORMAT_GOB, true)
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo, persister := open(t, opts, withUser(user))
+	review := models.Review{
+		Kind:         models.REVIEW_KIND_POST,
+		Action:       models.MODERATION_ACTION_HIDE,
+		AuthorId:     user.Id,
+		Title:        "title",
+		Content:      "content",
+		Reasons:      []string{"reason"},
+		CreationDate: time.Now().UTC().Round(0),
+	}
+	resolved := common.Unwrap(repo.CreateReview(ctx, review))
+	require.NoError(t, persister.Snapshot())
+	require.NoError(t, repo.DeleteReview(ctx, resolved.Id))
+	kept := common.Unwrap(repo.CreateReview(ctx, review))
+
+	// Act
+	persister.log.close()
+	restored, rpersister := open(t, opts, nil)
+	defer rpersister.Clear()
+
+	// Assert
+	assert.Equal(t, map[uuid.UUID]models.Review{kept.Id: kept}, restored.reviews)
+	assert.Equal(t, []uuid.UUID{kept.Id}, restored.reviewIndex.page(nil, false, -1))
+}
+
@@ -0,0 +1,201 @@
+package inmemory
+
+import (
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+)
+
+type TargetEntry struct {
+	Id     uuid.UUID
+	Target Target
+}
+
+// Single change of the repository. Applying a record only puts values, so
+// applying it again changes nothing, apart from bringing back an event
+// acknowledged since, which is delivered once more then
+type Record struct {
+	User    *models.User    `json:",omitempty"`
+	Post    *models.Post    `json:",omitempty"`
+	Comment *models.Comment `json:",omitempty"`
+	Target  *TargetEntry    `json:",omitempty"`
+	// Written with the change it describes, removed when acknowledged
+	Event        *models.Event `json:",omitempty"`
+	Acknowledged []uuid.UUID   `json:",omitempty"`
+
+	Webhook        *models.Webhook          `json:",omitempty"`
+	DeletedWebhook *uuid.UUID               `json:",omitempty"`
+	Deliveries     []models.WebhookDelivery `json:",omitempty"`
+
+	Notifications []models.Notification `json:",omitempty"`
+
+	Follow   *models.Follow `json:",omitempty"`
+	Unfollow *models.Follow `json:",omitempty"`
+
+	Review   *models.Review `json:",omitempty"`
+	Resolved *uuid.UUID     `json:",omitempty"`
+}
+
+// Complete content of the repository
+type State struct {
+	Users    []models.User
+	Posts    []models.Post
+	Comments []models.Comment
+	Targets  []TargetEntry
+	// Pending only
+	Events     []models.Event
+	Webhooks   []models.Webhook
+	Deliveries []models.WebhookDelivery
+
+	Notifications []models.Notification
+	Follows       []models.Follow
+	Reviews       []models.Review
+}
+
+func (self *Repository) apply(record *Record) {
+	if nil != record.User {
+		self.users[record.User.Id] = *record.User
+	}
+
+	if nil != record.Post {
+		if v, found := self.posts[record.Post.Id]; found {
+			self.postIndex.remove(postKey(&v))
+		}
+
+		self.posts[record.Post.Id] = *record.Post
+		self.postIndex.insert(postKey(record.Post))
+	}
+
+	if nil != record.Comment {
+		if v, found := self.comments[record.Comment.Id]; found {
+			self.repliesOf(v.TargetId).remove(commentKey(&v))
+		}
+
+		self.comments[record.Comment.Id] = *record.Comment
+		self.repliesOf(record.Comment.TargetId).insert(commentKey(record.Comment))
+	}
+
+	if nil != record.Target {
+		id, target := record.Target.Id, record.Target.Target
+		self.targets[id] = target
+		self.repliesOf(id)
+
+		if target.Post.Valid {
+			self.postTargets[target.Post.UUID] = id
+		}
+
+		if target.Comment.Valid {
+			self.commentTargets[target.Comment.UUID] = id
+		}
+	}
+
+	if nil != record.Event {
+		if _, found := self.events[record.Event.Id]; !found {
+			self.events[record.Event.Id] = *record.Event
+			self.eventIndex.insert(eventKey(record.Event))
+		}
+	}
+
+	for _, id := range record.Acknowledged {
+		if v, found := self.events[id]; found {
+			self.eventIndex.remove(eventKey(&v))
+			delete(self.events, id)
+		}
+	}
+
+	self.applyWebhooks(record)
+	self.applyNotifications(record)
+	self.applyFollows(record)
+	self.applyReviews(record)
+}
+
+// Index of the target, created on first use. Comments may come before
+// their target when restored
+func (self *Repository) repliesOf(targetId uuid.UUID) *index {
+	out, found := self.replies[targetId]
+
+	if !found {
+		out = new(index)
+		self.replies[targetId] = out
+	}
+
+	return out
+}
+
+func values[T any](m map[uuid.UUID]T) []T {
+	out := make([]T, 0, len(m))
+
+	for _, v := range m {
+		out = append(out, v)
+	}
+
+	return out
+}
+
+// Caller must hold the mutex
+func (self *Repository) state() State {
+	targets := make([]TargetEntry, 0, len(self.targets))
+	follows := make([]models.Follow, 0)
+
+	for id, v := range self.targets {
+		targets = append(targets, TargetEntry{id, v})
+	}
+
+	for _, user := range self.follows {
+		for _, v := range user {
+			follows = append(follows, v)
+		}
+	}
+
+	return State{
+		Users:    values(self.users),
+		Posts:    values(self.posts),
+		Comments: values(self.comments),
+		Targets:  targets,
+		Events:   values(self.events),
+
+		Webhooks:   values(self.webhooks),
+		Deliveries: values(self.deliveries),
+
+		Notifications: values(self.notifications),
+		Follows:       follows,
+		Reviews:       values(self.reviews),
+	}
+}
+
+func (self *Repository) restore(state *State) {
+	for i := range state.Users {
+		self.apply(&Record{User: &state.Users[i]})
+	}
+
+	for i := range state.Posts {
+		self.apply(&Record{Post: &state.Posts[i]})
+	}
+
+	for i := range state.Comments {
+		self.apply(&Record{Comment: &state.Comments[i]})
+	}
+
+	for i := range state.Targets {
+		self.apply(&Record{Target: &state.Targets[i]})
+	}
+
+	for i := range state.Events {
+		self.apply(&Record{Event: &state.Events[i]})
+	}
+
+	for i := range state.Webhooks {
+		self.apply(&Record{Webhook: &state.Webhooks[i]})
+	}
+
+	self.apply(&Record{Deliveries: state.Deliveries})
+	self.apply(&Record{Notifications: state.Notifications})
+
+	for i := range state.Follows {
+		self.apply(&Record{Follow: &state.Follows[i]})
+	}
+
+	for i := range state.Reviews {
+		self.apply(&Record{Review: &state.Reviews[i]})
+	}
+}
+
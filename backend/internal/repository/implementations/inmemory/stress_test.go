@@ -0,0 +1,179 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Meant to be run with -race
+const (
+	WRITERS     = 4
+	READERS     = 8
+	OPERATIONS  = 200
+	STRESS_PAGE = 10
+)
+
+func newComment(user models.User, target uuid.UUID) models.Comment {
+	return common.Unwrap(domainOM.CommentDefault(user.Id, target, nil, now()).Build())
+}
+
+func write(t *testing.T, repo *Repository, user models.User, thread models.Post) {
+	ctx := context.Background()
+	var created []models.Comment
+
+	for i := range OPERATIONS {
+		c, err := repo.CreatePostComment(ctx, newComment(user, thread.Id))
+		assert.NoError(t, err)
+		created = append(created, c)
+
+		switch {
+		case 0 == i%5:
+			_, err = repo.CreateCommentComment(ctx, newComment(user, created[i/2].Id))
+		case 0 == i%7:
+			thread.CommentsAllowed = !thread.CommentsAllowed
+			_, err = repo.UpdatePost(ctx, thread)
+		case 0 == i%10:
+			createPost(t, repo, user)
+		}
+
+		assert.NoError(t, err)
+	}
+}
+
+// Pages through the thread while it grows, every page must be ordered and
+// match values peeked by id
+func read(t *testing.T, repo *Repository, thread models.Post) {
+	ctx := context.Background()
+	var after *uuid.UUID
+
+	for range OPERATIONS {
+		var page []models.Comment
+		var peeked []models.Comment
+		col, err := repo.GetCommentsByPostId(ctx, thread.Id, comment.COMMENT_ORDER_DATE_DESC)
+
+		if nil == err {
+			err = pagination.Apply(col, after, STRESS_PAGE)
+		}
+
+		if nil == err {
+			page, err = pagination.Collect(col)
+		}
+
+		if nil == err {
+			ids := make([]uuid.UUID, len(page))
+
+			for i, v := range page {
+				ids[i] = v.Id
+			}
+
+			peeked, err = pagination.Collect(common.Unwrap(repo.GetCommentsById(ctx, ids...)))
+		}
+
+		if nil == err {
+			_, err = pagination.Collect(common.Unwrap(repo.GetPosts(ctx, post.POST_ORDER_DATE_ASC)))
+		}
+
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, page, peeked)
+
+		for i := 1; len(page) > i; i++ {
+			assert.Positive(t, commentKey(&page[i-1]).compare(commentKey(&page[i])))
+		}
+
+		if STRESS_PAGE == len(page) {
+			after = &page[len(page)-1].Id
+		} else {
+			after = nil
+		}
+	}
+}
+
+func stress(t *testing.T, repo *Repository, user models.User, thread models.Post) {
+	wg := sync.WaitGroup{}
+
+	for range WRITERS {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			write(t, repo, user, thread)
+		}()
+	}
+
+	for range READERS {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			read(t, repo, thread)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func countComments(t *testing.T, repo *Repository, thread models.Post) int {
+	col, err := repo.GetCommentsByPostId(context.Background(), thread.Id, comment.COMMENT_ORDER_DATE_ASC)
+	require.NoError(t, err)
+
+	return len(common.Unwrap(pagination.Collect(col)))
+}
+
+func TestConcurrentReadsAndWrites(t *testing.T) {
+	// Arrange
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo := New(withUser(user))
+	thread := createPost(t, repo, user)
+
+	// Act
+	stress(t, repo, user, thread)
+
+	// Assert
+	assert.Equal(t, WRITERS*OPERATIONS, countComments(t, repo, thread))
+}
+
+func TestConcurrentSnapshots(t *testing.T) {
+	// Arrange
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	opts := options(t, FORMAT_GOB, true)
+	repo, persister := open(t, opts, withUser(user))
+	thread := createPost(t, repo, user)
+	done := make(chan struct{})
+	snapshots := sync.WaitGroup{}
+	snapshots.Add(1)
+	go func() {
+		defer snapshots.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				assert.NoError(t, persister.Snapshot())
+			}
+		}
+	}()
+
+	// Act
+	stress(t, repo, user, thread)
+	close(done)
+	snapshots.Wait()
+	persister.Clear()
+	restored, _ := open(t, opts, nil)
+
+	// Assert
+	assert.Equal(t, WRITERS*OPERATIONS, countComments(t, restored, thread))
+}
+
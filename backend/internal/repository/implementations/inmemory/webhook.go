@@ -0,0 +1,285 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+type deliveryKey struct {
+	webhook uuid.UUID
+	event   uuid.UUID
+}
+
+func webhookKey(v *models.Webhook) entry {
+	return newEntry(v.CreationDate, v.Id)
+}
+
+func deliveryEntry(v *models.WebhookDelivery) entry {
+	return newEntry(v.CreationDate, v.Id)
+}
+
+func dueKey(v *models.WebhookDelivery) entry {
+	return newEntry(v.NextAttempt, v.Id)
+}
+
+func indexOf(m map[uuid.UUID]*index, id uuid.UUID) *index {
+	out, found := m[id]
+
+	if !found {
+		out = new(index)
+		m[id] = out
+	}
+
+	return out
+}
+
+func (self *Repository) applyWebhooks(record *Record) {
+	if nil != record.Webhook {
+		self.webhooks[record.Webhook.Id] = *record.Webhook
+		indexOf(self.ownerWebhooks, record.Webhook.OwnerId).insert(webhookKey(record.Webhook))
+	}
+
+	for i := range record.Deliveries {
+		v := &record.Deliveries[i]
+
+		if old, found := self.deliveries[v.Id]; found {
+			self.dueIndex.remove(dueKey(&old))
+		}
+
+		self.deliveries[v.Id] = *v
+		self.deliveryKeys[deliveryKey{v.WebhookId, v.EventId}] = v.Id
+		indexOf(self.webhookDeliveries, v.WebhookId).insert(deliveryEntry(v))
+
+		if models.DELIVERY_STATUS_PENDING == v.Status {
+			self.dueIndex.insert(dueKey(v))
+		}
+	}
+
+	if nil != record.DeletedWebhook {
+		id := *record.DeletedWebhook
+
+		if v, found := self.webhooks[id]; found {
+			indexOf(self.ownerWebhooks, v.OwnerId).remove(webhookKey(&v))
+			delete(self.webhooks, id)
+		}
+
+		if deliveries, found := self.webhookDeliveries[id]; found {
+			for _, did := range deliveries.page(nil, false, -1) {
+				v := self.deliveries[did]
+				self.dueIndex.remove(dueKey(&v))
+				delete(self.deliveryKeys, deliveryKey{v.WebhookId, v.EventId})
+				delete(self.deliveries, did)
+			}
+
+			delete(self.webhookDeliveries, id)
+		}
+	}
+}
+
+func (self *Repository) CreateWebhook(
+	ctx context.Context,
+	webhook models.Webhook,
+) (models.Webhook, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	_, err := find(self.users, webhook.OwnerId, "webhook owner")
+
+	if nil == err {
+		webhook.Id, err = findFreeUUID(self.webhooks)
+	}
+
+	if nil == err {
+		webhook.Events = slices.Clone(webhook.Events)
+		err = self.commit(&Record{Webhook: &webhook})
+	}
+
+	return webhook, err
+}
+
+func (self *Repository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	_, err := find(self.webhooks, id, "webhook")
+
+	if nil == err {
+		err = self.commit(&Record{DeletedWebhook: &id})
+	}
+
+	return err
+}
+
+func (self *Repository) GetWebhooksById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.Webhook]], error) {
+	return newPeekCollection(self.mutex.RLocker(), &self.webhooks, ids), nil
+}
+
+func (self *Repository) GetWebhooksByOwnerId(
+	ctx context.Context,
+	ownerId uuid.UUID,
+) (collection.Collection[result.Result[models.Webhook]], error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return collection.Map(
+		newIndexCollection(
+			self.mutex.RLocker(),
+			&self.webhooks,
+			indexOf(self.ownerWebhooks, ownerId),
+			webhookKey,
+			false,
+		),
+		func(v *models.Webhook) result.Result[models.Webhook] {
+			return result.Ok(*v)
+		},
+	), nil
+}
+
+func (self *Repository) GetWebhooksByEvent(
+	ctx context.Context,
+	kind string,
+	postId uuid.UUID,
+) (collection.Collection[result.Result[models.Webhook]], error) {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+	matched := make([]entry, 0)
+
+	for _, v := range self.webhooks {
+		if slices.Contains(v.Events, kind) &&
+			(!v.PostId.Valid || postId == v.PostId.UUID) {
+			matched = append(matched, webhookKey(&v))
+		}
+	}
+
+	slices.SortFunc(matched, entry.compare)
+	ids := make([]uuid.UUID, len(matched))
+
+	for i, e := range matched {
+		ids[i] = e.id
+	}
+
+	return newPeekCollection(self.mutex.RLocker(), &self.webhooks, ids), nil
+}
+
+func (self *Repository) CreateDeliveries(
+	ctx context.Context,
+	deliveries ...models.WebhookDelivery,
+) error {
+	var err error
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	record := Record{Deliveries: make([]models.WebhookDelivery, 0, len(deliveries))}
+
+	for i := 0; nil == err && len(deliveries) > i; i++ {
+		v := deliveries[i]
+		key := deliveryKey{v.WebhookId, v.EventId}
+		_, found := self.deliveryKeys[key]
+
+		if !found {
+			found = slices.ContainsFunc(record.Deliveries, func(d models.WebhookDelivery) bool {
+				return key == deliveryKey{d.WebhookId, d.EventId}
+			})
+		}
+
+		if found {
+			continue
+		}
+
+		_, err = find(self.webhooks, v.WebhookId, "delivery webhook")
+
+		if nil == err {
+			v.Id, err = findFreeUUID(self.deliveries)
+		}
+
+		if nil == err {
+			record.Deliveries = append(record.Deliveries, v)
+		}
+	}
+
+	if nil == err && 0 != len(record.Deliveries) {
+		err = self.commit(&record)
+	}
+
+	return err
+}
+
+func (self *Repository) UpdateDelivery(
+	ctx context.Context,
+	delivery models.WebhookDelivery,
+) (models.WebhookDelivery, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	old, err := find(self.deliveries, delivery.Id, "delivery")
+
+	if nil == err && (old.WebhookId != delivery.WebhookId || old.EventId != delivery.EventId) {
+		err = repoerrors.NotFound("delivery")
+	}
+
+	if nil == err {
+		err = self.commit(&Record{Deliveries: []models.WebhookDelivery{delivery}})
+	}
+
+	return delivery, err
+}
+
+func (self *Repository) GetDeliveriesById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.WebhookDelivery]], error) {
+	return newPeekCollection(self.mutex.RLocker(), &self.deliveries, ids), nil
+}
+
+func (self *Repository) GetDeliveriesByWebhookId(
+	ctx context.Context,
+	webhookId uuid.UUID,
+) (collection.Collection[result.Result[models.WebhookDelivery]], error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if _, found := self.webhooks[webhookId]; !found {
+		return nil, repoerrors.NotFound("webhook")
+	}
+
+	return collection.Map(
+		newIndexCollection(
+			self.mutex.RLocker(),
+			&self.deliveries,
+			indexOf(self.webhookDeliveries, webhookId),
+			deliveryEntry,
+			true,
+		),
+		func(v *models.WebhookDelivery) result.Result[models.WebhookDelivery] {
+			return result.Ok(*v)
+		},
+	), nil
+}
+
+func (self *Repository) GetDueDeliveries(
+	ctx context.Context,
+	until time.Time,
+) (collection.Collection[result.Result[models.WebhookDelivery]], error) {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+	ids := self.dueIndex.page(nil, false, -1)
+	due := slices.IndexFunc(ids, func(id uuid.UUID) bool {
+		return self.deliveries[id].NextAttempt.After(until)
+	})
+
+	if 0 <= due {
+		ids = ids[:due]
+	}
+
+	return newPeekCollection(self.mutex.RLocker(), &self.deliveries, ids), nil
+}
+
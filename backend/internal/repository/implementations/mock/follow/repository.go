@@ -0,0 +1,105 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../implementations/mock/follow/repository.go
+//
+
+// Package mock_follow is a generated GoMock package.
+package mock_follow
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	models "github.com/muji40k/ozontestcomms/internal/domain/models"
+	collection "github.com/muji40k/ozontestcomms/internal/repository/collection"
+	result "github.com/muji40k/ozontestcomms/misc/result"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateFollow mocks base method.
+func (m *MockRepository) CreateFollow(ctx context.Context, follow models.Follow) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFollow", ctx, follow)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFollow indicates an expected call of CreateFollow.
+func (mr *MockRepositoryMockRecorder) CreateFollow(ctx, follow any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFollow", reflect.TypeOf((*MockRepository)(nil).CreateFollow), ctx, follow)
+}
+
+// DeleteFollow mocks base method.
+func (m *MockRepository) DeleteFollow(ctx context.Context, userId uuid.UUID, kind models.FollowKind, targetId uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFollow", ctx, userId, kind, targetId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFollow indicates an expected call of DeleteFollow.
+func (mr *MockRepositoryMockRecorder) DeleteFollow(ctx, userId, kind, targetId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFollow", reflect.TypeOf((*MockRepository)(nil).DeleteFollow), ctx, userId, kind, targetId)
+}
+
+// GetFeed mocks base method.
+func (m *MockRepository) GetFeed(ctx context.Context, userId uuid.UUID) (collection.Collection[result.Result[models.Post]], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", ctx, userId)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.Post]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockRepositoryMockRecorder) GetFeed(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockRepository)(nil).GetFeed), ctx, userId)
+}
+
+// GetWatchedPosts mocks base method.
+func (m *MockRepository) GetWatchedPosts(ctx context.Context, userId uuid.UUID) (collection.Collection[result.Result[models.Post]], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchedPosts", ctx, userId)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.Post]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchedPosts indicates an expected call of GetWatchedPosts.
+func (mr *MockRepositoryMockRecorder) GetWatchedPosts(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchedPosts", reflect.TypeOf((*MockRepository)(nil).GetWatchedPosts), ctx, userId)
+}
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../implementations/mock/moderation/repository.go
+//
+
+// Package mock_moderation is a generated GoMock package.
+package mock_moderation
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	models "github.com/muji40k/ozontestcomms/internal/domain/models"
+	collection "github.com/muji40k/ozontestcomms/internal/repository/collection"
+	result "github.com/muji40k/ozontestcomms/misc/result"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateReview mocks base method.
+func (m *MockRepository) CreateReview(ctx context.Context, review models.Review) (models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReview", ctx, review)
+	ret0, _ := ret[0].(models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReview indicates an expected call of CreateReview.
+func (mr *MockRepositoryMockRecorder) CreateReview(ctx, review any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReview", reflect.TypeOf((*MockRepository)(nil).CreateReview), ctx, review)
+}
+
+// DeleteReview mocks base method.
+func (m *MockRepository) DeleteReview(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockRepositoryMockRecorder) DeleteReview(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockRepository)(nil).DeleteReview), ctx, id)
+}
+
+// GetReviews mocks base method.
+func (m *MockRepository) GetReviews(ctx context.Context) (collection.Collection[result.Result[models.Review]], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews", ctx)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.Review]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockRepositoryMockRecorder) GetReviews(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockRepository)(nil).GetReviews), ctx)
+}
+
+// GetReviewsById mocks base method.
+func (m *MockRepository) GetReviewsById(ctx context.Context, ids ...uuid.UUID) (collection.Collection[result.Result[models.Review]], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetReviewsById", varargs...)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.Review]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsById indicates an expected call of GetReviewsById.
+func (mr *MockRepositoryMockRecorder) GetReviewsById(ctx any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsById", reflect.TypeOf((*MockRepository)(nil).GetReviewsById), varargs...)
+}
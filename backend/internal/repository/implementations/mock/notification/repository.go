@@ -0,0 +1,135 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../implementations/mock/notification/repository.go
+//
+
+// Package mock_notification is a generated GoMock package.
+package mock_notification
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	models "github.com/muji40k/ozontestcomms/internal/domain/models"
+	collection "github.com/muji40k/ozontestcomms/internal/repository/collection"
+	notification "github.com/muji40k/ozontestcomms/internal/repository/interface/notification"
+	result "github.com/muji40k/ozontestcomms/misc/result"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockRepository) CountUnreadNotifications(ctx context.Context, userId uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", ctx, userId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockRepositoryMockRecorder) CountUnreadNotifications(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockRepository)(nil).CountUnreadNotifications), ctx, userId)
+}
+
+// CreateNotifications mocks base method.
+func (m *MockRepository) CreateNotifications(ctx context.Context, notifications ...models.Notification) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range notifications {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateNotifications", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotifications indicates an expected call of CreateNotifications.
+func (mr *MockRepositoryMockRecorder) CreateNotifications(ctx any, notifications ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, notifications...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotifications", reflect.TypeOf((*MockRepository)(nil).CreateNotifications), varargs...)
+}
+
+// GetNotificationsById mocks base method.
+func (m *MockRepository) GetNotificationsById(ctx context.Context, ids ...uuid.UUID) (collection.Collection[result.Result[models.Notification]], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetNotificationsById", varargs...)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.Notification]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationsById indicates an expected call of GetNotificationsById.
+func (mr *MockRepositoryMockRecorder) GetNotificationsById(ctx any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsById", reflect.TypeOf((*MockRepository)(nil).GetNotificationsById), varargs...)
+}
+
+// GetNotificationsByUserId mocks base method.
+func (m *MockRepository) GetNotificationsByUserId(ctx context.Context, userId uuid.UUID, unreadOnly bool, order notification.NotificationOrder) (collection.Collection[result.Result[models.Notification]], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationsByUserId", ctx, userId, unreadOnly, order)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.Notification]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationsByUserId indicates an expected call of GetNotificationsByUserId.
+func (mr *MockRepositoryMockRecorder) GetNotificationsByUserId(ctx, userId, unreadOnly, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsByUserId", reflect.TypeOf((*MockRepository)(nil).GetNotificationsByUserId), ctx, userId, unreadOnly, order)
+}
+
+// MarkNotificationsRead mocks base method.
+func (m *MockRepository) MarkNotificationsRead(ctx context.Context, userId uuid.UUID, ids ...uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, userId}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MarkNotificationsRead", varargs...)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationsRead indicates an expected call of MarkNotificationsRead.
+func (mr *MockRepositoryMockRecorder) MarkNotificationsRead(ctx, userId any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, userId}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationsRead", reflect.TypeOf((*MockRepository)(nil).MarkNotificationsRead), varargs...)
+}
@@ -44,6 +44,41 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CreateUser mocks base method.
+func (m *MockRepository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, user)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockRepositoryMockRecorder) CreateUser(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockRepository)(nil).CreateUser), ctx, user)
+}
+
+// GetUsersByEmail mocks base method.
+func (m *MockRepository) GetUsersByEmail(ctx context.Context, emails ...string) (collection.Collection[result.Result[models.User]], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range emails {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetUsersByEmail", varargs...)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.User]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByEmail indicates an expected call of GetUsersByEmail.
+func (mr *MockRepositoryMockRecorder) GetUsersByEmail(ctx any, emails ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, emails...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByEmail", reflect.TypeOf((*MockRepository)(nil).GetUsersByEmail), varargs...)
+}
+
 // GetUsersById mocks base method.
 func (m *MockRepository) GetUsersById(ctx context.Context, ids ...uuid.UUID) (collection.Collection[result.Result[models.User]], error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,209 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../implementations/mock/webhook/repository.go
+//
+
+// Package mock_webhook is a generated GoMock package.
+package mock_webhook
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	models "github.com/muji40k/ozontestcomms/internal/domain/models"
+	collection "github.com/muji40k/ozontestcomms/internal/repository/collection"
+	result "github.com/muji40k/ozontestcomms/misc/result"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateDeliveries mocks base method.
+func (m *MockRepository) CreateDeliveries(ctx context.Context, deliveries ...models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range deliveries {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateDeliveries", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDeliveries indicates an expected call of CreateDeliveries.
+func (mr *MockRepositoryMockRecorder) CreateDeliveries(ctx any, deliveries ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, deliveries...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeliveries", reflect.TypeOf((*MockRepository)(nil).CreateDeliveries), varargs...)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockRepository) CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockRepositoryMockRecorder) CreateWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockRepository)(nil).CreateWebhook), ctx, webhook)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockRepositoryMockRecorder) DeleteWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockRepository)(nil).DeleteWebhook), ctx, id)
+}
+
+// GetDeliveriesById mocks base method.
+func (m *MockRepository) GetDeliveriesById(ctx context.Context, ids ...uuid.UUID) (collection.Collection[result.Result[models.WebhookDelivery]], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetDeliveriesById", varargs...)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.WebhookDelivery]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveriesById indicates an expected call of GetDeliveriesById.
+func (mr *MockRepositoryMockRecorder) GetDeliveriesById(ctx any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveriesById", reflect.TypeOf((*MockRepository)(nil).GetDeliveriesById), varargs...)
+}
+
+// GetDeliveriesByWebhookId mocks base method.
+func (m *MockRepository) GetDeliveriesByWebhookId(ctx context.Context, webhookId uuid.UUID) (collection.Collection[result.Result[models.WebhookDelivery]], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveriesByWebhookId", ctx, webhookId)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.WebhookDelivery]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveriesByWebhookId indicates an expected call of GetDeliveriesByWebhookId.
+func (mr *MockRepositoryMockRecorder) GetDeliveriesByWebhookId(ctx, webhookId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveriesByWebhookId", reflect.TypeOf((*MockRepository)(nil).GetDeliveriesByWebhookId), ctx, webhookId)
+}
+
+// GetDueDeliveries mocks base method.
+func (m *MockRepository) GetDueDeliveries(ctx context.Context, until time.Time) (collection.Collection[result.Result[models.WebhookDelivery]], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueDeliveries", ctx, until)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.WebhookDelivery]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueDeliveries indicates an expected call of GetDueDeliveries.
+func (mr *MockRepositoryMockRecorder) GetDueDeliveries(ctx, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueDeliveries", reflect.TypeOf((*MockRepository)(nil).GetDueDeliveries), ctx, until)
+}
+
+// GetWebhooksByEvent mocks base method.
+func (m *MockRepository) GetWebhooksByEvent(ctx context.Context, kind string, postId uuid.UUID) (collection.Collection[result.Result[models.Webhook]], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooksByEvent", ctx, kind, postId)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.Webhook]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooksByEvent indicates an expected call of GetWebhooksByEvent.
+func (mr *MockRepositoryMockRecorder) GetWebhooksByEvent(ctx, kind, postId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooksByEvent", reflect.TypeOf((*MockRepository)(nil).GetWebhooksByEvent), ctx, kind, postId)
+}
+
+// GetWebhooksById mocks base method.
+func (m *MockRepository) GetWebhooksById(ctx context.Context, ids ...uuid.UUID) (collection.Collection[result.Result[models.Webhook]], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetWebhooksById", varargs...)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.Webhook]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooksById indicates an expected call of GetWebhooksById.
+func (mr *MockRepositoryMockRecorder) GetWebhooksById(ctx any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooksById", reflect.TypeOf((*MockRepository)(nil).GetWebhooksById), varargs...)
+}
+
+// GetWebhooksByOwnerId mocks base method.
+func (m *MockRepository) GetWebhooksByOwnerId(ctx context.Context, ownerId uuid.UUID) (collection.Collection[result.Result[models.Webhook]], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooksByOwnerId", ctx, ownerId)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.Webhook]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooksByOwnerId indicates an expected call of GetWebhooksByOwnerId.
+func (mr *MockRepositoryMockRecorder) GetWebhooksByOwnerId(ctx, ownerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooksByOwnerId", reflect.TypeOf((*MockRepository)(nil).GetWebhooksByOwnerId), ctx, ownerId)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockRepository) UpdateDelivery(ctx context.Context, delivery models.WebhookDelivery) (models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, delivery)
+	ret0, _ := ret[0].(models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockRepositoryMockRecorder) UpdateDelivery(ctx, delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockRepository)(nil).UpdateDelivery), ctx, delivery)
+}
@@ -0,0 +1,46 @@
+package psql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateOrder(t *testing.T) {
+	// Arrange
+	ids := []uuid.UUID{
+		uuid.MustParse("9c3d7dba-d1b2-42de-b708-158e32f11623"),
+		uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+	}
+
+	// Act
+	empty := generateOrder(nil)
+	order := generateOrder(ids)
+
+	// Assert
+	assert.Empty(t, empty)
+	assert.Equal(t,
+		"('9c3d7dba-d1b2-42de-b708-158e32f11623'::uuid, 0), "+
+			"('00000000-0000-0000-0000-000000000001'::uuid, 1)",
+		order,
+	)
+}
+
+func BenchmarkGenerateOrder(b *testing.B) {
+	for _, n := range []int{10, 100, 1_000} {
+		ids := make([]uuid.UUID, n)
+
+		for i := range ids {
+			ids[i] = uuid.Must(uuid.NewRandom())
+		}
+
+		b.Run(fmt.Sprintf("ids=%v", n), func(b *testing.B) {
+			for b.Loop() {
+				generateOrder(ids)
+			}
+		})
+	}
+}
+
@@ -47,6 +47,12 @@ func (self *localIterator[T]) Next() (result.Result[T], bool) {
 	}
 }
 
+// Rows are closed at the end as well, closing them twice is harmless
+func (self *localIterator[T]) Close() error {
+	self.end = true
+	return self.rows.Close()
+}
+
 func newIterator[T any](rows *sqlx.Rows) iterator.Iterator[result.Result[T]] {
 	return &localIterator[T]{rows, false}
 }
@@ -170,6 +176,11 @@ func (self *peekIterator[T]) Next() (result.Result[T], bool) {
 	}
 }
 
+func (self *peekIterator[T]) Close() error {
+	self.end = true
+	return self.rows.Close()
+}
+
 func newPeekIterator[T checkable](rows *sqlx.Rows) iterator.Iterator[result.Result[T]] {
 	return &peekIterator[T]{false, rows}
 }
@@ -0,0 +1,68 @@
+package psql_test
+
+import (
+	"os"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/psql"
+	"github.com/muji40k/ozontestcomms/test/common"
+	"github.com/muji40k/ozontestcomms/test/contract"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// Database with the schema from psql/ applied. It's emptied by the tests,
+// so it must not be the one the service uses
+const ENV_TEST_DSN = "POSTER_TEST_PSQL_DSN"
+
+func TestRepositoryContract(t *testing.T) {
+	dsn := os.Getenv(ENV_TEST_DSN)
+
+	if "" == dsn {
+		t.Skipf("$%v isn't set", ENV_TEST_DSN)
+	}
+
+	contract.RunRepository(t, func(t *testing.T) contract.Subject {
+		db, err := sqlx.Connect("pgx", dsn)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		author := common.Unwrap(domainOM.UserRandom().Build())
+		_, err = db.Exec(`
+            truncate comments.comments, posts.posts, commentables.commentables,
+                outbox.events, webhooks.deliveries, webhooks.webhooks,
+                notifications.notifications, follows.authors, follows.posts,
+                moderation.reviews
+        `)
+		require.NoError(t, err)
+		_, err = db.NamedExec(`
+            insert into users.users (id, email, password)
+            values (:id, :email, :password)
+        `, map[string]any{
+			"id":       author.Id,
+			"email":    author.Email,
+			"password": author.Password,
+		})
+		require.NoError(t, err)
+
+		// Pool is shared with the repository, so that its connections
+		// can be checked
+		repo := psql.NewRepository(db).WithOutbox()
+
+		return contract.Subject{
+			Comment:      repo,
+			Post:         repo,
+			User:         repo,
+			Outbox:       repo,
+			Webhook:      repo,
+			Notification: repo,
+			Follow:       repo,
+			Moderation:   repo,
+			Author:       author,
+			Pool:         db.DB,
+		}
+	})
+}
+
@@ -44,6 +44,14 @@ func mapUser(self *User) models.User {
 	}
 }
 
+func unmapUser(self *models.User) User {
+	return User{
+		Id:       self.Id,
+		Email:    self.Email,
+		Password: self.Password,
+	}
+}
+
 func mapQUser(self *qUser) models.User {
 	return models.User{
 		Id:       self.Id.UUID,
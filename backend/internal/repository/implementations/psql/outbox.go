@@ -0,0 +1,155 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+type Event struct {
+	Id           uuid.UUID `db:"id"`
+	Seq          int64     `db:"seq"`
+	Type         string    `db:"type"`
+	EntityId     uuid.UUID `db:"entity_id"`
+	Payload      []byte    `db:"payload"`
+	CreationDate time.Time `db:"creation_date"`
+}
+
+func mapEvent(value *Event) models.Event {
+	return models.Event{
+		Id:           value.Id,
+		Type:         value.Type,
+		EntityId:     value.EntityId,
+		Payload:      value.Payload,
+		CreationDate: value.CreationDate,
+	}
+}
+
+func unmapEvent(value *models.Event) Event {
+	return Event{
+		Id:           value.Id,
+		Type:         value.Type,
+		EntityId:     value.EntityId,
+		Payload:      value.Payload,
+		CreationDate: value.CreationDate,
+	}
+}
+
+// Records events with every following write, in the transaction of the
+// write
+func (self *Repository) WithOutbox() *Repository {
+	self.outbox = true
+	return self
+}
+
+func (self *Repository) record(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	event func() (models.Event, error),
+) error {
+	if !self.outbox {
+		return nil
+	}
+
+	v, err := event()
+
+	if nil == err {
+		_, err = tx.NamedExecContext(ctx, `
+            insert into outbox.events (
+                id, type, entity_id, payload, creation_date
+            ) values (
+                :id, :type, :entity_id, :payload, :creation_date
+            )
+        `, unmapEvent(&v))
+	}
+
+	return err
+}
+
+// Post at the root of the comment's thread
+func postOf(ctx context.Context, db sqlx.QueryerContext, commentId uuid.UUID) (uuid.UUID, error) {
+	var out uuid.UUID
+
+	err := sqlx.GetContext(ctx, db, &out, `
+        with recursive thread (target_id) as (
+            select target_id from comments.comments where id = $1
+            union all
+            select comments.target_id
+            from comments.comments
+            join thread on comments.commentable_id = thread.target_id
+        )
+        select posts.id
+        from posts.posts
+        join thread on posts.commentable_id = thread.target_id
+    `, commentId)
+
+	return out, notFound(err, "comment post")
+}
+
+func (self *Repository) GetPendingEvents(
+	ctx context.Context,
+) (collection.Collection[result.Result[models.Event]], error) {
+	return collection.Map(newCollection[Event](
+		func(
+			after *nullable.Nullable[uuid.UUID],
+			limit *nullable.Nullable[uint],
+		) (*sqlx.Rows, error) {
+			builder := strings.Builder{}
+			args := make([]any, 0, 2)
+			cnt := 1
+
+			fmt.Fprint(&builder, "select * from outbox.events")
+
+			nullable.IfSome(after, func(id *uuid.UUID) {
+				fmt.Fprintf(&builder, `
+                    where seq > (
+                        select seq from outbox.events where id = $%v
+                    )`, cnt)
+				cnt++
+				args = append(args, *id)
+			})
+
+			fmt.Fprint(&builder, " order by seq")
+
+			nullable.IfSome(limit, func(sz *uint) {
+				fmt.Fprintf(&builder, " limit $%v", cnt)
+				args = append(args, *sz)
+			})
+
+			return self.db.QueryxContext(ctx, builder.String(), args...)
+		},
+		func(id uuid.UUID) error {
+			return checkExists(ctx, self.db, "event", "outbox.events", id)
+		},
+	), result.OkMapper(mapEvent)), nil
+}
+
+func (self *Repository) AcknowledgeEvents(ctx context.Context, ids ...uuid.UUID) error {
+	if 0 == len(ids) {
+		return nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%v", i+1)
+		args[i] = id
+	}
+
+	_, err := self.db.ExecContext(ctx,
+		"delete from outbox.events where id in ("+strings.Join(placeholders, ", ")+")",
+		args...,
+	)
+
+	return err
+}
+
@@ -3,11 +3,14 @@ package psql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
 	"github.com/muji40k/ozontestcomms/internal/domain/models"
 	"github.com/muji40k/ozontestcomms/internal/repository/collection"
 	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
@@ -18,11 +21,29 @@ import (
 )
 
 type Repository struct {
-	db *sqlx.DB
+	db   *sqlx.DB
+	read *sqlx.DB
+	// Writes record their events to outbox.events in the same transaction
+	outbox bool
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
-	return &Repository{db}
+	return &Repository{db, db, false}
+}
+
+// Listings (GetPosts, GetCommentsBy*, GetUsersById) are served from the
+// replica, while writes and lookups which guard them (GetPostsById) stay on
+// the primary, so that replication lag doesn't affect them
+func NewRepositoryWithReplica(db *sqlx.DB, replica *sqlx.DB) *Repository {
+	return &Repository{db, replica, false}
+}
+
+func notFound(err error, what string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return repoerrors.NotFound(what)
+	}
+
+	return err
 }
 
 func generateId(
@@ -38,6 +59,7 @@ func generateId(
     `, where))
 
 	if nil == err {
+		defer stmt.Close()
 		id, err = uuid.NewRandom()
 
 		for found := false; nil == err && !found; {
@@ -85,6 +107,7 @@ func checkExists(
 func get[T any](
 	ctx context.Context,
 	db sqlx.QueryerContext,
+	what string,
 	where string,
 	id uuid.UUID,
 ) (T, error) {
@@ -95,7 +118,7 @@ func get[T any](
 		id,
 	)
 
-	return out, err
+	return out, notFound(err, what)
 }
 
 func getPost(
@@ -114,17 +137,28 @@ func getPost(
             on filtered.commentable_id = commentables.id
     `, id)
 
-	return out, err
+	return out, notFound(err, "post")
 }
 
+// Values list of (id, position) pairs, built in one buffer as it's called
+// with every batch of the dataloaders
 func generateOrder(ids []uuid.UUID) string {
-	order := make([]string, len(ids))
+	var out strings.Builder
+	out.Grow(len(ids) * len("('00000000-0000-0000-0000-000000000000'::uuid, 0000), "))
 
 	for i, id := range ids {
-		order[i] = fmt.Sprintf("('%v'::uuid, %v)", id, i)
+		if 0 != i {
+			out.WriteString(", ")
+		}
+
+		out.WriteString("('")
+		out.WriteString(id.String())
+		out.WriteString("'::uuid, ")
+		out.WriteString(strconv.Itoa(i))
+		out.WriteString(")")
 	}
 
-	return strings.Join(order, ", ")
+	return out.String()
 }
 
 func createCommentable(
@@ -179,7 +213,7 @@ func (self *Repository) CreatePostComment(
 ) (models.Comment, error) {
 	var post Post
 	lcomment := unmapComment(&comment)
-	tx, err := self.db.Beginx()
+	tx, err := self.db.BeginTxx(ctx, nil)
 
 	if nil == err {
 		post, err = getPost(ctx, tx, comment.TargetId)
@@ -200,6 +234,13 @@ func (self *Repository) CreatePostComment(
 		err = createComment(ctx, tx, lcomment)
 	}
 
+	if nil == err {
+		err = self.record(ctx, tx, func() (models.Event, error) {
+			created := mapComment(&lcomment)
+			return events.CommentCreated(&created, comment.TargetId, nil)
+		})
+	}
+
 	if nil == err {
 		err = tx.Commit()
 	}
@@ -224,10 +265,10 @@ func (self *Repository) CreateCommentComment(
 ) (models.Comment, error) {
 	var root Comment
 	lcomment := unmapComment(&comment)
-	tx, err := self.db.Beginx()
+	tx, err := self.db.BeginTxx(ctx, nil)
 
 	if nil == err {
-		root, err = get[Comment](ctx, tx, "comments.comments", comment.TargetId)
+		root, err = get[Comment](ctx, tx, "comment", "comments.comments", comment.TargetId)
 	}
 
 	if nil == err {
@@ -245,6 +286,19 @@ func (self *Repository) CreateCommentComment(
 		err = createComment(ctx, tx, lcomment)
 	}
 
+	if nil == err {
+		err = self.record(ctx, tx, func() (models.Event, error) {
+			created := mapComment(&lcomment)
+			post, err := postOf(ctx, tx, root.Id)
+
+			if nil != err {
+				return models.Event{}, err
+			}
+
+			return events.CommentCreated(&created, post, &root.Id)
+		})
+	}
+
 	if nil == err {
 		err = tx.Commit()
 	}
@@ -272,21 +326,13 @@ func (self *Repository) GetCommentsById(
 	}
 
 	return collection.Map(newPeekCollection[qComment](ids, func(ids []uuid.UUID) (*sqlx.Rows, error) {
-		var rows *sqlx.Rows
-
-		stmt, err := self.db.PreparexContext(ctx, `
+		return self.read.QueryxContext(ctx, `
             select comments.*, orderer.ord
             from comments.comments
             right outer join (values `+generateOrder(ids)+`) as orderer (id, ord)
                 on comments.id = orderer.id
             order by orderer.ord
         `)
-
-		if nil == err {
-			rows, err = stmt.QueryxContext(ctx)
-		}
-
-		return rows, err
 	}), result.OkMapper(mapQComment)), nil
 }
 
@@ -331,19 +377,12 @@ func (self *Repository) getCommentsByTarget(
 				args = append(args, *sz)
 			})
 
-			var rows *sqlx.Rows
-			stmt, err := self.db.PreparexContext(ctx, builder.String())
-
-			if nil == err {
-				rows, err = stmt.QueryxContext(ctx, args...)
-			}
-
-			return rows, err
+			return self.read.QueryxContext(ctx, builder.String(), args...)
 		},
 		func(id uuid.UUID) error {
 			var found bool
 
-			err := self.db.GetContext(ctx, &found, `
+			err := self.read.GetContext(ctx, &found, `
                     select exists(
                         select *
                         from comments.comments
@@ -365,7 +404,7 @@ func (self *Repository) GetCommentsByPostId(
 	postId uuid.UUID,
 	order comment.CommentOrder,
 ) (collection.Collection[result.Result[models.Comment]], error) {
-	post, err := getPost(ctx, self.db, postId)
+	post, err := getPost(ctx, self.read, postId)
 
 	if nil != err {
 		return nil, err
@@ -379,7 +418,7 @@ func (self *Repository) GetCommentsByCommentId(
 	commentId uuid.UUID,
 	order comment.CommentOrder,
 ) (collection.Collection[result.Result[models.Comment]], error) {
-	comment, err := get[Comment](ctx, self.db, "comments.comments", commentId)
+	comment, err := get[Comment](ctx, self.read, "comment", "comments.comments", commentId)
 
 	if nil != err {
 		return nil, err
@@ -388,6 +427,62 @@ func (self *Repository) GetCommentsByCommentId(
 	}
 }
 
+func (self *Repository) CreateUser(
+	ctx context.Context,
+	user models.User,
+) (models.User, error) {
+	var found bool
+	luser := unmapUser(&user)
+	tx, err := self.db.BeginTxx(ctx, nil)
+
+	if nil == err {
+		err = tx.GetContext(ctx, &found,
+			"select exists(select * from users.users where email = $1)",
+			luser.Email,
+		)
+	}
+
+	if nil == err && found {
+		err = repoerrors.Duplicate("user email")
+	}
+
+	if nil == err {
+		luser.Id, err = generateId(ctx, tx, "users.users")
+	}
+
+	if nil == err {
+		_, err = tx.NamedExecContext(ctx, `
+            insert into users.users (
+                id, email, password
+            ) values (
+                :id, :email, :password
+            )
+        `, luser)
+	}
+
+	if nil == err {
+		err = self.record(ctx, tx, func() (models.Event, error) {
+			created := mapUser(&luser)
+			return events.UserCreated(&created)
+		})
+	}
+
+	if nil == err {
+		err = tx.Commit()
+	}
+
+	if nil == err {
+		user.Id = luser.Id
+		return user, nil
+	} else {
+		if nil != tx {
+			tx.Rollback()
+		}
+
+		return user, err
+	}
+}
+
 func (self *Repository) GetUsersById(
 	ctx context.Context,
 	ids ...uuid.UUID,
@@ -397,22 +492,37 @@ func (self *Repository) GetUsersById(
 	}
 
 	return collection.Map(newPeekCollection[qUser](ids, func(ids []uuid.UUID) (*sqlx.Rows, error) {
-		var rows *sqlx.Rows
-
-		stmt, err := self.db.PreparexContext(ctx, `
+		return self.read.QueryxContext(ctx, `
             select users.*, orderer.ord
             from users.users
             right outer join (values `+generateOrder(ids)+`) as orderer (id, ord)
                 on users.id = orderer.id
             order by orderer.ord
         `)
+	}), result.OkMapper(mapQUser)), nil
+}
 
-		if nil == err {
-			rows, err = stmt.QueryxContext(ctx)
-		}
+func (self *Repository) GetUsersByEmail(
+	ctx context.Context,
+	emails ...string,
+) (collection.Collection[result.Result[models.User]], error) {
+	var ids []uuid.UUID
 
-		return rows, err
-	}), result.OkMapper(mapQUser)), nil
+	if 0 == len(emails) {
+		return collection.EmptyCollection[result.Result[models.User]](), nil
+	}
+
+	query, args, err := sqlx.In("select id from users.users where email in (?)", emails)
+
+	if nil == err {
+		err = self.read.SelectContext(ctx, &ids, self.read.Rebind(query), args...)
+	}
+
+	if nil != err {
+		return nil, err
+	}
+
+	return self.GetUsersById(ctx, ids...)
 }
 
 func (self *Repository) CreatePost(
@@ -420,7 +530,7 @@ func (self *Repository) CreatePost(
 	post models.Post,
 ) (models.Post, error) {
 	lpost := unmapPost(&post)
-	tx, err := self.db.Beginx()
+	tx, err := self.db.BeginTxx(ctx, nil)
 
 	if nil == err {
 		lpost.Id, err = generateId(ctx, tx, "posts.posts")
@@ -444,6 +554,14 @@ func (self *Repository) CreatePost(
         `, lpost)
 	}
 
+	if nil == err {
+		err = self.record(ctx, tx, func() (models.Event, error) {
+			created := post
+			created.Id = lpost.Id
+			return events.PostCreated(&created)
+		})
+	}
+
 	if nil == err {
 		err = tx.Commit()
 	}
@@ -502,17 +620,10 @@ func (self *Repository) GetPosts(
 				args = append(args, *sz)
 			})
 
-			var rows *sqlx.Rows
-			stmt, err := self.db.PreparexContext(ctx, builder.String())
-
-			if nil == err {
-				rows, err = stmt.QueryxContext(ctx, args...)
-			}
-
-			return rows, err
+			return self.read.QueryxContext(ctx, builder.String(), args...)
 		},
 		func(id uuid.UUID) error {
-			return checkExists(ctx, self.db, "post", "posts.posts", id)
+			return checkExists(ctx, self.read, "post", "posts.posts", id)
 		},
 	), result.OkMapper(mapPost)), nil
 }
@@ -526,9 +637,7 @@ func (self *Repository) GetPostsById(
 	}
 
 	return collection.Map(newPeekCollection[qPost](ids, func(ids []uuid.UUID) (*sqlx.Rows, error) {
-		var rows *sqlx.Rows
-
-		stmt, err := self.db.PreparexContext(ctx, `
+		return self.db.QueryxContext(ctx, `
             select filtered.*, commentables.comments_allowed
             from (
                 select posts.*, orderer.ord
@@ -540,12 +649,6 @@ func (self *Repository) GetPostsById(
                 on filtered.commentable_id = commentables.id
             order by filtered.ord
         `)
-
-		if nil == err {
-			rows, err = stmt.QueryxContext(ctx)
-		}
-
-		return rows, err
 	}), result.OkMapper(mapQPost)), nil
 }
 
@@ -562,11 +665,11 @@ func (self *Repository) UpdatePost(
 	}
 
 	if nil == err {
-		tx, err = self.db.Beginx()
+		tx, err = self.db.BeginTxx(ctx, nil)
 	}
 
 	if nil == err {
-		_, err = tx.NamedExec(`
+		_, err = tx.NamedExecContext(ctx, `
             update posts.posts
             set author_id = :author_id,
                 commentable_id = :commentable_id,
@@ -578,13 +681,19 @@ func (self *Repository) UpdatePost(
 	}
 
 	if nil == err {
-		_, err = tx.NamedExec(`
+		_, err = tx.NamedExecContext(ctx, `
             update commentables.commentables
             set comments_allowed = :comments_allowed
             where id = :commentable_id
         `, lpost)
 	}
 
+	if nil == err {
+		err = self.record(ctx, tx, func() (models.Event, error) {
+			return events.PostUpdated(&post)
+		})
+	}
+
 	if nil == err {
 		err = tx.Commit()
 	}
@@ -600,3 +709,13 @@ func (self *Repository) UpdatePost(
 	}
 }
 
+func (self *Repository) Ping(ctx context.Context) error {
+	err := self.db.PingContext(ctx)
+
+	if nil == err && self.read != self.db {
+		err = self.read.PingContext(ctx)
+	}
+
+	return err
+}
+
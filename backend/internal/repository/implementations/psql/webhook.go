@@ -0,0 +1,501 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+type Webhook struct {
+	Id           uuid.UUID     `db:"id"`
+	OwnerId      uuid.UUID     `db:"owner_id"`
+	Url          string        `db:"url"`
+	Secret       string        `db:"secret"`
+	Events       string        `db:"events"`
+	PostId       uuid.NullUUID `db:"post_id"`
+	CreationDate time.Time     `db:"creation_date"`
+}
+
+type qWebhook struct {
+	Id           uuid.NullUUID  `db:"id"`
+	OwnerId      uuid.NullUUID  `db:"owner_id"`
+	Url          sql.NullString `db:"url"`
+	Secret       sql.NullString `db:"secret"`
+	Events       sql.NullString `db:"events"`
+	PostId       uuid.NullUUID  `db:"post_id"`
+	CreationDate sql.NullTime   `db:"creation_date"`
+	Ord          uint           `db:"ord"`
+}
+
+func (self qWebhook) check() bool {
+	return self.Id.Valid
+}
+
+func (self qWebhook) what() string {
+	return "webhook"
+}
+
+func splitEvents(value string) []string {
+	if "" == value {
+		return []string{}
+	}
+
+	return strings.Split(value, ",")
+}
+
+func mapWebhook(value *Webhook) models.Webhook {
+	return models.Webhook{
+		Id:           value.Id,
+		OwnerId:      value.OwnerId,
+		Url:          value.Url,
+		Secret:       value.Secret,
+		Events:       splitEvents(value.Events),
+		PostId:       value.PostId,
+		CreationDate: value.CreationDate,
+	}
+}
+
+func unmapWebhook(value *models.Webhook) Webhook {
+	return Webhook{
+		Id:           value.Id,
+		OwnerId:      value.OwnerId,
+		Url:          value.Url,
+		Secret:       value.Secret,
+		Events:       strings.Join(value.Events, ","),
+		PostId:       value.PostId,
+		CreationDate: value.CreationDate,
+	}
+}
+
+func mapQWebhook(value *qWebhook) models.Webhook {
+	return models.Webhook{
+		Id:           value.Id.UUID,
+		OwnerId:      value.OwnerId.UUID,
+		Url:          value.Url.String,
+		Secret:       value.Secret.String,
+		Events:       splitEvents(value.Events.String),
+		PostId:       value.PostId,
+		CreationDate: value.CreationDate.Time,
+	}
+}
+
+type Delivery struct {
+	Id           uuid.UUID    `db:"id"`
+	WebhookId    uuid.UUID    `db:"webhook_id"`
+	EventId      uuid.UUID    `db:"event_id"`
+	EventType    string       `db:"event_type"`
+	Payload      []byte       `db:"payload"`
+	Status       uint         `db:"status"`
+	Attempts     int          `db:"attempts"`
+	StatusCode   int          `db:"status_code"`
+	Error        string       `db:"error"`
+	LastAttempt  sql.NullTime `db:"last_attempt"`
+	NextAttempt  time.Time    `db:"next_attempt"`
+	CreationDate time.Time    `db:"creation_date"`
+}
+
+type qDelivery struct {
+	Id           uuid.NullUUID  `db:"id"`
+	WebhookId    uuid.NullUUID  `db:"webhook_id"`
+	EventId      uuid.NullUUID  `db:"event_id"`
+	EventType    sql.NullString `db:"event_type"`
+	Payload      []byte         `db:"payload"`
+	Status       sql.NullInt64  `db:"status"`
+	Attempts     sql.NullInt64  `db:"attempts"`
+	StatusCode   sql.NullInt64  `db:"status_code"`
+	Error        sql.NullString `db:"error"`
+	LastAttempt  sql.NullTime   `db:"last_attempt"`
+	NextAttempt  sql.NullTime   `db:"next_attempt"`
+	CreationDate sql.NullTime   `db:"creation_date"`
+	Ord          uint           `db:"ord"`
+}
+
+func (self qDelivery) check() bool {
+	return self.Id.Valid
+}
+
+func (self qDelivery) what() string {
+	return "delivery"
+}
+
+func mapDelivery(value *Delivery) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		Id:           value.Id,
+		WebhookId:    value.WebhookId,
+		EventId:      value.EventId,
+		EventType:    value.EventType,
+		Payload:      value.Payload,
+		Status:       models.DeliveryStatus(value.Status),
+		Attempts:     value.Attempts,
+		StatusCode:   value.StatusCode,
+		Error:        value.Error,
+		LastAttempt:  value.LastAttempt.Time,
+		NextAttempt:  value.NextAttempt,
+		CreationDate: value.CreationDate,
+	}
+}
+
+func unmapDelivery(value *models.WebhookDelivery) Delivery {
+	return Delivery{
+		Id:         value.Id,
+		WebhookId:  value.WebhookId,
+		EventId:    value.EventId,
+		EventType:  value.EventType,
+		Payload:    value.Payload,
+		Status:     uint(value.Status),
+		Attempts:   value.Attempts,
+		StatusCode: value.StatusCode,
+		Error:      value.Error,
+		LastAttempt: sql.NullTime{
+			Time:  value.LastAttempt,
+			Valid: !value.LastAttempt.IsZero(),
+		},
+		NextAttempt:  value.NextAttempt,
+		CreationDate: value.CreationDate,
+	}
+}
+
+func mapQDelivery(value *qDelivery) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		Id:           value.Id.UUID,
+		WebhookId:    value.WebhookId.UUID,
+		EventId:      value.EventId.UUID,
+		EventType:    value.EventType.String,
+		Payload:      value.Payload,
+		Status:       models.DeliveryStatus(value.Status.Int64),
+		Attempts:     int(value.Attempts.Int64),
+		StatusCode:   int(value.StatusCode.Int64),
+		Error:        value.Error.String,
+		LastAttempt:  value.LastAttempt.Time,
+		NextAttempt:  value.NextAttempt.Time,
+		CreationDate: value.CreationDate.Time,
+	}
+}
+
+func (self *Repository) CreateWebhook(
+	ctx context.Context,
+	webhook models.Webhook,
+) (models.Webhook, error) {
+	lwebhook := unmapWebhook(&webhook)
+	tx, err := self.db.BeginTxx(ctx, nil)
+
+	if nil == err {
+		err = checkExists(ctx, tx, "webhook owner", "users.users", lwebhook.OwnerId)
+	}
+
+	if nil == err {
+		lwebhook.Id, err = generateId(ctx, tx, "webhooks.webhooks")
+	}
+
+	if nil == err {
+		_, err = tx.NamedExecContext(ctx, `
+            insert into webhooks.webhooks (
+                id, owner_id, url, secret, events, post_id, creation_date
+            ) values (
+                :id, :owner_id, :url, :secret, :events, :post_id,
+                :creation_date
+            )
+        `, lwebhook)
+	}
+
+	if nil == err {
+		err = tx.Commit()
+	}
+
+	if nil == err {
+		return mapWebhook(&lwebhook), nil
+	} else {
+		if nil != tx {
+			tx.Rollback()
+		}
+
+		return webhook, err
+	}
+}
+
+func (self *Repository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	res, err := self.db.ExecContext(ctx,
+		"delete from webhooks.webhooks where id = $1", id,
+	)
+
+	if nil == err {
+		var affected int64
+
+		if affected, err = res.RowsAffected(); nil == err && 0 == affected {
+			err = repoerrors.NotFound("webhook")
+		}
+	}
+
+	return err
+}
+
+func (self *Repository) GetWebhooksById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.Webhook]], error) {
+	if 0 == len(ids) {
+		return collection.EmptyCollection[result.Result[models.Webhook]](), nil
+	}
+
+	return collection.Map(newPeekCollection[qWebhook](ids, func(ids []uuid.UUID) (*sqlx.Rows, error) {
+		return self.db.QueryxContext(ctx, `
+            select webhooks.*, orderer.ord
+            from webhooks.webhooks
+            right outer join (values `+generateOrder(ids)+`) as orderer (id, ord)
+                on webhooks.id = orderer.id
+            order by orderer.ord
+        `)
+	}), result.OkMapper(mapQWebhook)), nil
+}
+
+// Webhooks matching the condition with arguments from $1, oldest first
+func (self *Repository) getWebhooks(
+	ctx context.Context,
+	condition string,
+	cargs ...any,
+) collection.Collection[result.Result[models.Webhook]] {
+	return collection.Map(newCollection[Webhook](
+		func(
+			after *nullable.Nullable[uuid.UUID],
+			limit *nullable.Nullable[uint],
+		) (*sqlx.Rows, error) {
+			builder := strings.Builder{}
+			args := append(make([]any, 0, len(cargs)+2), cargs...)
+			cnt := len(cargs) + 1
+
+			fmt.Fprint(&builder, "select * from webhooks.webhooks where ", condition)
+
+			nullable.IfSome(after, func(id *uuid.UUID) {
+				fmt.Fprintf(&builder, `
+                    and (creation_date, id) > (
+                        select creation_date, id
+                        from webhooks.webhooks
+                        where id = $%v
+                    )`, cnt)
+				cnt++
+				args = append(args, *id)
+			})
+
+			fmt.Fprint(&builder, " order by creation_date, id")
+
+			nullable.IfSome(limit, func(sz *uint) {
+				fmt.Fprintf(&builder, " limit $%v", cnt)
+				args = append(args, *sz)
+			})
+
+			return self.db.QueryxContext(ctx, builder.String(), args...)
+		},
+		func(id uuid.UUID) error {
+			return checkExists(ctx, self.db, "webhook", "webhooks.webhooks", id)
+		},
+	), result.OkMapper(mapWebhook))
+}
+
+func (self *Repository) GetWebhooksByOwnerId(
+	ctx context.Context,
+	ownerId uuid.UUID,
+) (collection.Collection[result.Result[models.Webhook]], error) {
+	return self.getWebhooks(ctx, "owner_id = $1", ownerId), nil
+}
+
+func (self *Repository) GetWebhooksByEvent(
+	ctx context.Context,
+	kind string,
+	postId uuid.UUID,
+) (collection.Collection[result.Result[models.Webhook]], error) {
+	return self.getWebhooks(ctx, `
+        $1 = any(string_to_array(events, ','))
+        and (post_id is null or post_id = $2)
+    `, kind, postId), nil
+}
+
+func (self *Repository) CreateDeliveries(
+	ctx context.Context,
+	deliveries ...models.WebhookDelivery,
+) error {
+	if 0 == len(deliveries) {
+		return nil
+	}
+
+	tx, err := self.db.BeginTxx(ctx, nil)
+
+	for i := 0; nil == err && len(deliveries) > i; i++ {
+		ldelivery := unmapDelivery(&deliveries[i])
+		err = checkExists(ctx, tx, "delivery webhook", "webhooks.webhooks", ldelivery.WebhookId)
+
+		if nil == err {
+			ldelivery.Id, err = generateId(ctx, tx, "webhooks.deliveries")
+		}
+
+		if nil == err {
+			_, err = tx.NamedExecContext(ctx, `
+                insert into webhooks.deliveries (
+                    id, webhook_id, event_id, event_type, payload, status,
+                    attempts, status_code, error, last_attempt, next_attempt,
+                    creation_date
+                ) values (
+                    :id, :webhook_id, :event_id, :event_type, :payload,
+                    :status, :attempts, :status_code, :error, :last_attempt,
+                    :next_attempt, :creation_date
+                ) on conflict (webhook_id, event_id) do nothing
+            `, ldelivery)
+		}
+	}
+
+	if nil == err {
+		err = tx.Commit()
+	} else if nil != tx {
+		tx.Rollback()
+	}
+
+	return err
+}
+
+func (self *Repository) UpdateDelivery(
+	ctx context.Context,
+	delivery models.WebhookDelivery,
+) (models.WebhookDelivery, error) {
+	res, err := self.db.NamedExecContext(ctx, `
+        update webhooks.deliveries
+        set status = :status,
+            attempts = :attempts,
+            status_code = :status_code,
+            error = :error,
+            last_attempt = :last_attempt,
+            next_attempt = :next_attempt
+        where id = :id and webhook_id = :webhook_id and event_id = :event_id
+    `, unmapDelivery(&delivery))
+
+	if nil == err {
+		var affected int64
+
+		if affected, err = res.RowsAffected(); nil == err && 0 == affected {
+			err = repoerrors.NotFound("delivery")
+		}
+	}
+
+	return delivery, err
+}
+
+func (self *Repository) GetDeliveriesById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.WebhookDelivery]], error) {
+	if 0 == len(ids) {
+		return collection.EmptyCollection[result.Result[models.WebhookDelivery]](), nil
+	}
+
+	return collection.Map(newPeekCollection[qDelivery](ids, func(ids []uuid.UUID) (*sqlx.Rows, error) {
+		return self.db.QueryxContext(ctx, `
+            select deliveries.*, orderer.ord
+            from webhooks.deliveries
+            right outer join (values `+generateOrder(ids)+`) as orderer (id, ord)
+                on deliveries.id = orderer.id
+            order by orderer.ord
+        `)
+	}), result.OkMapper(mapQDelivery)), nil
+}
+
+func (self *Repository) GetDeliveriesByWebhookId(
+	ctx context.Context,
+	webhookId uuid.UUID,
+) (collection.Collection[result.Result[models.WebhookDelivery]], error) {
+	if err := checkExists(ctx, self.db, "webhook", "webhooks.webhooks", webhookId); nil != err {
+		return nil, err
+	}
+
+	return collection.Map(newCollection[Delivery](
+		func(
+			after *nullable.Nullable[uuid.UUID],
+			limit *nullable.Nullable[uint],
+		) (*sqlx.Rows, error) {
+			builder := strings.Builder{}
+			args := make([]any, 1, 3)
+			cnt := 2
+
+			fmt.Fprint(&builder,
+				"select * from webhooks.deliveries where webhook_id = $1",
+			)
+			args[0] = webhookId
+
+			nullable.IfSome(after, func(id *uuid.UUID) {
+				fmt.Fprintf(&builder, `
+                    and (creation_date, id) < (
+                        select creation_date, id
+                        from webhooks.deliveries
+                        where id = $%v
+                    )`, cnt)
+				cnt++
+				args = append(args, *id)
+			})
+
+			fmt.Fprint(&builder, " order by creation_date desc, id desc")
+
+			nullable.IfSome(limit, func(sz *uint) {
+				fmt.Fprintf(&builder, " limit $%v", cnt)
+				args = append(args, *sz)
+			})
+
+			return self.db.QueryxContext(ctx, builder.String(), args...)
+		},
+		func(id uuid.UUID) error {
+			return checkExists(ctx, self.db, "delivery", "webhooks.deliveries", id)
+		},
+	), result.OkMapper(mapDelivery)), nil
+}
+
+func (self *Repository) GetDueDeliveries(
+	ctx context.Context,
+	until time.Time,
+) (collection.Collection[result.Result[models.WebhookDelivery]], error) {
+	return collection.Map(newCollection[Delivery](
+		func(
+			after *nullable.Nullable[uuid.UUID],
+			limit *nullable.Nullable[uint],
+		) (*sqlx.Rows, error) {
+			builder := strings.Builder{}
+			args := make([]any, 2, 4)
+			cnt := 3
+
+			fmt.Fprint(&builder, `
+                select * from webhooks.deliveries
+                where status = $1 and next_attempt <= $2`)
+			args[0] = uint(models.DELIVERY_STATUS_PENDING)
+			args[1] = until
+
+			nullable.IfSome(after, func(id *uuid.UUID) {
+				fmt.Fprintf(&builder, `
+                    and (next_attempt, id) > (
+                        select next_attempt, id
+                        from webhooks.deliveries
+                        where id = $%v
+                    )`, cnt)
+				cnt++
+				args = append(args, *id)
+			})
+
+			fmt.Fprint(&builder, " order by next_attempt, id")
+
+			nullable.IfSome(limit, func(sz *uint) {
+				fmt.Fprintf(&builder, " limit $%v", cnt)
+				args = append(args, *sz)
+			})
+
+			return self.db.QueryxContext(ctx, builder.String(), args...)
+		},
+		func(id uuid.UUID) error {
+			return checkExists(ctx, self.db, "delivery", "webhooks.deliveries", id)
+		},
+	), result.OkMapper(mapDelivery)), nil
+}
+
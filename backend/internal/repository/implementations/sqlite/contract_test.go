@@ -0,0 +1,47 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/sqlite"
+	"github.com/muji40k/ozontestcomms/test/contract"
+	"github.com/stretchr/testify/require"
+)
+
+// Pool is opened here rather than by the builder, so that its
+// connections can be checked
+func TestRepositoryContract(t *testing.T) {
+	contract.RunRepository(t, func(t *testing.T) contract.Subject {
+		path := filepath.Join(t.TempDir(), "poster.db")
+		db, err := sqlx.Connect("sqlite", "file:"+path+
+			"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_txlock=immediate")
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		require.NoError(t, sqlite.Migrate(context.Background(), db))
+		require.NoError(t, sqlite.Seed(context.Background(), db))
+
+		repo := sqlite.NewRepository(db).WithOutbox()
+
+		return contract.Subject{
+			Comment:      repo,
+			Post:         repo,
+			User:         repo,
+			Outbox:       repo,
+			Webhook:      repo,
+			Notification: repo,
+			Follow:       repo,
+			Moderation:   repo,
+			Author: models.User{
+				Id:       SEED_USER,
+				Email:    "aboba@mail.com",
+				Password: "asdf",
+			},
+			Pool: db.DB,
+		}
+	})
+}
+
@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// Follows of a kind are kept in their own table, the target column refers
+// to the followed table and is matched against the column of posts
+type followTable struct {
+	name   string
+	column string
+	target string
+	what   string
+	match  string
+}
+
+func mapFollowKind(kind models.FollowKind) followTable {
+	switch kind {
+	case models.FOLLOW_KIND_AUTHOR:
+		return followTable{"followed_authors", "author_id", "users", "followed author", "author_id"}
+	case models.FOLLOW_KIND_POST:
+		return followTable{"followed_posts", "post_id", "posts", "followed post", "id"}
+	default:
+		panic("Unknown variant")
+	}
+}
+
+func (self *Repository) CreateFollow(
+	ctx context.Context,
+	follow models.Follow,
+) (bool, error) {
+	var res sql.Result
+	var affected int64
+	table := mapFollowKind(follow.Kind)
+	tx, err := self.db.BeginTxx(ctx, nil)
+
+	if nil == err {
+		err = checkExists(ctx, tx, "follower", "users", follow.UserId)
+	}
+
+	if nil == err {
+		err = checkExists(ctx, tx, table.what, table.target, follow.TargetId)
+	}
+
+	if nil == err {
+		res, err = tx.ExecContext(ctx, fmt.Sprintf(`
+            insert into %v (user_id, %v, creation_date) values (?, ?, ?)
+            on conflict do nothing
+        `, table.name, table.column), follow.UserId, follow.TargetId, newTimestamp(follow.CreationDate))
+	}
+
+	if nil == err {
+		affected, err = res.RowsAffected()
+	}
+
+	if nil == err {
+		err = tx.Commit()
+	} else if nil != tx {
+		tx.Rollback()
+	}
+
+	return 0 != affected && nil == err, err
+}
+
+func (self *Repository) DeleteFollow(
+	ctx context.Context,
+	userId uuid.UUID,
+	kind models.FollowKind,
+	targetId uuid.UUID,
+) (bool, error) {
+	var affected int64
+	table := mapFollowKind(kind)
+	res, err := self.db.ExecContext(ctx, fmt.Sprintf(
+		"delete from %v where user_id = ? and %v = ?", table.name, table.column,
+	), userId, targetId)
+
+	if nil == err {
+		affected, err = res.RowsAffected()
+	}
+
+	return 0 != affected, err
+}
+
+// Posts newest first, matching a target of the kind followed by the user
+func (self *Repository) followedPosts(
+	ctx context.Context,
+	userId uuid.UUID,
+	kind models.FollowKind,
+) collection.Collection[result.Result[models.Post]] {
+	table := mapFollowKind(kind)
+
+	return collection.Map(newCollection[Post](
+		func(
+			after *nullable.Nullable[uuid.UUID],
+			limit *nullable.Nullable[uint],
+		) (*sqlx.Rows, error) {
+			builder := strings.Builder{}
+			args := make([]any, 1, 3)
+
+			fmt.Fprintf(&builder, `
+                select posts.*, commentables.comments_allowed
+                from posts
+                join commentables
+                    on posts.commentable_id = commentables.id
+                where posts.%v in (
+                    select %v from %v where user_id = ?
+                )`, table.match, table.column, table.name)
+			args[0] = userId
+
+			nullable.IfSome(after, func(id *uuid.UUID) {
+				fmt.Fprint(&builder, `
+                    and (posts.creation_date, posts.id) < (
+                        select creation_date, id
+                        from posts
+                        where posts.id = ?
+                    )`)
+				args = append(args, *id)
+			})
+
+			fmt.Fprint(&builder, " order by posts.creation_date desc, posts.id desc")
+
+			nullable.IfSome(limit, func(sz *uint) {
+				fmt.Fprint(&builder, " limit ?")
+				args = append(args, *sz)
+			})
+
+			return self.db.QueryxContext(ctx, builder.String(), args...)
+		},
+		func(id uuid.UUID) error {
+			return checkExists(ctx, self.db, "post", "posts", id)
+		},
+	), result.OkMapper(mapPost))
+}
+
+func (self *Repository) GetFeed(
+	ctx context.Context,
+	userId uuid.UUID,
+) (collection.Collection[result.Result[models.Post]], error) {
+	return self.followedPosts(ctx, userId, models.FOLLOW_KIND_AUTHOR), nil
+}
+
+func (self *Repository) GetWatchedPosts(
+	ctx context.Context,
+	userId uuid.UUID,
+) (collection.Collection[result.Result[models.Post]], error) {
+	return self.followedPosts(ctx, userId, models.FOLLOW_KIND_POST), nil
+}
+
@@ -0,0 +1,7 @@
+insert or ignore into users(
+    id, email, password
+) values (
+    '9c3d7dba-d1b2-42de-b708-158e32f11623',
+    'aboba@mail.com',
+    'asdf'
+);
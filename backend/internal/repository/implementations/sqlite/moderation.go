@@ -0,0 +1,228 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+type Review struct {
+	Id              uuid.UUID     `db:"id"`
+	Kind            uint          `db:"kind"`
+	Action          uint          `db:"action"`
+	ContentId       uuid.NullUUID `db:"content_id"`
+	AuthorId        uuid.UUID     `db:"author_id"`
+	TargetId        uuid.NullUUID `db:"target_id"`
+	Title           string        `db:"title"`
+	Content         string        `db:"content"`
+	CommentsAllowed bool          `db:"comments_allowed"`
+	Reasons         string        `db:"reasons"`
+	CreationDate    timestamp     `db:"creation_date"`
+}
+
+type qReview struct {
+	Id              uuid.NullUUID  `db:"id"`
+	Kind            sql.NullInt64  `db:"kind"`
+	Action          sql.NullInt64  `db:"action"`
+	ContentId       uuid.NullUUID  `db:"content_id"`
+	AuthorId        uuid.NullUUID  `db:"author_id"`
+	TargetId        uuid.NullUUID  `db:"target_id"`
+	Title           sql.NullString `db:"title"`
+	Content         sql.NullString `db:"content"`
+	CommentsAllowed sql.NullBool   `db:"comments_allowed"`
+	Reasons         sql.NullString `db:"reasons"`
+	CreationDate    timestamp      `db:"creation_date"`
+	Ord             uint           `db:"ord"`
+}
+
+func (self qReview) check() bool {
+	return self.Id.Valid
+}
+
+func (self qReview) what() string {
+	return "review"
+}
+
+// Reasons are single lines
+func splitReasons(value string) []string {
+	if "" == value {
+		return []string{}
+	}
+
+	return strings.Split(value, "\n")
+}
+
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: uuid.Nil != id}
+}
+
+func mapReview(value *Review) models.Review {
+	return models.Review{
+		Id:              value.Id,
+		Kind:            models.ReviewKind(value.Kind),
+		Action:          models.ModerationAction(value.Action),
+		ContentId:       value.ContentId.UUID,
+		AuthorId:        value.AuthorId,
+		TargetId:        value.TargetId.UUID,
+		Title:           value.Title,
+		Content:         value.Content,
+		CommentsAllowed: value.CommentsAllowed,
+		Reasons:         splitReasons(value.Reasons),
+		CreationDate:    value.CreationDate.Time,
+	}
+}
+
+func unmapReview(value *models.Review) Review {
+	return Review{
+		Id:              value.Id,
+		Kind:            uint(value.Kind),
+		Action:          uint(value.Action),
+		ContentId:       nullUUID(value.ContentId),
+		AuthorId:        value.AuthorId,
+		TargetId:        nullUUID(value.TargetId),
+		Title:           value.Title,
+		Content:         value.Content,
+		CommentsAllowed: value.CommentsAllowed,
+		Reasons:         strings.Join(value.Reasons, "\n"),
+		CreationDate:    newTimestamp(value.CreationDate),
+	}
+}
+
+func mapQReview(value *qReview) models.Review {
+	return models.Review{
+		Id:              value.Id.UUID,
+		Kind:            models.ReviewKind(value.Kind.Int64),
+		Action:          models.ModerationAction(value.Action.Int64),
+		ContentId:       value.ContentId.UUID,
+		AuthorId:        value.AuthorId.UUID,
+		TargetId:        value.TargetId.UUID,
+		Title:           value.Title.String,
+		Content:         value.Content.String,
+		CommentsAllowed: value.CommentsAllowed.Bool,
+		Reasons:         splitReasons(value.Reasons.String),
+		CreationDate:    value.CreationDate.Time,
+	}
+}
+
+func (self *Repository) CreateReview(
+	ctx context.Context,
+	review models.Review,
+) (models.Review, error) {
+	lreview := unmapReview(&review)
+	tx, err := self.db.BeginTxx(ctx, nil)
+
+	if nil == err {
+		err = checkExists(ctx, tx, "review author", "users", lreview.AuthorId)
+	}
+
+	if nil == err {
+		lreview.Id, err = generateId(ctx, tx, "reviews")
+	}
+
+	if nil == err {
+		_, err = tx.NamedExecContext(ctx, `
+            insert into reviews (
+                id, kind, action, content_id, author_id, target_id, title,
+                content, comments_allowed, reasons, creation_date
+            ) values (
+                :id, :kind, :action, :content_id, :author_id, :target_id,
+                :title, :content, :comments_allowed, :reasons, :creation_date
+            )
+        `, lreview)
+	}
+
+	if nil == err {
+		err = tx.Commit()
+	}
+
+	if nil == err {
+		return mapReview(&lreview), nil
+	} else {
+		if nil != tx {
+			tx.Rollback()
+		}
+
+		return review, err
+	}
+}
+
+func (self *Repository) DeleteReview(ctx context.Context, id uuid.UUID) error {
+	res, err := self.db.ExecContext(ctx, "delete from reviews where id = ?", id)
+
+	if nil == err {
+		var affected int64
+
+		if affected, err = res.RowsAffected(); nil == err && 0 == affected {
+			err = repoerrors.NotFound("review")
+		}
+	}
+
+	return err
+}
+
+func (self *Repository) GetReviewsById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.Review]], error) {
+	if 0 == len(ids) {
+		return collection.EmptyCollection[result.Result[models.Review]](), nil
+	}
+
+	return collection.Map(newPeekCollection[qReview](ids, func(ids []uuid.UUID) (*sqlx.Rows, error) {
+		orderer, args := generateOrder(ids)
+
+		return self.db.QueryxContext(ctx, orderer+`
+            select reviews.*, orderer.ord
+            from orderer
+            left outer join reviews
+                on reviews.id = orderer.id
+            order by orderer.ord
+        `, args...)
+	}), result.OkMapper(mapQReview)), nil
+}
+
+func (self *Repository) GetReviews(
+	ctx context.Context,
+) (collection.Collection[result.Result[models.Review]], error) {
+	return collection.Map(newCollection[Review](
+		func(
+			after *nullable.Nullable[uuid.UUID],
+			limit *nullable.Nullable[uint],
+		) (*sqlx.Rows, error) {
+			builder := strings.Builder{}
+			args := make([]any, 0, 2)
+
+			fmt.Fprint(&builder, "select * from reviews")
+
+			nullable.IfSome(after, func(id *uuid.UUID) {
+				fmt.Fprint(&builder, `
+                    where (creation_date, id) > (
+                        select creation_date, id from reviews where id = ?
+                    )`)
+				args = append(args, *id)
+			})
+
+			fmt.Fprint(&builder, " order by creation_date, id")
+
+			nullable.IfSome(limit, func(sz *uint) {
+				fmt.Fprint(&builder, " limit ?")
+				args = append(args, *sz)
+			})
+
+			return self.db.QueryxContext(ctx, builder.String(), args...)
+		},
+		func(id uuid.UUID) error {
+			return checkExists(ctx, self.db, "review", "reviews", id)
+		},
+	), result.OkMapper(mapReview)), nil
+}
+
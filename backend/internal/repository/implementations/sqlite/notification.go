@@ -0,0 +1,243 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/notification"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+type Notification struct {
+	Id           uuid.UUID `db:"id"`
+	UserId       uuid.UUID `db:"user_id"`
+	Kind         uint      `db:"kind"`
+	ActorId      uuid.UUID `db:"actor_id"`
+	PostId       uuid.UUID `db:"post_id"`
+	CommentId    uuid.UUID `db:"comment_id"`
+	Read         bool      `db:"read"`
+	CreationDate timestamp `db:"creation_date"`
+}
+
+type qNotification struct {
+	Id           uuid.NullUUID `db:"id"`
+	UserId       uuid.NullUUID `db:"user_id"`
+	Kind         sql.NullInt64 `db:"kind"`
+	ActorId      uuid.NullUUID `db:"actor_id"`
+	PostId       uuid.NullUUID `db:"post_id"`
+	CommentId    uuid.NullUUID `db:"comment_id"`
+	Read         sql.NullBool  `db:"read"`
+	CreationDate timestamp     `db:"creation_date"`
+	Ord          uint          `db:"ord"`
+}
+
+func (self qNotification) check() bool {
+	return self.Id.Valid
+}
+
+func (self qNotification) what() string {
+	return "notification"
+}
+
+func mapNotification(value *Notification) models.Notification {
+	return models.Notification{
+		Id:           value.Id,
+		UserId:       value.UserId,
+		Kind:         models.NotificationKind(value.Kind),
+		ActorId:      value.ActorId,
+		PostId:       value.PostId,
+		CommentId:    value.CommentId,
+		Read:         value.Read,
+		CreationDate: value.CreationDate.Time,
+	}
+}
+
+func unmapNotification(value *models.Notification) Notification {
+	return Notification{
+		Id:           value.Id,
+		UserId:       value.UserId,
+		Kind:         uint(value.Kind),
+		ActorId:      value.ActorId,
+		PostId:       value.PostId,
+		CommentId:    value.CommentId,
+		Read:         value.Read,
+		CreationDate: newTimestamp(value.CreationDate),
+	}
+}
+
+func mapQNotification(value *qNotification) models.Notification {
+	return models.Notification{
+		Id:           value.Id.UUID,
+		UserId:       value.UserId.UUID,
+		Kind:         models.NotificationKind(value.Kind.Int64),
+		ActorId:      value.ActorId.UUID,
+		PostId:       value.PostId.UUID,
+		CommentId:    value.CommentId.UUID,
+		Read:         value.Read.Bool,
+		CreationDate: value.CreationDate.Time,
+	}
+}
+
+func mapNotificationOrder(order notification.NotificationOrder) (string, string) {
+	switch order {
+	case notification.NOTIFICATION_ORDER_DATE_ASC:
+		return "asc", ">"
+	case notification.NOTIFICATION_ORDER_DATE_DESC:
+		return "desc", "<"
+	default:
+		panic("Unknown variant")
+	}
+}
+
+func (self *Repository) CreateNotifications(
+	ctx context.Context,
+	notifications ...models.Notification,
+) error {
+	if 0 == len(notifications) {
+		return nil
+	}
+
+	tx, err := self.db.BeginTxx(ctx, nil)
+
+	for i := 0; nil == err && len(notifications) > i; i++ {
+		lnotification := unmapNotification(&notifications[i])
+		err = checkExists(ctx, tx, "notification user", "users", lnotification.UserId)
+
+		if nil == err {
+			lnotification.Id, err = generateId(ctx, tx, "notifications")
+		}
+
+		if nil == err {
+			_, err = tx.NamedExecContext(ctx, `
+                insert into notifications (
+                    id, user_id, kind, actor_id, post_id, comment_id, read,
+                    creation_date
+                ) values (
+                    :id, :user_id, :kind, :actor_id, :post_id, :comment_id,
+                    :read, :creation_date
+                ) on conflict (user_id, comment_id) do nothing
+            `, lnotification)
+		}
+	}
+
+	if nil == err {
+		err = tx.Commit()
+	} else if nil != tx {
+		tx.Rollback()
+	}
+
+	return err
+}
+
+func (self *Repository) MarkNotificationsRead(
+	ctx context.Context,
+	userId uuid.UUID,
+	ids ...uuid.UUID,
+) (int, error) {
+	var affected int64
+	var res sql.Result
+	var err error
+	query := "update notifications set read = true where user_id = ? and not read"
+	args := []any{userId}
+
+	if 0 != len(ids) {
+		query, args, err = sqlx.In(query+" and id in (?)", userId, ids)
+	}
+
+	if nil == err {
+		res, err = self.db.ExecContext(ctx, query, args...)
+	}
+
+	if nil == err {
+		affected, err = res.RowsAffected()
+	}
+
+	return int(affected), err
+}
+
+func (self *Repository) GetNotificationsById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.Notification]], error) {
+	if 0 == len(ids) {
+		return collection.EmptyCollection[result.Result[models.Notification]](), nil
+	}
+
+	return collection.Map(newPeekCollection[qNotification](ids, func(ids []uuid.UUID) (*sqlx.Rows, error) {
+		orderer, args := generateOrder(ids)
+
+		return self.db.QueryxContext(ctx, orderer+`
+            select notifications.*, orderer.ord
+            from orderer
+            left outer join notifications
+                on notifications.id = orderer.id
+            order by orderer.ord
+        `, args...)
+	}), result.OkMapper(mapQNotification)), nil
+}
+
+func (self *Repository) GetNotificationsByUserId(
+	ctx context.Context,
+	userId uuid.UUID,
+	unreadOnly bool,
+	order notification.NotificationOrder,
+) (collection.Collection[result.Result[models.Notification]], error) {
+	sort, rel := mapNotificationOrder(order)
+
+	return collection.Map(newCollection[Notification](
+		func(
+			after *nullable.Nullable[uuid.UUID],
+			limit *nullable.Nullable[uint],
+		) (*sqlx.Rows, error) {
+			builder := strings.Builder{}
+			args := make([]any, 1, 3)
+
+			fmt.Fprint(&builder, "select * from notifications where user_id = ?")
+			args[0] = userId
+
+			if unreadOnly {
+				fmt.Fprint(&builder, " and not read")
+			}
+
+			nullable.IfSome(after, func(id *uuid.UUID) {
+				fmt.Fprintf(&builder, `
+                    and (creation_date, id) %v (
+                        select creation_date, id from notifications where id = ?
+                    )`, rel)
+				args = append(args, *id)
+			})
+
+			fmt.Fprintf(&builder, " order by creation_date %v, id %v", sort, sort)
+
+			nullable.IfSome(limit, func(sz *uint) {
+				fmt.Fprint(&builder, " limit ?")
+				args = append(args, *sz)
+			})
+
+			return self.db.QueryxContext(ctx, builder.String(), args...)
+		},
+		func(id uuid.UUID) error {
+			return checkExists(ctx, self.db, "notification", "notifications", id)
+		},
+	), result.OkMapper(mapNotification)), nil
+}
+
+func (self *Repository) CountUnreadNotifications(
+	ctx context.Context,
+	userId uuid.UUID,
+) (int, error) {
+	var out int
+	err := self.db.GetContext(ctx, &out, `
+        select count(*) from notifications where user_id = ? and not read
+    `, userId)
+
+	return out, err
+}
+
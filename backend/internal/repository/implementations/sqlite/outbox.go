@@ -0,0 +1,168 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+type Event struct {
+	Seq          int64     `db:"seq"`
+	Id           uuid.UUID `db:"id"`
+	Type         string    `db:"type"`
+	EntityId     uuid.UUID `db:"entity_id"`
+	Payload      string    `db:"payload"`
+	CreationDate timestamp `db:"creation_date"`
+}
+
+func mapEvent(value *Event) models.Event {
+	return models.Event{
+		Id:           value.Id,
+		Type:         value.Type,
+		EntityId:     value.EntityId,
+		Payload:      []byte(value.Payload),
+		CreationDate: value.CreationDate.Time,
+	}
+}
+
+func unmapEvent(value *models.Event) Event {
+	return Event{
+		Id:           value.Id,
+		Type:         value.Type,
+		EntityId:     value.EntityId,
+		Payload:      string(value.Payload),
+		CreationDate: newTimestamp(value.CreationDate),
+	}
+}
+
+// Records events with every following write, in the transaction of the
+// write
+func (self *Repository) WithOutbox() *Repository {
+	self.outbox = true
+	return self
+}
+
+func (self *Repository) record(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	event func() (models.Event, error),
+) error {
+	if !self.outbox {
+		return nil
+	}
+
+	v, err := event()
+
+	if nil == err {
+		_, err = tx.NamedExecContext(ctx, `
+            insert into events (
+                id, type, entity_id, payload, creation_date
+            ) values (
+                :id, :type, :entity_id, :payload, :creation_date
+            )
+        `, unmapEvent(&v))
+	}
+
+	return err
+}
+
+// Post at the root of the thread and the comment replied to, if the target
+// belongs to one
+func thread(
+	ctx context.Context,
+	db sqlx.QueryerContext,
+	targetId uuid.UUID,
+) (uuid.UUID, *uuid.UUID, error) {
+	var post uuid.UUID
+	var parent *uuid.UUID
+
+	err := sqlx.GetContext(ctx, db, &post, `
+        with recursive thread (target_id) as (
+            values (?)
+            union all
+            select comments.target_id
+            from comments
+            join thread on comments.commentable_id = thread.target_id
+        )
+        select posts.id
+        from posts
+        join thread on posts.commentable_id = thread.target_id
+    `, targetId)
+
+	if nil == err {
+		var id uuid.UUID
+		err = sqlx.GetContext(ctx, db, &id,
+			"select id from comments where commentable_id = ?", targetId,
+		)
+
+		if nil == err {
+			parent = &id
+		} else if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
+	}
+
+	return post, parent, err
+}
+
+func (self *Repository) GetPendingEvents(
+	ctx context.Context,
+) (collection.Collection[result.Result[models.Event]], error) {
+	return collection.Map(newCollection[Event](
+		func(
+			after *nullable.Nullable[uuid.UUID],
+			limit *nullable.Nullable[uint],
+		) (*sqlx.Rows, error) {
+			builder := strings.Builder{}
+			args := make([]any, 0, 2)
+
+			fmt.Fprint(&builder, "select * from events")
+
+			nullable.IfSome(after, func(id *uuid.UUID) {
+				fmt.Fprint(&builder, " where seq > (select seq from events where id = ?)")
+				args = append(args, *id)
+			})
+
+			fmt.Fprint(&builder, " order by seq")
+
+			nullable.IfSome(limit, func(sz *uint) {
+				fmt.Fprint(&builder, " limit ?")
+				args = append(args, *sz)
+			})
+
+			return self.db.QueryxContext(ctx, builder.String(), args...)
+		},
+		func(id uuid.UUID) error {
+			return checkExists(ctx, self.db, "event", "events", id)
+		},
+	), result.OkMapper(mapEvent)), nil
+}
+
+func (self *Repository) AcknowledgeEvents(ctx context.Context, ids ...uuid.UUID) error {
+	if 0 == len(ids) {
+		return nil
+	}
+
+	args := make([]any, len(ids))
+
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	_, err := self.db.ExecContext(ctx,
+		"delete from events where id in (?"+strings.Repeat(", ?", len(ids)-1)+")",
+		args...,
+	)
+
+	return err
+}
+
@@ -0,0 +1,628 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+// Same model as psql repository: posts and comments own a commentable,
+// which comments refer to as their target
+type Repository struct {
+	db *sqlx.DB
+	// Writes record their events to the events table in the same
+	// transaction
+	outbox bool
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{db, false}
+}
+
+func notFound(err error, what string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return repoerrors.NotFound(what)
+	}
+
+	return err
+}
+
+func generateId(
+	ctx context.Context,
+	db sqlx.QueryerContext,
+	where string,
+) (uuid.UUID, error) {
+	id, err := uuid.NewRandom()
+
+	for found := true; nil == err && found; {
+		err = sqlx.GetContext(ctx, db, &found,
+			fmt.Sprintf("select exists(select * from %v where id = ?)", where),
+			id,
+		)
+
+		if nil == err && found {
+			id, err = uuid.NewRandom()
+		}
+	}
+
+	return id, err
+}
+
+func checkExists(
+	ctx context.Context,
+	db sqlx.QueryerContext,
+	what string,
+	where string,
+	id uuid.UUID,
+) error {
+	var found bool
+
+	err := sqlx.GetContext(ctx, db, &found,
+		fmt.Sprintf("select exists(select * from %v where id = ?)", where),
+		id,
+	)
+
+	if nil == err && !found {
+		err = repoerrors.NotFound(what)
+	}
+
+	return err
+}
+
+func getComment(
+	ctx context.Context,
+	db sqlx.QueryerContext,
+	id uuid.UUID,
+) (Comment, error) {
+	var out Comment
+
+	err := sqlx.GetContext(ctx, db, &out,
+		"select * from comments where id = ?", id,
+	)
+
+	return out, notFound(err, "comment")
+}
+
+func getPost(
+	ctx context.Context,
+	db sqlx.QueryerContext,
+	id uuid.UUID,
+) (Post, error) {
+	var out Post
+
+	err := sqlx.GetContext(ctx, db, &out, `
+        select posts.*, commentables.comments_allowed
+        from posts
+        join commentables
+            on posts.commentable_id = commentables.id
+        where posts.id = ?
+    `, id)
+
+	return out, notFound(err, "post")
+}
+
+// Rows of (id, position) to keep requested order, with arguments for them
+func generateOrder(ids []uuid.UUID) (string, []any) {
+	order := make([]string, len(ids))
+	args := make([]any, len(ids))
+
+	for i, id := range ids {
+		order[i] = fmt.Sprintf("(?, %v)", i)
+		args[i] = id
+	}
+
+	return "with orderer (id, ord) as (values " + strings.Join(order, ", ") + ")", args
+}
+
+func createCommentable(
+	ctx context.Context,
+	db sqlx.ExtContext,
+	comments sql.NullBool,
+) (Commentable, error) {
+	out := Commentable{
+		CommentsAllowed: comments,
+	}
+	var err error
+
+	out.Id, err = generateId(ctx, db, "commentables")
+
+	if nil == err {
+		_, err = sqlx.NamedExecContext(ctx, db, `
+            insert into commentables (
+                id, comments_allowed
+            ) values (
+                :id, :comments_allowed
+            )
+        `, out)
+	}
+
+	return out, err
+}
+
+func (self *Repository) createComment(
+	ctx context.Context,
+	comment models.Comment,
+	target func(*sqlx.Tx) (uuid.UUID, error),
+) (models.Comment, error) {
+	lcomment := unmapComment(&comment)
+	tx, err := self.db.BeginTxx(ctx, nil)
+
+	if nil == err {
+		lcomment.TargetId, err = target(tx)
+	}
+
+	if nil == err {
+		lcomment.Id, err = generateId(ctx, tx, "comments")
+	}
+
+	if nil == err {
+		var comm Commentable
+		comm, err = createCommentable(ctx, tx, sql.NullBool{})
+		lcomment.CommentableId = comm.Id
+	}
+
+	if nil == err {
+		_, err = tx.NamedExecContext(ctx, `
+            insert into comments (
+                id, author_id, commentable_id, target_id, content,
+                creation_date
+            ) values (
+                :id, :author_id, :commentable_id, :target_id, :content,
+                :creation_date
+            )
+        `, lcomment)
+	}
+
+	if nil == err {
+		err = self.record(ctx, tx, func() (models.Event, error) {
+			post, parent, err := thread(ctx, tx, lcomment.TargetId)
+
+			if nil != err {
+				return models.Event{}, err
+			}
+
+			created := mapComment(&lcomment)
+			return events.CommentCreated(&created, post, parent)
+		})
+	}
+
+	if nil == err {
+		err = tx.Commit()
+	}
+
+	if nil == err {
+		comment.Id = lcomment.Id
+		comment.TargetId = lcomment.TargetId
+
+		return comment, nil
+	} else {
+		if nil != tx {
+			tx.Rollback()
+		}
+
+		return comment, err
+	}
+}
+
+func (self *Repository) CreatePostComment(
+	ctx context.Context,
+	comment models.Comment,
+) (models.Comment, error) {
+	return self.createComment(ctx, comment, func(tx *sqlx.Tx) (uuid.UUID, error) {
+		post, err := getPost(ctx, tx, comment.TargetId)
+		return post.CommentableId, err
+	})
+}
+
+func (self *Repository) CreateCommentComment(
+	ctx context.Context,
+	comment models.Comment,
+) (models.Comment, error) {
+	return self.createComment(ctx, comment, func(tx *sqlx.Tx) (uuid.UUID, error) {
+		root, err := getComment(ctx, tx, comment.TargetId)
+		return root.CommentableId, err
+	})
+}
+
+func (self *Repository) GetCommentsById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.Comment]], error) {
+	if 0 == len(ids) {
+		return collection.EmptyCollection[result.Result[models.Comment]](), nil
+	}
+
+	return collection.Map(newPeekCollection[qComment](ids, func(ids []uuid.UUID) (*sqlx.Rows, error) {
+		orderer, args := generateOrder(ids)
+
+		return self.db.QueryxContext(ctx, orderer+`
+            select comments.*, orderer.ord
+            from orderer
+            left outer join comments
+                on comments.id = orderer.id
+            order by orderer.ord
+        `, args...)
+	}), result.OkMapper(mapQComment)), nil
+}
+
+func (self *Repository) getCommentsByTarget(
+	ctx context.Context,
+	targetId uuid.UUID,
+	order comment.CommentOrder,
+) (collection.Collection[result.Result[models.Comment]], error) {
+	sort, rel := mapCommentOrder(order)
+
+	return collection.Map(newCollection[Comment](
+		func(
+			after *nullable.Nullable[uuid.UUID],
+			limit *nullable.Nullable[uint],
+		) (*sqlx.Rows, error) {
+			builder := strings.Builder{}
+			args := make([]any, 1, 3)
+
+			fmt.Fprint(&builder, "select * from comments where comments.target_id = ?")
+			args[0] = targetId
+
+			nullable.IfSome(after, func(id *uuid.UUID) {
+				fmt.Fprintf(&builder, `
+                        and (comments.creation_date, comments.id) %v (
+                            select creation_date, id
+                            from comments
+                            where comments.id = ?
+                        )`, rel)
+				args = append(args, *id)
+			})
+
+			fmt.Fprintf(&builder,
+				" order by comments.creation_date %v, comments.id %v", sort, sort,
+			)
+
+			nullable.IfSome(limit, func(sz *uint) {
+				fmt.Fprint(&builder, " limit ?")
+				args = append(args, *sz)
+			})
+
+			return self.db.QueryxContext(ctx, builder.String(), args...)
+		},
+		func(id uuid.UUID) error {
+			var found bool
+
+			err := self.db.GetContext(ctx, &found, `
+                    select exists(
+                        select *
+                        from comments
+                        where comments.id = ? and comments.target_id = ?
+                    )
+                `, id, targetId)
+
+			if nil == err && !found {
+				err = repoerrors.NotFound("comment")
+			}
+
+			return err
+		},
+	), result.OkMapper(mapComment)), nil
+}
+
+func (self *Repository) GetCommentsByPostId(
+	ctx context.Context,
+	postId uuid.UUID,
+	order comment.CommentOrder,
+) (collection.Collection[result.Result[models.Comment]], error) {
+	post, err := getPost(ctx, self.db, postId)
+
+	if nil != err {
+		return nil, err
+	} else {
+		return self.getCommentsByTarget(ctx, post.CommentableId, order)
+	}
+}
+
+func (self *Repository) GetCommentsByCommentId(
+	ctx context.Context,
+	commentId uuid.UUID,
+	order comment.CommentOrder,
+) (collection.Collection[result.Result[models.Comment]], error) {
+	comment, err := getComment(ctx, self.db, commentId)
+
+	if nil != err {
+		return nil, err
+	} else {
+		return self.getCommentsByTarget(ctx, comment.CommentableId, order)
+	}
+}
+
+func (self *Repository) CreateUser(
+	ctx context.Context,
+	user models.User,
+) (models.User, error) {
+	var found bool
+	luser := unmapUser(&user)
+	tx, err := self.db.BeginTxx(ctx, nil)
+
+	if nil == err {
+		err = tx.GetContext(ctx, &found,
+			"select exists(select * from users where email = ?)", luser.Email,
+		)
+	}
+
+	if nil == err && found {
+		err = repoerrors.Duplicate("user email")
+	}
+
+	if nil == err {
+		luser.Id, err = generateId(ctx, tx, "users")
+	}
+
+	if nil == err {
+		_, err = tx.NamedExecContext(ctx, `
+            insert into users (
+                id, email, password
+            ) values (
+                :id, :email, :password
+            )
+        `, luser)
+	}
+
+	if nil == err {
+		err = self.record(ctx, tx, func() (models.Event, error) {
+			created := user
+			created.Id = luser.Id
+			return events.UserCreated(&created)
+		})
+	}
+
+	if nil == err {
+		err = tx.Commit()
+	}
+
+	if nil == err {
+		user.Id = luser.Id
+		return user, nil
+	} else {
+		if nil != tx {
+			tx.Rollback()
+		}
+
+		return user, err
+	}
+}
+
+func (self *Repository) GetUsersById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.User]], error) {
+	if 0 == len(ids) {
+		return collection.EmptyCollection[result.Result[models.User]](), nil
+	}
+
+	return collection.Map(newPeekCollection[qUser](ids, func(ids []uuid.UUID) (*sqlx.Rows, error) {
+		orderer, args := generateOrder(ids)
+
+		return self.db.QueryxContext(ctx, orderer+`
+            select users.*, orderer.ord
+            from orderer
+            left outer join users
+                on users.id = orderer.id
+            order by orderer.ord
+        `, args...)
+	}), result.OkMapper(mapQUser)), nil
+}
+
+func (self *Repository) GetUsersByEmail(
+	ctx context.Context,
+	emails ...string,
+) (collection.Collection[result.Result[models.User]], error) {
+	var ids []uuid.UUID
+
+	if 0 == len(emails) {
+		return collection.EmptyCollection[result.Result[models.User]](), nil
+	}
+
+	query, args, err := sqlx.In("select id from users where email in (?)", emails)
+
+	if nil == err {
+		err = self.db.SelectContext(ctx, &ids, self.db.Rebind(query), args...)
+	}
+
+	if nil != err {
+		return nil, err
+	}
+
+	return self.GetUsersById(ctx, ids...)
+}
+
+func (self *Repository) CreatePost(
+	ctx context.Context,
+	post models.Post,
+) (models.Post, error) {
+	lpost := unmapPost(&post)
+	tx, err := self.db.BeginTxx(ctx, nil)
+
+	if nil == err {
+		lpost.Id, err = generateId(ctx, tx, "posts")
+	}
+
+	if nil == err {
+		var comm Commentable
+		comm, err = createCommentable(ctx, tx, lpost.CommentsAllowed)
+		lpost.CommentableId = comm.Id
+	}
+
+	if nil == err {
+		_, err = tx.NamedExecContext(ctx, `
+            insert into posts (
+                id, author_id, commentable_id, title, content,
+                creation_date
+            ) values (
+                :id, :author_id, :commentable_id, :title, :content,
+                :creation_date
+            )
+        `, lpost)
+	}
+
+	if nil == err {
+		err = self.record(ctx, tx, func() (models.Event, error) {
+			created := post
+			created.Id = lpost.Id
+			return events.PostCreated(&created)
+		})
+	}
+
+	if nil == err {
+		err = tx.Commit()
+	}
+
+	if nil == err {
+		post.Id = lpost.Id
+		return post, nil
+	} else {
+		if nil != tx {
+			tx.Rollback()
+		}
+
+		return post, err
+	}
+}
+
+func (self *Repository) GetPosts(
+	ctx context.Context,
+	order post.PostOrder,
+) (collection.Collection[result.Result[models.Post]], error) {
+	sort, rel := mapPostOrder(order)
+
+	return collection.Map(newCollection[Post](
+		func(
+			after *nullable.Nullable[uuid.UUID],
+			limit *nullable.Nullable[uint],
+		) (*sqlx.Rows, error) {
+			builder := strings.Builder{}
+			args := make([]any, 0, 2)
+
+			fmt.Fprint(&builder, `
+                select posts.*, commentables.comments_allowed
+                from posts
+                join commentables
+                    on posts.commentable_id = commentables.id
+            `)
+
+			nullable.IfSome(after, func(id *uuid.UUID) {
+				fmt.Fprintf(&builder, `
+                        where (posts.creation_date, posts.id) %v (
+                            select creation_date, id
+                            from posts
+                            where posts.id = ?
+                        )`, rel)
+				args = append(args, *id)
+			})
+
+			fmt.Fprintf(&builder,
+				" order by posts.creation_date %v, posts.id %v", sort, sort,
+			)
+
+			nullable.IfSome(limit, func(sz *uint) {
+				fmt.Fprint(&builder, " limit ?")
+				args = append(args, *sz)
+			})
+
+			return self.db.QueryxContext(ctx, builder.String(), args...)
+		},
+		func(id uuid.UUID) error {
+			return checkExists(ctx, self.db, "post", "posts", id)
+		},
+	), result.OkMapper(mapPost)), nil
+}
+
+func (self *Repository) GetPostsById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.Post]], error) {
+	if 0 == len(ids) {
+		return collection.EmptyCollection[result.Result[models.Post]](), nil
+	}
+
+	return collection.Map(newPeekCollection[qPost](ids, func(ids []uuid.UUID) (*sqlx.Rows, error) {
+		orderer, args := generateOrder(ids)
+
+		return self.db.QueryxContext(ctx, orderer+`
+            select posts.*, commentables.comments_allowed, orderer.ord
+            from orderer
+            left outer join posts
+                on posts.id = orderer.id
+            left outer join commentables
+                on posts.commentable_id = commentables.id
+            order by orderer.ord
+        `, args...)
+	}), result.OkMapper(mapQPost)), nil
+}
+
+func (self *Repository) UpdatePost(
+	ctx context.Context,
+	post models.Post,
+) (models.Post, error) {
+	lpost := unmapPost(&post)
+	tx, err := self.db.BeginTxx(ctx, nil)
+
+	if nil == err {
+		var cpost Post
+		cpost, err = getPost(ctx, tx, lpost.Id)
+		lpost.CommentableId = cpost.CommentableId
+	}
+
+	if nil == err {
+		_, err = tx.NamedExecContext(ctx, `
+            update posts
+            set author_id = :author_id,
+                title = :title,
+                content = :content,
+                creation_date = :creation_date
+            where id = :id
+        `, lpost)
+	}
+
+	if nil == err {
+		_, err = tx.NamedExecContext(ctx, `
+            update commentables
+            set comments_allowed = :comments_allowed
+            where id = :commentable_id
+        `, lpost)
+	}
+
+	if nil == err {
+		err = self.record(ctx, tx, func() (models.Event, error) {
+			return events.PostUpdated(&post)
+		})
+	}
+
+	if nil == err {
+		err = tx.Commit()
+	}
+
+	if nil == err {
+		return post, nil
+	} else {
+		if nil != tx {
+			tx.Rollback()
+		}
+
+		return post, err
+	}
+}
+
+func (self *Repository) Ping(ctx context.Context) error {
+	return self.db.PingContext(ctx)
+}
+
@@ -0,0 +1,146 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	sqlitebuilder "github.com/muji40k/ozontestcomms/builders/repositories/sqlite"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/sqlite"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var SEED_USER = uuid.MustParse("9c3d7dba-d1b2-42de-b708-158e32f11623")
+
+func open(t *testing.T) *sqlite.Repository {
+	repo, clear, err := sqlitebuilder.NewRepositoryBuilder().
+		WithPath(filepath.Join(t.TempDir(), "poster.db")).
+		WithSeed().
+		Build()
+	require.NoError(t, err)
+	t.Cleanup(clear)
+
+	return repo
+}
+
+func at(minutes int) *nullable.Nullable[time.Time] {
+	return nullable.Some(time.Date(2025, 1, 1, 12, minutes, 0, 0, time.UTC))
+}
+
+func createPost(t *testing.T, repo *sqlite.Repository, minutes int) models.Post {
+	return common.Unwrap(repo.CreatePost(context.Background(), common.Unwrap(
+		domainOM.PostDefault(SEED_USER, nullable.None[bool](), nullable.None[string](), at(minutes)).Build(),
+	)))
+}
+
+func TestSqliteCreateAndGetPost(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := open(t)
+	created := createPost(t, repo, 0)
+	missing := uuid.New()
+
+	// Act
+	created.CommentsAllowed = false
+	updated, uerr := repo.UpdatePost(ctx, created)
+	found, err := pagination.Collect(common.Unwrap(repo.GetPostsById(ctx, created.Id, missing)))
+
+	// Assert
+	require.NoError(t, uerr)
+	assert.Equal(t, created, updated)
+	assert.Nil(t, found)
+	assert.ErrorAs(t, err, &repoerrors.ErrorNotFound{})
+	posts := common.Unwrap(repo.GetPostsById(ctx, created.Id))
+	assert.Equal(t, []models.Post{created}, common.Unwrap(pagination.Collect(posts)))
+}
+
+func TestSqlitePostsPagination(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := open(t)
+	first := createPost(t, repo, 0)
+	second := createPost(t, repo, 1)
+	third := createPost(t, repo, 2)
+	col := common.Unwrap(repo.GetPosts(ctx, post.POST_ORDER_DATE_DESC))
+
+	// Act
+	err := col.After(third.Id)
+	col.Limit(1)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []models.Post{second}, common.Unwrap(pagination.Collect(col)))
+	all := common.Unwrap(repo.GetPosts(ctx, post.POST_ORDER_DATE_ASC))
+	assert.Equal(t, []models.Post{first, second, third}, common.Unwrap(pagination.Collect(all)))
+}
+
+func TestSqliteCommentTree(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := open(t)
+	created := createPost(t, repo, 0)
+	root := common.Unwrap(repo.CreatePostComment(ctx, common.Unwrap(
+		domainOM.CommentDefault(SEED_USER, created.Id, nullable.None[string](), at(1)).Build(),
+	)))
+
+	// Act
+	reply, err := repo.CreateCommentComment(ctx, common.Unwrap(
+		domainOM.CommentDefault(SEED_USER, root.Id, nullable.None[string](), at(2)).Build(),
+	))
+	_, missing := repo.CreateCommentComment(ctx, common.Unwrap(
+		domainOM.CommentDefault(SEED_USER, uuid.New(), nullable.None[string](), at(2)).Build(),
+	))
+
+	// Assert
+	require.NoError(t, err)
+	assert.ErrorAs(t, missing, &repoerrors.ErrorNotFound{})
+	roots := common.Unwrap(repo.GetCommentsByPostId(ctx, created.Id, comment.COMMENT_ORDER_DATE_ASC))
+	assert.Equal(t, []models.Comment{root}, common.Unwrap(pagination.Collect(roots)))
+	replies := common.Unwrap(repo.GetCommentsByCommentId(ctx, root.Id, comment.COMMENT_ORDER_DATE_ASC))
+	assert.Equal(t, []models.Comment{reply}, common.Unwrap(pagination.Collect(replies)))
+	byId := common.Unwrap(repo.GetCommentsById(ctx, reply.Id, root.Id))
+	assert.Equal(t, []models.Comment{reply, root}, common.Unwrap(pagination.Collect(byId)))
+}
+
+func TestSqliteUnknownAuthor(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := open(t)
+	value := common.Unwrap(domainOM.PostDefault(
+		uuid.New(), nullable.None[bool](), nullable.None[string](), at(0),
+	).Build())
+
+	// Act
+	_, err := repo.CreatePost(ctx, value)
+
+	// Assert
+	assert.Error(t, err)
+	posts := common.Unwrap(repo.GetPosts(ctx, post.POST_ORDER_DATE_ASC))
+	assert.Empty(t, common.Unwrap(pagination.Collect(posts)), "Transaction is rolled back")
+}
+
+func TestSqliteUsersById(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := open(t)
+
+	// Act
+	users, err := pagination.Collect(common.Unwrap(repo.GetUsersById(ctx, SEED_USER)))
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "aboba@mail.com", users[0].Email)
+}
+
@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed schema.sql
+var schema string
+
+//go:embed initials.sql
+var initials string
+
+func init() {
+	sqlx.BindDriver("sqlite", sqlx.QUESTION)
+}
+
+// Creates missing tables, existing content is kept
+func Migrate(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, schema)
+	return err
+}
+
+// Same initial content as the psql deployment has
+func Seed(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, initials)
+	return err
+}
+
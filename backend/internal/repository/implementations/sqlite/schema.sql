@@ -0,0 +1,139 @@
+create table if not exists users
+(
+    id text primary key,
+    email text not null unique,
+    password text not null
+);
+
+create table if not exists commentables
+(
+    id text primary key,
+    comments_allowed boolean
+);
+
+create table if not exists posts
+(
+    id text primary key,
+    author_id text not null references users(id),
+    commentable_id text not null references commentables(id),
+    title text not null check (length(title) <= 1000),
+    content text not null check (length(content) <= 4000),
+    creation_date integer not null
+);
+
+create index if not exists posts_creation_date on posts(creation_date);
+
+create table if not exists comments
+(
+    id text primary key,
+    author_id text not null references users(id),
+    commentable_id text not null references commentables(id),
+    target_id text not null references commentables(id),
+    content text not null check (length(content) <= 2000),
+    creation_date integer not null
+);
+
+create index if not exists comments_target_creation_date
+    on comments(target_id, creation_date);
+
+-- Pending domain events, seq keeps the order of writes
+create table if not exists events
+(
+    seq integer primary key autoincrement,
+    id text not null unique,
+    type text not null,
+    entity_id text not null,
+    payload text not null,
+    creation_date integer not null
+);
+
+-- Subscriptions to events, events are comma separated
+create table if not exists webhooks
+(
+    id text primary key,
+    owner_id text not null references users(id),
+    url text not null,
+    secret text not null,
+    events text not null,
+    post_id text references posts(id),
+    creation_date integer not null
+);
+
+create index if not exists webhooks_owner_creation_date
+    on webhooks(owner_id, creation_date);
+
+create table if not exists deliveries
+(
+    id text primary key,
+    webhook_id text not null references webhooks(id) on delete cascade,
+    event_id text not null,
+    event_type text not null,
+    payload text not null,
+    status integer not null,
+    attempts integer not null,
+    status_code integer not null,
+    error text not null,
+    last_attempt integer,
+    next_attempt integer not null,
+    creation_date integer not null,
+    unique (webhook_id, event_id)
+);
+
+create index if not exists deliveries_webhook_creation_date
+    on deliveries(webhook_id, creation_date);
+
+create index if not exists deliveries_pending_next_attempt
+    on deliveries(next_attempt) where status = 0;
+
+-- Comments users are notified about, once per user and comment
+create table if not exists notifications
+(
+    id text primary key,
+    user_id text not null references users(id),
+    kind integer not null,
+    actor_id text not null references users(id),
+    post_id text not null references posts(id),
+    comment_id text not null references comments(id),
+    read boolean not null,
+    creation_date integer not null,
+    unique (user_id, comment_id)
+);
+
+create index if not exists notifications_user_creation_date
+    on notifications(user_id, creation_date);
+
+-- Authors and posts followed by users
+create table if not exists followed_authors
+(
+    user_id text not null references users(id),
+    author_id text not null references users(id),
+    creation_date integer not null,
+    primary key (user_id, author_id)
+);
+
+create table if not exists followed_posts
+(
+    user_id text not null references users(id),
+    post_id text not null references posts(id),
+    creation_date integer not null,
+    primary key (user_id, post_id)
+);
+
+-- Content waiting for moderators, hidden content is kept only here
+create table if not exists reviews
+(
+    id text primary key,
+    kind integer not null,
+    action integer not null,
+    content_id text,
+    author_id text not null references users(id),
+    target_id text,
+    title text not null,
+    content text not null,
+    comments_allowed boolean not null,
+    reasons text not null,
+    creation_date integer not null
+);
+
+create index if not exists reviews_creation_date
+    on reviews(creation_date);
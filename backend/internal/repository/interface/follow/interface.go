@@ -0,0 +1,26 @@
+package follow
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../implementations/mock/follow/repository.go
+
+type Repository interface {
+	// Returns false if the target is followed already, the follow is kept
+	// as it was then
+	CreateFollow(ctx context.Context, follow models.Follow) (bool, error)
+	// Returns false if the target isn't followed
+	DeleteFollow(ctx context.Context, userId uuid.UUID, kind models.FollowKind, targetId uuid.UUID) (bool, error)
+
+	// Posts of followed authors, newest first
+	GetFeed(ctx context.Context, userId uuid.UUID) (collection.Collection[result.Result[models.Post]], error)
+	// Followed posts, newest first
+	GetWatchedPosts(ctx context.Context, userId uuid.UUID) (collection.Collection[result.Result[models.Post]], error)
+}
+
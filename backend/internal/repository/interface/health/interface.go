@@ -0,0 +1,10 @@
+package health
+
+import "context"
+
+// Optional capability of a repository: report whether the underlying storage
+// is reachable. Repositories without it are considered always available.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
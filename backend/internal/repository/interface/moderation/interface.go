@@ -0,0 +1,23 @@
+package moderation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../implementations/mock/moderation/repository.go
+
+type Repository interface {
+	CreateReview(ctx context.Context, review models.Review) (models.Review, error)
+	// Resolved reviews are removed
+	DeleteReview(ctx context.Context, id uuid.UUID) error
+
+	GetReviewsById(ctx context.Context, ids ...uuid.UUID) (collection.Collection[result.Result[models.Review]], error)
+	// Oldest first
+	GetReviews(ctx context.Context) (collection.Collection[result.Result[models.Review]], error)
+}
+
@@ -0,0 +1,33 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../implementations/mock/notification/repository.go
+
+type NotificationOrder uint
+
+const (
+	NOTIFICATION_ORDER_DATE_DESC NotificationOrder = iota
+	NOTIFICATION_ORDER_DATE_ASC
+)
+
+type Repository interface {
+	// Ids are assigned by the repository, a user is notified about a
+	// comment once, so notifications already made are skipped
+	CreateNotifications(ctx context.Context, notifications ...models.Notification) error
+	// Ids of other users are ignored, all notifications of the user are
+	// marked if none are given. Returns number of notifications marked
+	MarkNotificationsRead(ctx context.Context, userId uuid.UUID, ids ...uuid.UUID) (int, error)
+
+	GetNotificationsById(ctx context.Context, ids ...uuid.UUID) (collection.Collection[result.Result[models.Notification]], error)
+	GetNotificationsByUserId(ctx context.Context, userId uuid.UUID, unreadOnly bool, order NotificationOrder) (collection.Collection[result.Result[models.Notification]], error)
+	CountUnreadNotifications(ctx context.Context, userId uuid.UUID) (int, error)
+}
+
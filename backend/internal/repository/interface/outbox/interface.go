@@ -0,0 +1,23 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../implementations/mock/outbox/repository.go
+
+// Domain events written by the other repositories along with the changes
+// they describe
+type Repository interface {
+	// Events not acknowledged yet, oldest first
+	GetPendingEvents(ctx context.Context) (collection.Collection[result.Result[models.Event]], error)
+	// Unknown and already acknowledged ids are ignored, so that redelivered
+	// events can be acknowledged again
+	AcknowledgeEvents(ctx context.Context, ids ...uuid.UUID) error
+}
+
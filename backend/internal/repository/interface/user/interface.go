@@ -12,6 +12,9 @@ import (
 //go:generate mockgen -source=interface.go -destination=../../implementations/mock/user/repository.go
 
 type Repository interface {
+	CreateUser(ctx context.Context, user models.User) (models.User, error)
 	GetUsersById(ctx context.Context, ids ...uuid.UUID) (collection.Collection[result.Result[models.User]], error)
+	// Unknown emails are skipped, order isn't kept
+	GetUsersByEmail(ctx context.Context, emails ...string) (collection.Collection[result.Result[models.User]], error)
 }
 
@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../implementations/mock/webhook/repository.go
+
+type Repository interface {
+	CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	// Deliveries of the webhook are removed as well
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+
+	GetWebhooksById(ctx context.Context, ids ...uuid.UUID) (collection.Collection[result.Result[models.Webhook]], error)
+	// Oldest first
+	GetWebhooksByOwnerId(ctx context.Context, ownerId uuid.UUID) (collection.Collection[result.Result[models.Webhook]], error)
+	// Subscribed to the event type, either for every post or for the given
+	// one, oldest first
+	GetWebhooksByEvent(ctx context.Context, kind string, postId uuid.UUID) (collection.Collection[result.Result[models.Webhook]], error)
+
+	// Ids are assigned by the repository, deliveries of an event already
+	// made for the webhook are skipped, so that redelivered events don't
+	// duplicate them
+	CreateDeliveries(ctx context.Context, deliveries ...models.WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, delivery models.WebhookDelivery) (models.WebhookDelivery, error)
+
+	GetDeliveriesById(ctx context.Context, ids ...uuid.UUID) (collection.Collection[result.Result[models.WebhookDelivery]], error)
+	// Newest first
+	GetDeliveriesByWebhookId(ctx context.Context, webhookId uuid.UUID) (collection.Collection[result.Result[models.WebhookDelivery]], error)
+	// Pending deliveries with the next attempt due by the time, soonest
+	// first
+	GetDueDeliveries(ctx context.Context, until time.Time) (collection.Collection[result.Result[models.WebhookDelivery]], error)
+}
+
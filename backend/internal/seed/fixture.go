@@ -0,0 +1,248 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/builders/domain"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Content referenced by keys, which are local to the fixture. Authors may
+// also be ids of users already present in the repository. Missing dates
+// are set to the time of loading
+type Fixture struct {
+	Users []FixtureUser `yaml:"users"`
+	Posts []FixturePost `yaml:"posts"`
+}
+
+type FixtureUser struct {
+	Key      string `yaml:"key"`
+	Email    string `yaml:"email"`
+	Password string `yaml:"password"`
+}
+
+type FixturePost struct {
+	Key             string           `yaml:"key"`
+	Author          string           `yaml:"author"`
+	Title           string           `yaml:"title"`
+	Content         string           `yaml:"content"`
+	CommentsAllowed *bool            `yaml:"comments_allowed"`
+	CreatedAt       time.Time        `yaml:"created_at"`
+	Comments        []FixtureComment `yaml:"comments"`
+}
+
+type FixtureComment struct {
+	Key       string           `yaml:"key"`
+	Author    string           `yaml:"author"`
+	Content   string           `yaml:"content"`
+	CreatedAt time.Time        `yaml:"created_at"`
+	Comments  []FixtureComment `yaml:"comments"`
+}
+
+// Created content by fixture keys, entries without key are omitted
+type Loaded struct {
+	Users    map[string]models.User
+	Posts    map[string]models.Post
+	Comments map[string]models.Comment
+}
+
+func ReadFixture(r io.Reader) (Fixture, error) {
+	var out Fixture
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	err := decoder.Decode(&out)
+
+	if io.EOF == err {
+		err = nil
+	}
+
+	return out, err
+}
+
+func ReadFixtureFile(path string) (Fixture, error) {
+	var out Fixture
+	file, err := os.Open(path)
+
+	if nil == err {
+		defer file.Close()
+		out, err = ReadFixture(file)
+	}
+
+	if nil != err {
+		err = fmt.Errorf("fixture %v: %w", path, err)
+	}
+
+	return out, err
+}
+
+type loader struct {
+	ctx    context.Context
+	repos  Repositories
+	now    time.Time
+	loaded *Loaded
+	stats  Stats
+}
+
+func (self *loader) author(key string) (uuid.UUID, error) {
+	if user, found := self.loaded.Users[key]; found {
+		return user.Id, nil
+	} else if id, err := uuid.Parse(key); nil == err {
+		return id, nil
+	} else {
+		return uuid.Nil, fmt.Errorf("unknown author %q", key)
+	}
+}
+
+func (self *loader) date(value time.Time) time.Time {
+	if value.IsZero() {
+		return self.now
+	}
+
+	return value
+}
+
+func keep[T any](m map[string]T, key string, value T) error {
+	if "" == key {
+		return nil
+	} else if _, found := m[key]; found {
+		return fmt.Errorf("key %q is used twice", key)
+	}
+
+	m[key] = value
+	return nil
+}
+
+func (self *loader) user(value *FixtureUser) error {
+	user, err := domain.NewUserBuilder().
+		WithId(uuid.Nil).
+		WithEmail(value.Email).
+		WithPassword(value.Password).
+		Build()
+
+	if nil == err {
+		user, err = self.repos.User.CreateUser(self.ctx, user)
+	}
+
+	if nil == err {
+		self.stats.Users++
+		err = keep(self.loaded.Users, value.Key, user)
+	}
+
+	if nil != err {
+		err = fmt.Errorf("user %q: %w", value.Key, err)
+	}
+
+	return err
+}
+
+func (self *loader) comments(
+	create func(context.Context, models.Comment) (models.Comment, error),
+	target uuid.UUID,
+	values []FixtureComment,
+) error {
+	var err error
+
+	for i := 0; nil == err && len(values) > i; i++ {
+		var comment models.Comment
+		var author uuid.UUID
+		value := &values[i]
+		author, err = self.author(value.Author)
+
+		if nil == err {
+			comment, err = domain.NewCommentBuilder().
+				WithId(uuid.Nil).
+				WithAuthorId(author).
+				WithTargetId(target).
+				WithContent(value.Content).
+				WithCreationDate(self.date(value.CreatedAt)).
+				Build()
+		}
+
+		if nil == err {
+			comment, err = create(self.ctx, comment)
+		}
+
+		if nil == err {
+			self.stats.Comments++
+			err = keep(self.loaded.Comments, value.Key, comment)
+		}
+
+		if nil != err {
+			err = fmt.Errorf("comment %q: %w", value.Key, err)
+		} else {
+			err = self.comments(
+				self.repos.Comment.CreateCommentComment,
+				comment.Id,
+				value.Comments,
+			)
+		}
+	}
+
+	return err
+}
+
+func (self *loader) post(value *FixturePost) error {
+	var post models.Post
+	allowed := nil == value.CommentsAllowed || *value.CommentsAllowed
+	author, err := self.author(value.Author)
+
+	if nil == err {
+		post, err = domain.NewPostBuilder().
+			WithId(uuid.Nil).
+			WithAuthorId(author).
+			WithTitle(value.Title).
+			WithContent(value.Content).
+			WithCommentsAllowed(allowed).
+			WithCreationDate(self.date(value.CreatedAt)).
+			Build()
+	}
+
+	if nil == err {
+		post, err = self.repos.Post.CreatePost(self.ctx, post)
+	}
+
+	if nil == err {
+		self.stats.Posts++
+		err = keep(self.loaded.Posts, value.Key, post)
+	}
+
+	if nil != err {
+		return fmt.Errorf("post %q: %w", value.Key, err)
+	}
+
+	// Fixture may describe comments left before they were disabled
+	return self.comments(self.repos.Comment.CreatePostComment, post.Id, value.Comments)
+}
+
+// Stops at the first error, content created before it stays in the
+// repository
+func Load(ctx context.Context, repos Repositories, fixture *Fixture) (*Loaded, Stats, error) {
+	var err error
+	l := loader{
+		ctx:   ctx,
+		repos: repos,
+		now:   time.Now(),
+		loaded: &Loaded{
+			Users:    make(map[string]models.User),
+			Posts:    make(map[string]models.Post),
+			Comments: make(map[string]models.Comment),
+		},
+	}
+
+	for i := 0; nil == err && len(fixture.Users) > i; i++ {
+		err = l.user(&fixture.Users[i])
+	}
+
+	for i := 0; nil == err && len(fixture.Posts) > i; i++ {
+		err = l.post(&fixture.Posts[i])
+	}
+
+	return l.loaded, l.stats, err
+}
+
@@ -0,0 +1,243 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/builders/domain"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	commrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	usrrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/user"
+)
+
+type Repositories struct {
+	User    usrrepo.Repository
+	Post    postrepo.Repository
+	Comment commrepo.Repository
+}
+
+type Options struct {
+	Users int
+	Posts int
+	// Maximum number of direct replies to a post, halved on every level of
+	// the tree
+	Comments int
+	// Maximum nesting of comment trees
+	Depth int
+	// Posts are dated within the period before Now, replies follow their
+	// parents
+	Period time.Duration
+	Now    time.Time
+	// Same seed and Now generate the same posts and comments. Emails of users
+	// differ on every run, so that seeding can be repeated on the same
+	// repositories
+	Seed uint64
+}
+
+var DEFAULT_OPTIONS = Options{
+	Users:    10,
+	Posts:    50,
+	Comments: 8,
+	Depth:    4,
+	Period:   30 * 24 * time.Hour,
+	Seed:     1,
+}
+
+type Stats struct {
+	Users    int
+	Posts    int
+	Comments int
+}
+
+func (self Stats) String() string {
+	return fmt.Sprintf("%v users, %v posts, %v comments", self.Users, self.Posts, self.Comments)
+}
+
+var words = strings.Fields(`
+    lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod
+    tempor incididunt ut labore et dolore magna aliqua enim ad minim veniam
+    quis nostrud exercitation ullamco laboris nisi aliquip ex ea commodo
+    consequat duis aute irure in reprehenderit voluptate velit esse cillum
+    fugiat nulla pariatur excepteur sint occaecat cupidatat non proident
+`)
+
+type generator struct {
+	ctx     context.Context
+	repos   Repositories
+	options Options
+	rng     *rand.Rand
+	// Not derived from the seed, tells users of different runs apart
+	run   uint64
+	users []models.User
+	stats Stats
+}
+
+func (self *generator) text(min int, max int) string {
+	n := min + self.rng.IntN(max-min+1)
+	out := make([]string, n)
+
+	for i := range out {
+		out[i] = words[self.rng.IntN(len(words))]
+	}
+
+	out[0] = strings.ToUpper(out[0][:1]) + out[0][1:]
+
+	return strings.Join(out, " ")
+}
+
+func (self *generator) author() uuid.UUID {
+	return self.users[self.rng.IntN(len(self.users))].Id
+}
+
+// Replies come mostly soon after the parent, rarely close to now
+func (self *generator) after(parent time.Time) time.Time {
+	span := self.options.Now.Sub(parent)
+	u := self.rng.Float64()
+
+	return parent.Add(time.Duration(float64(span) * u * u * u)).Add(time.Second)
+}
+
+func (self *generator) createUsers() error {
+	var err error
+	self.users = make([]models.User, 0, self.options.Users)
+
+	for i := 0; nil == err && self.options.Users > i; i++ {
+		var user models.User
+		user, err = domain.NewUserBuilder().
+			WithId(uuid.Nil).
+			WithEmail(fmt.Sprintf("%v.%v.%x@poster.test",
+				words[self.rng.IntN(len(words))], i, self.run)).
+			WithPassword("password").
+			Build()
+
+		if nil == err {
+			user, err = self.repos.User.CreateUser(self.ctx, user)
+		}
+
+		if nil == err {
+			self.users = append(self.users, user)
+			self.stats.Users++
+		}
+	}
+
+	return err
+}
+
+func (self *generator) createReplies(
+	create func(context.Context, models.Comment) (models.Comment, error),
+	target uuid.UUID,
+	date time.Time,
+	level int,
+) error {
+	var err error
+
+	if self.options.Depth <= level {
+		return nil
+	}
+
+	n := self.rng.IntN(self.options.Comments>>level + 1)
+
+	for i := 0; nil == err && n > i; i++ {
+		var comment models.Comment
+		comment, err = domain.NewCommentBuilder().
+			WithId(uuid.Nil).
+			WithAuthorId(self.author()).
+			WithTargetId(target).
+			WithContent(self.text(3, 40)).
+			WithCreationDate(self.after(date)).
+			Build()
+
+		if nil == err {
+			comment, err = create(self.ctx, comment)
+		}
+
+		if nil == err {
+			self.stats.Comments++
+			err = self.createReplies(
+				self.repos.Comment.CreateCommentComment,
+				comment.Id,
+				comment.CreationDate,
+				level+1,
+			)
+		}
+	}
+
+	return err
+}
+
+func (self *generator) createPosts() error {
+	var err error
+
+	for i := 0; nil == err && self.options.Posts > i; i++ {
+		var post models.Post
+		offset := time.Duration(self.rng.Int64N(int64(self.options.Period) + 1))
+		post, err = domain.NewPostBuilder().
+			WithId(uuid.Nil).
+			WithAuthorId(self.author()).
+			WithTitle(self.text(2, 8)).
+			WithContent(self.text(20, 200)).
+			WithCommentsAllowed(0 != self.rng.IntN(10)).
+			WithCreationDate(self.options.Now.Add(-offset)).
+			Build()
+
+		if nil == err {
+			post, err = self.repos.Post.CreatePost(self.ctx, post)
+		}
+
+		if nil == err {
+			self.stats.Posts++
+		}
+
+		if nil == err && post.CommentsAllowed {
+			err = self.createReplies(
+				self.repos.Comment.CreatePostComment,
+				post.Id,
+				post.CreationDate,
+				0,
+			)
+		}
+	}
+
+	return err
+}
+
+// Creates users and posts with comment trees authored by them. Content
+// created before an error stays in the repository and is counted in stats
+func Generate(ctx context.Context, repos Repositories, options Options) (Stats, error) {
+	var err error
+
+	if 0 > options.Users || 0 > options.Posts || 0 > options.Comments ||
+		0 > options.Depth || 0 > options.Period {
+		err = fmt.Errorf("seed options can't be negative")
+	} else if 0 != options.Posts && 0 == options.Users {
+		err = fmt.Errorf("posts can't be generated without users")
+	}
+
+	if options.Now.IsZero() {
+		options.Now = time.Now()
+	}
+
+	gen := generator{
+		ctx:     ctx,
+		repos:   repos,
+		options: options,
+		rng:     rand.New(rand.NewPCG(options.Seed, options.Seed)),
+		run:     rand.Uint64(),
+	}
+
+	if nil == err {
+		err = gen.createUsers()
+	}
+
+	if nil == err {
+		err = gen.createPosts()
+	}
+
+	return gen.stats, err
+}
+
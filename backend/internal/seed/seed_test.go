@@ -0,0 +1,186 @@
+package seed_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	"github.com/muji40k/ozontestcomms/internal/seed"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/test/common"
+	"github.com/muji40k/ozontestcomms/test/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func repositories() (seed.Repositories, *inmemory.Repository) {
+	repo := inmemory.New(nil)
+	return seed.Repositories{User: repo, Post: repo, Comment: repo}, repo
+}
+
+// Walks comment tree, checking that replies follow their parents
+func walk(
+	t *testing.T,
+	repo *inmemory.Repository,
+	id uuid.UUID,
+	date time.Time,
+	level int,
+) (int, int) {
+	replies := common.Unwrap(pagination.Collect(common.Unwrap(
+		repo.GetCommentsByCommentId(context.Background(), id, comment.COMMENT_ORDER_DATE_ASC),
+	)))
+	count, depth := len(replies), level
+
+	for _, v := range replies {
+		assert.True(t, v.CreationDate.After(date))
+		c, d := walk(t, repo, v.Id, v.CreationDate, level+1)
+		count += c
+		depth = max(depth, d)
+	}
+
+	return count, depth
+}
+
+func TestGenerate(t *testing.T) {
+	// Arrange
+	repos, repo := repositories()
+	options := seed.DEFAULT_OPTIONS
+	options.Now = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	stats, err := seed.Generate(context.Background(), repos, options)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, options.Users, stats.Users)
+	assert.Equal(t, options.Posts, stats.Posts)
+	posts := common.Unwrap(pagination.Collect(common.Unwrap(
+		repo.GetPosts(context.Background(), post.POST_ORDER_DATE_ASC),
+	)))
+	require.Len(t, posts, options.Posts)
+	comments, depth := 0, 0
+
+	for _, v := range posts {
+		assert.False(t, v.CreationDate.After(options.Now))
+		assert.False(t, v.CreationDate.Before(options.Now.Add(-options.Period)))
+		roots := common.Unwrap(pagination.Collect(common.Unwrap(
+			repo.GetCommentsByPostId(context.Background(), v.Id, comment.COMMENT_ORDER_DATE_ASC),
+		)))
+		comments += len(roots)
+
+		for _, root := range roots {
+			assert.True(t, root.CreationDate.After(v.CreationDate))
+			c, d := walk(t, repo, root.Id, root.CreationDate, 1)
+			comments += c
+			depth = max(depth, d)
+		}
+	}
+
+	assert.Equal(t, stats.Comments, comments)
+	assert.LessOrEqual(t, depth, options.Depth)
+	assert.Less(t, 1, depth, "Trees are nested")
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	// Arrange
+	first, _ := repositories()
+	second, _ := repositories()
+	options := seed.DEFAULT_OPTIONS
+	options.Now = time.Now()
+
+	// Act
+	a, aerr := seed.Generate(context.Background(), first, options)
+	b, berr := seed.Generate(context.Background(), second, options)
+
+	// Assert
+	require.NoError(t, aerr)
+	require.NoError(t, berr)
+	assert.Equal(t, a, b)
+}
+
+func TestGenerateRepeatedly(t *testing.T) {
+	// Arrange
+	repos, _ := repositories()
+	options := seed.DEFAULT_OPTIONS
+	options.Now = time.Now()
+	first := common.Unwrap(seed.Generate(context.Background(), repos, options))
+
+	// Act
+	second, err := seed.Generate(context.Background(), repos, options)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestGeneratePostsWithoutUsers(t *testing.T) {
+	// Arrange
+	repos, _ := repositories()
+	options := seed.DEFAULT_OPTIONS
+	options.Users = 0
+
+	// Act
+	stats, err := seed.Generate(context.Background(), repos, options)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, seed.Stats{}, stats)
+}
+
+func TestLoadFixture(t *testing.T) {
+	// Arrange
+	repos, repo := repositories()
+	fixture := fixtures.Read(t, fixtures.THREAD)
+
+	// Act
+	loaded, stats, err := seed.Load(context.Background(), repos, &fixture)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, seed.Stats{Users: 3, Posts: 2, Comments: 4}, stats)
+	assert.False(t, loaded.Posts["rules"].CommentsAllowed)
+	assert.Equal(t, loaded.Users["bob"].Id, loaded.Comments["hello"].AuthorId)
+	replies := common.Unwrap(pagination.Collect(common.Unwrap(repo.GetCommentsByCommentId(
+		context.Background(), loaded.Comments["hello-reply"].Id, comment.COMMENT_ORDER_DATE_ASC,
+	))))
+	assert.Equal(t, []models.Comment{loaded.Comments["hello-reply-reply"]}, replies)
+}
+
+func TestLoadFixtureErrors(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown author":  `posts: [{key: p, author: nobody, title: t, content: c}]`,
+		"duplicate key":   `users: [{key: a, email: a@b.c, password: p}, {key: a, email: b@b.c, password: p}]`,
+		"duplicate email": `users: [{key: a, email: a@b.c, password: p}, {key: b, email: a@b.c, password: p}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			repos, _ := repositories()
+			fixture, err := seed.ReadFixture(strings.NewReader(content))
+			require.NoError(t, err)
+
+			// Act
+			_, _, err = seed.Load(context.Background(), repos, &fixture)
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReadFixtureUnknownField(t *testing.T) {
+	// Arrange
+	content := `users: [{key: a, mail: a@b.c}]`
+
+	// Act
+	_, err := seed.ReadFixture(strings.NewReader(content))
+
+	// Assert
+	assert.Error(t, err)
+}
+
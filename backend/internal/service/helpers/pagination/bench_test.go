@@ -0,0 +1,33 @@
+package pagination_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+func BenchmarkCollect(b *testing.B) {
+	for _, n := range []int{20, 1_000, 100_000} {
+		values := make([]int, n)
+
+		for i := range values {
+			values[i] = i
+		}
+
+		b.Run(fmt.Sprintf("items=%v", n), func(b *testing.B) {
+			for b.Loop() {
+				col := collection.Map(collection.Slice(values), func(v *int) result.Result[int] {
+					return result.Ok(*v)
+				})
+
+				if _, err := pagination.Collect(col); nil != err {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection/iterator"
 	"github.com/muji40k/ozontestcomms/misc/result"
 )
 
@@ -31,26 +32,11 @@ func Apply[T any](
 }
 
 func Collect[T any](col collection.Collection[result.Result[T]]) ([]T, error) {
-	var out []T
-	iter, err := col.Get()
-
-	if nil == err {
-		out = make([]T, 0)
-
-		for v, next := iter.Next(); nil == err && next; v, next = iter.Next() {
-			if v, cerr := v.Unwrap(); nil == cerr {
-				out = append(out, v)
-			} else {
-				err = cerr
-			}
-		}
+	if iter, err := col.Get(); nil == err {
+		return iterator.TryCollect(iter)
+	} else {
+		return nil, err
 	}
-
-	if nil != err {
-		out = nil
-	}
-
-	return out, err
 }
 
 var negativeLimit = errors.New("Limit value is negative")
@@ -10,9 +10,18 @@ func Unwrap[T any](col collection.Collection[T], err error) (T, error) {
 
 	if nil != err {
 		return empty, err
-	} else if iter, err := col.Get(); nil != err {
+	}
+
+	iter, err := col.Get()
+
+	if nil != err {
 		return empty, err
-	} else if val, next := iter.Next(); !next {
+	}
+
+	// At most two values are read, the rest is left to Close
+	defer iter.Close()
+
+	if val, next := iter.Next(); !next {
 		return empty, errors.IterEmpty()
 	} else if _, next := iter.Next(); next {
 		return empty, errors.IterMultiple()
@@ -0,0 +1,9 @@
+package follow
+
+type FollowKind uint
+
+const (
+	FOLLOW_KIND_AUTHOR FollowKind = iota
+	FOLLOW_KIND_POST
+)
+
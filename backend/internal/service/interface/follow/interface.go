@@ -0,0 +1,26 @@
+package follow
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../mock/follow/service.go
+
+type Service interface {
+	// Returns false if the author or post is followed already
+	Follow(ctx context.Context, userId uuid.UUID, kind FollowKind, targetId uuid.UUID) (bool, error)
+	// Returns false if the author or post isn't followed
+	Unfollow(ctx context.Context, userId uuid.UUID, kind FollowKind, targetId uuid.UUID) (bool, error)
+
+	// Posts of followed authors, newest first
+	GetFeed(ctx context.Context, userId uuid.UUID) (collection.Collection[result.Result[models.Post]], error)
+	// Followed posts, newest first
+	GetWatchedPosts(ctx context.Context, userId uuid.UUID) (collection.Collection[result.Result[models.Post]], error)
+}
+
@@ -0,0 +1,10 @@
+package health
+
+import "context"
+
+//go:generate mockgen -source=interface.go -destination=../../mock/health/service.go
+
+type Service interface {
+	Ping(ctx context.Context) error
+}
+
@@ -0,0 +1,23 @@
+package moderation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../mock/moderation/service.go
+
+// Available to moderators only
+type Service interface {
+	// Content waiting for review, oldest first
+	GetReviews(ctx context.Context, moderatorId uuid.UUID) (collection.Collection[result.Result[models.Review]], error)
+	// Approved hidden content is published, dismissed one is dropped.
+	// Flagged content stays published either way
+	ResolveReview(ctx context.Context, moderatorId uuid.UUID, reviewId uuid.UUID, approve bool) error
+}
+
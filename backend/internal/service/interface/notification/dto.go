@@ -0,0 +1,9 @@
+package notification
+
+type NotificationOrder uint
+
+const (
+	NOTIFICATION_ORDER_DATE_DESC NotificationOrder = iota
+	NOTIFICATION_ORDER_DATE_ASC
+)
+
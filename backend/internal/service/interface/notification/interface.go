@@ -0,0 +1,25 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../mock/notification/service.go
+
+// Notifications are made from the comment events, so the service only
+// reads and marks them
+type Service interface {
+	GetNotifications(ctx context.Context, userId uuid.UUID, unreadOnly bool, order NotificationOrder) (collection.Collection[result.Result[models.Notification]], error)
+	CountUnread(ctx context.Context, userId uuid.UUID) (int, error)
+	// Every notification of the user is marked if no ids are given, ids
+	// of other users' notifications are ignored. Returns number of
+	// notifications marked
+	MarkRead(ctx context.Context, userId uuid.UUID, ids ...uuid.UUID) (int, error)
+}
+
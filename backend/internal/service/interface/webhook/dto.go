@@ -0,0 +1,11 @@
+package webhook
+
+import "github.com/google/uuid"
+
+type WebhookCreationForm struct {
+	Url    string
+	Events []string
+	// Subscription is limited to a single post if set
+	PostId *uuid.UUID
+}
+
@@ -0,0 +1,28 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../mock/webhook/service.go
+
+type Service interface {
+	GetWebhooks(ctx context.Context, userId uuid.UUID) (collection.Collection[result.Result[models.Webhook]], error)
+	// Secret is generated and returned only here
+	CreateWebhook(ctx context.Context, userId uuid.UUID, form WebhookCreationForm) (models.Webhook, error)
+	DeleteWebhook(ctx context.Context, userId uuid.UUID, webhookId uuid.UUID) error
+
+	// Newest first, limited to the statuses if any are given, so that
+	// DELIVERY_STATUS_DEAD lists the dead letters
+	GetDeliveries(ctx context.Context, userId uuid.UUID, webhookId uuid.UUID, status ...models.DeliveryStatus) (collection.Collection[result.Result[models.WebhookDelivery]], error)
+	// Schedules a dead delivery for an immediate attempt with the full
+	// number of retries
+	RetryDelivery(ctx context.Context, userId uuid.UUID, deliveryId uuid.UUID) (models.WebhookDelivery, error)
+}
+
@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../mock/follow/service.go
+//
+
+// Package mock_follow is a generated GoMock package.
+package mock_follow
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	models "github.com/muji40k/ozontestcomms/internal/domain/models"
+	collection "github.com/muji40k/ozontestcomms/internal/repository/collection"
+	follow "github.com/muji40k/ozontestcomms/internal/service/interface/follow"
+	result "github.com/muji40k/ozontestcomms/misc/result"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// Follow mocks base method.
+func (m *MockService) Follow(ctx context.Context, userId uuid.UUID, kind follow.FollowKind, targetId uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", ctx, userId, kind, targetId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockServiceMockRecorder) Follow(ctx, userId, kind, targetId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockService)(nil).Follow), ctx, userId, kind, targetId)
+}
+
+// GetFeed mocks base method.
+func (m *MockService) GetFeed(ctx context.Context, userId uuid.UUID) (collection.Collection[result.Result[models.Post]], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", ctx, userId)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.Post]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockServiceMockRecorder) GetFeed(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockService)(nil).GetFeed), ctx, userId)
+}
+
+// GetWatchedPosts mocks base method.
+func (m *MockService) GetWatchedPosts(ctx context.Context, userId uuid.UUID) (collection.Collection[result.Result[models.Post]], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchedPosts", ctx, userId)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.Post]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchedPosts indicates an expected call of GetWatchedPosts.
+func (mr *MockServiceMockRecorder) GetWatchedPosts(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchedPosts", reflect.TypeOf((*MockService)(nil).GetWatchedPosts), ctx, userId)
+}
+
+// Unfollow mocks base method.
+func (m *MockService) Unfollow(ctx context.Context, userId uuid.UUID, kind follow.FollowKind, targetId uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", ctx, userId, kind, targetId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unfollow indicates an expected call of Unfollow.
+func (mr *MockServiceMockRecorder) Unfollow(ctx, userId, kind, targetId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockService)(nil).Unfollow), ctx, userId, kind, targetId)
+}
@@ -0,0 +1,74 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../mock/moderation/service.go
+//
+
+// Package mock_moderation is a generated GoMock package.
+package mock_moderation
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	models "github.com/muji40k/ozontestcomms/internal/domain/models"
+	collection "github.com/muji40k/ozontestcomms/internal/repository/collection"
+	result "github.com/muji40k/ozontestcomms/misc/result"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// GetReviews mocks base method.
+func (m *MockService) GetReviews(ctx context.Context, moderatorId uuid.UUID) (collection.Collection[result.Result[models.Review]], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews", ctx, moderatorId)
+	ret0, _ := ret[0].(collection.Collection[result.Result[models.Review]])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockServiceMockRecorder) GetReviews(ctx, moderatorId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockService)(nil).GetReviews), ctx, moderatorId)
+}
+
+// ResolveReview mocks base method.
+func (m *MockService) ResolveReview(ctx context.Context, moderatorId, reviewId uuid.UUID, approve bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveReview", ctx, moderatorId, reviewId, approve)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveReview indicates an expected call of ResolveReview.
+func (mr *MockServiceMockRecorder) ResolveReview(ctx, moderatorId, reviewId, approve any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReview", reflect.TypeOf((*MockService)(nil).ResolveReview), ctx, moderatorId, reviewId, approve)
+}
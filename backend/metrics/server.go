@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/muji40k/ozontestcomms/internal/application/health"
+	"github.com/muji40k/ozontestcomms/internal/application/httpserver"
+	healthsrv "github.com/muji40k/ozontestcomms/internal/service/interface/health"
+)
+
+// Operational endpoints (health probes and expvar variables) served apart
+// from the public API
+type Server struct {
+	server *httpserver.Server
+}
+
+func New(
+	host string,
+	port string,
+	shutdown time.Duration,
+	service healthsrv.Service,
+) *Server {
+	return &Server{
+		httpserver.New(
+			host,
+			port,
+			shutdown,
+			health.New(service, health.DEFAULT_READINESS_TIMEOUT),
+		),
+	}
+}
+
+func (self *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	self.server.Probe().Register(mux)
+
+	return mux
+}
+
+func (self *Server) Run(ctx context.Context) error {
+	log.Printf("serving metrics at http://%s/debug/vars", self.server.Address())
+	return self.server.Run(ctx, self.Handler())
+}
+
+func (self *Server) Clear() {
+	self.server.Clear()
+	log.Print("metrics server down")
+}
+
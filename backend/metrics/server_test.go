@@ -0,0 +1,39 @@
+package metrics_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metricsbuilder "github.com/muji40k/ozontestcomms/builders/applications/metrics"
+	"github.com/muji40k/ozontestcomms/test/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsEndpoints(t *testing.T) {
+	// Arrange
+	server := common.Unwrap(metricsbuilder.NewServerBuilder().
+		WithHost("127.0.0.1").
+		WithPort("0").
+		Build())
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+	var vars map[string]any
+
+	// Act
+	resp, err := http.Get(ts.URL + "/debug/vars")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&vars))
+	ready, err := http.Get(ts.URL + "/readyz")
+	require.NoError(t, err)
+	ready.Body.Close()
+
+	// Assert
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, vars, "memstats")
+	assert.Equal(t, http.StatusOK, ready.StatusCode)
+}
+
@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
+)
+
+type ErrorBadRequest struct{ Err error }
+
+func BadRequest(err error) ErrorBadRequest {
+	return ErrorBadRequest{err}
+}
+
+func (e ErrorBadRequest) Error() string {
+	return e.Err.Error()
+}
+
+func (e ErrorBadRequest) Unwrap() error {
+	return e.Err
+}
+
+func is[T error](err error) bool {
+	var target T
+	return errors.As(err, &target)
+}
+
+func mapError(err error) (int, Error) {
+	code := func(status int, code string) (int, Error) {
+		return status, Error{code, err.Error()}
+	}
+
+	switch {
+	case is[ErrorBadRequest](err):
+		return code(http.StatusBadRequest, "bad_request")
+	case is[srverrors.ErrorEmpty](err), is[srverrors.ErrorIncorrect](err):
+		return code(http.StatusBadRequest, "invalid")
+	case is[srverrors.ErrorNotFound](err), is[srverrors.ErrorIterEmpty](err),
+		is[repoerrors.ErrorNotFound](err):
+		return code(http.StatusNotFound, "not_found")
+	case is[srverrors.ErrorViolation](err):
+		return code(http.StatusConflict, "violation")
+	case is[srverrors.ErrorAuthentication](err):
+		return code(http.StatusUnauthorized, "unauthenticated")
+	case is[srverrors.ErrorAuthorization](err):
+		return code(http.StatusForbidden, "forbidden")
+	default:
+		// Internal details are not exposed
+		return http.StatusInternalServerError, Error{
+			"internal", http.StatusText(http.StatusInternalServerError),
+		}
+	}
+}
+
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/export"
+	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
+)
+
+func (self *Server) authorizeExport(r *http.Request) error {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	if !found || 1 != subtle.ConstantTimeCompare([]byte(token), []byte(self.context.ExportToken)) {
+		return srverrors.Authentication(errors.New("Export token is missing or wrong"))
+	}
+
+	return nil
+}
+
+func parseExport(r *http.Request) (export.Format, export.Filter, error) {
+	var filter export.Filter
+	var err error
+	format := export.FORMAT_NDJSON
+	query := r.URL.Query()
+
+	if v := query.Get("format"); "" != v {
+		format, err = export.ParseFormat(v)
+	}
+
+	if nil == err {
+		filter.From, err = export.ParseTime(query.Get("from"))
+	}
+
+	if nil == err {
+		filter.To, err = export.ParseTime(query.Get("to"))
+	}
+
+	for _, v := range query["author"] {
+		var id uuid.UUID
+
+		if nil != err {
+			break
+		} else if id, err = uuid.Parse(v); nil == err {
+			filter.Authors = append(filter.Authors, id)
+		} else {
+			err = fmt.Errorf("Malformed author: %w", err)
+		}
+	}
+
+	if nil != err {
+		err = BadRequest(err)
+	}
+
+	return format, filter, err
+}
+
+// Response is streamed, so errors after the first row can't be reported
+// with a status, the connection is dropped instead for the client to notice
+func (self *Server) export(w http.ResponseWriter, r *http.Request) error {
+	var format export.Format
+	var filter export.Filter
+	err := self.authorizeExport(r)
+
+	if nil == err {
+		format, filter, err = parseExport(r)
+	}
+
+	if nil != err {
+		return err
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=export.%v", format))
+	stats, err := export.Export(r.Context(), self.context.Export, w, format, filter)
+
+	if nil != err {
+		log.Printf("export failed after %v: %v", stats, err)
+		panic(http.ErrAbortHandler)
+	}
+
+	return nil
+}
+
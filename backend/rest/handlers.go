@@ -0,0 +1,408 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/singlewrap"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/post"
+	"github.com/muji40k/ozontestcomms/misc/result"
+)
+
+const (
+	DEFAULT_PAGE_LIMIT int32 = 20
+	MAX_PAGE_LIMIT     int32 = 100
+	MAX_BODY_SIZE      int64 = 1 << 20
+)
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+func handle(f handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := f(w, r); nil != err {
+			status, body := mapError(err)
+			writeJSON(w, status, ErrorResponse{body})
+		}
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MAX_BODY_SIZE))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); nil != err {
+		return BadRequest(fmt.Errorf("Malformed request body: %w", err))
+	}
+
+	return nil
+}
+
+func pathId(r *http.Request) (uuid.UUID, error) {
+	id, err := uuid.Parse(r.PathValue("id"))
+
+	if nil != err {
+		err = BadRequest(fmt.Errorf("Malformed id: %w", err))
+	}
+
+	return id, err
+}
+
+type page struct {
+	after *uuid.UUID
+	limit int32
+	asc   bool
+}
+
+func parsePage(r *http.Request) (page, error) {
+	var err error
+	out := page{limit: DEFAULT_PAGE_LIMIT}
+	query := r.URL.Query()
+
+	if v := query.Get("after"); "" != v {
+		var id uuid.UUID
+
+		if id, err = uuid.Parse(v); nil == err {
+			out.after = &id
+		} else {
+			err = BadRequest(fmt.Errorf("Malformed after: %w", err))
+		}
+	}
+
+	if v := query.Get("limit"); nil == err && "" != v {
+		var limit uint64
+
+		if limit, err = strconv.ParseUint(v, 10, 31); nil != err {
+			err = BadRequest(fmt.Errorf("Malformed limit: %w", err))
+		} else if int32(limit) > MAX_PAGE_LIMIT {
+			err = BadRequest(fmt.Errorf("Limit exceeds %v", MAX_PAGE_LIMIT))
+		} else {
+			out.limit = int32(limit)
+		}
+	}
+
+	if nil == err {
+		switch query.Get("order") {
+		case "", "desc":
+			out.asc = false
+		case "asc":
+			out.asc = true
+		default:
+			err = BadRequest(errors.New("Order must be one of: asc, desc"))
+		}
+	}
+
+	return out, err
+}
+
+func (self page) postOrder() post.PostOrder {
+	if self.asc {
+		return post.POST_ORDER_DATE_ASC
+	} else {
+		return post.POST_ORDER_DATE_DESC
+	}
+}
+
+func (self page) commentOrder() comment.CommentOrder {
+	if self.asc {
+		return comment.COMMENT_ORDER_DATE_ASC
+	} else {
+		return comment.COMMENT_ORDER_DATE_DESC
+	}
+}
+
+func paginate[T any, F any](
+	col collection.Collection[result.Result[T]],
+	p page,
+	mapf func(*T) F,
+	id func(*F) uuid.UUID,
+) (Cursor[F], error) {
+	var out Cursor[F]
+	err := pagination.Apply(col, p.after, p.limit)
+
+	if nil == err {
+		out.Data, err = pagination.Collect(collection.Map(col,
+			func(v *result.Result[T]) result.Result[F] {
+				return result.Map(v, mapf)
+			},
+		))
+	}
+
+	if nil == err {
+		if l := len(out.Data); 0 != l {
+			v := id(&out.Data[l-1])
+			out.EndId = &v
+		}
+	}
+
+	return out, err
+}
+
+func single[T any, F any](
+	col collection.Collection[result.Result[T]],
+	err error,
+	mapf func(*T) F,
+) (F, error) {
+	var out F
+	res, err := singlewrap.Unwrap(col, err)
+
+	if nil == err {
+		var v T
+
+		if v, err = res.Unwrap(); nil == err {
+			out = mapf(&v)
+		}
+	}
+
+	return out, err
+}
+
+func postId(v *Post) uuid.UUID {
+	return v.Id
+}
+
+func commentId(v *Comment) uuid.UUID {
+	return v.Id
+}
+
+func (self *Server) getPosts(w http.ResponseWriter, r *http.Request) error {
+	var out Cursor[Post]
+	p, err := parsePage(r)
+
+	if nil == err {
+		var col collection.Collection[result.Result[models.Post]]
+
+		if col, err = self.context.Post.GetPosts(r.Context(), p.postOrder()); nil == err {
+			out, err = paginate(col, p, mapPost, postId)
+		}
+	}
+
+	if nil == err {
+		writeJSON(w, http.StatusOK, out)
+	}
+
+	return err
+}
+
+func (self *Server) getPost(w http.ResponseWriter, r *http.Request) error {
+	var out Post
+	id, err := pathId(r)
+
+	if nil == err {
+		col, cerr := self.context.Post.GetPostsById(r.Context(), id)
+		out, err = single(col, cerr, mapPost)
+	}
+
+	if nil == err {
+		writeJSON(w, http.StatusOK, out)
+	}
+
+	return err
+}
+
+func (self *Server) createPost(w http.ResponseWriter, r *http.Request) error {
+	var input CreatePostInput
+	var created models.Post
+	err := readJSON(w, r, &input)
+
+	if nil == err {
+		created, err = self.context.Post.CreatePost(
+			r.Context(),
+			input.UserId,
+			unmapCreatePostInput(&input),
+		)
+	}
+
+	if nil == err {
+		w.Header().Set("Location", fmt.Sprintf("/posts/%v", created.Id))
+		writeJSON(w, http.StatusCreated, mapPost(&created))
+	}
+
+	return err
+}
+
+func (self *Server) modifyPost(w http.ResponseWriter, r *http.Request) error {
+	var input PostModificationInput
+	var value models.Post
+	id, err := pathId(r)
+
+	if nil == err {
+		err = readJSON(w, r, &input)
+	}
+
+	if nil == err {
+		col, cerr := self.context.Post.GetPostsById(r.Context(), id)
+		value, err = single(col, cerr, func(v *models.Post) models.Post {
+			return *v
+		})
+	}
+
+	if nil == err {
+		applyPostModificationInput(&value, &input)
+		value, err = self.context.Post.UpdatePost(r.Context(), input.UserId, value)
+	}
+
+	if nil == err {
+		writeJSON(w, http.StatusOK, mapPost(&value))
+	}
+
+	return err
+}
+
+func (self *Server) getPostComments(w http.ResponseWriter, r *http.Request) error {
+	var out Cursor[Comment]
+	var p page
+	id, err := pathId(r)
+
+	if nil == err {
+		p, err = parsePage(r)
+	}
+
+	if nil == err {
+		var col collection.Collection[result.Result[models.Comment]]
+
+		col, err = self.context.Comment.GetCommentsByPostId(
+			r.Context(),
+			id,
+			p.commentOrder(),
+		)
+
+		if nil == err {
+			out, err = paginate(col, p, mapComment, commentId)
+		}
+	}
+
+	if nil == err {
+		writeJSON(w, http.StatusOK, out)
+	}
+
+	return err
+}
+
+func (self *Server) createPostComment(w http.ResponseWriter, r *http.Request) error {
+	var input CommentInput
+	var created models.Comment
+	id, err := pathId(r)
+
+	if nil == err {
+		err = readJSON(w, r, &input)
+	}
+
+	if nil == err {
+		created, err = self.context.Comment.CreatePostComment(
+			r.Context(),
+			input.UserId,
+			id,
+			unmapCommentInput(&input),
+		)
+	}
+
+	if nil == err {
+		w.Header().Set("Location", fmt.Sprintf("/comments/%v", created.Id))
+		writeJSON(w, http.StatusCreated, mapComment(&created))
+	}
+
+	return err
+}
+
+func (self *Server) getComment(w http.ResponseWriter, r *http.Request) error {
+	var out Comment
+	id, err := pathId(r)
+
+	if nil == err {
+		col, cerr := self.context.Comment.GetCommentsById(r.Context(), id)
+		out, err = single(col, cerr, mapComment)
+	}
+
+	if nil == err {
+		writeJSON(w, http.StatusOK, out)
+	}
+
+	return err
+}
+
+func (self *Server) getCommentComments(w http.ResponseWriter, r *http.Request) error {
+	var out Cursor[Comment]
+	var p page
+	id, err := pathId(r)
+
+	if nil == err {
+		p, err = parsePage(r)
+	}
+
+	if nil == err {
+		var col collection.Collection[result.Result[models.Comment]]
+
+		col, err = self.context.Comment.GetCommentsByCommentId(
+			r.Context(),
+			id,
+			p.commentOrder(),
+		)
+
+		if nil == err {
+			out, err = paginate(col, p, mapComment, commentId)
+		}
+	}
+
+	if nil == err {
+		writeJSON(w, http.StatusOK, out)
+	}
+
+	return err
+}
+
+func (self *Server) createCommentComment(w http.ResponseWriter, r *http.Request) error {
+	var input CommentInput
+	var created models.Comment
+	id, err := pathId(r)
+
+	if nil == err {
+		err = readJSON(w, r, &input)
+	}
+
+	if nil == err {
+		created, err = self.context.Comment.CreateCommentComment(
+			r.Context(),
+			input.UserId,
+			id,
+			unmapCommentInput(&input),
+		)
+	}
+
+	if nil == err {
+		w.Header().Set("Location", fmt.Sprintf("/comments/%v", created.Id))
+		writeJSON(w, http.StatusCreated, mapComment(&created))
+	}
+
+	return err
+}
+
+func (self *Server) getUser(w http.ResponseWriter, r *http.Request) error {
+	var out User
+	id, err := pathId(r)
+
+	if nil == err {
+		col, cerr := self.context.User.GetUsersById(r.Context(), id)
+		out, err = single(col, cerr, mapUser)
+	}
+
+	if nil == err {
+		writeJSON(w, http.StatusOK, out)
+	}
+
+	return err
+}
+
@@ -0,0 +1,119 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/comment"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/post"
+)
+
+type User struct {
+	Id    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+}
+
+type Post struct {
+	Id              uuid.UUID `json:"id"`
+	AuthorId        uuid.UUID `json:"author_id"`
+	Title           string    `json:"title"`
+	Content         string    `json:"content"`
+	CommentsAllowed bool      `json:"comments_allowed"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type Comment struct {
+	Id        uuid.UUID `json:"id"`
+	AuthorId  uuid.UUID `json:"author_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Cursor[T any] struct {
+	Data  []T        `json:"data"`
+	EndId *uuid.UUID `json:"end_id,omitempty"`
+}
+
+type CreatePostInput struct {
+	UserId        uuid.UUID `json:"user_id"`
+	Title         string    `json:"title"`
+	Content       string    `json:"content"`
+	AllowComments *bool     `json:"allow_comments,omitempty"`
+}
+
+type PostModificationInput struct {
+	UserId        uuid.UUID `json:"user_id"`
+	AllowComments *bool     `json:"allow_comments,omitempty"`
+}
+
+type CommentInput struct {
+	UserId  uuid.UUID `json:"user_id"`
+	Content string    `json:"content"`
+}
+
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type ErrorResponse struct {
+	Error Error `json:"error"`
+}
+
+func mapUser(value *models.User) User {
+	return User{
+		Id:    value.Id,
+		Email: value.Email,
+	}
+}
+
+func mapPost(value *models.Post) Post {
+	return Post{
+		Id:              value.Id,
+		AuthorId:        value.AuthorId,
+		Title:           value.Title,
+		Content:         value.Content,
+		CommentsAllowed: value.CommentsAllowed,
+		CreatedAt:       value.CreationDate,
+	}
+}
+
+func mapComment(value *models.Comment) Comment {
+	return Comment{
+		Id:        value.Id,
+		AuthorId:  value.AuthorId,
+		Content:   value.Content,
+		CreatedAt: value.CreationDate,
+	}
+}
+
+func unmapCreatePostInput(input *CreatePostInput) post.PostCreationForm {
+	allow := true
+
+	if nil != input.AllowComments {
+		allow = *input.AllowComments
+	}
+
+	return post.PostCreationForm{
+		Title:         input.Title,
+		Content:       input.Content,
+		AllowComments: allow,
+	}
+}
+
+func applyPostModificationInput(
+	value *models.Post,
+	input *PostModificationInput,
+) {
+	if nil != input.AllowComments {
+		value.CommentsAllowed = *input.AllowComments
+	}
+}
+
+func unmapCommentInput(input *CommentInput) comment.CommentForm {
+	return comment.CommentForm{
+		Content: input.Content,
+	}
+}
+
@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"context"
+	_ "embed"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/muji40k/ozontestcomms/internal/application/health"
+	"github.com/muji40k/ozontestcomms/internal/application/httpserver"
+	"github.com/muji40k/ozontestcomms/internal/seed"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/comment"
+	healthsrv "github.com/muji40k/ozontestcomms/internal/service/interface/health"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/post"
+	"github.com/muji40k/ozontestcomms/internal/service/interface/user"
+)
+
+//go:embed openapi.json
+var openapi []byte
+
+type Context struct {
+	User    user.Service
+	Comment comment.Service
+	Post    post.Service
+	Health  healthsrv.Service
+	// Bearer token of the export endpoint, it's not served if empty
+	ExportToken string
+	// Export reads them directly to dump what the services don't show
+	Export seed.Repositories
+}
+
+type Server struct {
+	context Context
+	server  *httpserver.Server
+}
+
+func New(
+	host string,
+	port string,
+	shutdown time.Duration,
+	context Context,
+) *Server {
+	return &Server{
+		context,
+		httpserver.New(
+			host,
+			port,
+			shutdown,
+			health.New(context.Health, health.DEFAULT_READINESS_TIMEOUT),
+		),
+	}
+}
+
+func (self *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.Handle("GET /posts", handle(self.getPosts))
+	mux.Handle("POST /posts", handle(self.createPost))
+	mux.Handle("GET /posts/{id}", handle(self.getPost))
+	mux.Handle("PATCH /posts/{id}", handle(self.modifyPost))
+	mux.Handle("GET /posts/{id}/comments", handle(self.getPostComments))
+	mux.Handle("POST /posts/{id}/comments", handle(self.createPostComment))
+	mux.Handle("GET /comments/{id}", handle(self.getComment))
+	mux.Handle("GET /comments/{id}/comments", handle(self.getCommentComments))
+	mux.Handle("POST /comments/{id}/comments", handle(self.createCommentComment))
+	mux.Handle("GET /users/{id}", handle(self.getUser))
+
+	if "" != self.context.ExportToken {
+		mux.Handle("GET /export", handle(self.export))
+	}
+
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openapi)
+	})
+
+	self.server.Probe().Register(mux)
+
+	return mux
+}
+
+func (self *Server) Run(ctx context.Context) error {
+	log.Printf("serving REST API at http://%s/, see /openapi.json", self.server.Address())
+	return self.server.Run(ctx, self.Handler())
+}
+
+func (self *Server) Clear() {
+	self.server.Clear()
+	log.Print("rest server down")
+}
+
@@ -0,0 +1,258 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	restbuilder "github.com/muji40k/ozontestcomms/builders/applications/rest"
+	"github.com/muji40k/ozontestcomms/builders/services/domain"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	"github.com/muji40k/ozontestcomms/internal/seed"
+	"github.com/muji40k/ozontestcomms/rest"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setup(t *testing.T) (*httptest.Server, models.User) {
+	return setupWithToken(t, "")
+}
+
+func setupWithToken(t *testing.T, token string) (*httptest.Server, models.User) {
+	user := common.Unwrap(domainOM.UserRandom().Build())
+	repo := inmemory.New(
+		func(adduser func(models.User), _ func(inmemory.Comment), _ func(models.Post)) {
+			adduser(user)
+		},
+	)
+	svc := common.Unwrap(domain.NewLogicBuilder().
+		WithCommentRepository(repo).
+		WithPostRepository(repo).
+		WithUserRepository(repo).
+		Build())
+	server := common.Unwrap(restbuilder.NewServerBuilder().
+		WithHost("127.0.0.1").
+		WithPort("0").
+		WithExportToken(token).
+		WithExportRepositories(seed.Repositories{User: repo, Post: repo, Comment: repo}).
+		WithCommentService(svc).
+		WithPostService(svc).
+		WithUserService(svc).
+		WithHealthService(svc).
+		Build())
+
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+
+	return ts, user
+}
+
+func do[T any](
+	t *testing.T,
+	method string,
+	url string,
+	body any,
+	status int,
+) T {
+	var out T
+	var reader bytes.Buffer
+
+	if nil != body {
+		require.NoError(t, json.NewEncoder(&reader).Encode(body))
+	}
+
+	req, err := http.NewRequest(method, url, &reader)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, status, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+	return out
+}
+
+func TestRestPostsLifecycle(t *testing.T) {
+	// Arrange
+	ts, user := setup(t)
+	created := make([]rest.Post, 3)
+
+	// Act
+	for i := range created {
+		created[i] = do[rest.Post](t, http.MethodPost, ts.URL+"/posts",
+			rest.CreatePostInput{
+				UserId:  user.Id,
+				Title:   fmt.Sprint("title ", i),
+				Content: fmt.Sprint("content ", i),
+			},
+			http.StatusCreated,
+		)
+	}
+
+	first := do[rest.Cursor[rest.Post]](t, http.MethodGet,
+		ts.URL+"/posts?order=asc&limit=2", nil, http.StatusOK,
+	)
+	second := do[rest.Cursor[rest.Post]](t, http.MethodGet,
+		fmt.Sprintf("%v/posts?order=asc&limit=2&after=%v", ts.URL, *first.EndId),
+		nil, http.StatusOK,
+	)
+	single := do[rest.Post](t, http.MethodGet,
+		fmt.Sprintf("%v/posts/%v", ts.URL, created[1].Id), nil, http.StatusOK,
+	)
+
+	// Assert
+	assert.Equal(t, created[:2], first.Data)
+	assert.Equal(t, created[2:], second.Data)
+	assert.Equal(t, created[1], single)
+}
+
+func TestRestComments(t *testing.T) {
+	// Arrange
+	ts, user := setup(t)
+	post := do[rest.Post](t, http.MethodPost, ts.URL+"/posts",
+		rest.CreatePostInput{UserId: user.Id, Title: "title", Content: "content"},
+		http.StatusCreated,
+	)
+
+	// Act
+	comment := do[rest.Comment](t, http.MethodPost,
+		fmt.Sprintf("%v/posts/%v/comments", ts.URL, post.Id),
+		rest.CommentInput{UserId: user.Id, Content: "root"},
+		http.StatusCreated,
+	)
+	reply := do[rest.Comment](t, http.MethodPost,
+		fmt.Sprintf("%v/comments/%v/comments", ts.URL, comment.Id),
+		rest.CommentInput{UserId: user.Id, Content: "reply"},
+		http.StatusCreated,
+	)
+	roots := do[rest.Cursor[rest.Comment]](t, http.MethodGet,
+		fmt.Sprintf("%v/posts/%v/comments", ts.URL, post.Id), nil, http.StatusOK,
+	)
+	replies := do[rest.Cursor[rest.Comment]](t, http.MethodGet,
+		fmt.Sprintf("%v/comments/%v/comments", ts.URL, comment.Id), nil, http.StatusOK,
+	)
+
+	// Assert
+	assert.Equal(t, []rest.Comment{comment}, roots.Data)
+	assert.Equal(t, []rest.Comment{reply}, replies.Data)
+	assert.Equal(t, &reply.Id, replies.EndId)
+}
+
+func TestRestCommentsDisabled(t *testing.T) {
+	// Arrange
+	ts, user := setup(t)
+	allow := false
+	post := do[rest.Post](t, http.MethodPost, ts.URL+"/posts",
+		rest.CreatePostInput{
+			UserId:        user.Id,
+			Title:         "title",
+			Content:       "content",
+			AllowComments: &allow,
+		},
+		http.StatusCreated,
+	)
+
+	// Act
+	res := do[rest.ErrorResponse](t, http.MethodPost,
+		fmt.Sprintf("%v/posts/%v/comments", ts.URL, post.Id),
+		rest.CommentInput{UserId: user.Id, Content: "comment"},
+		http.StatusConflict,
+	)
+
+	// Assert
+	assert.Equal(t, "violation", res.Error.Code)
+}
+
+func TestRestErrors(t *testing.T) {
+	// Arrange
+	ts, user := setup(t)
+
+	// Act
+	notFound := do[rest.ErrorResponse](t, http.MethodGet,
+		fmt.Sprintf("%v/posts/%v", ts.URL, uuid.New()), nil, http.StatusNotFound,
+	)
+	malformed := do[rest.ErrorResponse](t, http.MethodGet,
+		ts.URL+"/posts/not-an-id", nil, http.StatusBadRequest,
+	)
+	limit := do[rest.ErrorResponse](t, http.MethodGet,
+		ts.URL+"/posts?limit=100000", nil, http.StatusBadRequest,
+	)
+	empty := do[rest.ErrorResponse](t, http.MethodPost, ts.URL+"/posts",
+		rest.CreatePostInput{UserId: user.Id, Title: "title"},
+		http.StatusBadRequest,
+	)
+
+	// Assert
+	assert.Equal(t, "not_found", notFound.Error.Code)
+	assert.Equal(t, "bad_request", malformed.Error.Code)
+	assert.Equal(t, "bad_request", limit.Error.Code)
+	assert.Equal(t, "invalid", empty.Error.Code)
+}
+
+func TestRestOpenAPI(t *testing.T) {
+	// Arrange
+	ts, _ := setup(t)
+
+	// Act
+	doc := do[map[string]any](t, http.MethodGet, ts.URL+"/openapi.json", nil, http.StatusOK)
+
+	// Assert
+	assert.Equal(t, "3.0.3", doc["openapi"])
+	assert.Contains(t, doc["paths"], "/posts/{id}/comments")
+}
+
+func TestRestExport(t *testing.T) {
+	// Arrange
+	ts, user := setupWithToken(t, "token")
+	disabled, _ := setup(t)
+	do[rest.Post](t, http.MethodPost, ts.URL+"/posts",
+		rest.CreatePostInput{UserId: user.Id, Title: "title", Content: "content"},
+		http.StatusCreated,
+	)
+	request := func(url string, token string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+
+		if "" != token {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+
+		return resp
+	}
+
+	// Act
+	missing := request(ts.URL+"/export", "")
+	wrong := request(ts.URL+"/export", "other")
+	malformed := request(ts.URL+"/export?format=xml", "token")
+	notServed := request(disabled.URL+"/export", "token")
+	ok := request(ts.URL+"/export?format=csv&author="+user.Id.String(), "token")
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, missing.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, wrong.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, malformed.StatusCode)
+	assert.Equal(t, http.StatusNotFound, notServed.StatusCode)
+	require.Equal(t, http.StatusOK, ok.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", ok.Header.Get("Content-Type"))
+	body, err := io.ReadAll(ok.Body)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[1], "user,"+user.Id.String()))
+	assert.True(t, strings.HasPrefix(lines[2], "post,"))
+}
+
@@ -0,0 +1,154 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Cases are skipped for subjects without follow repository
+
+func newFollow(userId uuid.UUID, kind models.FollowKind, targetId uuid.UUID) models.Follow {
+	return models.Follow{
+		UserId:       userId,
+		Kind:         kind,
+		TargetId:     targetId,
+		CreationDate: moment(0),
+	}
+}
+
+// Post of another author
+func createForeignPost(t *testing.T, s *Subject, author models.User, minutes int) models.Post {
+	value := newPost(s, minutes, true)
+	value.AuthorId = author.Id
+	v, err := s.Post.CreatePost(context.Background(), value)
+	require.NoError(t, err)
+
+	return normalizePost(v)
+}
+
+func testCreateFollow(t *testing.T, s Subject) {
+	if nil == s.Follow {
+		t.Skip("no follows")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	other := common.Unwrap(s.User.CreateUser(ctx, common.Unwrap(domainOM.UserRandom().Build())))
+	post := createPost(t, &s, 0)
+
+	// Act
+	author, aerr := s.Follow.CreateFollow(ctx, newFollow(other.Id, models.FOLLOW_KIND_AUTHOR, s.Author.Id))
+	again, rerr := s.Follow.CreateFollow(ctx, newFollow(other.Id, models.FOLLOW_KIND_AUTHOR, s.Author.Id))
+	watched, werr := s.Follow.CreateFollow(ctx, newFollow(other.Id, models.FOLLOW_KIND_POST, post.Id))
+	_, uaerr := s.Follow.CreateFollow(ctx, newFollow(other.Id, models.FOLLOW_KIND_AUTHOR, uuid.New()))
+	_, uperr := s.Follow.CreateFollow(ctx, newFollow(other.Id, models.FOLLOW_KIND_POST, uuid.New()))
+	_, uuerr := s.Follow.CreateFollow(ctx, newFollow(uuid.New(), models.FOLLOW_KIND_POST, post.Id))
+
+	// Assert
+	require.NoError(t, aerr)
+	require.NoError(t, rerr)
+	require.NoError(t, werr)
+	assert.True(t, author)
+	assert.False(t, again, "Followed already")
+	assert.True(t, watched, "Kinds are followed separately")
+	assertNotFound(t, uaerr)
+	assertNotFound(t, uperr)
+	assertNotFound(t, uuerr)
+}
+
+func testDeleteFollow(t *testing.T, s Subject) {
+	if nil == s.Follow {
+		t.Skip("no follows")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	other := common.Unwrap(s.User.CreateUser(ctx, common.Unwrap(domainOM.UserRandom().Build())))
+	post := createPost(t, &s, 0)
+	common.Unwrap(s.Follow.CreateFollow(ctx, newFollow(other.Id, models.FOLLOW_KIND_AUTHOR, s.Author.Id)))
+	common.Unwrap(s.Follow.CreateFollow(ctx, newFollow(other.Id, models.FOLLOW_KIND_POST, post.Id)))
+
+	// Act
+	deleted, err := s.Follow.DeleteFollow(ctx, other.Id, models.FOLLOW_KIND_AUTHOR, s.Author.Id)
+	again, aerr := s.Follow.DeleteFollow(ctx, other.Id, models.FOLLOW_KIND_AUTHOR, s.Author.Id)
+	feed := collectOk(t, common.Unwrap(s.Follow.GetFeed(ctx, other.Id)), normalizePost)
+	watched := collectOk(t, common.Unwrap(s.Follow.GetWatchedPosts(ctx, other.Id)), normalizePost)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, aerr)
+	assert.True(t, deleted)
+	assert.False(t, again)
+	assert.Empty(t, feed)
+	assert.Equal(t, []models.Post{post}, watched, "Other kinds are kept")
+}
+
+func testGetFeed(t *testing.T, s Subject) {
+	if nil == s.Follow {
+		t.Skip("no follows")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	reader := common.Unwrap(s.User.CreateUser(ctx, common.Unwrap(domainOM.UserRandom().Build())))
+	followed := common.Unwrap(s.User.CreateUser(ctx, common.Unwrap(domainOM.UserRandom().Build())))
+	posts := []models.Post{
+		createPost(t, &s, 1),
+		createForeignPost(t, &s, followed, 2),
+		createForeignPost(t, &s, reader, 3),
+		createPost(t, &s, 4),
+		createForeignPost(t, &s, followed, 5),
+	}
+	common.Unwrap(s.Follow.CreateFollow(ctx, newFollow(reader.Id, models.FOLLOW_KIND_AUTHOR, s.Author.Id)))
+	common.Unwrap(s.Follow.CreateFollow(ctx, newFollow(reader.Id, models.FOLLOW_KIND_AUTHOR, followed.Id)))
+	common.Unwrap(s.Follow.CreateFollow(ctx, newFollow(s.Author.Id, models.FOLLOW_KIND_AUTHOR, reader.Id)))
+
+	// Act
+	feed := collectOk(t, common.Unwrap(s.Follow.GetFeed(ctx, reader.Id)), normalizePost)
+	col := common.Unwrap(s.Follow.GetFeed(ctx, reader.Id))
+	aerr := col.After(posts[3].Id)
+	col.Limit(2)
+	page := collectOk(t, col, normalizePost)
+
+	// Assert
+	require.NoError(t, aerr)
+	assert.Equal(t, []models.Post{posts[4], posts[3], posts[1], posts[0]}, feed, "Newest first")
+	assert.Equal(t, []uuid.UUID{posts[1].Id, posts[0].Id}, ids(page, postId))
+	assert.Empty(t, collectOk(t, common.Unwrap(s.Follow.GetFeed(ctx, followed.Id)), normalizePost))
+}
+
+func testGetWatchedPosts(t *testing.T, s Subject) {
+	if nil == s.Follow {
+		t.Skip("no follows")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	reader := common.Unwrap(s.User.CreateUser(ctx, common.Unwrap(domainOM.UserRandom().Build())))
+	posts := []models.Post{createPost(t, &s, 1), createPost(t, &s, 2), createPost(t, &s, 3)}
+
+	for _, v := range []models.Post{posts[2], posts[0]} {
+		common.Unwrap(s.Follow.CreateFollow(ctx, newFollow(reader.Id, models.FOLLOW_KIND_POST, v.Id)))
+	}
+
+	common.Unwrap(s.Follow.CreateFollow(ctx, newFollow(reader.Id, models.FOLLOW_KIND_AUTHOR, s.Author.Id)))
+
+	// Act
+	watched := collectOk(t, common.Unwrap(s.Follow.GetWatchedPosts(ctx, reader.Id)), normalizePost)
+	col := common.Unwrap(s.Follow.GetWatchedPosts(ctx, reader.Id))
+	aerr := col.After(posts[2].Id)
+	page := collectOk(t, col, normalizePost)
+
+	// Assert
+	require.NoError(t, aerr)
+	assert.Equal(t, []models.Post{posts[2], posts[0]}, watched, "Newest first")
+	assert.Equal(t, []models.Post{posts[0]}, page)
+}
+
@@ -0,0 +1,131 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/test/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Cases are skipped for subjects without moderation repository
+
+func normalizeReview(v models.Review) models.Review {
+	v.CreationDate = v.CreationDate.UTC()
+	return v
+}
+
+func reviewId(v *models.Review) uuid.UUID {
+	return v.Id
+}
+
+func newReview(s *Subject, minutes int, action models.ModerationAction) models.Review {
+	return models.Review{
+		Kind:         models.REVIEW_KIND_POST_COMMENT,
+		Action:       action,
+		AuthorId:     s.Author.Id,
+		TargetId:     uuid.New(),
+		Content:      "content",
+		Reasons:      []string{"first reason", "second, with comma"},
+		CreationDate: moment(minutes),
+	}
+}
+
+func createReview(t *testing.T, s *Subject, minutes int) models.Review {
+	v, err := s.Moderation.CreateReview(
+		context.Background(),
+		newReview(s, minutes, models.MODERATION_ACTION_HIDE),
+	)
+	require.NoError(t, err)
+
+	return normalizeReview(v)
+}
+
+func testCreateReview(t *testing.T, s Subject) {
+	if nil == s.Moderation {
+		t.Skip("no moderation")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	post := createPost(t, &s, 0)
+	value := newReview(&s, 1, models.MODERATION_ACTION_FLAG)
+	value.Kind = models.REVIEW_KIND_POST
+	value.ContentId = post.Id
+	value.TargetId = uuid.Nil
+	value.Title = "title"
+	value.CommentsAllowed = true
+	unknown := newReview(&s, 2, models.MODERATION_ACTION_HIDE)
+	unknown.AuthorId = uuid.New()
+
+	// Act
+	created, err := s.Moderation.CreateReview(ctx, value)
+	_, uerr := s.Moderation.CreateReview(ctx, unknown)
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, created.Id)
+	value.Id = created.Id
+	assert.Equal(t, value, normalizeReview(created))
+	found := collectOk(t, common.Unwrap(s.Moderation.GetReviewsById(ctx, created.Id)), normalizeReview)
+	assert.Equal(t, []models.Review{value}, found)
+	assertNotFound(t, uerr)
+}
+
+func testGetReviews(t *testing.T, s Subject) {
+	if nil == s.Moderation {
+		t.Skip("no moderation")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	reviews := make([]models.Review, 4)
+
+	for i, minutes := range []int{3, 0, 2, 1} {
+		reviews[i] = createReview(t, &s, minutes)
+	}
+
+	col := common.Unwrap(s.Moderation.GetReviews(ctx))
+	paged := common.Unwrap(s.Moderation.GetReviews(ctx))
+
+	// Act
+	all := collectOk(t, col, normalizeReview)
+	err := paged.After(reviews[1].Id)
+	paged.Limit(2)
+
+	// Assert
+	require.NoError(t, err)
+	expected := []models.Review{reviews[1], reviews[3], reviews[2], reviews[0]}
+	assert.Equal(t, expected, all, "Oldest first")
+	assert.Equal(t, ids(expected[1:3], reviewId), ids(collectOk(t, paged, normalizeReview), reviewId))
+	assertNotFound(t, common.Unwrap(s.Moderation.GetReviews(ctx)).After(uuid.New()))
+}
+
+func testDeleteReview(t *testing.T, s Subject) {
+	if nil == s.Moderation {
+		t.Skip("no moderation")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	deleted := createReview(t, &s, 0)
+	kept := createReview(t, &s, 1)
+
+	// Act
+	err := s.Moderation.DeleteReview(ctx, deleted.Id)
+	uerr := s.Moderation.DeleteReview(ctx, deleted.Id)
+
+	// Assert
+	require.NoError(t, err)
+	assertNotFound(t, uerr)
+	_, errs := collect(t, common.Unwrap(s.Moderation.GetReviewsById(ctx, deleted.Id)), normalizeReview)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, []models.Review{kept}, collectOk(t,
+		common.Unwrap(s.Moderation.GetReviews(ctx)),
+		normalizeReview,
+	))
+}
+
@@ -0,0 +1,182 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	notifrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/notification"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Cases are skipped for subjects without notification repository
+
+func normalizeNotification(v models.Notification) models.Notification {
+	v.CreationDate = v.CreationDate.UTC()
+	return v
+}
+
+func notificationId(v *models.Notification) uuid.UUID {
+	return v.Id
+}
+
+func newNotification(
+	userId uuid.UUID,
+	comment *models.Comment,
+	postId uuid.UUID,
+	minutes int,
+) models.Notification {
+	return models.Notification{
+		UserId:       userId,
+		Kind:         models.NOTIFICATION_KIND_POST_REPLY,
+		ActorId:      comment.AuthorId,
+		PostId:       postId,
+		CommentId:    comment.Id,
+		CreationDate: moment(minutes),
+	}
+}
+
+func notifications(
+	t *testing.T,
+	s *Subject,
+	userId uuid.UUID,
+	unreadOnly bool,
+	order notifrepo.NotificationOrder,
+) []models.Notification {
+	return collectOk(t,
+		common.Unwrap(s.Notification.GetNotificationsByUserId(
+			context.Background(), userId, unreadOnly, order,
+		)),
+		normalizeNotification,
+	)
+}
+
+func testCreateNotificationsSkipsDuplicates(t *testing.T, s Subject) {
+	if nil == s.Notification {
+		t.Skip("no notifications")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	post := createPost(t, &s, 0)
+	first := createPostComment(t, &s, post.Id, 1)
+	second := createPostComment(t, &s, post.Id, 2)
+	fvalue := newNotification(s.Author.Id, &first, post.Id, 1)
+	svalue := newNotification(s.Author.Id, &second, post.Id, 2)
+	svalue.Kind = models.NOTIFICATION_KIND_MENTION
+	again := fvalue
+	again.CreationDate = moment(3)
+	unknown := newNotification(uuid.New(), &second, post.Id, 4)
+
+	// Act
+	err := s.Notification.CreateNotifications(ctx, fvalue, svalue, fvalue)
+	aerr := s.Notification.CreateNotifications(ctx, again)
+	uerr := s.Notification.CreateNotifications(ctx, unknown)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, aerr)
+	assertNotFound(t, uerr)
+	created := notifications(t, &s, s.Author.Id, false, notifrepo.NOTIFICATION_ORDER_DATE_ASC)
+	require.Len(t, created, 2)
+	fvalue.Id, svalue.Id = created[0].Id, created[1].Id
+	assert.Equal(t, []models.Notification{fvalue, svalue}, created)
+	found := collectOk(t,
+		common.Unwrap(s.Notification.GetNotificationsById(ctx, svalue.Id)),
+		normalizeNotification,
+	)
+	assert.Equal(t, []models.Notification{svalue}, found)
+}
+
+func testGetNotificationsByUserId(t *testing.T, s Subject) {
+	if nil == s.Notification {
+		t.Skip("no notifications")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	other := common.Unwrap(s.User.CreateUser(ctx, common.Unwrap(domainOM.UserRandom().Build())))
+	post := createPost(t, &s, 0)
+
+	for i := range 4 {
+		comment := createPostComment(t, &s, post.Id, i+1)
+		require.NoError(t, s.Notification.CreateNotifications(ctx,
+			newNotification(s.Author.Id, &comment, post.Id, i+1),
+			newNotification(other.Id, &comment, post.Id, i+1),
+		))
+	}
+
+	all := notifications(t, &s, s.Author.Id, false, notifrepo.NOTIFICATION_ORDER_DATE_DESC)
+	require.Len(t, all, 4)
+
+	// Act
+	col := common.Unwrap(s.Notification.GetNotificationsByUserId(
+		ctx, s.Author.Id, false, notifrepo.NOTIFICATION_ORDER_DATE_DESC,
+	))
+	aerr := col.After(all[0].Id)
+	col.Limit(2)
+	page := collectOk(t, col, normalizeNotification)
+	asc := notifications(t, &s, s.Author.Id, false, notifrepo.NOTIFICATION_ORDER_DATE_ASC)
+
+	// Assert
+	require.NoError(t, aerr)
+	assert.Equal(t, []uuid.UUID{all[1].Id, all[2].Id}, ids(page, notificationId))
+	assert.Equal(t, moment(4), all[0].CreationDate, "Newest first")
+	assert.Equal(t, []uuid.UUID{all[3].Id, all[2].Id, all[1].Id, all[0].Id},
+		ids(asc, notificationId),
+	)
+	assert.Empty(t, notifications(t, &s, uuid.New(), false, notifrepo.NOTIFICATION_ORDER_DATE_DESC))
+}
+
+func testMarkNotificationsRead(t *testing.T, s Subject) {
+	if nil == s.Notification {
+		t.Skip("no notifications")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	other := common.Unwrap(s.User.CreateUser(ctx, common.Unwrap(domainOM.UserRandom().Build())))
+	post := createPost(t, &s, 0)
+
+	for i := range 3 {
+		comment := createPostComment(t, &s, post.Id, i+1)
+		require.NoError(t, s.Notification.CreateNotifications(ctx,
+			newNotification(s.Author.Id, &comment, post.Id, i+1),
+			newNotification(other.Id, &comment, post.Id, i+1),
+		))
+	}
+
+	all := notifications(t, &s, s.Author.Id, false, notifrepo.NOTIFICATION_ORDER_DATE_ASC)
+	foreign := notifications(t, &s, other.Id, false, notifrepo.NOTIFICATION_ORDER_DATE_ASC)
+	require.Len(t, all, 3)
+
+	// Act
+	marked, err := s.Notification.MarkNotificationsRead(ctx, s.Author.Id, all[0].Id, foreign[1].Id)
+	again, aerr := s.Notification.MarkNotificationsRead(ctx, s.Author.Id, all[0].Id)
+	unread := notifications(t, &s, s.Author.Id, true, notifrepo.NOTIFICATION_ORDER_DATE_ASC)
+	count, cerr := s.Notification.CountUnreadNotifications(ctx, s.Author.Id)
+	rest, rerr := s.Notification.MarkNotificationsRead(ctx, s.Author.Id)
+	after, ferr := s.Notification.CountUnreadNotifications(ctx, s.Author.Id)
+	ocount, oerr := s.Notification.CountUnreadNotifications(ctx, other.Id)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, aerr)
+	require.NoError(t, cerr)
+	require.NoError(t, rerr)
+	require.NoError(t, ferr)
+	require.NoError(t, oerr)
+	assert.Equal(t, 1, marked, "Notifications of other users are ignored")
+	assert.Equal(t, 0, again)
+	assert.Equal(t, []uuid.UUID{all[1].Id, all[2].Id}, ids(unread, notificationId))
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 2, rest, "Every notification is marked without ids")
+	assert.Equal(t, 0, after)
+	assert.Equal(t, 3, ocount)
+	assert.Empty(t, notifications(t, &s, s.Author.Id, true, notifrepo.NOTIFICATION_ORDER_DATE_ASC))
+}
+
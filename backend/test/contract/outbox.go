@@ -0,0 +1,145 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Events are recorded by the writes of the other repositories, cases are
+// skipped for subjects without outbox
+
+func pending(t *testing.T, s *Subject) []models.Event {
+	return collectOk(t, common.Unwrap(s.Outbox.GetPendingEvents(context.Background())),
+		identity[models.Event],
+	)
+}
+
+func eventTypes(values []models.Event) []string {
+	out := make([]string, len(values))
+
+	for i, v := range values {
+		out[i] = v.Type
+	}
+
+	return out
+}
+
+func eventId(v *models.Event) uuid.UUID {
+	return v.Id
+}
+
+func testOutboxRecordsWrites(t *testing.T, s Subject) {
+	if nil == s.Outbox {
+		t.Skip("no outbox")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	user := common.Unwrap(domainOM.UserRandom().WithId(uuid.Nil).Build())
+
+	// Act
+	user, err := s.User.CreateUser(ctx, user)
+	require.NoError(t, err)
+	post := createPost(t, &s, 0)
+	post.Title = "Changed title"
+	_, err = s.Post.UpdatePost(ctx, post)
+	require.NoError(t, err)
+	top := createPostComment(t, &s, post.Id, 1)
+	reply := createCommentComment(t, &s, top.Id, 2)
+	nested := createCommentComment(t, &s, reply.Id, 3)
+
+	// Assert
+	values := pending(t, &s)
+	require.Equal(t, []string{
+		events.USER_CREATED,
+		events.POST_CREATED,
+		events.POST_UPDATED,
+		events.COMMENT_CREATED,
+		events.COMMENT_CREATED,
+		events.COMMENT_CREATED,
+	}, eventTypes(values))
+	assert.Equal(t, []uuid.UUID{user.Id, post.Id, post.Id, top.Id, reply.Id, nested.Id},
+		[]uuid.UUID{
+			values[0].EntityId, values[1].EntityId, values[2].EntityId,
+			values[3].EntityId, values[4].EntityId, values[5].EntityId,
+		},
+	)
+
+	created := common.Unwrap(events.Decode[events.User](&values[0]))
+	assert.Equal(t, events.User{Id: user.Id, Email: user.Email}, created)
+	updated := common.Unwrap(events.Decode[events.Post](&values[2]))
+	assert.Equal(t, "Changed title", updated.Title)
+	assert.Equal(t, s.Author.Id, updated.AuthorId)
+
+	for i, parent := range []*uuid.UUID{nil, &top.Id, &reply.Id} {
+		comment := common.Unwrap(events.Decode[events.Comment](&values[3+i]))
+		assert.Equal(t, post.Id, comment.PostId)
+		assert.Equal(t, parent, comment.ParentId)
+		assert.Equal(t, s.Author.Id, comment.AuthorId)
+	}
+}
+
+func testOutboxFailedWritesRecordNothing(t *testing.T, s Subject) {
+	if nil == s.Outbox {
+		t.Skip("no outbox")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	orphan := newPost(&s, 0, true)
+	orphan.AuthorId = uuid.New()
+	duplicate := common.Unwrap(domainOM.UserRandom().
+		WithId(uuid.Nil).
+		WithEmail(s.Author.Email).
+		Build())
+
+	// Act
+	_, postErr := s.Post.CreatePost(ctx, orphan)
+	_, userErr := s.User.CreateUser(ctx, duplicate)
+	_, commentErr := s.Comment.CreatePostComment(ctx, newComment(&s, uuid.New(), 0))
+
+	// Assert
+	assert.Error(t, postErr)
+	assert.Error(t, userErr)
+	assert.Error(t, commentErr)
+	assert.Empty(t, pending(t, &s))
+}
+
+func testOutboxAcknowledge(t *testing.T, s Subject) {
+	if nil == s.Outbox {
+		t.Skip("no outbox")
+	}
+
+	// Arrange
+	ctx := context.Background()
+
+	for i := range 4 {
+		createPost(t, &s, i)
+	}
+
+	all := ids(pending(t, &s), eventId)
+	require.Len(t, all, 4)
+
+	// Act
+	err := s.Outbox.AcknowledgeEvents(ctx, all[0], all[2])
+	again := s.Outbox.AcknowledgeEvents(ctx, all[0], uuid.New())
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, again)
+	assert.Equal(t, []uuid.UUID{all[1], all[3]}, ids(pending(t, &s), eventId))
+
+	col := common.Unwrap(s.Outbox.GetPendingEvents(ctx))
+	require.NoError(t, pagination.Apply(col, &all[1], 1))
+	assert.Equal(t, []uuid.UUID{all[3]}, ids(collectOk(t, col, identity[models.Event]), eventId))
+}
+
@@ -0,0 +1,118 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/graphql/graph/dataloader/user"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection/iterator"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	srverrors "github.com/muji40k/ozontestcomms/internal/service/errors"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/pagination"
+	"github.com/muji40k/ozontestcomms/internal/service/helpers/singlewrap"
+	"github.com/muji40k/ozontestcomms/misc/result"
+	"github.com/muji40k/ozontestcomms/test/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Consumers reading only a part of a collection must release it, checked
+// against the pool by RunRepository
+
+func testUnwrapMultipleReleases(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	createPost(t, &s, 0)
+	createPost(t, &s, 1)
+
+	// Act
+	_, err := singlewrap.Unwrap(s.Post.GetPosts(ctx, post.POST_ORDER_DATE_ASC))
+
+	// Assert
+	assert.ErrorAs(t, err, &srverrors.ErrorIterMultiple{})
+}
+
+func testCollectErrorReleases(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	created := createPost(t, &s, 0)
+	col := common.Unwrap(s.Post.GetPostsById(ctx, uuid.New(), created.Id))
+
+	// Act
+	found, err := pagination.Collect(col)
+
+	// Assert
+	assertNotFound(t, err)
+	assert.Nil(t, found)
+}
+
+func testCloseAbandonedReleases(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	createPost(t, &s, 0)
+	createPost(t, &s, 1)
+	iter, err := common.Unwrap(s.Post.GetPosts(ctx, post.POST_ORDER_DATE_ASC)).Get()
+	require.NoError(t, err)
+
+	// Act
+	first, next := iter.Next()
+	cerr := iter.Close()
+	_, after := iter.Next()
+
+	// Assert
+	assert.True(t, next)
+	assert.NoError(t, first.Error)
+	assert.NoError(t, cerr)
+	assert.False(t, after, "Closed iterator is exhausted")
+	assert.NoError(t, iter.Close(), "Close is idempotent")
+}
+
+type failingCollection[T any] struct{}
+
+var errFailingGet = errors.New("failing collection")
+
+func (failingCollection[T]) After(uuid.UUID) error { return nil }
+func (failingCollection[T]) Limit(uint)            {}
+
+func (failingCollection[T]) Get() (iterator.Iterator[T], error) {
+	return nil, errFailingGet
+}
+
+func testConcatErrorReleases(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	createPost(t, &s, 0)
+	col := collection.Concat(
+		common.Unwrap(s.Post.GetPosts(ctx, post.POST_ORDER_DATE_ASC)),
+		failingCollection[result.Result[models.Post]]{},
+	)
+
+	// Act
+	iter, err := col.Get()
+
+	// Assert
+	assert.ErrorIs(t, err, errFailingGet)
+	assert.Nil(t, iter)
+}
+
+func testUserLoaderReleases(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	missing := uuid.New()
+
+	// Act
+	found, errs := user.New(s.User)(ctx, []uuid.UUID{missing, s.Author.Id})
+
+	// Assert
+	require.Len(t, found, 2)
+	require.Len(t, errs, 2)
+	assertNotFound(t, errs[0])
+	assert.Nil(t, found[0])
+	assert.NoError(t, errs[1])
+	assert.Equal(t, s.Author.Id, found[1].ID)
+}
+
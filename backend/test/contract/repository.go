@@ -0,0 +1,618 @@
+// Behaviour every repository implementation must share, run from the test
+// of each implementation:
+//
+//	contract.RunRepository(t, func(t *testing.T) contract.Subject { ... })
+package contract
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection/iterator"
+	repoerrors "github.com/muji40k/ozontestcomms/internal/repository/errors"
+	commrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/comment"
+	followrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/follow"
+	moderationrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/moderation"
+	notifrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/notification"
+	"github.com/muji40k/ozontestcomms/internal/repository/interface/outbox"
+	postrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/post"
+	usrrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/user"
+	webhookrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/webhook"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/misc/result"
+	"github.com/muji40k/ozontestcomms/test/common"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Subject struct {
+	Comment commrepo.Repository
+	Post    postrepo.Repository
+	User    usrrepo.Repository
+	// Existing user, content is created on behalf of
+	Author models.User
+	// Optional, repository with the outbox on, events of the writes above
+	// are checked when it's set
+	Outbox outbox.Repository
+	// Optional, cases of webhooks are skipped without it
+	Webhook webhookrepo.Repository
+	// Optional, cases of notifications are skipped without it
+	Notification notifrepo.Repository
+	// Optional, cases of follows are skipped without it
+	Follow followrepo.Repository
+	// Optional, cases of moderation are skipped without it
+	Moderation moderationrepo.Repository
+	// Optional pool of SQL implementations, every connection must be
+	// returned to it after each case
+	Pool *sql.DB
+}
+
+// Must return an empty repository (apart from users) for every call
+type Factory func(t *testing.T) Subject
+
+func RunRepository(t *testing.T, factory Factory) {
+	cases := []struct {
+		name string
+		f    func(*testing.T, Subject)
+	}{
+		{"CreatePost", testCreatePost},
+		{"CreatePostUnknownAuthor", testCreatePostUnknownAuthor},
+		{"UpdatePost", testUpdatePost},
+		{"UpdateUnknownPost", testUpdateUnknownPost},
+		{"CommentsDisabled", testCommentsDisabled},
+		{"GetPostsOrder", testGetPostsOrder},
+		{"GetPostsAfterLimit", testGetPostsAfterLimit},
+		{"GetPostsAfterUnknown", testGetPostsAfterUnknown},
+		{"GetPostsById", testGetPostsById},
+		{"GetPostsByIdAfterLimit", testGetPostsByIdAfterLimit},
+		{"CreatePostComment", testCreatePostComment},
+		{"CreateCommentComment", testCreateCommentComment},
+		{"CreateCommentUnknownTarget", testCreateCommentUnknownTarget},
+		{"GetCommentsOrder", testGetCommentsOrder},
+		{"GetCommentsAfterLimit", testGetCommentsAfterLimit},
+		{"GetCommentsAfterForeign", testGetCommentsAfterForeign},
+		{"GetCommentsUnknownTarget", testGetCommentsUnknownTarget},
+		{"GetCommentsById", testGetCommentsById},
+		{"GetUsersById", testGetUsersById},
+		{"GetUsersByEmail", testGetUsersByEmail},
+		{"CreateUser", testCreateUser},
+		{"CreateUserDuplicateEmail", testCreateUserDuplicateEmail},
+		{"UnwrapMultipleReleases", testUnwrapMultipleReleases},
+		{"CollectErrorReleases", testCollectErrorReleases},
+		{"CloseAbandonedReleases", testCloseAbandonedReleases},
+		{"ConcatErrorReleases", testConcatErrorReleases},
+		{"UserLoaderReleases", testUserLoaderReleases},
+		{"OutboxRecordsWrites", testOutboxRecordsWrites},
+		{"OutboxFailedWritesRecordNothing", testOutboxFailedWritesRecordNothing},
+		{"OutboxAcknowledge", testOutboxAcknowledge},
+		{"CreateWebhook", testCreateWebhook},
+		{"GetWebhooksByEvent", testGetWebhooksByEvent},
+		{"DeleteWebhook", testDeleteWebhook},
+		{"CreateDeliveriesSkipsDuplicates", testCreateDeliveriesSkipsDuplicates},
+		{"GetDeliveries", testGetDeliveries},
+		{"UpdateDeliveryDue", testUpdateDeliveryDue},
+		{"CreateNotificationsSkipsDuplicates", testCreateNotificationsSkipsDuplicates},
+		{"GetNotificationsByUserId", testGetNotificationsByUserId},
+		{"MarkNotificationsRead", testMarkNotificationsRead},
+		{"CreateFollow", testCreateFollow},
+		{"DeleteFollow", testDeleteFollow},
+		{"GetFeed", testGetFeed},
+		{"GetWatchedPosts", testGetWatchedPosts},
+		{"CreateReview", testCreateReview},
+		{"GetReviews", testGetReviews},
+		{"DeleteReview", testDeleteReview},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := factory(t)
+			c.f(t, s)
+
+			if nil != s.Pool {
+				assert.Zero(t, s.Pool.Stats().InUse, "Connections left checked out")
+			}
+		})
+	}
+}
+
+// Only microseconds and the instant itself are kept by every storage
+func at(minutes int) *nullable.Nullable[time.Time] {
+	return nullable.Some(time.Date(2025, 1, 1, 12, minutes, 0, 0, time.UTC))
+}
+
+func normalizePost(v models.Post) models.Post {
+	v.CreationDate = v.CreationDate.UTC()
+	return v
+}
+
+func normalizeComment(v models.Comment) models.Comment {
+	v.CreationDate = v.CreationDate.UTC()
+	return v
+}
+
+func identity[T any](v T) T {
+	return v
+}
+
+// Reads every item, failed ones included, so that nothing is left open
+func collect[T any](
+	t *testing.T,
+	col collection.Collection[result.Result[T]],
+	normalize func(T) T,
+) ([]T, []error) {
+	iter, err := col.Get()
+	require.NoError(t, err)
+	values := make([]T, 0)
+	errs := make([]error, 0)
+
+	for _, v := range iterator.Collect(iter) {
+		if v, err := v.Unwrap(); nil == err {
+			values = append(values, normalize(v))
+		} else {
+			errs = append(errs, err)
+		}
+	}
+
+	return values, errs
+}
+
+func collectOk[T any](
+	t *testing.T,
+	col collection.Collection[result.Result[T]],
+	normalize func(T) T,
+) []T {
+	values, errs := collect(t, col, normalize)
+	require.Empty(t, errs)
+
+	return values
+}
+
+func ids[T any](values []T, id func(*T) uuid.UUID) []uuid.UUID {
+	out := make([]uuid.UUID, len(values))
+
+	for i := range values {
+		out[i] = id(&values[i])
+	}
+
+	return out
+}
+
+func postId(v *models.Post) uuid.UUID {
+	return v.Id
+}
+
+func commentId(v *models.Comment) uuid.UUID {
+	return v.Id
+}
+
+func assertNotFound(t *testing.T, err error) {
+	assert.ErrorAs(t, err, &repoerrors.ErrorNotFound{})
+}
+
+func newPost(s *Subject, minutes int, comments bool) models.Post {
+	return common.Unwrap(domainOM.PostDefault(
+		s.Author.Id, nullable.Some(comments), nullable.None[string](), at(minutes),
+	).Build())
+}
+
+func createPost(t *testing.T, s *Subject, minutes int) models.Post {
+	v, err := s.Post.CreatePost(context.Background(), newPost(s, minutes, true))
+	require.NoError(t, err)
+
+	return normalizePost(v)
+}
+
+func newComment(s *Subject, target uuid.UUID, minutes int) models.Comment {
+	return common.Unwrap(domainOM.CommentDefault(
+		s.Author.Id, target, nullable.None[string](), at(minutes),
+	).Build())
+}
+
+func createPostComment(t *testing.T, s *Subject, postId uuid.UUID, minutes int) models.Comment {
+	v, err := s.Comment.CreatePostComment(context.Background(), newComment(s, postId, minutes))
+	require.NoError(t, err)
+
+	return normalizeComment(v)
+}
+
+func createCommentComment(t *testing.T, s *Subject, commentId uuid.UUID, minutes int) models.Comment {
+	v, err := s.Comment.CreateCommentComment(context.Background(), newComment(s, commentId, minutes))
+	require.NoError(t, err)
+
+	return normalizeComment(v)
+}
+
+func testCreatePost(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	value := newPost(&s, 0, true)
+
+	// Act
+	created, err := s.Post.CreatePost(ctx, value)
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, created.Id)
+	value.Id = created.Id
+	assert.Equal(t, value, normalizePost(created))
+	found := collectOk(t, common.Unwrap(s.Post.GetPostsById(ctx, created.Id)), normalizePost)
+	assert.Equal(t, []models.Post{value}, found)
+}
+
+func testCreatePostUnknownAuthor(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	value := newPost(&s, 0, true)
+	value.AuthorId = uuid.New()
+
+	// Act
+	_, err := s.Post.CreatePost(ctx, value)
+
+	// Assert
+	assert.Error(t, err)
+	posts := common.Unwrap(s.Post.GetPosts(ctx, postrepo.POST_ORDER_DATE_ASC))
+	assert.Empty(t, collectOk(t, posts, normalizePost))
+}
+
+func testUpdatePost(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	created := createPost(t, &s, 0)
+	changed := created
+	changed.Title = "Changed title"
+	changed.Content = "Changed content"
+
+	// Act
+	updated, err := s.Post.UpdatePost(ctx, changed)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, changed, normalizePost(updated))
+	found := collectOk(t, common.Unwrap(s.Post.GetPostsById(ctx, created.Id)), normalizePost)
+	assert.Equal(t, []models.Post{changed}, found)
+}
+
+func testUpdateUnknownPost(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	value := newPost(&s, 0, true)
+
+	// Act
+	_, err := s.Post.UpdatePost(ctx, value)
+
+	// Assert
+	assertNotFound(t, err)
+}
+
+func testCommentsDisabled(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	created, err := s.Post.CreatePost(ctx, newPost(&s, 0, false))
+	require.NoError(t, err)
+	created = normalizePost(created)
+
+	// Act
+	listed := collectOk(t, common.Unwrap(s.Post.GetPosts(ctx, postrepo.POST_ORDER_DATE_ASC)), normalizePost)
+	created.CommentsAllowed = true
+	_, uerr := s.Post.UpdatePost(ctx, created)
+	enabled := collectOk(t, common.Unwrap(s.Post.GetPostsById(ctx, created.Id)), normalizePost)
+
+	// Assert
+	require.Len(t, listed, 1)
+	assert.False(t, listed[0].CommentsAllowed)
+	require.NoError(t, uerr)
+	assert.Equal(t, []models.Post{created}, enabled)
+}
+
+func testGetPostsOrder(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	second := createPost(t, &s, 1)
+	first := createPost(t, &s, 0)
+	third := createPost(t, &s, 2)
+
+	// Act
+	asc := common.Unwrap(s.Post.GetPosts(ctx, postrepo.POST_ORDER_DATE_ASC))
+	desc := common.Unwrap(s.Post.GetPosts(ctx, postrepo.POST_ORDER_DATE_DESC))
+
+	// Assert
+	assert.Equal(t, []models.Post{first, second, third}, collectOk(t, asc, normalizePost))
+	assert.Equal(t, []models.Post{third, second, first}, collectOk(t, desc, normalizePost))
+}
+
+func testGetPostsAfterLimit(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	posts := make([]models.Post, 5)
+
+	for i := range posts {
+		posts[i] = createPost(t, &s, i)
+	}
+
+	asc := common.Unwrap(s.Post.GetPosts(ctx, postrepo.POST_ORDER_DATE_ASC))
+	desc := common.Unwrap(s.Post.GetPosts(ctx, postrepo.POST_ORDER_DATE_DESC))
+	limited := common.Unwrap(s.Post.GetPosts(ctx, postrepo.POST_ORDER_DATE_ASC))
+
+	// Act
+	aerr := asc.After(posts[1].Id)
+	asc.Limit(2)
+	derr := desc.After(posts[1].Id)
+	limited.Limit(10)
+
+	// Assert
+	require.NoError(t, aerr)
+	require.NoError(t, derr)
+	assert.Equal(t, ids(posts[2:4], postId), ids(collectOk(t, asc, normalizePost), postId))
+	assert.Equal(t, []uuid.UUID{posts[0].Id}, ids(collectOk(t, desc, normalizePost), postId))
+	assert.Equal(t, ids(posts, postId), ids(collectOk(t, limited, normalizePost), postId))
+}
+
+func testGetPostsAfterUnknown(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	createPost(t, &s, 0)
+	col := common.Unwrap(s.Post.GetPosts(ctx, postrepo.POST_ORDER_DATE_ASC))
+
+	// Act
+	err := col.After(uuid.New())
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func testGetPostsById(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	first := createPost(t, &s, 0)
+	second := createPost(t, &s, 1)
+	missing := uuid.New()
+
+	// Act
+	found, errs := collect(
+		t,
+		common.Unwrap(s.Post.GetPostsById(ctx, second.Id, missing, first.Id)),
+		normalizePost,
+	)
+	empty := common.Unwrap(s.Post.GetPostsById(ctx))
+
+	// Assert
+	assert.Equal(t, []models.Post{second, first}, found, "Requested order is kept")
+	require.Len(t, errs, 1)
+	assertNotFound(t, errs[0])
+	assert.Empty(t, collectOk(t, empty, normalizePost))
+}
+
+func testGetPostsByIdAfterLimit(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	posts := make([]models.Post, 4)
+
+	for i := range posts {
+		posts[i] = createPost(t, &s, i)
+	}
+
+	col := common.Unwrap(s.Post.GetPostsById(ctx, ids(posts, postId)...))
+	foreign := common.Unwrap(s.Post.GetPostsById(ctx, posts[0].Id))
+
+	// Act
+	err := col.After(posts[0].Id)
+	col.Limit(2)
+	ferr := foreign.After(posts[1].Id)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, posts[1:3], collectOk(t, col, normalizePost), "Starts after the element")
+	assert.Error(t, ferr, "Id isn't in the requested list")
+}
+
+func testCreatePostComment(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	post := createPost(t, &s, 0)
+	value := newComment(&s, post.Id, 1)
+
+	// Act
+	created, err := s.Comment.CreatePostComment(ctx, value)
+	other := createPostComment(t, &s, post.Id, 2)
+
+	// Assert
+	require.NoError(t, err)
+	created = normalizeComment(created)
+	assert.NotEqual(t, uuid.Nil, created.Id)
+	assert.Equal(t, value.Content, created.Content)
+	assert.Equal(t, value.AuthorId, created.AuthorId)
+	assert.Equal(t, other.TargetId, created.TargetId, "Comments of a post share target")
+	found := collectOk(t, common.Unwrap(s.Comment.GetCommentsById(ctx, created.Id)), normalizeComment)
+	assert.Equal(t, []models.Comment{created}, found)
+}
+
+func testCreateCommentComment(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	post := createPost(t, &s, 0)
+	root := createPostComment(t, &s, post.Id, 1)
+
+	// Act
+	reply, err := s.Comment.CreateCommentComment(ctx, newComment(&s, root.Id, 2))
+
+	// Assert
+	require.NoError(t, err)
+	reply = normalizeComment(reply)
+	assert.NotEqual(t, root.TargetId, reply.TargetId)
+	replies := common.Unwrap(s.Comment.GetCommentsByCommentId(ctx, root.Id, commrepo.COMMENT_ORDER_DATE_ASC))
+	assert.Equal(t, []models.Comment{reply}, collectOk(t, replies, normalizeComment))
+	roots := common.Unwrap(s.Comment.GetCommentsByPostId(ctx, post.Id, commrepo.COMMENT_ORDER_DATE_ASC))
+	assert.Equal(t, []models.Comment{root}, collectOk(t, roots, normalizeComment), "Replies aren't listed")
+}
+
+func testCreateCommentUnknownTarget(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	post := createPost(t, &s, 0)
+
+	// Act
+	_, perr := s.Comment.CreatePostComment(ctx, newComment(&s, uuid.New(), 1))
+	_, cerr := s.Comment.CreateCommentComment(ctx, newComment(&s, uuid.New(), 1))
+	_, postAsComment := s.Comment.CreateCommentComment(ctx, newComment(&s, post.Id, 1))
+
+	// Assert
+	assertNotFound(t, perr)
+	assertNotFound(t, cerr)
+	assertNotFound(t, postAsComment)
+}
+
+func testGetCommentsOrder(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	post := createPost(t, &s, 0)
+	second := createPostComment(t, &s, post.Id, 2)
+	first := createPostComment(t, &s, post.Id, 1)
+
+	// Act
+	asc := common.Unwrap(s.Comment.GetCommentsByPostId(ctx, post.Id, commrepo.COMMENT_ORDER_DATE_ASC))
+	desc := common.Unwrap(s.Comment.GetCommentsByPostId(ctx, post.Id, commrepo.COMMENT_ORDER_DATE_DESC))
+
+	// Assert
+	assert.Equal(t, []models.Comment{first, second}, collectOk(t, asc, normalizeComment))
+	assert.Equal(t, []models.Comment{second, first}, collectOk(t, desc, normalizeComment))
+}
+
+func testGetCommentsAfterLimit(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	post := createPost(t, &s, 0)
+	root := createPostComment(t, &s, post.Id, 1)
+	replies := make([]models.Comment, 4)
+
+	for i := range replies {
+		replies[i] = createCommentComment(t, &s, root.Id, 2+i)
+	}
+
+	col := common.Unwrap(s.Comment.GetCommentsByCommentId(ctx, root.Id, commrepo.COMMENT_ORDER_DATE_DESC))
+
+	// Act
+	err := col.After(replies[3].Id)
+	col.Limit(2)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]uuid.UUID{replies[2].Id, replies[1].Id},
+		ids(collectOk(t, col, normalizeComment), commentId),
+	)
+}
+
+func testGetCommentsAfterForeign(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	first := createPost(t, &s, 0)
+	second := createPost(t, &s, 1)
+	createPostComment(t, &s, first.Id, 2)
+	foreign := createPostComment(t, &s, second.Id, 3)
+	col := common.Unwrap(s.Comment.GetCommentsByPostId(ctx, first.Id, commrepo.COMMENT_ORDER_DATE_ASC))
+
+	// Act
+	err := col.After(foreign.Id)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func testGetCommentsUnknownTarget(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+
+	// Act
+	_, perr := s.Comment.GetCommentsByPostId(ctx, uuid.New(), commrepo.COMMENT_ORDER_DATE_ASC)
+	_, cerr := s.Comment.GetCommentsByCommentId(ctx, uuid.New(), commrepo.COMMENT_ORDER_DATE_ASC)
+
+	// Assert
+	assertNotFound(t, perr)
+	assertNotFound(t, cerr)
+}
+
+func testGetCommentsById(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	post := createPost(t, &s, 0)
+	first := createPostComment(t, &s, post.Id, 1)
+	second := createCommentComment(t, &s, first.Id, 2)
+	missing := uuid.New()
+	col := common.Unwrap(s.Comment.GetCommentsById(ctx, missing, second.Id, first.Id))
+
+	// Act
+	err := col.After(missing)
+	found, errs := collect(t, col, normalizeComment)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []models.Comment{second, first}, found)
+	assert.Empty(t, errs)
+}
+
+func testGetUsersById(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	missing := uuid.New()
+
+	// Act
+	found, errs := collect(t, common.Unwrap(s.User.GetUsersById(ctx, missing, s.Author.Id)), identity)
+
+	// Assert
+	assert.Equal(t, []models.User{s.Author}, found)
+	require.Len(t, errs, 1)
+	assertNotFound(t, errs[0])
+}
+
+func testGetUsersByEmail(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	other := common.Unwrap(s.User.CreateUser(ctx, common.Unwrap(domainOM.UserRandom().Build())))
+
+	// Act
+	found := collectOk(t, common.Unwrap(s.User.GetUsersByEmail(ctx,
+		"missing@mail.com", other.Email, s.Author.Email,
+	)), identity)
+	empty := collectOk(t, common.Unwrap(s.User.GetUsersByEmail(ctx)), identity)
+
+	// Assert
+	assert.ElementsMatch(t, []models.User{s.Author, other}, found)
+	assert.Empty(t, empty)
+}
+
+func testCreateUser(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	value := common.Unwrap(domainOM.UserRandom().Build())
+
+	// Act
+	created, err := s.User.CreateUser(ctx, value)
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, created.Id)
+	value.Id = created.Id
+	assert.Equal(t, value, created)
+	found := collectOk(t, common.Unwrap(s.User.GetUsersById(ctx, created.Id)), identity)
+	assert.Equal(t, []models.User{value}, found)
+}
+
+func testCreateUserDuplicateEmail(t *testing.T, s Subject) {
+	// Arrange
+	ctx := context.Background()
+	value := common.Unwrap(domainOM.UserRandom().Build())
+	value.Email = s.Author.Email
+
+	// Act
+	_, err := s.User.CreateUser(ctx, value)
+
+	// Assert
+	assert.ErrorAs(t, err, &repoerrors.ErrorDuplicate{})
+}
+
@@ -0,0 +1,297 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/misc/nullable"
+	"github.com/muji40k/ozontestcomms/test/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Cases are skipped for subjects without webhook repository
+
+func moment(minutes int) time.Time {
+	return nullable.Unwrap(at(minutes))
+}
+
+func normalizeWebhook(v models.Webhook) models.Webhook {
+	v.CreationDate = v.CreationDate.UTC()
+	return v
+}
+
+func normalizeDelivery(v models.WebhookDelivery) models.WebhookDelivery {
+	v.LastAttempt = v.LastAttempt.UTC()
+	v.NextAttempt = v.NextAttempt.UTC()
+	v.CreationDate = v.CreationDate.UTC()
+	return v
+}
+
+func webhookId(v *models.Webhook) uuid.UUID {
+	return v.Id
+}
+
+func deliveryId(v *models.WebhookDelivery) uuid.UUID {
+	return v.Id
+}
+
+func newWebhook(s *Subject, minutes int, postId *uuid.UUID, kinds ...string) models.Webhook {
+	out := models.Webhook{
+		OwnerId:      s.Author.Id,
+		Url:          "http://127.0.0.1/hook",
+		Secret:       "secret",
+		Events:       kinds,
+		CreationDate: moment(minutes),
+	}
+
+	if nil != postId {
+		out.PostId = uuid.NullUUID{UUID: *postId, Valid: true}
+	}
+
+	return out
+}
+
+func createWebhook(t *testing.T, s *Subject, minutes int, postId *uuid.UUID, kinds ...string) models.Webhook {
+	v, err := s.Webhook.CreateWebhook(context.Background(), newWebhook(s, minutes, postId, kinds...))
+	require.NoError(t, err)
+
+	return normalizeWebhook(v)
+}
+
+func newDelivery(webhook *models.Webhook, minutes int) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		WebhookId:    webhook.Id,
+		EventId:      uuid.New(),
+		EventType:    events.COMMENT_CREATED,
+		Payload:      []byte(`{"type":"comment.created"}`),
+		Status:       models.DELIVERY_STATUS_PENDING,
+		NextAttempt:  moment(minutes),
+		CreationDate: moment(minutes),
+	}
+}
+
+func deliveries(t *testing.T, s *Subject, webhookId uuid.UUID) []models.WebhookDelivery {
+	return collectOk(t,
+		common.Unwrap(s.Webhook.GetDeliveriesByWebhookId(context.Background(), webhookId)),
+		normalizeDelivery,
+	)
+}
+
+func testCreateWebhook(t *testing.T, s Subject) {
+	if nil == s.Webhook {
+		t.Skip("no webhooks")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	post := createPost(t, &s, 0)
+	value := newWebhook(&s, 2, &post.Id, events.POST_UPDATED, events.COMMENT_CREATED)
+	older := createWebhook(t, &s, 1, nil, events.POST_CREATED)
+	unknown := newWebhook(&s, 3, nil, events.POST_CREATED)
+	unknown.OwnerId = uuid.New()
+
+	// Act
+	created, err := s.Webhook.CreateWebhook(ctx, value)
+	_, uerr := s.Webhook.CreateWebhook(ctx, unknown)
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, created.Id)
+	value.Id = created.Id
+	assert.Equal(t, value, normalizeWebhook(created))
+	found := collectOk(t, common.Unwrap(s.Webhook.GetWebhooksById(ctx, created.Id)), normalizeWebhook)
+	assert.Equal(t, []models.Webhook{value}, found)
+	owned := collectOk(t,
+		common.Unwrap(s.Webhook.GetWebhooksByOwnerId(ctx, s.Author.Id)),
+		normalizeWebhook,
+	)
+	assert.Equal(t, []models.Webhook{older, value}, owned)
+	assertNotFound(t, uerr)
+}
+
+func testGetWebhooksByEvent(t *testing.T, s Subject) {
+	if nil == s.Webhook {
+		t.Skip("no webhooks")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	followed := createPost(t, &s, 0)
+	other := createPost(t, &s, 1)
+	everything := createWebhook(t, &s, 2, nil, events.POST_UPDATED, events.COMMENT_CREATED)
+	single := createWebhook(t, &s, 3, &followed.Id, events.COMMENT_CREATED)
+	createWebhook(t, &s, 4, nil, events.POST_CREATED)
+
+	// Act
+	fcol, ferr := s.Webhook.GetWebhooksByEvent(ctx, events.COMMENT_CREATED, followed.Id)
+	ocol, oerr := s.Webhook.GetWebhooksByEvent(ctx, events.COMMENT_CREATED, other.Id)
+	ucol, uerr := s.Webhook.GetWebhooksByEvent(ctx, events.USER_CREATED, other.Id)
+
+	// Assert
+	require.NoError(t, ferr)
+	require.NoError(t, oerr)
+	require.NoError(t, uerr)
+	assert.Equal(t, []uuid.UUID{everything.Id, single.Id},
+		ids(collectOk(t, fcol, normalizeWebhook), webhookId),
+	)
+	assert.Equal(t, []uuid.UUID{everything.Id},
+		ids(collectOk(t, ocol, normalizeWebhook), webhookId),
+	)
+	assert.Empty(t, collectOk(t, ucol, normalizeWebhook))
+}
+
+func testDeleteWebhook(t *testing.T, s Subject) {
+	if nil == s.Webhook {
+		t.Skip("no webhooks")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	webhook := createWebhook(t, &s, 0, nil, events.COMMENT_CREATED)
+	delivery := newDelivery(&webhook, 1)
+	require.NoError(t, s.Webhook.CreateDeliveries(ctx, delivery))
+	created := deliveries(t, &s, webhook.Id)
+	require.Len(t, created, 1)
+
+	// Act
+	err := s.Webhook.DeleteWebhook(ctx, webhook.Id)
+	uerr := s.Webhook.DeleteWebhook(ctx, webhook.Id)
+
+	// Assert
+	require.NoError(t, err)
+	assertNotFound(t, uerr)
+	_, errs := collect(t, common.Unwrap(s.Webhook.GetWebhooksById(ctx, webhook.Id)), normalizeWebhook)
+	assert.Len(t, errs, 1)
+	_, derrs := collect(t,
+		common.Unwrap(s.Webhook.GetDeliveriesById(ctx, created[0].Id)),
+		normalizeDelivery,
+	)
+	assert.Len(t, derrs, 1, "Deliveries are removed with the webhook")
+	assert.Empty(t, collectOk(t,
+		common.Unwrap(s.Webhook.GetDueDeliveries(ctx, moment(59))),
+		normalizeDelivery,
+	))
+	_, gerr := s.Webhook.GetDeliveriesByWebhookId(ctx, webhook.Id)
+	assertNotFound(t, gerr)
+}
+
+func testCreateDeliveriesSkipsDuplicates(t *testing.T, s Subject) {
+	if nil == s.Webhook {
+		t.Skip("no webhooks")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	webhook := createWebhook(t, &s, 0, nil, events.COMMENT_CREATED)
+	first := newDelivery(&webhook, 1)
+	second := newDelivery(&webhook, 2)
+	again := first
+	again.CreationDate = moment(3)
+	unknown := newDelivery(&webhook, 4)
+	unknown.WebhookId = uuid.New()
+
+	// Act
+	err := s.Webhook.CreateDeliveries(ctx, first, second)
+	aerr := s.Webhook.CreateDeliveries(ctx, again)
+	uerr := s.Webhook.CreateDeliveries(ctx, unknown)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, aerr)
+	assertNotFound(t, uerr)
+	created := deliveries(t, &s, webhook.Id)
+	require.Len(t, created, 2)
+	first.Id, second.Id = created[1].Id, created[0].Id
+	assert.Equal(t, []models.WebhookDelivery{second, first}, created)
+}
+
+func testGetDeliveries(t *testing.T, s Subject) {
+	if nil == s.Webhook {
+		t.Skip("no webhooks")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	webhook := createWebhook(t, &s, 0, nil, events.COMMENT_CREATED)
+	other := createWebhook(t, &s, 0, nil, events.COMMENT_CREATED)
+
+	for i := range 4 {
+		require.NoError(t, s.Webhook.CreateDeliveries(ctx, newDelivery(&webhook, i+1)))
+	}
+
+	require.NoError(t, s.Webhook.CreateDeliveries(ctx, newDelivery(&other, 10)))
+	all := deliveries(t, &s, webhook.Id)
+	require.Len(t, all, 4)
+
+	// Act
+	col := common.Unwrap(s.Webhook.GetDeliveriesByWebhookId(ctx, webhook.Id))
+	aerr := col.After(all[0].Id)
+	col.Limit(2)
+	page := collectOk(t, col, normalizeDelivery)
+	found := collectOk(t,
+		common.Unwrap(s.Webhook.GetDeliveriesById(ctx, all[2].Id, all[1].Id)),
+		normalizeDelivery,
+	)
+
+	// Assert
+	require.NoError(t, aerr)
+	assert.Equal(t, []uuid.UUID{all[1].Id, all[2].Id}, ids(page, deliveryId))
+	assert.Equal(t, moment(4), all[0].CreationDate, "Newest first")
+	assert.Equal(t, []models.WebhookDelivery{all[2], all[1]}, found)
+}
+
+func testUpdateDeliveryDue(t *testing.T, s Subject) {
+	if nil == s.Webhook {
+		t.Skip("no webhooks")
+	}
+
+	// Arrange
+	ctx := context.Background()
+	webhook := createWebhook(t, &s, 0, nil, events.COMMENT_CREATED)
+	require.NoError(t, s.Webhook.CreateDeliveries(ctx,
+		newDelivery(&webhook, 3),
+		newDelivery(&webhook, 1),
+		newDelivery(&webhook, 2),
+		newDelivery(&webhook, 30),
+	))
+	all := deliveries(t, &s, webhook.Id)
+	require.Len(t, all, 4)
+	delivered, retried := all[3], all[2]
+	delivered.Status = models.DELIVERY_STATUS_DELIVERED
+	delivered.Attempts = 1
+	delivered.StatusCode = 204
+	delivered.LastAttempt = moment(5)
+	retried.Attempts = 1
+	retried.StatusCode = 500
+	retried.Error = "Internal Server Error"
+	retried.LastAttempt = moment(5)
+	retried.NextAttempt = moment(20)
+	unknown := retried
+	unknown.Id = uuid.New()
+
+	// Act
+	_, derr := s.Webhook.UpdateDelivery(ctx, delivered)
+	_, rerr := s.Webhook.UpdateDelivery(ctx, retried)
+	_, uerr := s.Webhook.UpdateDelivery(ctx, unknown)
+	due := collectOk(t,
+		common.Unwrap(s.Webhook.GetDueDeliveries(ctx, moment(20))),
+		normalizeDelivery,
+	)
+
+	// Assert
+	require.NoError(t, derr)
+	require.NoError(t, rerr)
+	assertNotFound(t, uerr)
+	assert.Equal(t, []uuid.UUID{all[1].Id, all[2].Id}, ids(due, deliveryId),
+		"Delivered one is gone, retried one is due later, the last isn't due yet",
+	)
+	assert.Equal(t, retried, due[1])
+	found := collectOk(t, common.Unwrap(s.Webhook.GetDeliveriesById(ctx, delivered.Id)), normalizeDelivery)
+	assert.Equal(t, []models.WebhookDelivery{delivered}, found)
+}
+
@@ -0,0 +1,293 @@
+// In-process harness for end-to-end tests of the GraphQL API over the
+// in-memory repository
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/google/uuid"
+	"github.com/muji40k/ozontestcomms/builders/applications/graphql"
+	"github.com/muji40k/ozontestcomms/builders/services/domain"
+	"github.com/muji40k/ozontestcomms/internal/application/dispatcher"
+	"github.com/muji40k/ozontestcomms/internal/domain/events"
+	"github.com/muji40k/ozontestcomms/internal/domain/models"
+	"github.com/muji40k/ozontestcomms/internal/domain/moderation"
+	"github.com/muji40k/ozontestcomms/internal/repository/collection"
+	"github.com/muji40k/ozontestcomms/internal/repository/implementations/inmemory"
+	webhookrepo "github.com/muji40k/ozontestcomms/internal/repository/interface/webhook"
+	"github.com/muji40k/ozontestcomms/internal/seed"
+	usrsrv "github.com/muji40k/ozontestcomms/internal/service/interface/user"
+	"github.com/muji40k/ozontestcomms/misc/result"
+	"github.com/muji40k/ozontestcomms/test/common"
+	"github.com/muji40k/ozontestcomms/test/fixtures"
+	domainOM "github.com/muji40k/ozontestcomms/test/mothers/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type GraphQL struct {
+	t      *testing.T
+	server *httptest.Server
+	repos  seed.Repositories
+	// Registered users, content is created on behalf of. The first one, if
+	// any, is the moderator
+	Users []models.User
+	// Deliveries are scheduled by the event dispatcher, which isn't
+	// running here, so tests make them directly
+	Webhooks webhookrepo.Repository
+	// Only notifications are handled, pending events are delivered on
+	// Dispatch
+	Events *dispatcher.Dispatcher
+	users  *countingUsers
+}
+
+// Counts batches the GraphQL API requests users by
+type countingUsers struct {
+	usrsrv.Service
+	batches atomic.Int64
+}
+
+func (self *countingUsers) GetUsersById(
+	ctx context.Context,
+	ids ...uuid.UUID,
+) (collection.Collection[result.Result[models.User]], error) {
+	self.batches.Add(1)
+	return self.Service.GetUsersById(ctx, ids...)
+}
+
+type Error struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path"`
+}
+
+type Response struct {
+	t *testing.T
+	// Requests rejected before execution (parse and validation errors) get
+	// 422 instead of 200
+	Status int             `json:"-"`
+	Data   json.RawMessage `json:"data"`
+	Errors []Error         `json:"errors"`
+}
+
+// Content containing the words is moderated with the action
+var MODERATED_WORDS = map[string]models.ModerationAction{
+	"dubious": models.MODERATION_ACTION_FLAG,
+	"spam":    models.MODERATION_ACTION_HIDE,
+	"scam":    models.MODERATION_ACTION_REJECT,
+}
+
+// Starts the server with given number of users, it's stopped with the test
+func NewGraphQL(t *testing.T, users int) *GraphQL {
+	registered := make([]models.User, users)
+
+	for i := range registered {
+		registered[i] = common.Unwrap(domainOM.UserRandom().Build())
+	}
+
+	repo := inmemory.New(
+		func(adduser func(models.User), _ func(inmemory.Comment), _ func(models.Post)) {
+			for _, v := range registered {
+				adduser(v)
+			}
+		},
+	).WithOutbox()
+	checkers := make([]moderation.Checker, 0, len(MODERATED_WORDS))
+
+	for word, action := range MODERATED_WORDS {
+		checkers = append(checkers, moderation.NewBannedWords(action, word))
+	}
+
+	svc, err := domain.NewLogicBuilder().
+		WithCommentRepository(repo).
+		WithPostRepository(repo).
+		WithUserRepository(repo).
+		WithModeration(moderation.NewPipeline(checkers...), repo).
+		Build()
+	require.NoError(t, err)
+	// Receivers of the tests are local servers
+	webhooks, err := domain.NewWebhookLogicBuilder().
+		WithWebhookRepository(repo).
+		WithPostRepository(repo).
+		WithUserRepository(repo).
+		WithAllowInternal(true).
+		Build()
+	require.NoError(t, err)
+	notifications, err := domain.NewNotificationLogicBuilder().
+		WithNotificationRepository(repo).
+		WithCommentRepository(repo).
+		WithPostRepository(repo).
+		WithUserRepository(repo).
+		Build()
+	require.NoError(t, err)
+	follows, err := domain.NewFollowLogicBuilder().
+		WithFollowRepository(repo).
+		Build()
+	require.NoError(t, err)
+	moderators := make([]uuid.UUID, 0, 1)
+
+	if 0 != users {
+		moderators = append(moderators, registered[0].Id)
+	}
+
+	reviews, err := domain.NewModerationLogicBuilder().
+		WithReviewRepository(repo).
+		WithPublisher(svc).
+		WithModerators(moderators...).
+		Build()
+	require.NoError(t, err)
+	counted := &countingUsers{Service: svc}
+	handled := dispatcher.New(repo, time.Second, 100)
+	handled.Register(events.COMMENT_CREATED, notifications.Handle)
+
+	app, err := graphql.NewServerBuilder().
+		WithHost("127.0.0.1").
+		WithPort("0").
+		WithLoaderDuration(time.Millisecond).
+		WithShutdownTimeout(time.Second).
+		WithCommentService(svc).
+		WithPostService(svc).
+		WithUserService(counted).
+		WithHealthService(svc).
+		WithWebhookService(webhooks).
+		WithNotificationService(notifications).
+		WithFollowService(follows).
+		WithModerationService(reviews).
+		WithWatchInterval(10 * time.Millisecond).
+		Build()
+	require.NoError(t, err)
+
+	server := httptest.NewServer(app.Handler())
+	t.Cleanup(server.Close)
+
+	return &GraphQL{t, server, seed.Repositories{User: repo, Post: repo, Comment: repo}, registered, repo, handled, counted}
+}
+
+// Number of batches users were requested by so far
+func (self *GraphQL) UserBatches() int64 {
+	return self.users.batches.Load()
+}
+
+// Loads fixture from test/fixtures into the repository
+func (self *GraphQL) Load(name string) *seed.Loaded {
+	return fixtures.Load(self.t, self.repos, name)
+}
+
+// Endpoint for clients other than Do
+func (self *GraphQL) URL() string {
+	return self.server.URL + "/query"
+}
+
+// Runs query or mutation, failing the test on transport errors only
+func (self *GraphQL) Do(query string, variables map[string]any) *Response {
+	body, err := json.Marshal(map[string]any{
+		"query":     query,
+		"variables": variables,
+	})
+	require.NoError(self.t, err)
+
+	resp, err := self.server.Client().Post(
+		self.URL(),
+		"application/json",
+		bytes.NewReader(body),
+	)
+	require.NoError(self.t, err)
+	defer resp.Body.Close()
+
+	out := &Response{t: self.t, Status: resp.StatusCode}
+	require.NoError(self.t, json.NewDecoder(resp.Body).Decode(out))
+
+	return out
+}
+
+// Subscription over websocket, closed along with the test
+func (self *GraphQL) Subscribe(query string, variables map[string]any) *client.Subscription {
+	options := make([]client.Option, 0, len(variables))
+
+	for name, value := range variables {
+		options = append(options, client.Var(name, value))
+	}
+
+	out := client.New(self.server.Config.Handler, client.Path("/query")).Websocket(query, options...)
+	self.t.Cleanup(func() { out.Close() })
+
+	return out
+}
+
+// Same as Do, but the response must have no errors
+func (self *GraphQL) Must(query string, variables map[string]any) *Response {
+	out := self.Do(query, variables)
+	require.Empty(self.t, out.Errors, "query: %v", query)
+	require.Equal(self.t, http.StatusOK, out.Status)
+
+	return out
+}
+
+// Decodes the data into v
+func (self *Response) Decode(v any) {
+	require.NoError(self.t, json.Unmarshal(self.Data, v))
+}
+
+// Value at dotted path in data, list items are addressed by index, e.g.
+// "posts.data.0.id"
+func (self *Response) Get(path string) any {
+	var current any
+	self.Decode(&current)
+
+	for _, key := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case map[string]any:
+			value, found := v[key]
+			require.True(self.t, found, "no %q in path %q", key, path)
+			current = value
+		case []any:
+			i, err := strconv.Atoi(key)
+			require.NoError(self.t, err, "list index expected in path %q", path)
+			require.Greater(self.t, len(v), i, "no item %v in path %q", i, path)
+			current = v[i]
+		default:
+			require.Failf(self.t, "unexpected value", "%q of path %q isn't a container", key, path)
+		}
+	}
+
+	return current
+}
+
+func (self *Response) String(path string) string {
+	v, ok := self.Get(path).(string)
+	require.True(self.t, ok, "%q isn't a string", path)
+
+	return v
+}
+
+// Length of list at path
+func (self *Response) Len(path string) int {
+	v, ok := self.Get(path).([]any)
+	require.True(self.t, ok, "%q isn't a list", path)
+
+	return len(v)
+}
+
+// Values of key in every item of list at path
+func (self *Response) Pluck(path string, key string) []any {
+	list, ok := self.Get(path).([]any)
+	require.True(self.t, ok, "%q isn't a list", path)
+	out := make([]any, len(list))
+
+	for i, item := range list {
+		v, ok := item.(map[string]any)
+		require.True(self.t, ok, "item %v of %q isn't an object", i, path)
+		out[i] = v[key]
+	}
+
+	return out
+}
+
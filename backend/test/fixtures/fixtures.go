@@ -0,0 +1,42 @@
+// Fixture files for tests, see seed.Fixture for the format
+package fixtures
+
+import (
+	"context"
+	"embed"
+	"testing"
+
+	"github.com/muji40k/ozontestcomms/internal/seed"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed *.yaml
+var files embed.FS
+
+const (
+	// Post "welcome" with three levels of replies and post "rules" with
+	// comments disabled
+	THREAD = "thread.yaml"
+	// Seven posts "p1".."p7", one a day
+	FEED = "feed.yaml"
+)
+
+func Read(t *testing.T, name string) seed.Fixture {
+	file, err := files.Open(name)
+	require.NoError(t, err)
+	defer file.Close()
+
+	fixture, err := seed.ReadFixture(file)
+	require.NoError(t, err)
+
+	return fixture
+}
+
+func Load(t *testing.T, repos seed.Repositories, name string) *seed.Loaded {
+	fixture := Read(t, name)
+	loaded, _, err := seed.Load(context.Background(), repos, &fixture)
+	require.NoError(t, err)
+
+	return loaded
+}
+
@@ -7,4 +7,9 @@ create database poster;
 \i /scripts/init_commentables.sql
 \i /scripts/init_posts.sql
 \i /scripts/init_comments.sql
+\i /scripts/init_outbox.sql
+\i /scripts/init_webhooks.sql
+\i /scripts/init_notifications.sql
+\i /scripts/init_follows.sql
+\i /scripts/init_moderation.sql
 
@@ -0,0 +1,42 @@
+\c poster
+
+drop schema if exists follows cascade;
+create schema follows;
+
+drop table if exists follows.authors;
+create table follows.authors
+(
+    user_id uuid not null,
+    author_id uuid not null,
+    creation_date timestamptz not null,
+    primary key (user_id, author_id)
+);
+
+alter table follows.authors add
+    constraint "fkey_follow_user_id"
+    foreign key (user_id)
+    references users.users(id);
+
+alter table follows.authors add
+    constraint "fkey_follow_author_id"
+    foreign key (author_id)
+    references users.users(id);
+
+drop table if exists follows.posts;
+create table follows.posts
+(
+    user_id uuid not null,
+    post_id uuid not null,
+    creation_date timestamptz not null,
+    primary key (user_id, post_id)
+);
+
+alter table follows.posts add
+    constraint "fkey_follow_user_id"
+    foreign key (user_id)
+    references users.users(id);
+
+alter table follows.posts add
+    constraint "fkey_follow_post_id"
+    foreign key (post_id)
+    references posts.posts(id);
@@ -0,0 +1,30 @@
+\c poster
+
+drop schema if exists moderation cascade;
+create schema moderation;
+
+-- Content waiting for moderators, hidden content is kept only here. Target
+-- is either a post or a comment, reasons are newline separated
+drop table if exists moderation.reviews;
+create table moderation.reviews
+(
+    id uuid primary key,
+    kind integer not null,
+    action integer not null,
+    content_id uuid,
+    author_id uuid not null,
+    target_id uuid,
+    title text not null,
+    content text not null,
+    comments_allowed boolean not null,
+    reasons text not null,
+    creation_date timestamptz not null
+);
+
+alter table moderation.reviews add
+    constraint "fkey_review_author_id"
+    foreign key (author_id)
+    references users.users(id);
+
+create index reviews_creation_date
+    on moderation.reviews(creation_date);
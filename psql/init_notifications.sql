@@ -0,0 +1,45 @@
+\c poster
+
+drop schema if exists notifications cascade;
+create schema notifications;
+
+-- A user is notified about a comment once
+drop table if exists notifications.notifications;
+create table notifications.notifications
+(
+    id uuid primary key,
+    user_id uuid not null,
+    kind integer not null,
+    actor_id uuid not null,
+    post_id uuid not null,
+    comment_id uuid not null,
+    read boolean not null,
+    creation_date timestamptz not null,
+    unique (user_id, comment_id)
+);
+
+alter table notifications.notifications add
+    constraint "fkey_notification_user_id"
+    foreign key (user_id)
+    references users.users(id);
+
+alter table notifications.notifications add
+    constraint "fkey_notification_actor_id"
+    foreign key (actor_id)
+    references users.users(id);
+
+alter table notifications.notifications add
+    constraint "fkey_notification_post_id"
+    foreign key (post_id)
+    references posts.posts(id);
+
+alter table notifications.notifications add
+    constraint "fkey_notification_comment_id"
+    foreign key (comment_id)
+    references comments.comments(id);
+
+create index notifications_user_creation_date
+    on notifications.notifications(user_id, creation_date);
+
+create index notifications_user_unread
+    on notifications.notifications(user_id) where not read;
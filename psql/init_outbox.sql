@@ -0,0 +1,15 @@
+\c poster
+
+drop schema if exists outbox cascade;
+create schema outbox;
+
+drop table if exists outbox.events;
+create table outbox.events
+(
+    id uuid primary key,
+    seq bigint generated always as identity unique,
+    type text not null,
+    entity_id uuid not null,
+    payload jsonb not null,
+    creation_date timestamptz not null
+);
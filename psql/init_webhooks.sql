@@ -0,0 +1,62 @@
+\c poster
+
+drop schema if exists webhooks cascade;
+create schema webhooks;
+
+-- Events are comma separated
+drop table if exists webhooks.webhooks;
+create table webhooks.webhooks
+(
+    id uuid primary key,
+    owner_id uuid not null,
+    url text not null,
+    secret text not null,
+    events text not null,
+    post_id uuid,
+    creation_date timestamptz not null
+);
+
+alter table webhooks.webhooks add
+    constraint "fkey_webhook_owner_id"
+    foreign key (owner_id)
+    references users.users(id);
+
+alter table webhooks.webhooks add
+    constraint "fkey_webhook_post_id"
+    foreign key (post_id)
+    references posts.posts(id);
+
+create index webhooks_owner_creation_date
+    on webhooks.webhooks(owner_id, creation_date);
+
+-- Payload is json rather than jsonb, so that it's sent byte for byte as
+-- it was signed
+drop table if exists webhooks.deliveries;
+create table webhooks.deliveries
+(
+    id uuid primary key,
+    webhook_id uuid not null,
+    event_id uuid not null,
+    event_type text not null,
+    payload json not null,
+    status integer not null,
+    attempts integer not null,
+    status_code integer not null,
+    error text not null,
+    last_attempt timestamptz,
+    next_attempt timestamptz not null,
+    creation_date timestamptz not null,
+    unique (webhook_id, event_id)
+);
+
+alter table webhooks.deliveries add
+    constraint "fkey_delivery_webhook_id"
+    foreign key (webhook_id)
+    references webhooks.webhooks(id)
+    on delete cascade;
+
+create index deliveries_webhook_creation_date
+    on webhooks.deliveries(webhook_id, creation_date);
+
+create index deliveries_pending_next_attempt
+    on webhooks.deliveries(next_attempt) where status = 0;